@@ -4,8 +4,13 @@ import (
 	"log"
 	"os"
 
+	"github.com/Optum/dce/pkg/account/accountiface"
+	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/event/eventiface"
+	"github.com/Optum/dce/pkg/lease/leaseiface"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
@@ -22,6 +27,7 @@ var (
 	_s3Service    *common.S3
 	_snsService   *common.SNS
 	_db           *db.DB
+	_services     *config.ServiceBuilder
 )
 
 // service struct holds all the services to be used by
@@ -39,11 +45,16 @@ type serviceConfig struct {
 	accountAdminRoleName       string
 	accountAdminRoleARN        string
 	nukeRegions                []string
+	maxFailedResetAttempts     int
+	resetSteps                 []string
 
 	isNukeEnabled       bool
+	isVerifyEnabled     bool
 	nukeTemplateDefault string
 	nukeTemplateBucket  string
 	nukeTemplateKey     string
+	nukeOverridesBucket string
+	resetReportBucket   string
 }
 
 func (svc *service) config() *serviceConfig {
@@ -60,10 +71,16 @@ func (svc *service) config() *serviceConfig {
 		accountAdminRoleARN:        "arn:aws:iam::" + childAccountID + ":role/" + accountAdminRoleName,
 
 		isNukeEnabled:       os.Getenv("RESET_NUKE_TOGGLE") != "false",
+		isVerifyEnabled:     os.Getenv("RESET_VERIFY_TOGGLE") != "false",
 		nukeTemplateDefault: common.RequireEnv("RESET_NUKE_TEMPLATE_DEFAULT"),
 		nukeTemplateBucket:  common.RequireEnv("RESET_NUKE_TEMPLATE_BUCKET"),
 		nukeTemplateKey:     common.RequireEnv("RESET_NUKE_TEMPLATE_KEY"),
 		nukeRegions:         common.RequireEnvStringSlice("RESET_NUKE_REGIONS", ","),
+		nukeOverridesBucket: common.RequireEnv("RESET_NUKE_OVERRIDES_BUCKET"),
+		resetReportBucket:   common.RequireEnv("RESET_REPORT_BUCKET"),
+
+		maxFailedResetAttempts: common.RequireEnvInt("RESET_MAX_FAILED_ATTEMPTS"),
+		resetSteps:             common.RequireEnvStringSlice("RESET_STEPS", ","),
 	}
 
 	return _config
@@ -127,3 +144,52 @@ func (svc *service) snsService() *common.SNS {
 
 	return _snsService
 }
+
+// services builds the DCE services used by the reset
+func (svc *service) services() *config.ServiceBuilder {
+	if _services != nil {
+		return _services
+	}
+	cfgBldr := &config.ConfigurationBuilder{}
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Fatalf("Failed to load configuration:  %s", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+	_, err = svcBldr.
+		WithLeaseService().
+		WithAccountManagerService().
+		WithAccountService().
+		Build()
+	if err != nil {
+		log.Fatalf("Failed to initialize services:  %s", err)
+	}
+	_services = svcBldr
+	return _services
+}
+
+func (svc *service) leaseService() leaseiface.Servicer {
+	return svc.services().LeaseService()
+}
+
+func (svc *service) accountService() accountiface.Servicer {
+	return svc.services().AccountService()
+}
+
+func (svc *service) eventService() eventiface.Servicer {
+	var eventSvc eventiface.Servicer
+	err := svc.services().Config.GetService(&eventSvc)
+	if err != nil {
+		log.Fatalf("Failed to initialize Event Service:  %s", err)
+	}
+	return eventSvc
+}
+
+func (svc *service) accountManagerService() accountmanageriface.Servicer {
+	var accountManagerSvc accountmanageriface.Servicer
+	err := svc.services().Config.GetService(&accountManagerSvc)
+	if err != nil {
+		log.Fatalf("Failed to initialize Account Manager Service:  %s", err)
+	}
+	return accountManagerSvc
+}
@@ -20,6 +20,8 @@ func TestService(t *testing.T) {
 		"RESET_NUKE_TEMPLATE_DEFAULT",
 		"RESET_NUKE_TEMPLATE_BUCKET",
 		"RESET_NUKE_TEMPLATE_KEY",
+		"RESET_REPORT_BUCKET",
+		"RESET_NUKE_OVERRIDES_BUCKET",
 	}
 	for _, envKey := range envVars {
 		_ = os.Setenv(envKey, envKey+"_VAL")
@@ -27,10 +29,14 @@ func TestService(t *testing.T) {
 
 	// Set toggle env vars
 	_ = os.Setenv("RESET_NUKE_TOGGLE", "true")
+	_ = os.Setenv("RESET_VERIFY_TOGGLE", "false")
 
 	// Set regions env var
 	_ = os.Setenv("RESET_NUKE_REGIONS", "us-east-1,us-west-1")
 
+	_ = os.Setenv("RESET_MAX_FAILED_ATTEMPTS", "3")
+	_ = os.Setenv("RESET_STEPS", "Athena,AwsNuke")
+
 	t.Run("getConfig", func(t *testing.T) {
 
 		t.Run("should configure from env vars", func(t *testing.T) {
@@ -47,13 +53,18 @@ func TestService(t *testing.T) {
 			require.Equal(t, "RESET_NUKE_TEMPLATE_DEFAULT_VAL", config.nukeTemplateDefault)
 			require.Equal(t, "RESET_NUKE_TEMPLATE_BUCKET_VAL", config.nukeTemplateBucket)
 			require.Equal(t, "RESET_NUKE_TEMPLATE_KEY_VAL", config.nukeTemplateKey)
+			require.Equal(t, "RESET_REPORT_BUCKET_VAL", config.resetReportBucket)
+			require.Equal(t, "RESET_NUKE_OVERRIDES_BUCKET_VAL", config.nukeOverridesBucket)
 			require.Equal(t, []string{"us-east-1", "us-west-1"}, config.nukeRegions)
+			require.Equal(t, 3, config.maxFailedResetAttempts)
+			require.Equal(t, []string{"Athena", "AwsNuke"}, config.resetSteps)
 
 			// Check computed config vals
 			require.Equal(t, "arn:aws:iam::RESET_ACCOUNT_VAL:role/RESET_ACCOUNT_ADMIN_ROLE_NAME_VAL", config.accountAdminRoleARN)
 
 			// Check toggle env vars
 			require.Equal(t, true, config.isNukeEnabled)
+			require.Equal(t, false, config.isVerifyEnabled)
 		})
 
 		t.Run("should be a singleton", func(t *testing.T) {
@@ -96,21 +96,25 @@ func Handler(_ events.CloudWatchEvent) {
 	NotReady := getMetric(account.StatusNotReady)
 	Leased := getMetric(account.StatusLeased)
 	Orphaned := getMetric(account.StatusOrphaned)
+	ResetFailed := getMetric(account.StatusResetFailed)
 
 	log.Println("Found ", Ready.count, Ready.name, " accounts")
 	log.Println("Found ", NotReady.count, NotReady.name, " accounts")
 	log.Println("Found ", Leased.count, Leased.name, " accounts")
 	log.Println("Found ", Orphaned.count, Orphaned.name, " accounts")
+	log.Println("Found ", ResetFailed.count, ResetFailed.name, " accounts")
 
 	publishMetrics("DCE/AccountPool", Ready)
 	publishMetrics("DCE/AccountPool", NotReady)
 	publishMetrics("DCE/AccountPool", Leased)
 	publishMetrics("DCE/AccountPool", Orphaned)
+	publishMetrics("DCE/AccountPool", ResetFailed)
 
 	log.Println("Published ReadyAccount Metric: ", float64(Ready.count))
 	log.Println("Published NotReadyAccounts Metric: ", float64(NotReady.count))
 	log.Println("Published LeasedAccounts Metric: ", float64(Leased.count))
 	log.Println("Published OrphanedAccounts Metric: ", float64(Orphaned.count))
+	log.Println("Published ResetFailedAccounts Metric: ", float64(ResetFailed.count))
 
 	log.Print("Account pool metrics lambda complete")
 }
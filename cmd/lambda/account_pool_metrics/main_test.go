@@ -33,6 +33,10 @@ func TestGetMetrics(t *testing.T) {
 			name:   "get orphaned accounts",
 			status: account.StatusOrphaned,
 		},
+		{
+			name:   "get reset failed accounts",
+			status: account.StatusResetFailed,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
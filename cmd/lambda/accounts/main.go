@@ -38,6 +38,8 @@ var (
 	Services *config.ServiceBuilder
 	// Settings - the configuration settings for the controller
 	Settings *accountControllerConfiguration
+	// userDetails identifies the user making each request
+	userDetails api.UserDetailer
 )
 
 var (
@@ -58,6 +60,7 @@ func init() {
 			"/accounts",
 			api.EmptyQueryString,
 			GetAccounts,
+			api.PermissionAccountsRead,
 		},
 		api.Route{
 			"GetAccountByID",
@@ -65,6 +68,15 @@ func init() {
 			"/accounts/{accountId}",
 			api.EmptyQueryString,
 			GetAccountByID,
+			api.PermissionAccountsRead,
+		},
+		api.Route{
+			"GetLatestResetReport",
+			"GET",
+			"/accounts/{accountId}/resets/latest",
+			api.EmptyQueryString,
+			GetLatestResetReport,
+			api.PermissionAccountsRead,
 		},
 		api.Route{
 			"UpdateAccountByID",
@@ -72,6 +84,7 @@ func init() {
 			"/accounts/{accountId}",
 			api.EmptyQueryString,
 			UpdateAccountByID,
+			api.PermissionAccountsWrite,
 		},
 		api.Route{
 			"DeleteAccount",
@@ -79,6 +92,7 @@ func init() {
 			"/accounts/{accountId}",
 			api.EmptyQueryString,
 			DeleteAccount,
+			api.PermissionAccountsWrite,
 		},
 		api.Route{
 			"CreateAccount",
@@ -86,6 +100,7 @@ func init() {
 			"/accounts",
 			api.EmptyQueryString,
 			CreateAccount,
+			api.PermissionAccountsWrite,
 		},
 	}
 	r := api.NewRouter(accountRoutes)
@@ -110,12 +125,14 @@ func initConfig() {
 
 	_, err = svcBldr.
 		WithAccountService().
+		WithUserDetailer().
 		Build()
 	if err != nil {
 		panic(err)
 	}
 
 	Services = svcBldr
+	userDetails = svcBldr.UserDetailer()
 
 }
 
@@ -128,8 +145,9 @@ func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 	baseRequest.Host = req.Headers["Host"]
 	baseRequest.Path = fmt.Sprintf("%s%s", req.RequestContext.Stage, req.Path)
 
-	// If no name is provided in the HTTP request body, throw an error
-	return muxLambda.ProxyWithContext(ctx, req)
+	// Routes are authorized for the user making the request
+	ctxWithUser := api.WithUser(ctx, userDetails.GetUser(&req))
+	return muxLambda.ProxyWithContext(ctxWithUser, req)
 }
 
 func main() {
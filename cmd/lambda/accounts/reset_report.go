@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/errors"
+)
+
+// GetLatestResetReport - Returns the report of the account's last dry run reset,
+// as JSON, or as CSV with `?format=csv`
+func GetLatestResetReport(w http.ResponseWriter, r *http.Request) {
+
+	accountID := mux.Vars(r)["accountId"]
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		api.WriteAPIErrorResponse(w, errors.NewBadRequest("format must be json or csv"))
+		return
+	}
+
+	account, err := Services.AccountService().Get(accountID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	// Reports are only created by dry run resets
+	if account.LastResetResult == nil || account.LastResetResult.ReportKey == "" {
+		api.WriteAPIErrorResponse(w, errors.NewNotFound("reset report", accountID))
+		return
+	}
+	reportKey := account.LastResetResult.ReportKey
+	if format == "csv" {
+		reportKey = account.LastResetResult.ReportCSVKey
+	}
+
+	var storager common.Storager
+	if err := Services.Config.GetService(&storager); err != nil {
+		api.WriteAPIErrorResponse(w, errors.NewInternalServer("unable to get storage service", err))
+		return
+	}
+	report, err := storager.GetObject(Settings.ArtifactsBucket, reportKey)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, errors.NewInternalServer("failed to get reset report", err))
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(report))
+		return
+	}
+	api.WriteAPIResponse(w, http.StatusOK, json.RawMessage(report))
+}
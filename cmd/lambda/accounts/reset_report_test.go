@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/account/accountiface/mocks"
+	commonMocks "github.com/Optum/dce/pkg/common/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetLatestResetReport(t *testing.T) {
+
+	type response struct {
+		StatusCode  int
+		ContentType string
+		Body        string
+	}
+	dryRunAccount := &account.Account{
+		LastResetResult: &account.ResetResult{
+			ReportKey:    "reset-reports/123456789012/20200101T000000Z.json",
+			ReportCSVKey: "reset-reports/123456789012/20200101T000000Z.csv",
+		},
+	}
+	tests := []struct {
+		name       string
+		query      string
+		expResp    response
+		retAccount *account.Account
+		retErr     error
+		expKey     string
+		retReport  string
+		retGetErr  error
+	}{
+		{
+			name:       "should get the JSON report",
+			expResp:    response{StatusCode: 200, ContentType: "application/json", Body: "{\"accountId\":\"123456789012\"}\n"},
+			retAccount: dryRunAccount,
+			expKey:     "reset-reports/123456789012/20200101T000000Z.json",
+			retReport:  `{"accountId":"123456789012"}`,
+		},
+		{
+			name:       "should get the CSV report",
+			query:      "?format=csv",
+			expResp:    response{StatusCode: 200, ContentType: "text/csv", Body: "region,resourceType\n"},
+			retAccount: dryRunAccount,
+			expKey:     "reset-reports/123456789012/20200101T000000Z.csv",
+			retReport:  "region,resourceType\n",
+		},
+		{
+			name:    "should fail with an invalid format",
+			query:   "?format=xml",
+			expResp: response{StatusCode: 400, ContentType: "application/json", Body: "{\"error\":{\"message\":\"format must be json or csv\",\"code\":\"ClientError\"}}\n"},
+		},
+		{
+			name:       "should fail without a dry run reset",
+			expResp:    response{StatusCode: 404, ContentType: "application/json", Body: "{\"error\":{\"message\":\"reset report \\\"123456789012\\\" not found\",\"code\":\"NotFoundError\"}}\n"},
+			retAccount: &account.Account{},
+		},
+		{
+			name:    "should fail when the account can't be retrieved",
+			expResp: response{StatusCode: 500, ContentType: "application/json", Body: "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n"},
+			retErr:  fmt.Errorf("failure"),
+		},
+		{
+			name:       "should fail when the report can't be retrieved",
+			expResp:    response{StatusCode: 500, ContentType: "application/json", Body: "{\"error\":{\"message\":\"failed to get reset report\",\"code\":\"ServerError\"}}\n"},
+			retAccount: dryRunAccount,
+			expKey:     "reset-reports/123456789012/20200101T000000Z.json",
+			retGetErr:  fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://example.com/accounts/123456789012/resets/latest"+tt.query, nil)
+
+			r = mux.SetURLVars(r, map[string]string{
+				"accountId": "123456789012",
+			})
+			w := httptest.NewRecorder()
+			// Set by the router
+			w.Header().Add("Content-Type", "application/json")
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			accountSvc := mocks.Servicer{}
+			accountSvc.On("Get", "123456789012").Return(
+				tt.retAccount, tt.retErr,
+			)
+			storageSvc := commonMocks.Storager{}
+			storageSvc.On("GetObject", Settings.ArtifactsBucket, tt.expKey).Return(
+				tt.retReport, tt.retGetErr,
+			)
+			svcBldr.Config.WithService(&accountSvc).WithService(&storageSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetLatestResetReport(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.ContentType, resp.Header.Get("Content-Type"))
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+
+}
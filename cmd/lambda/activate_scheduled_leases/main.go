@@ -0,0 +1,97 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type configuration struct {
+	Debug string `env:"DEBUG" envDefault:"false"`
+}
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithLeaseService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+
+}
+
+// Handler is the base handler function for the lambda
+func Handler(cloudWatchEvent events.CloudWatchEvent) error {
+
+	query := &lease.Lease{
+		Status: lease.StatusPending.StatusPtr(),
+	}
+
+	// Find the scheduled leases which have reached their start time
+	now := time.Now().Unix()
+	scheduled := lease.Leases{}
+	err := services.LeaseService().ListPages(query,
+		func(leases *lease.Leases) bool {
+			for _, l := range *leases {
+				if l.StatusReason == nil || *l.StatusReason != lease.StatusReasonScheduled {
+					continue
+				}
+				if l.StartsOn != nil && *l.StartsOn > now {
+					continue
+				}
+				scheduled = append(scheduled, l)
+			}
+			return true //always continue
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, l := range scheduled {
+		activated, err := services.LeaseService().ActivateScheduled(*l.ID)
+		if err != nil {
+			log.Printf("Failed to activate scheduled lease %s for principal %s: %s", *l.ID, *l.PrincipalID, err)
+			errs = append(errs, err)
+			continue
+		}
+		log.Printf("Scheduled lease %s for principal %s is now %s", *l.ID, *l.PrincipalID, *activated.Status)
+	}
+
+	if len(errs) > 0 {
+		return errors.NewMultiError("error when activating scheduled leases", errs)
+	}
+	return nil
+}
+
+// Main
+func main() {
+	lambda.Start(Handler)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func ptrInt64(i int64) *int64 {
+	ptrI := i
+	return &ptrI
+}
+
+// TestActivateScheduledLeases tests that scheduled leases are activated
+// once they reach their start time
+func TestActivateScheduledLeases(t *testing.T) {
+	past := time.Now().Add(-time.Minute).Unix()
+	future := time.Now().AddDate(0, 0, 2).Unix()
+
+	tests := []struct {
+		name         string
+		pending      *lease.Leases
+		listErr      error
+		activateErr  error
+		expActivated []string
+		expErr       error
+	}{
+		{
+			name: "should activate scheduled leases which have started",
+			pending: &lease.Leases{
+				{
+					ID:           ptrString("started"),
+					PrincipalID:  ptrString("jdoe123"),
+					Status:       lease.StatusPending.StatusPtr(),
+					StatusReason: lease.StatusReasonScheduled.StatusReasonPtr(),
+					StartsOn:     ptrInt64(past),
+				},
+				{
+					ID:           ptrString("not-started"),
+					PrincipalID:  ptrString("jdoe123"),
+					Status:       lease.StatusPending.StatusPtr(),
+					StatusReason: lease.StatusReasonScheduled.StatusReasonPtr(),
+					StartsOn:     ptrInt64(future),
+				},
+				{
+					ID:           ptrString("waitlisted"),
+					PrincipalID:  ptrString("jdoe123"),
+					Status:       lease.StatusPending.StatusPtr(),
+					StatusReason: lease.StatusReasonPendingAccount.StatusReasonPtr(),
+				},
+			},
+			expActivated: []string{"started"},
+		},
+		{
+			name:    "should fail on list err",
+			pending: &lease.Leases{},
+			listErr: errors.NewInternalServer("error", fmt.Errorf("error")),
+			expErr:  errors.NewInternalServer("error", fmt.Errorf("error")),
+		},
+		{
+			name: "should fail on activate err",
+			pending: &lease.Leases{
+				{
+					ID:           ptrString("started"),
+					PrincipalID:  ptrString("jdoe123"),
+					Status:       lease.StatusPending.StatusPtr(),
+					StatusReason: lease.StatusReasonScheduled.StatusReasonPtr(),
+					StartsOn:     ptrInt64(past),
+				},
+			},
+			activateErr:  errors.NewInternalServer("error", fmt.Errorf("error")),
+			expActivated: []string{"started"},
+			expErr: errors.NewMultiError("error when activating scheduled leases", []error{
+				errors.NewInternalServer("error", fmt.Errorf("error")),
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			leaseSvc := &mocks.Servicer{}
+			leaseSvc.On("ListPages", mock.MatchedBy(func(input *lease.Lease) bool {
+				return *input.Status == lease.StatusPending
+			}), mock.Anything).Run(func(args mock.Arguments) {
+				fn := args.Get(1).(func(*lease.Leases) bool)
+				fn(tt.pending)
+			}).Return(tt.listErr)
+			leaseSvc.On("ActivateScheduled", mock.AnythingOfType("string")).
+				Return(&lease.Lease{Status: lease.StatusActive.StatusPtr()}, tt.activateErr)
+
+			svcBldr.Config.WithService(leaseSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				services = svcBldr
+			}
+
+			err = Handler(events.CloudWatchEvent{})
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+
+			leaseSvc.AssertNumberOfCalls(t, "ActivateScheduled", len(tt.expActivated))
+			for _, id := range tt.expActivated {
+				leaseSvc.AssertCalled(t, "ActivateScheduled", id)
+			}
+		})
+	}
+}
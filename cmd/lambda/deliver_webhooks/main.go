@@ -0,0 +1,153 @@
+// Package main sets the handler for the Deliver Webhooks AWS Lambda Function,
+// which delivers the events published to the lease and account SNS topics to
+// the registered webhooks
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event"
+	"github.com/Optum/dce/pkg/webhook/webhookiface"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// eventIDAttribute is the SNS message attribute holding the ID of an event
+const eventIDAttribute = "EventId"
+
+type configuration struct {
+	AccountCreatedTopicArn         string `env:"ACCOUNT_CREATED_TOPIC_ARN"`
+	AccountDeletedTopicArn         string `env:"ACCOUNT_DELETED_TOPIC_ARN"`
+	LeaseAddedTopicArn             string `env:"LEASE_ADDED_TOPIC"`
+	LeaseLockedTopicArn            string `env:"LEASE_LOCKED_TOPIC_ARN"`
+	LeaseReservationFailedTopicArn string `env:"LEASE_RESERVATION_FAILED_TOPIC"`
+	EventSource                    string `env:"EVENT_SOURCE" envDefault:"dce"`
+}
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithWebhookService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+}
+
+// Start the Lambda Handler
+func main() {
+	lambda.Start(handler)
+}
+
+// handler delivers each SNS message to the webhooks subscribed to its type
+// of event.  Deliveries are retried within the invocation, so an error is only
+// returned if a failed delivery couldn't be recorded as a dead letter
+func handler(ctx context.Context, snsEvent events.SNSEvent) error {
+	d := &deliverer{
+		webhookSvc: services.WebhookService(),
+		source:     settings.EventSource,
+		eventTypes: map[string]event.Type{
+			settings.AccountCreatedTopicArn:         event.TypeAccountCreated,
+			settings.AccountDeletedTopicArn:         event.TypeAccountDeleted,
+			settings.LeaseAddedTopicArn:             event.TypeLeaseCreated,
+			settings.LeaseLockedTopicArn:            event.TypeLeaseEnded,
+			settings.LeaseReservationFailedTopicArn: event.TypeLeaseReservationFailed,
+		},
+	}
+
+	// Defer errors for later
+	deferredErrors := []error{}
+	for _, record := range snsEvent.Records {
+		err := d.handleRecord(record.SNS)
+		if err != nil {
+			deferredErrors = append(deferredErrors, err)
+		}
+	}
+
+	if len(deferredErrors) > 0 {
+		return errors.NewMultiError("Failed to deliver events to webhooks", deferredErrors)
+	}
+
+	return nil
+}
+
+// deliverer turns SNS messages into events, and delivers them to webhooks
+type deliverer struct {
+	webhookSvc webhookiface.Servicer
+	source     string
+	// eventTypes maps the ARN of each topic to the type of its events
+	eventTypes map[string]event.Type
+}
+
+func (d *deliverer) handleRecord(msg events.SNSEntity) error {
+	eventType, ok := d.eventTypes[msg.TopicArn]
+	if !ok {
+		log.Printf("Skipping message %s from unknown topic %s", msg.MessageID, msg.TopicArn)
+		return nil
+	}
+
+	evt, err := d.newEvent(eventType, msg)
+	if err != nil {
+		return err
+	}
+
+	return d.webhookSvc.Deliver(evt)
+}
+
+// newEvent wraps the message in a CloudEvent.  Events published from the
+// outbox carry their ID in a message attribute, which is kept so webhooks
+// can discard duplicates.  Other events use the ID of the SNS message
+func (d *deliverer) newEvent(eventType event.Type, msg events.SNSEntity) (*event.CloudEvent, error) {
+	data := json.RawMessage(msg.Message)
+	subject := struct {
+		ID string `json:"id"`
+	}{}
+	err := json.Unmarshal(data, &subject)
+	if err != nil {
+		return nil, errors.NewInternalServer("unexpected error parsing SNS message", err)
+	}
+
+	evt := event.NewCloudEvent(d.source, eventType, data)
+	evt.ID = msg.MessageID
+	if id := messageAttribute(msg, eventIDAttribute); id != "" {
+		evt.ID = id
+	}
+	evt.Subject = subject.ID
+	if !msg.Timestamp.IsZero() {
+		evt.Time = msg.Timestamp.UTC()
+	}
+	return evt, nil
+}
+
+// messageAttribute returns the value of a String message attribute
+func messageAttribute(msg events.SNSEntity, name string) string {
+	attr, ok := msg.MessageAttributes[name].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, _ := attr["Value"].(string)
+	return value
+}
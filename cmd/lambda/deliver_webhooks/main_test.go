@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dataMocks "github.com/Optum/dce/pkg/data/dataiface/mocks"
+	"github.com/Optum/dce/pkg/event"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleRecord(t *testing.T) {
+	tests := []struct {
+		name         string
+		topicArn     string
+		attributes   map[string]interface{}
+		expDelivered bool
+		expID        string
+		expType      event.Type
+	}{
+		{
+			name:         "should deliver an event with the ID of the message",
+			topicArn:     "arn:aws:sns:us-east-1:123456789012:lease-added",
+			expDelivered: true,
+			expID:        "msg-123",
+			expType:      event.TypeLeaseCreated,
+		},
+		{
+			name:     "should deliver an event with the ID of an outbox event",
+			topicArn: "arn:aws:sns:us-east-1:123456789012:account-created",
+			attributes: map[string]interface{}{
+				"EventId": map[string]interface{}{
+					"Type":  "String",
+					"Value": "abc-123",
+				},
+			},
+			expDelivered: true,
+			expID:        "abc-123",
+			expType:      event.TypeAccountCreated,
+		},
+		{
+			name:     "should skip a message from an unknown topic",
+			topicArn: "arn:aws:sns:us-east-1:123456789012:other",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var delivered *event.CloudEvent
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				assert.True(t, webhook.Verify(
+					"0123456789abcdef",
+					r.Header.Get(webhook.TimestampHeader),
+					body,
+					r.Header.Get(webhook.SignatureHeader),
+				), "signature doesn't match")
+
+				delivered = &event.CloudEvent{}
+				assert.Nil(t, json.Unmarshal(body, delivered))
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			dataSvc := &dataMocks.WebhookData{}
+			dataSvc.On("List").Return(&webhook.Webhooks{
+				{
+					ID:     aws.String("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+					URL:    aws.String(server.URL),
+					Secret: aws.String("0123456789abcdef"),
+				},
+			}, nil)
+
+			d := &deliverer{
+				webhookSvc: webhook.NewService(webhook.NewServiceInput{
+					DataSvc:     dataSvc,
+					HTTPClient:  server.Client(),
+					MaxAttempts: 1,
+				}),
+				source: "dce.test",
+				eventTypes: map[string]event.Type{
+					"arn:aws:sns:us-east-1:123456789012:account-created": event.TypeAccountCreated,
+					"arn:aws:sns:us-east-1:123456789012:lease-added":     event.TypeLeaseCreated,
+				},
+			}
+			err := d.handleRecord(events.SNSEntity{
+				MessageID:         "msg-123",
+				TopicArn:          tt.topicArn,
+				Message:           `{"id":"123456789012","accountStatus":"NotReady"}`,
+				MessageAttributes: tt.attributes,
+				Timestamp:         time.Unix(1573592058, 0),
+			})
+
+			assert.Nil(t, err)
+			if tt.expDelivered {
+				assert.NotNil(t, delivered)
+				assert.Equal(t, tt.expID, delivered.ID)
+				assert.Equal(t, tt.expType, delivered.Type)
+				assert.Equal(t, "dce.test", delivered.Source)
+				assert.Equal(t, "123456789012", delivered.Subject)
+				assert.Equal(t, map[string]interface{}{
+					"id":            "123456789012",
+					"accountStatus": "NotReady",
+				}, delivered.Data)
+			} else {
+				assert.Nil(t, delivered)
+				dataSvc.AssertNotCalled(t, "List")
+			}
+		})
+	}
+}
@@ -54,11 +54,9 @@ func (controller CreateController) Call(ctx context.Context, req *events.APIGate
 
 	// Get the User Information
 	user := controller.UserDetailer.GetUser(req)
-	if user.Role != api.AdminGroupName {
-		if lease.PrincipalID != user.Username {
-			log.Printf("User (%s) doesn't have access to lease %s", user.Username, leaseID)
-			return response.NotFoundError(), nil
-		}
+	if !api.DefaultAuthorizer.AllowedFor(user, api.PermissionLeasesLogin, lease.PrincipalID) {
+		log.Printf("User (%s) doesn't have access to lease %s", user.Username, leaseID)
+		return response.NotFoundError(), nil
 	}
 
 	// Get the Account Information
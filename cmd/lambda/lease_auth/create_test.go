@@ -216,6 +216,27 @@ func TestGetLeaseAuth(t *testing.T) {
 				userRole:         api.UserGroupName,
 				principalRoleArn: "arn:aws:iam::Account123:role/Principal",
 			},
+			{
+				name:            "AuditorHasNoAccessToLease",
+				leaseID:         "Lease987",
+				accountID:       "Account987",
+				getLeaseByIDErr: nil,
+				getAccountErr:   nil,
+				expectedResponse: &events.APIGatewayProxyResponse{
+					StatusCode: 404,
+					Headers: map[string]string{
+						"Content-Type":                "application/json",
+						"Access-Control-Allow-Origin": "*",
+					},
+					Body: `{"error":{"code":"NotFound","message":"The requested resource could not be found."}}`,
+				},
+				assumeRoleErr:    nil,
+				leaseStatus:      db.Active,
+				expectedErr:      nil,
+				userName:         "TestUser",
+				userRole:         api.AuditorGroupName,
+				principalRoleArn: "arn:aws:iam::Account123:role/Principal",
+			},
 		}
 
 		// Close the server when test finishes
@@ -7,9 +7,9 @@ import (
 
 	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/db"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go/service/sts"
 
 	"github.com/aws/aws-lambda-go/lambda"
@@ -28,12 +28,7 @@ func main() {
 	// Create the Token Service
 	awsSession := newAWSSession()
 	tokenSvc := common.STS{Client: sts.New(awsSession)}
-	cognitoSvc := cognitoidentityprovider.New(awsSession)
-	userDetails := &api.UserDetails{
-		CognitoUserPoolID:        common.RequireEnv("COGNITO_USER_POOL_ID"),
-		RolesAttributesAdminName: common.RequireEnv("COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME"),
-		CognitoClient:            cognitoSvc,
-	}
+	userDetails := newUserDetails()
 
 	router := &api.Router{
 		ResourceName: "/auth",
@@ -45,6 +40,7 @@ func main() {
 			UserDetailer:  userDetails,
 		},
 		UserDetails: userDetails,
+		Permission:  api.PermissionLeasesLogin,
 	}
 
 	lambda.Start(router.Route)
@@ -68,3 +64,22 @@ func newAWSSession() *session.Session {
 	}
 	return awsSession
 }
+
+func newUserDetails() api.UserDetailer {
+	cfgBldr := &config.ConfigurationBuilder{}
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithUserDetailer().
+		Build()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed to initialize user details: %s", err)
+		log.Fatal(errorMessage)
+	}
+
+	return svcBldr.UserDetailer()
+}
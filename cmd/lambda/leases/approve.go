@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/email"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gorilla/mux"
+)
+
+// ApproveLease - Approves a lease which is pending approval, and claims an account for it
+func ApproveLease(w http.ResponseWriter, r *http.Request) {
+
+	leaseID := mux.Vars(r)["leaseID"]
+
+	approved, err := Services.LeaseService().Approve(leaseID)
+	if err != nil {
+		log.Printf("Failed to approve lease %s: %s", leaseID, err)
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	// Approved leases on the waitlist or scheduled for later don't have an account yet
+	if approved.Status != nil && *approved.Status == lease.StatusPending {
+		api.WriteAPIResponse(w, http.StatusAccepted, approved)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, approved)
+}
+
+// RejectLease - Rejects a lease which is pending approval
+func RejectLease(w http.ResponseWriter, r *http.Request) {
+
+	leaseID := mux.Vars(r)["leaseID"]
+
+	rejected, err := Services.LeaseService().Reject(leaseID)
+	if err != nil {
+		log.Printf("Failed to reject lease %s: %s", leaseID, err)
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, rejected)
+}
+
+// notifyApprovers emails the approvers about a lease which is pending approval
+func notifyApprovers(l *lease.Lease, reason string) error {
+	if len(Settings.LeaseApprovalEmails) == 0 {
+		log.Printf("Skipping lease approval emails: no approver email addresses were provided for lease %s",
+			aws.StringValue(l.ID))
+		return nil
+	}
+
+	principalID := aws.StringValue(l.PrincipalID)
+	leaseID := aws.StringValue(l.ID)
+	subject := fmt.Sprintf("Lease for %s is pending approval", principalID)
+	bodyText := fmt.Sprintf("Principal %s requested a lease which needs approval.\n\n%s\n\n"+
+		"Approve the lease with POST /leases/%s/approve, or reject it with POST /leases/%s/reject.",
+		principalID, reason, leaseID, leaseID)
+	bodyHTML := fmt.Sprintf("<p>Principal %s requested a lease which needs approval.</p><p>%s</p>"+
+		"<p>Approve the lease with <code>POST /leases/%s/approve</code>, or reject it with <code>POST /leases/%s/reject</code>.</p>",
+		html.EscapeString(principalID), html.EscapeString(reason), leaseID, leaseID)
+
+	return emailSvc.SendEmail(&email.SendEmailInput{
+		FromAddress: Settings.LeaseApprovalFromEmail,
+		ToAddresses: Settings.LeaseApprovalEmails,
+		Subject:     subject,
+		BodyHTML:    bodyHTML,
+		BodyText:    bodyText,
+	})
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproveLease(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name       string
+		leaseID    string
+		retLease   *lease.Lease
+		approveErr error
+		expResp    response
+	}{
+		{
+			name:    "should activate the approved lease",
+			leaseID: "abc123",
+			retLease: &lease.Lease{
+				ID:           ptrString("abc123"),
+				PrincipalID:  ptrString("principal"),
+				AccountID:    ptrString("123456789012"),
+				Status:       lease.StatusActive.StatusPtr(),
+				StatusReason: lease.StatusReasonActive.StatusReasonPtr(),
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"accountId\":\"123456789012\",\"principalId\":\"principal\",\"id\":\"abc123\",\"leaseStatus\":\"Active\",\"leaseStatusReason\":\"Active\"}\n",
+			},
+		},
+		{
+			name:    "should accept an approved lease which is scheduled for later",
+			leaseID: "abc123",
+			retLease: &lease.Lease{
+				ID:           ptrString("abc123"),
+				PrincipalID:  ptrString("principal"),
+				Status:       lease.StatusPending.StatusPtr(),
+				StatusReason: lease.StatusReasonScheduled.StatusReasonPtr(),
+			},
+			expResp: response{
+				StatusCode: 202,
+				Body:       "{\"principalId\":\"principal\",\"id\":\"abc123\",\"leaseStatus\":\"Pending\",\"leaseStatusReason\":\"Scheduled\"}\n",
+			},
+		},
+		{
+			name:       "should fail when the lease isn't pending approval",
+			leaseID:    "abc123",
+			approveErr: errors.NewConflict("lease", "abc123", fmt.Errorf("lease is not pending approval")),
+			expResp: response{
+				StatusCode: 409,
+				Body:       "{\"error\":{\"message\":\"operation cannot be fulfilled on lease \\\"abc123\\\": lease is not pending approval\",\"code\":\"ConflictError\"}}\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", fmt.Sprintf("http://example.com/leases/%s/approve", tt.leaseID), nil)
+			r = withUser(r, adminUser)
+			r = mux.SetURLVars(r, map[string]string{
+				"leaseID": tt.leaseID,
+			})
+			w := httptest.NewRecorder()
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			leaseSvc := mocks.Servicer{}
+			leaseSvc.On("Approve", tt.leaseID).Return(tt.retLease, tt.approveErr)
+
+			svcBldr.Config.WithService(&leaseSvc)
+			_, err := svcBldr.Build()
+			assert.Nil(t, err)
+			Services = svcBldr
+
+			ApproveLease(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+}
+
+func TestRejectLease(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name      string
+		leaseID   string
+		retLease  *lease.Lease
+		rejectErr error
+		expResp   response
+	}{
+		{
+			name:    "should end the rejected lease",
+			leaseID: "abc123",
+			retLease: &lease.Lease{
+				ID:           ptrString("abc123"),
+				PrincipalID:  ptrString("principal"),
+				Status:       lease.StatusInactive.StatusPtr(),
+				StatusReason: lease.StatusReasonRejected.StatusReasonPtr(),
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"principalId\":\"principal\",\"id\":\"abc123\",\"leaseStatus\":\"Inactive\",\"leaseStatusReason\":\"Rejected\"}\n",
+			},
+		},
+		{
+			name:      "should fail when the lease doesn't exist",
+			leaseID:   "abc123",
+			rejectErr: errors.NewNotFound("lease", "abc123"),
+			expResp: response{
+				StatusCode: 404,
+				Body:       "{\"error\":{\"message\":\"lease \\\"abc123\\\" not found\",\"code\":\"NotFoundError\"}}\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", fmt.Sprintf("http://example.com/leases/%s/reject", tt.leaseID), nil)
+			r = withUser(r, adminUser)
+			r = mux.SetURLVars(r, map[string]string{
+				"leaseID": tt.leaseID,
+			})
+			w := httptest.NewRecorder()
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			leaseSvc := mocks.Servicer{}
+			leaseSvc.On("Reject", tt.leaseID).Return(tt.retLease, tt.rejectErr)
+
+			svcBldr.Config.WithService(&leaseSvc)
+			_, err := svcBldr.Build()
+			assert.Nil(t, err)
+			Services = svcBldr
+
+			RejectLease(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+}
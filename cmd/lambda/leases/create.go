@@ -1,18 +1,15 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
-	"github.com/google/uuid"
-	"github.com/pkg/errors"
-
+	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/api/response"
-	"github.com/Optum/dce/pkg/common"
-	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
 )
 
 type createLeaseRequest struct {
@@ -21,7 +18,12 @@ type createLeaseRequest struct {
 	BudgetCurrency           string                 `json:"budgetCurrency"`
 	BudgetNotificationEmails []string               `json:"budgetNotificationEmails"`
 	ExpiresOn                int64                  `json:"expiresOn"`
+	StartsOn                 int64                  `json:"startsOn"`
 	Metadata                 map[string]interface{} `json:"metadata"`
+	AccountSelector          map[string]string      `json:"accountSelector"`
+	TeamID                   string                 `json:"teamId"`
+	// approvalReason is why the lease needs an approver, when it's over the max lease budget or period
+	approvalReason string
 }
 
 // CreateLease - Creates the lease
@@ -36,160 +38,102 @@ func CreateLease(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Extract the Body from the Request
-	requestBody, isValid, validationErrorMessage, err := validateLeaseFromRequest(&c, r)
+	requestBody, isValid, validationErrorMessage := parseLeaseFromRequest(r)
 
-	if err != nil {
-		response.WriteServerErrorWithResponse(w, err.Error())
+	if !isValid {
+		response.WriteRequestValidationError(w, validationErrorMessage)
 		return
 	}
 
-	if !isValid {
-		response.WriteRequestValidationError(w, validationErrorMessage)
+	// Validation reports the principal's usage, so only validate leases the user may create
+	if !api.AllowedFor(r, api.PermissionLeasesWrite, requestBody.PrincipalID) {
+		api.WriteAPIErrorResponse(w,
+			errors.NewForbidden(fmt.Sprintf("user is not authorized to create leases for principal %s", requestBody.PrincipalID)))
 		return
 	}
 
-	principalID := requestBody.PrincipalID
-	log.Printf("Creating lease for Principal %s", principalID)
+	isValid, validationErrorMessage, err := validateLeaseRequest(&c, requestBody)
 
-	// Fail if the Principal already has an active lease
-	principalLeases, err := dao.FindLeasesByPrincipal(requestBody.PrincipalID)
 	if err != nil {
-		log.Printf("Failed to list leases for principal %s: %s", requestBody.PrincipalID, err)
-		response.WriteServerErrorWithResponse(w, "Internal server error")
+		response.WriteServerErrorWithResponse(w, err.Error())
+		return
+	}
+
+	if !isValid {
+		response.WriteRequestValidationError(w, validationErrorMessage)
 		return
 	}
 
-	for _, lease := range principalLeases {
-		if lease.LeaseStatus == db.Active {
-			msg := fmt.Sprintf("Principal already has an active lease for account %s", lease.AccountID)
-			response.WriteConflictError(w, msg)
+	// Leases on a team are held to the team's budget as well as their own
+	var teamID *string
+	if requestBody.TeamID != "" {
+		validationErrStr, err := validateTeamMember(requestBody.TeamID, requestBody.PrincipalID)
+		if err != nil {
+			api.WriteAPIErrorResponse(w, err)
+			return
+		}
+		if validationErrStr != "" {
+			response.WriteRequestValidationError(w, validationErrStr)
 			return
 		}
+		teamID = &requestBody.TeamID
 	}
 
-	// Get the First Ready Account
-	// Exit if there's an error or no ready accounts
-	account, err := dao.GetReadyAccount()
-	if err != nil {
-		log.Printf("Failed to Check Ready Accounts: %s", err)
-		response.WriteServerErrorWithResponse(
-			w,
-			fmt.Sprintf("Failed to find a Ready Account: %s", err),
-		)
-		return
-	} else if account == nil {
-		errStr := "No Available accounts at this moment"
-		log.Println(errStr)
-		response.WriteServiceUnavailableError(w, errStr)
-		return
+	log.Printf("Creating lease for Principal %s", requestBody.PrincipalID)
+
+	// Leases without a start date start right away
+	var startsOn *int64
+	if requestBody.StartsOn != 0 {
+		startsOn = &requestBody.StartsOn
 	}
-	log.Printf("Principal %s will be Leased to Account: %s\n", principalID,
-		account.ID)
-
-	// Create/Update lease record
-	now := time.Now()
-	lease, err := dao.UpsertLease(db.Lease{
-		AccountID:                account.ID,
-		PrincipalID:              requestBody.PrincipalID,
-		ID:                       uuid.New().String(),
-		LeaseStatus:              db.Active,
-		LeaseStatusReason:        db.LeaseActive,
-		BudgetAmount:             requestBody.BudgetAmount,
-		BudgetCurrency:           requestBody.BudgetCurrency,
-		BudgetNotificationEmails: requestBody.BudgetNotificationEmails,
-		CreatedOn:                now.Unix(),
-		LastModifiedOn:           now.Unix(),
-		LeaseStatusModifiedOn:    now.Unix(),
-		ExpiresOn:                requestBody.ExpiresOn,
+
+	leaseRequest := &lease.Lease{
+		PrincipalID:              &requestBody.PrincipalID,
+		BudgetAmount:             &requestBody.BudgetAmount,
+		BudgetCurrency:           &requestBody.BudgetCurrency,
+		BudgetNotificationEmails: &requestBody.BudgetNotificationEmails,
+		ExpiresOn:                &requestBody.ExpiresOn,
+		StartsOn:                 startsOn,
 		Metadata:                 requestBody.Metadata,
-	})
-	if err != nil {
-		log.Printf("Failed to create lease DB record for %s @ %s: %s",
-			requestBody.PrincipalID, account.ID, err)
-		response.WriteServerError(w)
-		return
+		AccountSelector:          requestBody.AccountSelector,
+		TeamID:                   teamID,
 	}
 
-	// Mark the accOunt as Status=Leased
-	_, err = dao.TransitionAccountStatus(account.ID, db.Ready, db.Leased)
-	if err != nil {
-		log.Printf("ERROR Failed to transition account %s to Leased for lease for %s. Attemping to deactivate lease...",
-			lease.AccountID, lease.PrincipalID)
-		// If setting the account status fails, attempt to deactivate the lease
-		// before returning a 500 error
-		_, err = dao.TransitionLeaseStatus(
-			lease.AccountID, lease.PrincipalID,
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		)
+	// Leases over the max lease budget or period wait for an approver
+	if requestBody.approvalReason != "" {
+		newLease, err := Services.LeaseService().RequestApproval(leaseRequest)
 		if err != nil {
-			log.Printf("Failed to deactivate lease on DB error for %s / %s: %s",
-				lease.AccountID, lease.PrincipalID, err)
+			log.Printf("Failed to request approval of lease for principal %s: %s", requestBody.PrincipalID, err)
+			api.WriteAPIErrorResponse(w, err)
+			return
 		}
 
-		response.WriteServerError(w)
-		return
-	}
-
-	// Publish Lease to the topic
-	message, err := publishLease(snsSvc, lease, &leaseAddedTopicARN)
-	if err != nil {
-		log.Print(err.Error())
-
-		// Attempt to rollback the lease
-		_, err := dao.TransitionLeaseStatus(lease.AccountID, lease.PrincipalID, db.Active, db.Inactive, db.LeaseRolledBack)
+		// The lease is saved, so approvers can still find it if the email fails
+		err = notifyApprovers(newLease, requestBody.approvalReason)
 		if err != nil {
-			log.Printf("Failed to deactivate lease on SNS error for %s / %s: %s",
-				lease.AccountID, lease.PrincipalID, err)
-		} else {
-			_, err := dao.TransitionAccountStatus(lease.AccountID, db.Leased, db.Ready)
-			log.Printf("Failed to rollback account status on SNS error for %s / %s: %s",
-				lease.AccountID, lease.PrincipalID, err)
+			log.Printf("Failed to notify approvers of lease %s: %s", *newLease.ID, err)
 		}
 
-		response.WriteServerError(w)
+		api.WriteAPIResponse(w, http.StatusAccepted, newLease)
 		return
 	}
 
-	response.WriteAPIResponse(w, http.StatusCreated, *message)
-}
-
-// publishLease is a helper function to create and publish an lease
-// structured message to an SNS Topic
-func publishLease(snsSvc common.Notificationer,
-	lease *db.Lease, topic *string) (*string, error) {
-	// Create a LeaseResponse based on the lease
-	leaseResp := response.CreateLeaseResponse(lease)
-
-	// Create the message to send to the topic from the Lease
-	messageBytes, err := json.Marshal(leaseResp)
+	// The lease service claims a Ready account and publishes the lease
+	// to the lease added topic
+	newLease, err := Services.LeaseService().Create(leaseRequest)
 	if err != nil {
-		// Rollback
-		log.Printf("Error to Marshal Account Lease: %s", err)
-		return nil, err
+		log.Printf("Failed to create lease for principal %s: %s", requestBody.PrincipalID, err)
+		api.WriteAPIErrorResponse(w, err)
+		return
 	}
-	message := string(messageBytes)
 
-	// Create the messageBody to make it compatible with SNS JSON
-	leaseMsgBody := messageBody{
-		Default: message,
-		Body:    message,
-	}
-	leaseMsgBytes, err := json.Marshal(leaseMsgBody)
-	if err != nil {
-		// Rollback
-		log.Printf("Error to Marshal Message Body: %s", err)
-		return nil, err
+	// Leases on the waitlist or scheduled for later are accepted, but don't have an account yet
+	if newLease.Status != nil && *newLease.Status == lease.StatusPending {
+		api.WriteAPIResponse(w, http.StatusAccepted, newLease)
+		return
 	}
-	leaseMsg := string(leaseMsgBytes)
 
-	// Publish message to the lease topic on the success of the lease creation
-	log.Printf("Sending Lease Message to SNS Topic %s\n", *topic)
-	messageID, err := snsSvc.PublishMessage(topic, &leaseMsg, true)
-	if err != nil {
-		return nil, errors.Wrapf(err, "Error to Send Message to SNS Topic %s", *topic)
-	}
-	log.Printf("Success Message Sent to SNS Topic %s: %s\n", *topic, *messageID)
-	return &message, nil
+	api.WriteAPIResponse(w, http.StatusCreated, newLease)
 }
 
 // getBeginningOfCurrentBillingPeriod returns starts of the billing period based on budget period
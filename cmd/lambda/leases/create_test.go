@@ -3,205 +3,242 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
-	"reflect"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/Optum/dce/pkg/usage"
-	util "github.com/Optum/dce/tests/testutils"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
-
-	"github.com/stretchr/testify/mock"
-
+	"github.com/Optum/dce/pkg/api"
+	apiMocks "github.com/Optum/dce/pkg/api/mocks"
 	"github.com/Optum/dce/pkg/api/response"
-	"github.com/Optum/dce/pkg/common"
-	commonMock "github.com/Optum/dce/pkg/common/mocks"
-	"github.com/Optum/dce/pkg/db"
-	mockDB "github.com/Optum/dce/pkg/db/mocks"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/email"
+	emailMocks "github.com/Optum/dce/pkg/email/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/Optum/dce/pkg/principal"
+	mockPrincipal "github.com/Optum/dce/pkg/principal/principaliface/mocks"
+	"github.com/Optum/dce/pkg/team"
+	mockTeam "github.com/Optum/dce/pkg/team/teamiface/mocks"
+	"github.com/Optum/dce/pkg/usage"
 	mockUsage "github.com/Optum/dce/pkg/usage/mocks"
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreateController_Call(t *testing.T) {
 
-	t.Run("should create leases", func(t *testing.T) {
-		type (
-			fields struct {
-				Dao                   db.DBer
-				SNS                   common.Notificationer
-				LeaseTopicARN         *string
-				UsageSvc              usage.DBer
-				PrincipalBudgetAmount *float64
-				PrincipalBudgetPeriod *string
-				MaxLeaseBudgetAmount  *float64
-				MaxLeasePeriod        *int64
-			}
-		)
-		type args struct {
-			ctx context.Context
-			req *events.APIGatewayProxyRequest
-		}
+	amount := 1000.00
+	period := "WEEKLY"
+	leasePeriod := int64(704800)
 
-		leaseTopicARN := "some-topic-arn"
-		messageID := "message123456789"
+	principalBudgetAmount = amount
+	principalBudgetPeriod = period
+	maxLeaseBudgetAmount = amount
+	maxLeasePeriod = leasePeriod
 
-		amount := 1000.00
-		period := "WEEKLY"
-		leasePeriod := int64(704800)
+	usageMock := &mockUsage.DBer{}
+	usageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return(nil, nil)
+	usageSvc = usageMock
 
-		principalBudgetAmount = amount
-		principalBudgetPeriod = period
-		maxLeaseBudgetAmount = amount
-		maxLeasePeriod = leasePeriod
-
-		dbMock := stubDb()
-		snsMock := &commonMock.Notificationer{}
-		usageMock := &mockUsage.DBer{}
-		sevenDaysOut := time.Now().AddDate(0, 0, 7).Unix()
-
-		// Should create/update the lease record
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease", mock.MatchedBy(func(lease db.Lease) bool {
-			assert.Equal(t, "123456789012", lease.AccountID)
-			assert.Equal(t, "jdoe123", lease.PrincipalID)
-			assert.IsType(t, "string", lease.ID)
-			assert.Equal(t, db.Active, lease.LeaseStatus)
-			assert.Equal(t, db.LeaseActive, lease.LeaseStatusReason)
-			assert.Equal(t, float64(50), lease.BudgetAmount)
-			assert.Equal(t, "USD", lease.BudgetCurrency)
-			assert.Equal(t, []string{"user3@example.com", "user2@example.com"}, lease.BudgetNotificationEmails)
-			assert.Equal(t, sevenDaysOut, lease.ExpiresOn)
-
-			assert.InDelta(t, time.Now().Unix(), lease.CreatedOn, 2)
-			assert.Equal(t, lease.CreatedOn, lease.LastModifiedOn)
-			assert.Equal(t, lease.CreatedOn, lease.LeaseStatusModifiedOn)
-
-			return true
-		})).
-			Return(&db.Lease{}, nil)
-
-		// Should publish SNS message
-		snsMock.On("PublishMessage", &leaseTopicARN, mock.Anything, true).Return(&messageID, nil)
-		usageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return(nil, nil)
-
-		testFields := &fields{
-			Dao:                   dbMock,
-			SNS:                   snsMock,
-			LeaseTopicARN:         &leaseTopicARN,
-			UsageSvc:              usageMock,
-			PrincipalBudgetAmount: &principalBudgetAmount,
-			PrincipalBudgetPeriod: &principalBudgetPeriod,
-			MaxLeaseBudgetAmount:  &maxLeaseBudgetAmount,
-			MaxLeasePeriod:        &maxLeasePeriod,
-		}
-
-		successResponse := createSuccessCreateResponse()
-		badRequestResponse := response.CreateMultiValueHeaderAPIErrorResponse(http.StatusBadRequest, "RequestValidationError", "invalid request parameters")
-		pastRequestResponse := response.RequestValidationError("Requested lease has a desired expiry date less than today: 1570627876")
-		invalidBudgetRequestResponse := response.RequestValidationError("Requested lease has a budget amount of 5000.000000, which is greater than max lease budget amount of 1000.000000")
-		invalidBudgetPeriodRequestResponse := response.RequestValidationError("Requested lease has a budget expires on of 1577745392, which is greater than max lease period of 1573176192")
-
-		successArgs := &args{ctx: context.Background(), req: createSuccessfulCreateRequest()}
-		pastArgs := &args{ctx: context.Background(), req: createPastCreateRequest()}
-		invalidBudgetArgs := &args{ctx: context.Background(), req: invalidBudgetAmountCreateRequest()}
-		invalidBudgetPeriodArgs := &args{ctx: context.Background(), req: invalidBudgetPeriodCreateRequest()}
-		badArgs := &args{ctx: context.Background(), req: createBadCreateRequest()}
+	t.Run("should validate lease requests", func(t *testing.T) {
+		stubLeaseService(t)
 
 		tests := []struct {
-			name    string
-			fields  fields
-			args    args
-			want    events.APIGatewayProxyResponse
-			wantErr bool
+			name string
+			req  *events.APIGatewayProxyRequest
+			want events.APIGatewayProxyResponse
 		}{
 			{
-				name:    "Bad request.",
-				fields:  *testFields,
-				args:    *badArgs,
-				want:    badRequestResponse,
-				wantErr: false,
+				name: "Bad request.",
+				req:  createBadCreateRequest(),
+				want: response.CreateMultiValueHeaderAPIErrorResponse(http.StatusBadRequest, "RequestValidationError", "invalid request parameters"),
 			},
 			{
-				name:    "Past request.",
-				fields:  *testFields,
-				args:    *pastArgs,
-				want:    pastRequestResponse,
-				wantErr: false,
-			},
-			{
-				name:    "Invalid budget amount request.",
-				fields:  *testFields,
-				args:    *invalidBudgetArgs,
-				want:    invalidBudgetRequestResponse,
-				wantErr: false,
+				name: "Past request.",
+				req:  createPastCreateRequest(),
+				want: response.RequestValidationError("Requested lease has a desired expiry date less than today: 1570627876"),
 			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, err := Handler(context.TODO(), *tt.req)
+				require.Nil(t, err)
+				assert.Equal(t, tt.want, got)
+			})
+		}
+	})
+
+	t.Run("should hold leases over the max lease budget or period for approval", func(t *testing.T) {
+		Settings.LeaseApprovalEmails = []string{"approver@example.com"}
+		Settings.LeaseApprovalFromEmail = "dce@example.com"
+		defer func() {
+			Settings.LeaseApprovalEmails = nil
+			Settings.LeaseApprovalFromEmail = ""
+		}()
+
+		tests := []struct {
+			name      string
+			req       *events.APIGatewayProxyRequest
+			expReason string
+		}{
 			{
-				name:    "Invalid budget period request.",
-				fields:  *testFields,
-				args:    *invalidBudgetPeriodArgs,
-				want:    invalidBudgetPeriodRequestResponse,
-				wantErr: false,
+				name:      "over the max lease budget amount",
+				req:       overBudgetAmountCreateRequest(),
+				expReason: "Requested lease has a budget amount of 5000.000000, which is greater than max lease budget amount of 1000.000000",
 			},
 			{
-				name: "Successful create.",
-				fields: fields{
-					Dao:                   dbMock,
-					SNS:                   snsMock,
-					LeaseTopicARN:         &leaseTopicARN,
-					UsageSvc:              usageMock,
-					PrincipalBudgetAmount: aws.Float64(9999999999),
-					PrincipalBudgetPeriod: &principalBudgetPeriod,
-					MaxLeaseBudgetAmount:  aws.Float64(9999999999),
-					MaxLeasePeriod:        aws.Int64(600000000),
-				},
-				args:    *successArgs,
-				want:    *successResponse,
-				wantErr: false,
+				name:      "over the max lease period",
+				req:       overLeasePeriodCreateRequest(),
+				expReason: "which is greater than max lease period of",
 			},
 		}
 		for _, tt := range tests {
 			t.Run(tt.name, func(t *testing.T) {
-				dao = tt.fields.Dao
-				snsSvc = tt.fields.SNS
-				leaseAddedTopicARN = *tt.fields.LeaseTopicARN
-				usageSvc = tt.fields.UsageSvc
-				principalBudgetAmount = *tt.fields.PrincipalBudgetAmount
-				principalBudgetPeriod = *tt.fields.PrincipalBudgetPeriod
-				maxLeaseBudgetAmount = *tt.fields.MaxLeaseBudgetAmount
-				maxLeasePeriod = *tt.fields.MaxLeasePeriod
-				got, err := Handler(tt.args.ctx, *tt.args.req)
-				if (err != nil) != tt.wantErr {
-					t.Errorf("CreateController.Call() error = %v, wantErr %v", err, tt.wantErr)
-					return
-				}
-				if !reflect.DeepEqual(got, tt.want) {
-
-					//comparing first 50 characters of error message for invalid budget period error
-					if strings.HasPrefix(tt.want.Body, got.Body[:30]) {
-						return
-					}
-					t.Errorf("CreateController.Call() = %v, want %v", got, tt.want)
-				}
+				leaseSvc := stubLeaseService(t)
+				mockEmail := &emailMocks.Service{}
+				mockEmail.On("SendEmail", mock.AnythingOfType("*email.SendEmailInput")).Return(nil)
+				emailSvc = mockEmail
+
+				res, err := Handler(context.TODO(), *tt.req)
+				require.Nil(t, err)
+				require.Equal(t, http.StatusAccepted, res.StatusCode)
+
+				leaseSvc.AssertCalled(t, "RequestApproval", mock.MatchedBy(func(req *lease.Lease) bool {
+					return *req.PrincipalID == "123456"
+				}))
+				leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+				mockEmail.AssertCalled(t, "SendEmail", mock.MatchedBy(func(input *email.SendEmailInput) bool {
+					return input.FromAddress == "dce@example.com" &&
+						assert.ObjectsAreEqual([]string{"approver@example.com"}, input.ToAddresses) &&
+						strings.Contains(input.BodyText, tt.expReason) &&
+						strings.Contains(input.BodyText, "/leases/70c2d96d-7938-4ec9-917d-476f2b09cc04/approve")
+				}))
+
+				resJSON := unmarshal(t, res.Body)
+				require.Equal(t, "Pending", resJSON["leaseStatus"])
+				require.Equal(t, "PendingApproval", resJSON["leaseStatusReason"])
 			})
 		}
+
+		t.Run("should hold the lease when the approvers can't be emailed", func(t *testing.T) {
+			stubLeaseService(t)
+			mockEmail := &emailMocks.Service{}
+			mockEmail.On("SendEmail", mock.Anything).Return(fmt.Errorf("ses failure"))
+			emailSvc = mockEmail
+
+			res, err := Handler(context.TODO(), *overBudgetAmountCreateRequest())
+			require.Nil(t, err)
+			require.Equal(t, http.StatusAccepted, res.StatusCode)
+		})
+	})
+
+	t.Run("should create leases", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+		sevenDaysOut := time.Now().AddDate(0, 0, 7).Unix()
+
+		res, err := Handler(context.TODO(), *createSuccessfulCreateRequest())
+		require.Nil(t, err)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+
+		// Should create the lease with the requested values
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return *req.PrincipalID == "jdoe123" &&
+				*req.BudgetAmount == float64(50) &&
+				*req.BudgetCurrency == "USD" &&
+				assert.ObjectsAreEqual([]string{"user3@example.com", "user2@example.com"}, *req.BudgetNotificationEmails) &&
+				*req.ExpiresOn == sevenDaysOut &&
+				req.AccountID == nil &&
+				req.Status == nil
+		}))
+
+		// Should respond with the created lease
+		resJSON := unmarshal(t, res.Body)
+		require.Equal(t, "123456789012", resJSON["accountId"])
+		require.Equal(t, "jdoe123", resJSON["principalId"])
+		require.Equal(t, "70c2d96d-7938-4ec9-917d-476f2b09cc04", resJSON["id"])
+		require.Equal(t, "Active", resJSON["leaseStatus"])
+		require.Equal(t, "Active", resJSON["leaseStatusReason"])
+	})
+
+	t.Run("should apply the limits of the principal's profile", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+
+		// jbigspender's profile allows budgets up to 10000
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jbigspender",
+			"budgetAmount":   5000,
+			"budgetCurrency": "USD",
+			"expiresOn":      time.Now().AddDate(0, 0, 5).Unix(),
+		}))
+		require.Nil(t, err)
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return *req.PrincipalID == "jbigspender" && *req.BudgetAmount == float64(5000)
+		}))
+
+		// Principals without a profile need approval over the default limits
+		res, err = Handler(context.TODO(), *overBudgetAmountCreateRequest())
+		require.Nil(t, err)
+		require.Equal(t, http.StatusAccepted, res.StatusCode)
+		leaseSvc.AssertCalled(t, "RequestApproval", mock.MatchedBy(func(req *lease.Lease) bool {
+			return *req.PrincipalID == "123456" && *req.BudgetAmount == float64(5000)
+		}))
 	})
 
-	t.Run("should fail if the principal already has a lease", func(t *testing.T) {
-		// Mock active lease for the principal
-		dbMock := stubDb()
-		util.ReplaceMock(&dbMock.Mock, "FindLeasesByPrincipal", "jdoe123").
-			Return([]*db.Lease{{
-				AccountID:   "123456789012",
-				LeaseStatus: db.Active,
-			}}, nil)
+	t.Run("should fail if the principal's profile can't be read", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jbroken",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+			"expiresOn":      time.Now().AddDate(0, 0, 5).Unix(),
+		}))
+		require.Nil(t, err)
+		require.Equal(t, http.StatusInternalServerError, res.StatusCode)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("should not validate leases for other principals", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+
+		// Validation would report the principal's spend
+		spent := 5000.0
+		otherUsageMock := &mockUsage.DBer{}
+		otherUsageMock.On("GetUsageByPrincipal", mock.Anything, mock.Anything).Return([]*usage.Usage{
+			{CostAmount: &spent},
+		}, nil)
+		usageSvc = otherUsageMock
+		defer func() { usageSvc = usageMock }()
+
+		users := &apiMocks.UserDetailer{}
+		users.On("GetUser", mock.Anything).Return(&api.User{Username: "other", Role: api.UserGroupName})
+		prevUserDetails := userDetails
+		userDetails = users
+		defer func() { userDetails = prevUserDetails }()
+
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t, http.StatusForbidden, res.StatusCode)
+		require.NotContains(t, res.Body, "5000")
+		otherUsageMock.AssertNotCalled(t, "GetUsageByPrincipal", mock.Anything, mock.Anything)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
 
-		// Create the controller
-		dao = dbMock
+	t.Run("should fail if the principal has the max active leases", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("Create", mock.Anything).Return(nil,
+			errors.NewConflict("lease", "jdoe123", fmt.Errorf("principal already has 1 active leases, which is the max of 1 active leases per principal")))
+		setLeaseService(t, leaseSvc)
 
 		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -213,82 +250,168 @@ func TestCreateController_Call(t *testing.T) {
 		require.Nil(t, err)
 		// Check HTTP error response
 		require.Equal(t,
-			response.CreateMultiValueHeaderAPIErrorResponse(http.StatusConflict, "ClientError", "Principal already has an active lease for account 123456789012"),
+			MockAPIErrorResponse(http.StatusConflict, "{\"error\":{\"message\":\"operation cannot be fulfilled on lease \\\"jdoe123\\\": principal already has 1 active leases, which is the max of 1 active leases per principal\",\"code\":\"ConflictError\"}}\n"),
 			res,
 		)
 	})
 
-	t.Run("should mark the account.Status=Leased", func(t *testing.T) {
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
+	t.Run("should fail if there are no available accounts", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("Create", mock.Anything).Return(nil,
+			errors.NewServiceUnavailable("No Available accounts at this moment"))
+		setLeaseService(t, leaseSvc)
 
-		// Return a ready account
-		util.ReplaceMock(&dbMock.Mock, "GetReadyAccount").
-			Return(&db.Account{ID: "123456789012"}, nil)
+		// Call the controller
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			MockAPIErrorResponse(http.StatusServiceUnavailable, "{\"error\":{\"message\":\"No Available accounts at this moment\",\"code\":\"ServerError\"}}\n"),
+			res,
+		)
+	})
 
-		// Should set account.Status=Leased
-		util.ReplaceMock(&dbMock.Mock, "TransitionAccountStatus",
-			"123456789012", db.Ready, db.Leased,
-		).Return(&db.Account{}, nil)
+	t.Run("should accept leases put on the waitlist", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("Create", mock.Anything).Return(&lease.Lease{
+			ID:           ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+			PrincipalID:  ptrString("jdoe123"),
+			Status:       lease.StatusPending.StatusPtr(),
+			StatusReason: lease.StatusReasonPendingAccount.StatusReasonPtr(),
+		}, nil)
+		setLeaseService(t, leaseSvc)
 
 		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
-			"expiresOn":      time.Now().AddDate(0, 0, 7).Unix(),
 		}))
 		require.Nil(t, err)
-		require.Equal(t, 201, res.StatusCode)
+		require.Equal(t, http.StatusAccepted, res.StatusCode)
 
-		dbMock.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+		resJSON := unmarshal(t, res.Body)
+		require.Nil(t, resJSON["accountId"])
+		require.Equal(t, "Pending", resJSON["leaseStatus"])
+		require.Equal(t, "PendingAccount", resJSON["leaseStatusReason"])
+	})
+
+	t.Run("should fail if the lease can't be created", func(t *testing.T) {
+		leaseSvc := &mocks.Servicer{}
+		leaseSvc.On("Create", mock.Anything).Return(nil,
+			errors.NewInternalServer("failure", fmt.Errorf("original failure")))
+		setLeaseService(t, leaseSvc)
+
+		// Call the controller
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			MockAPIErrorResponse(http.StatusInternalServerError, "{\"error\":{\"message\":\"failure\",\"code\":\"ServerError\"}}\n"),
+			res,
+		)
 	})
 
 	t.Run("should set default expiresOn", func(t *testing.T) {
-		dbMock := stubDb()
+		leaseSvc := stubLeaseService(t)
 
-		// Should set expiresOn to 7 days from now
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease", mock.MatchedBy(func(lease db.Lease) bool {
-			assert.InDelta(t, time.Now().Add(7*time.Hour*24).Unix(), lease.ExpiresOn, 2)
+		// Call the controller
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 201, res.StatusCode)
 
-			return true
-		})).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		// Should set expiresOn to 7 days from now
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return assert.InDelta(t, time.Now().Add(7*time.Hour*24).Unix(), *req.ExpiresOn, 2)
+		}))
+		resJSON := unmarshal(t, res.Body)
+		require.InDelta(t, time.Now().Add(7*time.Hour*24).Unix(), resJSON["expiresOn"], 2)
+	})
 
-		dao = dbMock
+	t.Run("should schedule leases with a start date", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+		startsOn := time.Now().AddDate(0, 0, 2).Unix()
 
 		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
+			"startsOn":       startsOn,
 		}))
 		require.Nil(t, err)
+		require.Equal(t, 201, res.StatusCode)
+
+		// Should set expiresOn to 7 days from the start date
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return *req.StartsOn == startsOn &&
+				*req.ExpiresOn == time.Unix(startsOn, 0).AddDate(0, 0, 7).Unix()
+		}))
+	})
+
+	t.Run("should not set startsOn for leases starting now", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
 
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+		}))
+		require.Nil(t, err)
 		require.Equal(t, 201, res.StatusCode)
-		resJSON := unmarshal(t, res.Body)
-		require.InDelta(t, time.Now().Add(7*time.Hour*24).Unix(), resJSON["expiresOn"], 2)
+
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return req.StartsOn == nil
+		}))
+	})
+
+	t.Run("should fail if the start date is in the past", func(t *testing.T) {
+		stubLeaseService(t)
+
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+			"startsOn":       1570627876,
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			response.RequestValidationError("Requested lease has a desired start date less than today: 1570627876"),
+			res,
+		)
+	})
+
+	t.Run("should fail if the lease expires before it starts", func(t *testing.T) {
+		stubLeaseService(t)
+		startsOn := time.Now().AddDate(0, 0, 7).Unix()
+		expiresOn := time.Now().AddDate(0, 0, 2).Unix()
+
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "jdoe123",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+			"startsOn":       startsOn,
+			"expiresOn":      expiresOn,
+		}))
+		require.Nil(t, err)
+		require.Equal(t,
+			response.RequestValidationError(fmt.Sprintf("Requested lease has a desired expiry date of %d, which is not after its start date of %d", expiresOn, startsOn)),
+			res,
+		)
 	})
 
 	t.Run("should create lease with metadata", func(t *testing.T) {
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Should put lease metadata to DB
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease",
-			mock.MatchedBy(func(lease db.Lease) bool {
-				assert.Equal(t, map[string]interface{}{
-					"foo": "bar",
-					"faz": "baz",
-				}, lease.Metadata)
-				return true
-			}),
-		).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		leaseSvc := stubLeaseService(t)
 
 		// Call the controller with some metadata
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -303,6 +426,14 @@ func TestCreateController_Call(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, 201, res.StatusCode)
 
+		// Should pass lease metadata to the lease service
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return assert.ObjectsAreEqual(map[string]interface{}{
+				"foo": "bar",
+				"faz": "baz",
+			}, req.Metadata)
+		}))
+
 		// Check that controller responded with the metadata
 		resJSON := unmarshal(t, res.Body)
 		require.Contains(t, resJSON, "metadata")
@@ -310,36 +441,10 @@ func TestCreateController_Call(t *testing.T) {
 			"foo": "bar",
 			"faz": "baz",
 		}, resJSON["metadata"])
-
-		// Verify our DB call
-		dbMock.AssertExpectations(t)
 	})
 
 	t.Run("should allow complex types in metadata", func(t *testing.T) {
-		// Setup the controller
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Should put lease metadata to DB
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease",
-			mock.MatchedBy(func(lease db.Lease) bool {
-				assert.Equal(t, map[string]interface{}{
-					"foo": map[string]interface{}{
-						"bar": map[string]interface{}{
-							"faz":   "baz",
-							"int":   float64(5),
-							"float": 1.2345,
-							"bool":  true,
-							"nil":   nil,
-						},
-					},
-				}, lease.Metadata)
-				return true
-			}),
-		).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		leaseSvc := stubLeaseService(t)
 
 		// Call the controller with some metadata
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -361,10 +466,7 @@ func TestCreateController_Call(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, 201, res.StatusCode)
 
-		// Check that controller responded with the metadata
-		resJSON := unmarshal(t, res.Body)
-		require.Contains(t, resJSON, "metadata")
-		require.Equal(t, map[string]interface{}{
+		expectedMetadata := map[string]interface{}{
 			"foo": map[string]interface{}{
 				"bar": map[string]interface{}{
 					"faz":   "baz",
@@ -374,28 +476,21 @@ func TestCreateController_Call(t *testing.T) {
 					"nil":   nil,
 				},
 			},
-		}, resJSON["metadata"])
+		}
+
+		// Should pass lease metadata to the lease service
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return assert.ObjectsAreEqual(expectedMetadata, req.Metadata)
+		}))
 
-		// Verify our DB call
-		dbMock.AssertExpectations(t)
+		// Check that controller responded with the metadata
+		resJSON := unmarshal(t, res.Body)
+		require.Contains(t, resJSON, "metadata")
+		require.Equal(t, expectedMetadata, resJSON["metadata"])
 	})
 
 	t.Run("should default to an empty metadata object", func(t *testing.T) {
-		// Setup the controller
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Should put lease metadata to DB
-		util.ReplaceMock(&dbMock.Mock, "UpsertLease",
-			mock.MatchedBy(func(lease db.Lease) bool {
-				// Should save empty metadata to DB
-				assert.Equal(t, map[string]interface{}{}, lease.Metadata)
-				return true
-			}),
-		).Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+		leaseSvc := stubLeaseService(t)
 
 		// Call the controller with no metadata
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
@@ -406,143 +501,116 @@ func TestCreateController_Call(t *testing.T) {
 		require.Nil(t, err)
 		require.Equal(t, 201, res.StatusCode)
 
-		// Check that controller responded with empty metadata
-		resJSON := unmarshal(t, res.Body)
-		require.Contains(t, resJSON, "metadata")
-		require.Equal(t, map[string]interface{}{}, resJSON["metadata"])
-
-		// Verify our DB call
-		dbMock.AssertExpectations(t)
+		// Should pass empty metadata to the lease service
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return assert.ObjectsAreEqual(map[string]interface{}{}, req.Metadata)
+		}))
 	})
 
-	t.Run("should not allow non-object types for metadata", func(t *testing.T) {
+	t.Run("should pass account selector hints to the lease service", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
 
-		// Metadata must be a JSON object
-		invalidMetadatas := []interface{}{
-			"string",
-			14.5,
-			true,
-		}
-
-		for _, metadata := range invalidMetadatas {
-			// Call the controller with invalid metadata values
-			res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
-				"principalId":    "pid",
-				"budgetAmount":   100,
-				"budgetCurrency": "USD",
-				"metadata":       metadata,
-			}))
-			require.Nil(t, err)
+		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+			"principalId":    "pid",
+			"budgetAmount":   100,
+			"budgetCurrency": "USD",
+			"accountSelector": map[string]interface{}{
+				"metadata.accountTier": "gpu",
+			},
+		}))
+		require.Nil(t, err)
+		require.Equal(t, 201, res.StatusCode)
 
-			// Check HTTP error response
-			require.Equalf(t,
-				response.RequestValidationError("invalid request parameters"),
-				res,
-				"should fail for metadata: %s", metadata,
-			)
-		}
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return assert.ObjectsAreEqual(map[string]string{
+				"metadata.accountTier": "gpu",
+			}, req.AccountSelector)
+		}))
 	})
 
-	t.Run("should deactivate the lease if the account status update fails", func(t *testing.T) {
-		// Setup the controller
-		dbMock := stubDb()
-		dao = dbMock
-
-		// Mock account status update to fail
-		util.ReplaceMock(&dbMock.Mock, "TransitionAccountStatus",
-			mock.Anything, mock.Anything, mock.Anything,
-		).Return(nil, errors.New("test error"))
-
-		// Should deactivate the lease
-		util.ReplaceMock(&dbMock.Mock, "TransitionLeaseStatus",
-			"123456789012", "jdoe123",
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		).Return(&db.Lease{}, nil)
+	t.Run("should create leases on a team", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+		stubTeamService(t)
 
-		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
+			"teamId":         "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e",
 		}))
 		require.Nil(t, err)
+		require.Equal(t, 201, res.StatusCode)
 
-		// Should return a 500 error
-		require.Equal(t, response.CreateMultiValueHeaderAPIErrorResponse(http.StatusInternalServerError, "ServerError", "Internal server error"), res)
-
-		// Should have deactivated lease
-		dbMock.AssertNumberOfCalls(t, "TransitionLeaseStatus", 1)
+		leaseSvc.AssertCalled(t, "Create", mock.MatchedBy(func(req *lease.Lease) bool {
+			return *req.TeamID == "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"
+		}))
+		resJSON := unmarshal(t, res.Body)
+		require.Equal(t, "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e", resJSON["teamId"])
 	})
 
-	t.Run("should deactivate the lease and update the account status if the SNS publish fails", func(t *testing.T) {
-		// Mock SNS to fail
-		snsMock := &commonMock.Notificationer{}
-		snsMock.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).
-			Return(nil, errors.New("test error"))
+	t.Run("should fail if the principal isn't on the team", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+		stubTeamService(t)
 
-		dbMock := stubDb()
-		snsSvc = snsMock
-		dao = dbMock
-
-		// Should deactivate the lease
-		util.ReplaceMock(&dbMock.Mock, "TransitionLeaseStatus",
-			"123456789012", "jdoe123",
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		).Return(&db.Lease{}, nil)
-
-		// Should mark Account.Status=Ready
-		dbMock.On("TransitionAccountStatus",
-			"123456789012", db.Leased, db.Ready,
-		).Return(&db.Account{}, nil)
-
-		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
-			"principalId":    "jdoe123",
+			"principalId":    "other",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
+			"teamId":         "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e",
 		}))
 		require.Nil(t, err)
-
-		// Should return a 500 error
-		require.Equal(t, response.CreateMultiValueHeaderAPIErrorResponse(http.StatusInternalServerError, "ServerError", "Internal server error"), res)
-
-		// Should have deactivated lease
-		dbMock.AssertNumberOfCalls(t, "TransitionLeaseStatus", 1)
-		dbMock.AssertNumberOfCalls(t, "TransitionAccountStatus", 2)
+		require.Equal(t,
+			response.RequestValidationError("Unable to create lease: User principal other is not a member of team 4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+			res,
+		)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
-	// ...otherwise, we'd end up with a Lease=Active, Account=Ready state.
-	t.Run("should not rollback accounts status on SNS rollback, if the lease deactivation fails", func(t *testing.T) {
-		// Mock SNS to fail
-		snsMock := &commonMock.Notificationer{}
-		snsMock.On("PublishMessage", mock.Anything, mock.Anything, mock.Anything).
-			Return(nil, errors.New("test error"))
-
-		dbMock := stubDb()
-		snsSvc = snsMock
-		dao = dbMock
+	t.Run("should fail if the team doesn't exist", func(t *testing.T) {
+		leaseSvc := stubLeaseService(t)
+		stubTeamService(t)
 
-		// Should deactivate the lease (FAILS)
-		util.ReplaceMock(&dbMock.Mock, "TransitionLeaseStatus",
-			"123456789012", "jdoe123",
-			db.Active, db.Inactive, db.LeaseRolledBack,
-		).Return(nil, errors.New("test error"))
-
-		// Call the controller
 		res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
 			"principalId":    "jdoe123",
 			"budgetAmount":   100,
 			"budgetCurrency": "USD",
+			"teamId":         "missing",
 		}))
 		require.Nil(t, err)
+		require.Equal(t,
+			response.RequestValidationError("Requested lease has a team missing, which does not exist"),
+			res,
+		)
+		leaseSvc.AssertNotCalled(t, "Create", mock.Anything)
+	})
 
-		// Should return a 500 error
-		require.Equal(t, response.CreateMultiValueHeaderAPIErrorResponse(http.StatusInternalServerError, "ServerError", "Internal server error"), res)
+	t.Run("should not allow non-object types for metadata", func(t *testing.T) {
+		stubLeaseService(t)
 
-		// Should have deactivated lease
-		dbMock.AssertNumberOfCalls(t, "TransitionLeaseStatus", 1)
-		// Should only call this once, for the original Account Status=Leased
-		dbMock.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+		// Metadata must be a JSON object
+		invalidMetadatas := []interface{}{
+			"string",
+			14.5,
+			true,
+		}
+
+		for _, metadata := range invalidMetadatas {
+			// Call the controller with invalid metadata values
+			res, err := Handler(context.TODO(), *apiGatewayRequest(t, map[string]interface{}{
+				"principalId":    "pid",
+				"budgetAmount":   100,
+				"budgetCurrency": "USD",
+				"metadata":       metadata,
+			}))
+			require.Nil(t, err)
+
+			// Check HTTP error response
+			require.Equalf(t,
+				response.RequestValidationError("invalid request parameters"),
+				res,
+				"should fail for metadata: %s", metadata,
+			)
+		}
 	})
 
 }
@@ -598,13 +666,6 @@ func createPastCreateRequest() *events.APIGatewayProxyRequest {
 	}
 }
 
-func createSuccessCreateResponse() *events.APIGatewayProxyResponse {
-	return &events.APIGatewayProxyResponse{
-		StatusCode: 201,
-		Body:       "{\"accountId\":\"\",\"principalId\":\"\",\"id\":\"\",\"leaseStatus\":\"\",\"leaseStatusReason\":\"\",\"createdOn\":0,\"lastModifiedOn\":0,\"budgetAmount\":0,\"budgetCurrency\":\"\",\"budgetNotificationEmails\":null,\"leaseStatusModifiedOn\":0,\"expiresOn\":0,\"metadata\":{}}",
-	}
-}
-
 func unmarshal(t *testing.T, jsonStr string) map[string]interface{} {
 	var data map[string]interface{}
 	err := json.Unmarshal([]byte(jsonStr), &data)
@@ -615,7 +676,7 @@ func unmarshal(t *testing.T, jsonStr string) map[string]interface{} {
 	return data
 }
 
-func invalidBudgetAmountCreateRequest() *events.APIGatewayProxyRequest {
+func overBudgetAmountCreateRequest() *events.APIGatewayProxyRequest {
 	createLeaseRequest := &createLeaseRequest{
 		PrincipalID:              "123456",
 		BudgetAmount:             5000,
@@ -631,7 +692,7 @@ func invalidBudgetAmountCreateRequest() *events.APIGatewayProxyRequest {
 	}
 }
 
-func invalidBudgetPeriodCreateRequest() *events.APIGatewayProxyRequest {
+func overLeasePeriodCreateRequest() *events.APIGatewayProxyRequest {
 	createLeaseRequest := &createLeaseRequest{
 		PrincipalID:              "123456",
 		BudgetAmount:             50,
@@ -647,28 +708,93 @@ func invalidBudgetPeriodCreateRequest() *events.APIGatewayProxyRequest {
 	}
 }
 
-// stubDb creates a mock DBer,
-// with stub/no-op mocks for each method used by the create lease controller
-func stubDb() *mockDB.DBer {
-	dbMock := &mockDB.DBer{}
+// stubLeaseService creates a mock lease Servicer which creates leases on
+// account 123456789012, and configures the controller to use it
+func stubLeaseService(t *testing.T) *mocks.Servicer {
+	leaseSvc := &mocks.Servicer{}
+
+	// Return the requested lease with a newly claimed account
+	leaseSvc.On("Create", mock.AnythingOfType("*lease.Lease")).
+		Return(func(req *lease.Lease) *lease.Lease {
+			now := time.Now().Unix()
+			return &lease.Lease{
+				ID:                       ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:                ptrString("123456789012"),
+				PrincipalID:              req.PrincipalID,
+				Status:                   lease.StatusActive.StatusPtr(),
+				StatusReason:             lease.StatusReasonActive.StatusReasonPtr(),
+				BudgetAmount:             req.BudgetAmount,
+				BudgetCurrency:           req.BudgetCurrency,
+				BudgetNotificationEmails: req.BudgetNotificationEmails,
+				ExpiresOn:                req.ExpiresOn,
+				Metadata:                 req.Metadata,
+				TeamID:                   req.TeamID,
+				CreatedOn:                &now,
+				LastModifiedOn:           &now,
+				StatusModifiedOn:         &now,
+			}
+		}, nil)
 
-	// Return a ready account
-	dbMock.On("GetReadyAccount").
-		Return(&db.Account{ID: "123456789012"}, nil)
+	// Return the requested lease, waiting for an approver
+	leaseSvc.On("RequestApproval", mock.AnythingOfType("*lease.Lease")).
+		Return(func(req *lease.Lease) *lease.Lease {
+			now := time.Now().Unix()
+			return &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				PrincipalID:    req.PrincipalID,
+				Status:         lease.StatusPending.StatusPtr(),
+				StatusReason:   lease.StatusReasonPendingApproval.StatusReasonPtr(),
+				BudgetAmount:   req.BudgetAmount,
+				BudgetCurrency: req.BudgetCurrency,
+				ExpiresOn:      req.ExpiresOn,
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+			}
+		}, nil)
 
-	// Requesting principal has no leases in the DB
-	dbMock.On("FindLeasesByPrincipal", mock.Anything).Return(nil, nil)
+	setLeaseService(t, leaseSvc)
+	return leaseSvc
+}
 
-	// Should upsert the lease DB record
-	dbMock.On("UpsertLease", mock.Anything).
-		// Return the same lease object that was passed to this method
-		Return(func(lease db.Lease) *db.Lease {
-			return &lease
-		}, nil)
+func setLeaseService(t *testing.T, leaseSvc *mocks.Servicer) {
+	cfgBldr := &config.ConfigurationBuilder{}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+	svcBldr.Config.WithService(leaseSvc)
+	svcBldr.Config.WithService(stubPrincipalService())
+	_, err := svcBldr.Build()
+	require.Nil(t, err)
+	Services = svcBldr
+}
 
-	// Transitions account to status=Leased
-	dbMock.On("TransitionAccountStatus", mock.Anything, mock.Anything, mock.Anything).
-		Return(&db.Account{}, nil)
+// stubPrincipalService creates a mock principal Servicer, where jbigspender's
+// profile raises their max lease budget amount to 10000, jbroken's profile
+// fails to be read, and other principals don't have a profile
+func stubPrincipalService() *mockPrincipal.Servicer {
+	principalSvc := &mockPrincipal.Servicer{}
+	principalSvc.On("Limits", "jbigspender", mock.AnythingOfType("principal.Limits")).Return(
+		func(ID string, defaults principal.Limits) *principal.Limits {
+			defaults.MaxLeaseBudgetAmount = 10000
+			return &defaults
+		}, nil)
+	principalSvc.On("Limits", "jbroken", mock.AnythingOfType("principal.Limits")).Return(
+		nil, errors.NewInternalServer("get failed for principal \"jbroken\"", nil))
+	principalSvc.On("Limits", mock.Anything, mock.AnythingOfType("principal.Limits")).Return(
+		func(ID string, defaults principal.Limits) *principal.Limits {
+			return &defaults
+		}, nil)
+	return principalSvc
+}
 
-	return dbMock
+// stubTeamService adds a mock team Servicer with a team jdoe123 is a member of
+func stubTeamService(t *testing.T) *mockTeam.Servicer {
+	teamSvc := &mockTeam.Servicer{}
+	teamSvc.On("Get", "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e").Return(&team.Team{
+		ID:      ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+		Name:    ptrString("platform"),
+		Members: []string{"jdoe123"},
+	}, nil)
+	teamSvc.On("Get", "missing").Return(nil, errors.NewNotFound("team", "missing"))
+
+	Services.Config.WithService(teamSvc)
+	return teamSvc
 }
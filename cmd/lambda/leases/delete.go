@@ -9,6 +9,7 @@ import (
 	"github.com/Optum/dce/pkg/api/response"
 	"github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/lease"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/gorilla/mux"
 )
 
@@ -17,6 +18,19 @@ func DeleteLeaseByID(w http.ResponseWriter, r *http.Request) {
 
 	leaseID := mux.Vars(r)["leaseID"]
 
+	// Only users who may end every lease can skip checking whose lease it is
+	if api.ScopeFor(r, api.PermissionLeasesWrite) != api.ScopeAll {
+		existing, err := Services.LeaseService().Get(leaseID)
+		if err != nil {
+			api.WriteAPIErrorResponse(w, err)
+			return
+		}
+		if !api.AllowedFor(r, api.PermissionLeasesWrite, aws.StringValue(existing.PrincipalID)) {
+			api.WriteAPIErrorResponse(w, errors.NewNotFound("lease", leaseID))
+			return
+		}
+	}
+
 	lease, err := Services.LeaseService().Delete(leaseID)
 
 	if err != nil {
@@ -52,6 +66,12 @@ func DeleteLease(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !api.AllowedFor(r, api.PermissionLeasesWrite, *queryLease.PrincipalID) {
+		api.WriteAPIErrorResponse(w,
+			errors.NewForbidden(fmt.Sprintf("user is not authorized to end leases for principal %s", *queryLease.PrincipalID)))
+		return
+	}
+
 	leases, err := Services.LeaseService().List(queryLease)
 	if err != nil {
 		api.WriteAPIErrorResponse(w, err)
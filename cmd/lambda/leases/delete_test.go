@@ -67,6 +67,7 @@ func TestDeleteLeaseByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := httptest.NewRequest("DELETE", fmt.Sprintf("http://example.com/lease/%s", tt.leaseID), nil)
+			r = withUser(r, adminUser)
 
 			r = mux.SetURLVars(r, map[string]string{
 				"leaseID": tt.leaseID,
@@ -229,6 +230,7 @@ func TestDeleteLease(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := httptest.NewRequest("DELETE", "http://example.com/leases", nil)
+			r = withUser(r, adminUser)
 
 			b := new(bytes.Buffer)
 			err := json.NewEncoder(b).Encode(tt.inputLease)
@@ -6,6 +6,8 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
 )
 
 // GetLeaseByID - Returns the single lease by ID
@@ -20,5 +22,11 @@ func GetLeaseByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Don't reveal leases the user isn't allowed to see
+	if !api.AllowedFor(r, api.PermissionLeasesRead, aws.StringValue(lease.PrincipalID)) {
+		api.WriteAPIErrorResponse(w, errors.NewNotFound("lease", leaseID))
+		return
+	}
+
 	api.WriteAPIResponse(w, http.StatusOK, lease)
 }
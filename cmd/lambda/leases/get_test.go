@@ -5,6 +5,7 @@ import (
 
 	gErrors "errors"
 	"fmt"
+	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
@@ -28,6 +29,7 @@ func TestGetLeaseByID(t *testing.T) {
 		name     string
 		expResp  response
 		leaseID  string
+		user     *api.User
 		retLease *lease.Lease
 		retErr   error
 	}{
@@ -51,11 +53,38 @@ func TestGetLeaseByID(t *testing.T) {
 			retLease: nil,
 			retErr:   fmt.Errorf("failure"),
 		},
+		{
+			name:    "When the lease belongs to the user",
+			leaseID: "abc123",
+			user:    &api.User{Username: "user1", Role: api.UserGroupName},
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"principalId\":\"user1\"}\n",
+			},
+			retLease: &lease.Lease{PrincipalID: ptrString("user1")},
+			retErr:   nil,
+		},
+		{
+			name:    "When the lease belongs to another principal",
+			leaseID: "abc123",
+			user:    &api.User{Username: "user1", Role: api.UserGroupName},
+			expResp: response{
+				StatusCode: 404,
+				Body:       "{\"error\":{\"message\":\"lease \\\"abc123\\\" not found\",\"code\":\"NotFoundError\"}}\n",
+			},
+			retLease: &lease.Lease{PrincipalID: ptrString("user2")},
+			retErr:   nil,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/lease/%s", tt.leaseID), nil)
+			user := adminUser
+			if tt.user != nil {
+				user = tt.user
+			}
+			r = withUser(r, user)
 
 			r = mux.SetURLVars(r, map[string]string{
 				"leaseID": tt.leaseID,
@@ -22,6 +22,27 @@ func GetLeases(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Leases are filtered by the principals the user may see in the query, so
+	// each page and its next link cover the same leases
+	switch api.ScopeFor(r, api.PermissionLeasesRead) {
+	case api.ScopeOwn:
+		query.PrincipalID = &api.UserFromContext(r.Context()).Username
+	case api.ScopeTeam:
+		if query.PrincipalID != nil {
+			if !api.AllowedFor(r, api.PermissionLeasesRead, *query.PrincipalID) {
+				api.WriteAPIResponse(w, http.StatusOK, lease.Leases{})
+				return
+			}
+			break
+		}
+		principalIDs, err := api.TeamMembersFor(r)
+		if err != nil {
+			api.WriteAPIErrorResponse(w, err)
+			return
+		}
+		query.PrincipalIDs = principalIDs
+	}
+
 	leases, err := Services.LeaseService().List(query)
 	if err != nil {
 		api.WriteAPIErrorResponse(w, err)
@@ -7,6 +7,8 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/Optum/dce/pkg/api"
+	apiMocks "github.com/Optum/dce/pkg/api/mocks"
 	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
@@ -77,6 +79,7 @@ func TestGetLeases(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			r := httptest.NewRequest("GET", "http://example.com/leases", nil)
+			r = withUser(r, adminUser)
 
 			baseRequest = url.URL{}
 			baseRequest.Scheme = "https"
@@ -129,3 +132,105 @@ func TestGetLeases(t *testing.T) {
 	}
 
 }
+
+func TestGetLeasesForTeamLead(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name            string
+		principalID     string
+		expPrincipalIDs []string
+		expList         bool
+		expResp         response
+		expLink         string
+	}{
+		{
+			name:            "should query the leases of the lead's teams",
+			expPrincipalIDs: []string{"lead", "user1"},
+			expList:         true,
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[{\"accountId\":\"123456789012\",\"principalId\":\"user1\"}]\n",
+			},
+			expLink: "<https://example.com/unit/leases?limit=1&nextAccountId=234567890123&nextPrincipalId=lead>; rel=\"next\"",
+		},
+		{
+			name:        "should query the leases of a member of the lead's teams",
+			principalID: "user1",
+			expList:     true,
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[{\"accountId\":\"123456789012\",\"principalId\":\"user1\"}]\n",
+			},
+			expLink: "<https://example.com/unit/leases?limit=1&nextAccountId=234567890123&nextPrincipalId=lead&principalId=user1>; rel=\"next\"",
+		},
+		{
+			name:        "should not query the leases of principals off the lead's teams",
+			principalID: "user2",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "[]\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://example.com/leases", nil)
+			if tt.principalID != "" {
+				r.URL.RawQuery = url.Values{"principalId": {tt.principalID}}.Encode()
+			}
+			r = withUser(r, &api.User{Username: "lead", Role: api.TeamLeadGroupName})
+
+			baseRequest = url.URL{}
+			baseRequest.Scheme = "https"
+			baseRequest.Host = "example.com"
+			baseRequest.Path = fmt.Sprintf("%s%s", "unit", "/leases")
+
+			teams := &apiMocks.TeamLister{}
+			teams.On("TeamMembers", "lead").Return([]string{"lead", "user1"}, nil)
+			api.DefaultAuthorizer.Teams = teams
+			defer func() { api.DefaultAuthorizer.Teams = nil }()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			leaseSvc := mocks.Servicer{}
+			leaseSvc.On("List", mock.MatchedBy(func(input *lease.Lease) bool {
+				if !assert.ObjectsAreEqual(tt.expPrincipalIDs, input.PrincipalIDs) {
+					return false
+				}
+				input.NextAccountID = ptrString("234567890123")
+				input.NextPrincipalID = ptrString("lead")
+				input.Limit = ptr64(1)
+				return true
+			})).Return(&lease.Leases{
+				{
+					AccountID:   ptrString("123456789012"),
+					PrincipalID: ptrString("user1"),
+				},
+			}, nil)
+			svcBldr.Config.WithService(&leaseSvc)
+			_, err := svcBldr.Build()
+			assert.Nil(t, err)
+			Services = svcBldr
+
+			w := httptest.NewRecorder()
+			GetLeases(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+			assert.Equal(t, tt.expLink, w.Header().Get("Link"))
+			if !tt.expList {
+				leaseSvc.AssertNotCalled(t, "List", mock.Anything)
+			}
+		})
+	}
+}
@@ -9,10 +9,13 @@ import (
 
 	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/currency"
 	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/email"
 	"github.com/Optum/dce/pkg/usage"
 	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ses"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -22,16 +25,18 @@ import (
 )
 
 type leaseControllerConfiguration struct {
-	Debug                    string  `env:"DEBUG" defaultEnv:"false"`
-	LeaseAddedTopicARN       string  `env:"LEASE_ADDED_TOPIC" defaultEnv:"DCEDefaultProvisionTopic"`
-	DecommissionTopicARN     string  `env:"DECOMMISSION_TOPIC" defaultEnv:"DefaultDecommissionTopicArn"`
-	CognitoUserPoolID        string  `env:"COGNITO_USER_POOL_ID" defaultEnv:"DefaultCognitoUserPoolId"`
-	CognitoAdminName         string  `env:"COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME" defaultEnv:"DefaultCognitoAdminName"`
-	PrincipalBudgetAmount    float64 `env:"PRINCIPAL_BUDGET_AMOUNT" defaultEnv:"1000.00"`
-	PrincipalBudgetPeriod    string  `env:"PRINCIPAL_BUDGET_PERIOD" defaultEnv:"Weekly"`
-	MaxLeaseBudgetAmount     float64 `env:"MAX_LEASE_BUDGET_AMOUNT" defaultEnv:"1000.00"`
-	MaxLeasePeriod           int64   `env:"MAX_LEASE_PERIOD" defaultEnv:"704800"`
-	DefaultLeaseLengthInDays int     `env:"DEFAULT_LEASE_LENGTH_IN_DAYS" defaultEnv:"7"`
+	Debug                    string   `env:"DEBUG" defaultEnv:"false"`
+	LeaseAddedTopicARN       string   `env:"LEASE_ADDED_TOPIC" defaultEnv:"DCEDefaultProvisionTopic"`
+	DecommissionTopicARN     string   `env:"DECOMMISSION_TOPIC" defaultEnv:"DefaultDecommissionTopicArn"`
+	CognitoUserPoolID        string   `env:"COGNITO_USER_POOL_ID" defaultEnv:"DefaultCognitoUserPoolId"`
+	CognitoAdminName         string   `env:"COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME" defaultEnv:"DefaultCognitoAdminName"`
+	PrincipalBudgetAmount    float64  `env:"PRINCIPAL_BUDGET_AMOUNT" defaultEnv:"1000.00"`
+	PrincipalBudgetPeriod    string   `env:"PRINCIPAL_BUDGET_PERIOD" defaultEnv:"Weekly"`
+	MaxLeaseBudgetAmount     float64  `env:"MAX_LEASE_BUDGET_AMOUNT" defaultEnv:"1000.00"`
+	MaxLeasePeriod           int64    `env:"MAX_LEASE_PERIOD" defaultEnv:"704800"`
+	DefaultLeaseLengthInDays int      `env:"DEFAULT_LEASE_LENGTH_IN_DAYS" defaultEnv:"7"`
+	LeaseApprovalEmails      []string `env:"LEASE_APPROVAL_EMAILS"`
+	LeaseApprovalFromEmail   string   `env:"LEASE_APPROVAL_FROM_EMAIL"`
 }
 
 const (
@@ -45,34 +50,29 @@ var (
 	Services *config.ServiceBuilder
 	// Settings - the configuration settings for the controller
 	Settings *leaseControllerConfiguration
+	// userDetails identifies the user making each request
+	userDetails api.UserDetailer
 )
 
 var (
 	// Soon to be deprecated - Legacy support
-	Config             common.DefaultEnvConfig
-	awsSession         *session.Session
-	dao                db.DBer
-	snsSvc             common.Notificationer
-	usageSvc           usage.DBer
-	leaseAddedTopicARN string
+	Config     common.DefaultEnvConfig
+	awsSession *session.Session
+	dao        db.DBer
+	usageSvc   usage.DBer
+	emailSvc   email.Service
+	// rateProvider converts usage into the currency of the principal budget
+	rateProvider currency.RateProvider
 	//decommissionTopicARN     string
 	principalBudgetAmount    float64
 	principalBudgetPeriod    string
+	principalBudgetCurrency  string
 	maxLeaseBudgetAmount     float64
 	maxLeasePeriod           int64
 	defaultLeaseLengthInDays int
 	baseRequest              url.URL
-	//cognitoUserPoolId        string
-	//cognitoAdminName         string
 )
 
-// messageBody is the structured object of the JSON Message to send
-// to an SNS Topic for lease creation/destruction
-type messageBody struct {
-	Default string `json:"default"`
-	Body    string `json:"Body"`
-}
-
 func init() {
 	initConfig()
 	log.Println("Cold start; creating router for /leases")
@@ -84,6 +84,7 @@ func init() {
 			"/leases",
 			api.EmptyQueryString,
 			GetLeases,
+			api.PermissionLeasesRead,
 		},
 		api.Route{
 			"GetLeaseByID",
@@ -91,6 +92,15 @@ func init() {
 			"/leases/{leaseID}",
 			api.EmptyQueryString,
 			GetLeaseByID,
+			api.PermissionLeasesRead,
+		},
+		api.Route{
+			"UpdateLeaseByID",
+			"PUT",
+			"/leases/{leaseID}",
+			api.EmptyQueryString,
+			UpdateLeaseByID,
+			api.PermissionLeasesWrite,
 		},
 		api.Route{
 			"DeleteLeaseByID",
@@ -98,6 +108,7 @@ func init() {
 			"/leases/{leaseID}",
 			api.EmptyQueryString,
 			DeleteLeaseByID,
+			api.PermissionLeasesWrite,
 		},
 		api.Route{
 			"DeleteLease",
@@ -105,6 +116,7 @@ func init() {
 			"/leases",
 			api.EmptyQueryString,
 			DeleteLease,
+			api.PermissionLeasesWrite,
 		},
 		api.Route{
 			"CreateLease",
@@ -112,6 +124,23 @@ func init() {
 			"/leases",
 			api.EmptyQueryString,
 			CreateLease,
+			api.PermissionLeasesWrite,
+		},
+		api.Route{
+			"ApproveLease",
+			"POST",
+			"/leases/{leaseID}/approve",
+			api.EmptyQueryString,
+			ApproveLease,
+			api.PermissionLeasesApprove,
+		},
+		api.Route{
+			"RejectLease",
+			"POST",
+			"/leases/{leaseID}/reject",
+			api.EmptyQueryString,
+			RejectLease,
+			api.PermissionLeasesApprove,
 		},
 	}
 	r := api.NewRouter(leasesRoutes)
@@ -136,19 +165,23 @@ func initConfig() {
 
 	_, err = svcBldr.
 		WithLeaseService().
+		WithTeamService().
+		WithPrincipalService().
+		WithUserDetailer().
 		Build()
 	if err != nil {
 		panic(err)
 	}
 
 	Services = svcBldr
+	userDetails = svcBldr.UserDetailer()
+	// Team leads may manage the leases of principals on the teams they lead
+	api.DefaultAuthorizer.Teams = svcBldr.TeamService()
 
-	leaseAddedTopicARN = Config.GetEnvVar("LEASE_ADDED_TOPIC", "DCEDefaultProvisionTopic")
 	//decommissionTopicARN = Config.GetEnvVar("DECOMMISSION_TOPIC", "DefaultDecommissionTopicArn")
-	//cognitoUserPoolId = Config.GetEnvVar("COGNITO_USER_POOL_ID", "DefaultCognitoUserPoolId")
-	//cognitoAdminName = Config.GetEnvVar("COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME", "DefaultCognitoAdminName")
 	principalBudgetAmount = Config.GetEnvFloatVar("PRINCIPAL_BUDGET_AMOUNT", 1000.00)
 	principalBudgetPeriod = Config.GetEnvVar("PRINCIPAL_BUDGET_PERIOD", Weekly)
+	principalBudgetCurrency = Config.GetEnvVar("PRINCIPAL_BUDGET_CURRENCY", currency.USD)
 	maxLeaseBudgetAmount = Config.GetEnvFloatVar("MAX_LEASE_BUDGET_AMOUNT", 1000.00)
 	maxLeasePeriod = int64(Config.GetEnvIntVar("MAX_LEASE_PERIOD", 704800))
 	defaultLeaseLengthInDays = Config.GetEnvIntVar("DEFAULT_LEASE_LENGTH_IN_DAYS", 7)
@@ -156,18 +189,15 @@ func initConfig() {
 
 // Handler - Handle the lambda function
 func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// If no name is provided in the HTTP request body, throw an error
-	// requestUser := userDetails.GetUser(&req)
-	// ctxWithUser := context.WithValue(ctx, api.DceCtxKey, *requestUser)
-	// return muxLambda.ProxyWithContext(ctxWithUser, req)
-
 	// Set baseRequest information lost by integration with gorilla mux
 	baseRequest = url.URL{}
 	baseRequest.Scheme = req.Headers["X-Forwarded-Proto"]
 	baseRequest.Host = req.Headers["Host"]
 	baseRequest.Path = fmt.Sprintf("%s%s", req.RequestContext.Stage, req.Path)
 
-	return muxLambda.ProxyWithContext(ctx, req)
+	// Routes are authorized for the user making the request
+	ctxWithUser := api.WithUser(ctx, userDetails.GetUser(&req))
+	return muxLambda.ProxyWithContext(ctxWithUser, req)
 }
 
 func main() {
@@ -175,7 +205,6 @@ func main() {
 	awsSession = newAWSSession()
 	// Create the Database Service from the environment
 	dao = newDBer()
-	snsSvc = &common.SNS{Client: sns.New(awsSession)}
 
 	usageService, err := usage.NewFromEnv()
 	if err != nil {
@@ -184,6 +213,12 @@ func main() {
 	}
 
 	usageSvc = usageService
+	emailSvc = &email.SESEmailService{SES: ses.New(awsSession)}
+
+	rateProvider, err = newRateProvider(awsSession)
+	if err != nil {
+		log.Fatalf("Failed to load exchange rates: %s", err)
+	}
 
 	lambda.Start(Handler)
 }
@@ -198,6 +233,20 @@ func newDBer() db.DBer {
 	return dao
 }
 
+// newRateProvider loads the exchange rates for converting usage into the principal budget currency.
+// Without an exchange rates file, only USD budgets are supported
+func newRateProvider(awsSession *session.Session) (currency.RateProvider, error) {
+	key := common.GetEnv("EXCHANGE_RATES_S3_KEY", "")
+	if key == "" {
+		return &currency.StaticRateProvider{Base: currency.USD}, nil
+	}
+	return currency.NewStaticRateProviderFromS3(
+		&common.S3{Client: s3.New(awsSession)},
+		common.RequireEnv("ARTIFACTS_BUCKET"),
+		key,
+	)
+}
+
 func newAWSSession() *session.Session {
 	awsSession, err := session.NewSession()
 	if err != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/stretchr/testify/mock"
 	"os"
@@ -32,6 +33,13 @@ func MockAPIResponse(status int, body string) events.APIGatewayProxyResponse {
 	}
 }
 
+// withUser returns a copy of the request, made by the user
+func withUser(r *http.Request, user *api.User) *http.Request {
+	return r.WithContext(api.WithUser(r.Context(), user))
+}
+
+var adminUser = &api.User{Role: api.AdminGroupName}
+
 func MockAPIErrorResponse(status int, body string) events.APIGatewayProxyResponse {
 
 	return events.APIGatewayProxyResponse{
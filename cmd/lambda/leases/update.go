@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gorilla/mux"
+)
+
+// UpdateLeaseByID - Extends the budget amount and expiration of the given lease
+func UpdateLeaseByID(w http.ResponseWriter, r *http.Request) {
+
+	leaseID := mux.Vars(r)["leaseID"]
+
+	// Deserialize the request JSON as an request object
+	updLease := &lease.Lease{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(updLease)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	existing, err := Services.LeaseService().Get(leaseID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	if !api.AllowedFor(r, api.PermissionLeasesWrite, aws.StringValue(existing.PrincipalID)) {
+		api.WriteAPIErrorResponse(w, errors.NewNotFound("lease", leaseID))
+		return
+	}
+
+	c := leaseValidationContext{
+		maxLeaseBudgetAmount:     maxLeaseBudgetAmount,
+		maxLeasePeriod:           maxLeasePeriod,
+		defaultLeaseLengthInDays: defaultLeaseLengthInDays,
+		principalBudgetPeriod:    principalBudgetPeriod,
+		principalBudgetAmount:    principalBudgetAmount,
+	}
+
+	isValid, validationErrorMessage, err := validateLeaseExtension(&c, existing, updLease)
+	if err != nil {
+		response.WriteServerErrorWithResponse(w, err.Error())
+		return
+	}
+
+	if !isValid {
+		response.WriteRequestValidationError(w, validationErrorMessage)
+		return
+	}
+
+	updatedLease, err := Services.LeaseService().Update(leaseID, updLease)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, updatedLease)
+}
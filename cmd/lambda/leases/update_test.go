@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/Optum/dce/pkg/usage"
+	mockUsage "github.com/Optum/dce/pkg/usage/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateLeaseByID(t *testing.T) {
+	now := time.Now().Unix()
+	expiresOn := now + 1000
+	shortExpiresOn := now + 500
+	tooLongExpiresOn := now + 10000
+	budgetAmount := 200.0
+	lowBudgetAmount := 50.0
+	highBudgetAmount := 2000.0
+	spent := 1500.0
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name      string
+		leaseID   string
+		user      *api.User
+		updLease  *lease.Lease
+		getLease  *lease.Lease
+		getErr    error
+		usages    []*usage.Usage
+		retLease  *lease.Lease
+		updateErr error
+		expResp   response
+	}{
+		{
+			name:    "should extend the lease",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				ExpiresOn:    &expiresOn,
+				BudgetAmount: &budgetAmount,
+			},
+			retLease: &lease.Lease{
+				ID:           ptrString("abc123"),
+				Status:       lease.StatusActive.StatusPtr(),
+				PrincipalID:  ptrString("principal"),
+				AccountID:    ptrString("123456789012"),
+				BudgetAmount: &budgetAmount,
+				ExpiresOn:    &expiresOn,
+			},
+			expResp: response{
+				StatusCode: 200,
+				Body:       fmt.Sprintf("\"budgetAmount\":200,\"expiresOn\":%d", expiresOn),
+			},
+		},
+		{
+			name:    "should not extend the leases of other principals",
+			leaseID: "abc123",
+			user:    &api.User{Username: "other", Role: api.UserGroupName},
+			updLease: &lease.Lease{
+				ExpiresOn: &expiresOn,
+			},
+			expResp: response{
+				StatusCode: 404,
+				Body:       "NotFoundError",
+			},
+		},
+		{
+			name:    "should fail when the lease doesn't exist",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				ExpiresOn: &expiresOn,
+			},
+			getErr: errors.NewNotFound("lease", "abc123"),
+			expResp: response{
+				StatusCode: 404,
+				Body:       "lease \\\"abc123\\\" not found",
+			},
+		},
+		{
+			name:     "should fail when nothing is being changed",
+			leaseID:  "abc123",
+			updLease: &lease.Lease{},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "expiresOn or budgetAmount is required",
+			},
+		},
+		{
+			name:    "should fail when shortening the lease",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				ExpiresOn: &shortExpiresOn,
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "which is less than the current expiry date",
+			},
+		},
+		{
+			name:    "should fail when extending past the max lease period",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				ExpiresOn: &tooLongExpiresOn,
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "which is greater than max lease period",
+			},
+		},
+		{
+			name:    "should fail when lowering the budget",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				BudgetAmount: &lowBudgetAmount,
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "which is less than the current budget amount",
+			},
+		},
+		{
+			name:    "should fail when raising the budget past the max",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				BudgetAmount: &highBudgetAmount,
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "which is greater than max lease budget amount",
+			},
+		},
+		{
+			name:    "should fail when the principal is over budget",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				BudgetAmount: &budgetAmount,
+			},
+			usages: []*usage.Usage{
+				{CostAmount: &spent},
+			},
+			expResp: response{
+				StatusCode: 400,
+				Body:       "has already spent 1500.00 of their 1000.00 principal budget",
+			},
+		},
+		{
+			name:    "should fail on a conflicting update",
+			leaseID: "abc123",
+			updLease: &lease.Lease{
+				ExpiresOn: &expiresOn,
+			},
+			updateErr: errors.NewConflict("lease", "abc123", fmt.Errorf("conditional check failed")),
+			expResp: response{
+				StatusCode: 409,
+				Body:       "conditional check failed",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := new(bytes.Buffer)
+			err := json.NewEncoder(b).Encode(tt.updLease)
+			assert.Nil(t, err)
+			r := httptest.NewRequest("PUT", fmt.Sprintf("http://example.com/leases/%s", tt.leaseID), b)
+			user := tt.user
+			if user == nil {
+				user = adminUser
+			}
+			r = withUser(r, user)
+
+			r = mux.SetURLVars(r, map[string]string{
+				"leaseID": tt.leaseID,
+			})
+			w := httptest.NewRecorder()
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			getLease := tt.getLease
+			if getLease == nil && tt.getErr == nil {
+				getLease = &lease.Lease{
+					ID:           ptrString(tt.leaseID),
+					Status:       lease.StatusActive.StatusPtr(),
+					PrincipalID:  ptrString("principal"),
+					AccountID:    ptrString("123456789012"),
+					BudgetAmount: ptrFloat64(100),
+					ExpiresOn:    ptrInt64(now + 800),
+					CreatedOn:    ptrInt64(now),
+				}
+			}
+
+			leaseSvc := mocks.Servicer{}
+			leaseSvc.On("Get", tt.leaseID).Return(getLease, tt.getErr)
+			leaseSvc.On("Update", tt.leaseID, mock.AnythingOfType("*lease.Lease")).Return(tt.retLease, tt.updateErr)
+
+			svcBldr.Config.WithService(&leaseSvc)
+			svcBldr.Config.WithService(stubPrincipalService())
+			_, err = svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			usageMock := &mockUsage.DBer{}
+			usageMock.On("GetUsageByPrincipal", mock.Anything, "principal").Return(tt.usages, nil)
+			usageSvc = usageMock
+			maxLeaseBudgetAmount = 1000
+			maxLeasePeriod = 5000
+			principalBudgetAmount = 1000
+			principalBudgetPeriod = Weekly
+
+			UpdateLeaseByID(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Contains(t, string(body), tt.expResp.Body)
+		})
+	}
+}
+
+func ptrFloat64(f float64) *float64 {
+	ptrF := f
+	return &ptrF
+}
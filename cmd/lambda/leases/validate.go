@@ -7,6 +7,11 @@ import (
 	"math"
 	"net/http"
 	"time"
+
+	"github.com/Optum/dce/pkg/currency"
+	apiErrors "github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/principal"
 )
 
 type leaseValidationContext struct {
@@ -17,24 +22,45 @@ type leaseValidationContext struct {
 	defaultLeaseLengthInDays int
 }
 
-// ValidateLease validates lease budget amount and period
-func validateLeaseFromRequest(context *leaseValidationContext, req *http.Request) (*createLeaseRequest, bool, string, error) {
+// parseLeaseFromRequest parses the lease requested in the body of the request
+func parseLeaseFromRequest(req *http.Request) (*createLeaseRequest, bool, string) {
 
 	// Validate body from the Request
 	requestBody := &createLeaseRequest{}
-	var err error
 
 	decoder := json.NewDecoder(req.Body)
-	err = decoder.Decode(&requestBody)
+	err := decoder.Decode(&requestBody)
 
 	if err != nil || requestBody.PrincipalID == "" {
 		validationErrStr := "invalid request parameters"
-		return requestBody, false, validationErrStr, nil
+		return requestBody, false, validationErrStr
+	}
+
+	return requestBody, true, ""
+}
+
+// validateLeaseRequest validates lease budget amount and period
+func validateLeaseRequest(context *leaseValidationContext, requestBody *createLeaseRequest) (bool, string, error) {
+
+	// The principal's profile may override the default limits
+	context, err := withPrincipalLimits(context, requestBody.PrincipalID)
+	if err != nil {
+		return true, "", err
+	}
+
+	// Scheduled leases start in the future, other leases start now
+	leaseStart := time.Now()
+	if requestBody.StartsOn != 0 {
+		if requestBody.StartsOn <= leaseStart.Unix() {
+			validationErrStr := fmt.Sprintf("Requested lease has a desired start date less than today: %d", requestBody.StartsOn)
+			return false, validationErrStr, nil
+		}
+		leaseStart = time.Unix(requestBody.StartsOn, 0)
 	}
 
 	// Set default expiresOn
 	if requestBody.ExpiresOn == 0 {
-		requestBody.ExpiresOn = time.Now().AddDate(0, 0, context.defaultLeaseLengthInDays).Unix()
+		requestBody.ExpiresOn = leaseStart.AddDate(0, 0, context.defaultLeaseLengthInDays).Unix()
 	}
 
 	// Set default metadata (empty object)
@@ -45,45 +71,186 @@ func validateLeaseFromRequest(context *leaseValidationContext, req *http.Request
 	// Validate requested lease end date is greater than today
 	if requestBody.ExpiresOn <= time.Now().Unix() {
 		validationErrStr := fmt.Sprintf("Requested lease has a desired expiry date less than today: %d", requestBody.ExpiresOn)
-		return requestBody, false, validationErrStr, nil
+		return false, validationErrStr, nil
 	}
 
-	// Validate requested lease budget amount is less than MAX_LEASE_BUDGET_AMOUNT
-	if requestBody.BudgetAmount > context.maxLeaseBudgetAmount {
-		validationErrStr := fmt.Sprintf("Requested lease has a budget amount of %f, which is greater than max lease budget amount of %f", math.Round(requestBody.BudgetAmount), math.Round(context.maxLeaseBudgetAmount))
-		return requestBody, false, validationErrStr, nil
+	// Validate requested lease end date is after the start date
+	if requestBody.ExpiresOn <= leaseStart.Unix() {
+		validationErrStr := fmt.Sprintf("Requested lease has a desired expiry date of %d, which is not after its start date of %d", requestBody.ExpiresOn, leaseStart.Unix())
+		return false, validationErrStr, nil
 	}
 
-	// Validate requested lease budget period is less than MAX_LEASE_BUDGET_PERIOD
-	currentTime := time.Now()
-	maxLeaseExpiresOn := currentTime.Add(time.Second * time.Duration(context.maxLeasePeriod))
-	if requestBody.ExpiresOn > maxLeaseExpiresOn.Unix() {
-		validationErrStr := fmt.Sprintf("Requested lease has a budget expires on of %d, which is greater than max lease period of %d", requestBody.ExpiresOn, maxLeaseExpiresOn.Unix())
-		return requestBody, false, validationErrStr, nil
+	// Leases over MAX_LEASE_BUDGET_AMOUNT or MAX_LEASE_PERIOD are held for an approver
+	if approvalReason := validateBudgetAmount(context, requestBody.BudgetAmount); approvalReason != "" {
+		requestBody.approvalReason = approvalReason
+	} else if approvalReason := validateLeasePeriod(context, leaseStart, requestBody.ExpiresOn); approvalReason != "" {
+		requestBody.approvalReason = approvalReason
 	}
 
 	// Validate requested lease budget amount is less than PRINCIPAL_BUDGET_AMOUNT for current principal billing period
+	validationErrStr, err := validatePrincipalSpend(context, requestBody.PrincipalID)
+	if err != nil {
+		return true, "", err
+	}
+	if validationErrStr != "" {
+		return false, validationErrStr, nil
+	}
+
+	return true, "", nil
+}
+
+// validateLeaseExtension validates a change to the budget amount and expiration of an existing lease
+func validateLeaseExtension(context *leaseValidationContext, existing *lease.Lease, update *lease.Lease) (bool, string, error) {
+	if update.ExpiresOn == nil && update.BudgetAmount == nil {
+		return false, "invalid request parameters: expiresOn or budgetAmount is required", nil
+	}
+
+	// The principal's profile may override the default limits
+	context, err := withPrincipalLimits(context, *existing.PrincipalID)
+	if err != nil {
+		return true, "", err
+	}
+
+	if update.ExpiresOn != nil {
+		// An extension can't shorten the lease
+		if existing.ExpiresOn != nil && *update.ExpiresOn < *existing.ExpiresOn {
+			validationErrStr := fmt.Sprintf("Requested lease has a desired expiry date of %d, which is less than the current expiry date of %d", *update.ExpiresOn, *existing.ExpiresOn)
+			return false, validationErrStr, nil
+		}
+
+		if *update.ExpiresOn <= time.Now().Unix() {
+			validationErrStr := fmt.Sprintf("Requested lease has a desired expiry date less than today: %d", *update.ExpiresOn)
+			return false, validationErrStr, nil
+		}
+
+		// The max lease period is measured from when the lease was created,
+		// or from when a scheduled lease starts
+		leaseStart := time.Now()
+		if existing.StartsOn != nil {
+			leaseStart = time.Unix(*existing.StartsOn, 0)
+		} else if existing.CreatedOn != nil {
+			leaseStart = time.Unix(*existing.CreatedOn, 0)
+		}
+		if validationErrStr := validateLeasePeriod(context, leaseStart, *update.ExpiresOn); validationErrStr != "" {
+			return false, validationErrStr, nil
+		}
+	}
+
+	if update.BudgetAmount != nil {
+		// An extension can't lower the budget
+		if existing.BudgetAmount != nil && *update.BudgetAmount < *existing.BudgetAmount {
+			validationErrStr := fmt.Sprintf("Requested lease has a budget amount of %f, which is less than the current budget amount of %f", math.Round(*update.BudgetAmount), math.Round(*existing.BudgetAmount))
+			return false, validationErrStr, nil
+		}
+
+		if validationErrStr := validateBudgetAmount(context, *update.BudgetAmount); validationErrStr != "" {
+			return false, validationErrStr, nil
+		}
+	}
+
+	validationErrStr, err := validatePrincipalSpend(context, *existing.PrincipalID)
+	if err != nil {
+		return true, "", err
+	}
+	if validationErrStr != "" {
+		return false, validationErrStr, nil
+	}
+
+	return true, "", nil
+}
+
+// withPrincipalLimits returns a copy of the validation context, with the limits
+// set on the principal's profile in place of the defaults
+func withPrincipalLimits(context *leaseValidationContext, principalID string) (*leaseValidationContext, error) {
+	limits, err := Services.PrincipalService().Limits(principalID, principal.Limits{
+		MaxLeaseBudgetAmount:  context.maxLeaseBudgetAmount,
+		MaxLeasePeriod:        context.maxLeasePeriod,
+		PrincipalBudgetAmount: context.principalBudgetAmount,
+		PrincipalBudgetPeriod: context.principalBudgetPeriod,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &leaseValidationContext{
+		maxLeaseBudgetAmount:     limits.MaxLeaseBudgetAmount,
+		maxLeasePeriod:           limits.MaxLeasePeriod,
+		principalBudgetAmount:    limits.PrincipalBudgetAmount,
+		principalBudgetPeriod:    limits.PrincipalBudgetPeriod,
+		defaultLeaseLengthInDays: context.defaultLeaseLengthInDays,
+	}, nil
+}
+
+// validateBudgetAmount checks a budget amount against MAX_LEASE_BUDGET_AMOUNT
+func validateBudgetAmount(context *leaseValidationContext, budgetAmount float64) string {
+	if budgetAmount > context.maxLeaseBudgetAmount {
+		return fmt.Sprintf("Requested lease has a budget amount of %f, which is greater than max lease budget amount of %f", math.Round(budgetAmount), math.Round(context.maxLeaseBudgetAmount))
+	}
+	return ""
+}
+
+// validateLeasePeriod checks that a lease starting at startTime and ending at expiresOn is within MAX_LEASE_PERIOD
+func validateLeasePeriod(context *leaseValidationContext, startTime time.Time, expiresOn int64) string {
+	maxLeaseExpiresOn := startTime.Add(time.Second * time.Duration(context.maxLeasePeriod))
+	if expiresOn > maxLeaseExpiresOn.Unix() {
+		return fmt.Sprintf("Requested lease has a budget expires on of %d, which is greater than max lease period of %d", expiresOn, maxLeaseExpiresOn.Unix())
+	}
+	return ""
+}
+
+// validatePrincipalSpend checks the principal's spend for the current billing period against PRINCIPAL_BUDGET_AMOUNT
+func validatePrincipalSpend(context *leaseValidationContext, principalID string) (string, error) {
 	usageStartTime := getBeginningOfCurrentBillingPeriod(context.principalBudgetPeriod)
 
-	usageRecords, err := usageSvc.GetUsageByPrincipal(usageStartTime, requestBody.PrincipalID)
+	usageRecords, err := usageSvc.GetUsageByPrincipal(usageStartTime, principalID)
 	if err != nil {
 		errStr := fmt.Sprintf("Failed to retrieve usage: %s", err)
-		return requestBody, true, "", errors.New(errStr)
+		return "", errors.New(errStr)
 	}
 
-	// Group by PrincipalID to get sum of total spent for current billing period
+	// Group by PrincipalID to get sum of total spent for current billing period.
+	// The principal's usage records include the cost of all of their leases,
+	// converted to the currency of the principal budget.
 	spent := 0.0
 	for _, usageItem := range usageRecords {
-		spent = spent + *usageItem.CostAmount
+		if usageItem.CostAmount == nil {
+			continue
+		}
+		costCurrency := currency.USD
+		if usageItem.CostCurrency != nil {
+			costCurrency = *usageItem.CostCurrency
+		}
+		cost, err := currency.Convert(rateProvider, *usageItem.CostAmount, costCurrency, principalBudgetCurrency)
+		if err != nil {
+			return "", fmt.Errorf("Failed to convert usage to %s: %s", principalBudgetCurrency, err)
+		}
+		spent = spent + cost
 	}
 
 	if spent > context.principalBudgetAmount {
-		validationErrStr := fmt.Sprintf(
+		return fmt.Sprintf(
 			"Unable to create lease: User principal %s has already spent %.2f of their %.2f principal budget",
-			requestBody.PrincipalID, spent, context.principalBudgetAmount,
-		)
-		return requestBody, false, validationErrStr, nil
+			principalID, spent, context.principalBudgetAmount,
+		), nil
+	}
+	return "", nil
+}
+
+// validateTeamMember checks the team exists, and the principal is one of its members or leads
+func validateTeamMember(teamID string, principalID string) (string, error) {
+	leaseTeam, err := Services.TeamService().Get(teamID)
+	if err != nil {
+		if apiErrors.HTTPCodeForError(err) == http.StatusNotFound {
+			return fmt.Sprintf("Requested lease has a team %s, which does not exist", teamID), nil
+		}
+		return "", err
 	}
 
-	return requestBody, true, "", nil
+	if !leaseTeam.HasMember(principalID) {
+		return fmt.Sprintf(
+			"Unable to create lease: User principal %s is not a member of team %s",
+			principalID, teamID,
+		), nil
+	}
+	return "", nil
 }
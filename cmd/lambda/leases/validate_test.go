@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/currency"
+	"github.com/Optum/dce/pkg/usage"
+	mockUsage "github.com/Optum/dce/pkg/usage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestValidatePrincipalSpend(t *testing.T) {
+	type response struct {
+		validationErr string
+		hasErr        bool
+	}
+
+	tests := []struct {
+		name           string
+		budgetCurrency string
+		usages         []*usage.Usage
+		exp            response
+	}{
+		{
+			name:           "should pass when spend is under the principal budget",
+			budgetCurrency: currency.USD,
+			usages: []*usage.Usage{
+				{CostAmount: ptrFloat64(400), CostCurrency: ptrString("USD")},
+				{CostAmount: ptrFloat64(400), CostCurrency: ptrString("USD")},
+			},
+		},
+		{
+			name:           "should fail when spend is over the principal budget",
+			budgetCurrency: currency.USD,
+			usages: []*usage.Usage{
+				{CostAmount: ptrFloat64(600), CostCurrency: ptrString("USD")},
+				{CostAmount: ptrFloat64(600)},
+			},
+			exp: response{
+				validationErr: "User principal jdoe123 has already spent 1200.00 of their 1000.00 principal budget",
+			},
+		},
+		{
+			name:           "should convert spend to the principal budget currency",
+			budgetCurrency: "EUR",
+			usages: []*usage.Usage{
+				{CostAmount: ptrFloat64(600), CostCurrency: ptrString("USD")},
+				{CostAmount: ptrFloat64(600), CostCurrency: ptrString("USD")},
+			},
+		},
+		{
+			name:           "should skip usage without a cost",
+			budgetCurrency: currency.USD,
+			usages: []*usage.Usage{
+				{CostAmount: ptrFloat64(600), CostCurrency: ptrString("USD")},
+				{CostCurrency: ptrString("USD")},
+			},
+		},
+		{
+			name:           "should fail when usage can't be converted",
+			budgetCurrency: currency.USD,
+			usages: []*usage.Usage{
+				{CostAmount: ptrFloat64(600), CostCurrency: ptrString("JPY")},
+			},
+			exp: response{
+				hasErr: true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usageMock := &mockUsage.DBer{}
+			usageMock.On("GetUsageByPrincipal", mock.Anything, "jdoe123").Return(tt.usages, nil)
+			usageSvc = usageMock
+			rateProvider = &currency.StaticRateProvider{
+				Base:  currency.USD,
+				Rates: map[string]float64{"EUR": 0.5},
+			}
+			principalBudgetCurrency = tt.budgetCurrency
+			defer func() {
+				principalBudgetCurrency = ""
+				rateProvider = nil
+			}()
+
+			validationErr, err := validatePrincipalSpend(&leaseValidationContext{
+				principalBudgetAmount: 1000,
+				principalBudgetPeriod: Weekly,
+			}, "jdoe123")
+
+			if tt.exp.hasErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+			if tt.exp.validationErr == "" {
+				assert.Equal(t, "", validationErr)
+			} else {
+				assert.Contains(t, validationErr, tt.exp.validationErr)
+			}
+		})
+	}
+}
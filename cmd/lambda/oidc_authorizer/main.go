@@ -0,0 +1,87 @@
+// Package main sets the handler for the OIDC Authorizer AWS Lambda Function,
+// which lets the API Gateway accept the bearer tokens of the OIDC identity
+// provider
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// authenticator verifies bearer tokens
+type authenticator interface {
+	Authenticate(token string) (string, map[string]interface{}, error)
+}
+
+// errUnauthorized makes the API Gateway respond with a 401
+var errUnauthorized = errors.New("Unauthorized")
+
+var tokenAuthenticator authenticator
+
+// Start the Lambda Handler
+func main() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	userDetails := &api.OIDCUserDetails{}
+	if err := cfgBldr.Unmarshal(userDetails); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+	// Tokens are only accepted for the configured issuer and audience
+	if err := userDetails.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %s", err.Error())
+	}
+
+	userDetails.Keys = api.NewRemoteKeySet(userDetails.JWKSURL)
+	tokenAuthenticator = userDetails
+
+	lambda.Start(handler)
+}
+
+// handler allows requests with a valid bearer token to invoke the API.  The
+// API's handlers decide which requests the user is allowed to make
+func handler(ctx context.Context, req events.APIGatewayCustomAuthorizerRequest) (events.APIGatewayCustomAuthorizerResponse, error) {
+	token := api.BearerToken(req.AuthorizationToken)
+	if token == "" {
+		log.Printf("Request doesn't have a bearer token")
+		return events.APIGatewayCustomAuthorizerResponse{}, errUnauthorized
+	}
+
+	username, _, err := tokenAuthenticator.Authenticate(token)
+	if err != nil {
+		log.Printf("Failed to authenticate bearer token: %s", err)
+		return events.APIGatewayCustomAuthorizerResponse{}, errUnauthorized
+	}
+
+	return events.APIGatewayCustomAuthorizerResponse{
+		PrincipalID: username,
+		PolicyDocument: events.APIGatewayCustomAuthorizerPolicy{
+			Version: "2012-10-17",
+			Statement: []events.IAMPolicyStatement{
+				{
+					Action:   []string{"execute-api:Invoke"},
+					Effect:   "Allow",
+					Resource: []string{apiResource(req.MethodArn)},
+				},
+			},
+		},
+	}, nil
+}
+
+// apiResource returns the ARN of every method of the API stage, e.g.
+// `arn:aws:execute-api:us-east-1:123456789012:abcdef1234/api/*` for
+// `arn:aws:execute-api:us-east-1:123456789012:abcdef1234/api/GET/leases`.
+// The API Gateway caches the policy for the token, so it must cover the
+// other methods the user calls
+func apiResource(methodArn string) string {
+	parts := strings.SplitN(methodArn, "/", 3)
+	if len(parts) < 2 {
+		return methodArn
+	}
+	return parts[0] + "/" + parts[1] + "/*"
+}
@@ -6,13 +6,17 @@ import (
 	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/reset"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 )
 
 type configuration struct {
-	Debug         string `env:"DEBUG" envDefault:"false"`
-	ResetQueueURL string `env:"RESET_SQS_URL" envDefault:"SqsUrl"`
+	Debug               string `env:"DEBUG" envDefault:"false"`
+	ResetQueueURL       string `env:"RESET_SQS_URL" envDefault:"SqsUrl"`
+	BuildName           string `env:"RESET_BUILD_NAME" envDefault:"ResetCodeBuild"`
+	MaxConcurrentBuilds int    `env:"RESET_MAX_CONCURRENT_BUILDS" envDefault:"0"`
+	PoolMetadataKey     string `env:"RESET_POOL_METADATA_KEY" envDefault:""`
 }
 
 var (
@@ -37,6 +41,7 @@ func init() {
 
 	_, err = svcBldr.
 		WithAccountService().
+		WithCodeBuild().
 		Build()
 	if err != nil {
 		panic(err)
@@ -49,20 +54,42 @@ func init() {
 // Handler is the base handler function for the lambda
 func Handler(cloudWatchEvent events.CloudWatchEvent) error {
 
-	query := &account.Account{
-		Status: account.StatusNotReady.StatusPtr(),
+	var buildSvc reset.BuildService
+	if err := services.Config.GetService(&buildSvc); err != nil {
+		return errors.NewInternalServer("unexpected error getting the CodeBuild service", err)
+	}
+	scheduler := &reset.Scheduler{
+		Builds:          buildSvc,
+		BuildName:       settings.BuildName,
+		MaxInFlight:     settings.MaxConcurrentBuilds,
+		PoolMetadataKey: settings.PoolMetadataKey,
 	}
 
-	var errs []error
-	err := services.AccountService().ListPages(query,
+	// Count the Ready accounts in each pool
+	readyByPool := map[string]int{}
+	err := services.AccountService().ListPages(
+		&account.Account{
+			Status: account.StatusReady.StatusPtr(),
+		},
 		func(accts *account.Accounts) bool {
+			for i := range *accts {
+				readyByPool[scheduler.Pool(&(*accts)[i])]++
+			}
+			return true //always continue
+		},
+	)
+	if err != nil {
+		return err
+	}
 
-			for _, acct := range *accts {
-				// Send Message
-				err := services.AccountService().Reset(&acct)
-				if err != nil {
-					errs = append(errs, err)
-				}
+	notReady := []*account.Account{}
+	err = services.AccountService().ListPages(
+		&account.Account{
+			Status: account.StatusNotReady.StatusPtr(),
+		},
+		func(accts *account.Accounts) bool {
+			for i := range *accts {
+				notReady = append(notReady, &(*accts)[i])
 			}
 			return true //always continue
 		},
@@ -71,6 +98,24 @@ func Handler(cloudWatchEvent events.CloudWatchEvent) error {
 		return err
 	}
 
+	inFlight, err := scheduler.InFlight(notReady)
+	if err != nil {
+		return errors.NewInternalServer("unexpected error finding resets in progress", err)
+	}
+
+	scheduled := scheduler.Schedule(notReady, readyByPool, inFlight)
+	log.Printf("Scheduling %d of %d NotReady accounts to reset, with %d resets in progress",
+		len(scheduled), len(notReady), len(inFlight))
+
+	var errs []error
+	for _, acct := range scheduled {
+		// Send Message
+		err := services.AccountService().Reset(acct)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if len(errs) > 0 {
 		return errors.NewMultiError("error when processing accounts", errs)
 	}
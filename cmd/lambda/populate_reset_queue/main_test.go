@@ -10,8 +10,11 @@ import (
 	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/errors"
 	eventMocks "github.com/Optum/dce/pkg/event/eventiface/mocks"
+	resetMocks "github.com/Optum/dce/pkg/reset/mocks"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -111,6 +114,9 @@ func TestPopulateResetQeue(t *testing.T) {
 				}
 				return false
 			})).Return(tt.listAccounts, tt.listErr)
+			mocksRwd.On("List", mock.MatchedBy(func(input *account.Account) bool {
+				return input.Status.String() == "Ready"
+			})).Return(&account.Accounts{}, nil)
 
 			mocksEvent := &eventMocks.Servicer{}
 			mocksEvent.On("AccountReset", mock.AnythingOfType("*account.Account")).
@@ -123,7 +129,11 @@ func TestPopulateResetQeue(t *testing.T) {
 				},
 			)
 
-			svcBldr.Config.WithService(accountSvc)
+			mocksBuild := &resetMocks.BuildService{}
+			mocksBuild.On("ListBuildsForProject", mock.Anything).
+				Return(&codebuild.ListBuildsForProjectOutput{}, nil)
+
+			svcBldr.Config.WithService(accountSvc).WithService(mocksBuild)
 			_, err := svcBldr.Build()
 
 			assert.Nil(t, err)
@@ -137,3 +147,83 @@ func TestPopulateResetQeue(t *testing.T) {
 		})
 	}
 }
+
+func TestPopulateResetQueueThrottled(t *testing.T) {
+	notReady := func(id string, tier string, lastModifiedOn int64) account.Account {
+		return account.Account{
+			ID:               ptrString(id),
+			Status:           account.StatusNotReady.StatusPtr(),
+			LastModifiedOn:   aws.Int64(lastModifiedOn),
+			AdminRoleArn:     arn.New("aws", "iam", "", id, "role/AdminRole"),
+			PrincipalRoleArn: arn.New("aws", "iam", "", id, "role/PrincipalRole"),
+			Metadata:         map[string]interface{}{"accountTier": tier},
+		}
+	}
+
+	defer func(maxConcurrentBuilds int, poolMetadataKey string) {
+		settings.MaxConcurrentBuilds = maxConcurrentBuilds
+		settings.PoolMetadataKey = poolMetadataKey
+	}(settings.MaxConcurrentBuilds, settings.PoolMetadataKey)
+	settings.MaxConcurrentBuilds = 2
+	settings.PoolMetadataKey = "accountTier"
+
+	cfgBldr := &config.ConfigurationBuilder{}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksRwd.On("List", mock.MatchedBy(func(input *account.Account) bool {
+		return input.Status.String() == "NotReady"
+	})).Return(&account.Accounts{
+		notReady("111111111111", "standard", 100),
+		notReady("222222222222", "gpu", 300),
+		notReady("333333333333", "gpu", 200),
+	}, nil)
+	mocksRwd.On("List", mock.MatchedBy(func(input *account.Account) bool {
+		return input.Status.String() == "Ready"
+	})).Return(&account.Accounts{
+		{ID: ptrString("444444444444"), Metadata: map[string]interface{}{"accountTier": "standard"}},
+	}, nil)
+
+	// Only the gpu account which has waited longest is reset,
+	// the other is already being reset
+	mocksEvent := &eventMocks.Servicer{}
+	mocksEvent.On("AccountReset", mock.MatchedBy(func(acct *account.Account) bool {
+		return *acct.ID == "333333333333"
+	})).Return(nil)
+
+	accountSvc := account.NewService(
+		account.NewServiceInput{
+			DataSvc:  mocksRwd,
+			EventSvc: mocksEvent,
+		},
+	)
+
+	mocksBuild := &resetMocks.BuildService{}
+	mocksBuild.On("ListBuildsForProject", mock.Anything).
+		Return(&codebuild.ListBuildsForProjectOutput{
+			Ids: aws.StringSlice([]string{"build-1"}),
+		}, nil)
+	mocksBuild.On("BatchGetBuilds", mock.Anything).
+		Return(&codebuild.BatchGetBuildsOutput{
+			Builds: []*codebuild.Build{
+				{
+					Id:          aws.String("build-1"),
+					BuildStatus: aws.String("IN_PROGRESS"),
+					Environment: &codebuild.ProjectEnvironment{
+						EnvironmentVariables: []*codebuild.EnvironmentVariable{
+							{Name: aws.String("RESET_ACCOUNT"), Value: aws.String("222222222222")},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	svcBldr.Config.WithService(accountSvc).WithService(mocksBuild)
+	_, err := svcBldr.Build()
+	assert.Nil(t, err)
+	services = svcBldr
+
+	err = Handler(events.CloudWatchEvent{})
+	assert.Nil(t, err)
+	mocksEvent.AssertNumberOfCalls(t, "AccountReset", 1)
+}
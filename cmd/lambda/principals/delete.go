@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/gorilla/mux"
+)
+
+// DeletePrincipalByID - Deletes the profile of the principal.  The principal's
+// leases fall back to the default limits
+func DeletePrincipalByID(w http.ResponseWriter, r *http.Request) {
+
+	principalID := mux.Vars(r)["principalId"]
+
+	result, err := Services.PrincipalService().Get(principalID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	err = Services.PrincipalService().Delete(result)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusNoContent, nil)
+}
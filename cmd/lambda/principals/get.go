@@ -0,0 +1,22 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/gorilla/mux"
+)
+
+// GetPrincipalByID - Returns the profile of the principal
+func GetPrincipalByID(w http.ResponseWriter, r *http.Request) {
+
+	principalID := mux.Vars(r)["principalId"]
+
+	result, err := Services.PrincipalService().Get(principalID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, result)
+}
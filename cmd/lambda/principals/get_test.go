@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/Optum/dce/pkg/principal/principaliface/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func ptrFloat64(f float64) *float64 {
+	ptrF := f
+	return &ptrF
+}
+
+func TestGetPrincipalByID(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name         string
+		expResp      response
+		principalID  string
+		retPrincipal *principal.Principal
+		retErr       error
+	}{
+		{
+			name:        "success",
+			principalID: "jdoe99",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"id\":\"jdoe99\",\"maxLeaseBudgetAmount\":5000}\n",
+			},
+			retPrincipal: &principal.Principal{
+				ID:                   ptrString("jdoe99"),
+				MaxLeaseBudgetAmount: ptrFloat64(5000),
+			},
+			retErr: nil,
+		},
+		{
+			name:        "not found",
+			principalID: "jdoe99",
+			expResp: response{
+				StatusCode: 404,
+				Body:       "{\"error\":{\"message\":\"principal \\\"jdoe99\\\" not found\",\"code\":\"NotFoundError\"}}\n",
+			},
+			retPrincipal: nil,
+			retErr:       errors.NewNotFound("principal", "jdoe99"),
+		},
+		{
+			name:        "failure",
+			principalID: "jdoe99",
+			expResp: response{
+				StatusCode: 500,
+				Body:       "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+			},
+			retPrincipal: nil,
+			retErr:       fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/principals/%s", tt.principalID), nil)
+
+			r = mux.SetURLVars(r, map[string]string{
+				"principalId": tt.principalID,
+			})
+			w := httptest.NewRecorder()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			principalSvc := mocks.Servicer{}
+			principalSvc.On("Get", tt.principalID).Return(
+				tt.retPrincipal, tt.retErr,
+			)
+			svcBldr.Config.WithService(&principalSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetPrincipalByID(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+
+}
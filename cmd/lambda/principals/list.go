@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+)
+
+// GetPrincipals - Returns the principal profiles
+func GetPrincipals(w http.ResponseWriter, r *http.Request) {
+
+	principals, err := Services.PrincipalService().List()
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, principals)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/gorilla/mux"
+)
+
+// PutPrincipalByID creates or replaces the profile of the principal.  Limits
+// left out of the request fall back to the defaults
+func PutPrincipalByID(w http.ResponseWriter, r *http.Request) {
+	principalID := mux.Vars(r)["principalId"]
+
+	// Deserialize the request JSON as an request object
+	newPrincipal := &principal.Principal{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newPrincipal)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	result, err := Services.PrincipalService().Put(principalID, newPrincipal)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, result)
+}
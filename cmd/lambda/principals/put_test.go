@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/Optum/dce/pkg/principal/principaliface/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWhenPut(t *testing.T) {
+	standardHeaders := map[string][]string{
+		"Access-Control-Allow-Origin": []string{"*"},
+		"Content-Type":                []string{"application/json"},
+	}
+
+	tests := []struct {
+		name         string
+		expResp      events.APIGatewayProxyResponse
+		request      events.APIGatewayProxyRequest
+		retPrincipal *principal.Principal
+		retErr       error
+	}{
+		{
+			name: "When given good values. Then the principal is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusOK,
+				Body:              "{\"id\":\"jdoe99\",\"maxLeaseBudgetAmount\":5000,\"principalBudgetPeriod\":\"MONTHLY\"}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPut,
+				Path:       "/principals/jdoe99",
+				Body:       "{ \"maxLeaseBudgetAmount\": 5000, \"principalBudgetPeriod\": \"MONTHLY\" }",
+			},
+			retPrincipal: &principal.Principal{
+				ID:                    ptrString("jdoe99"),
+				MaxLeaseBudgetAmount:  ptrFloat64(5000),
+				PrincipalBudgetPeriod: ptrString("MONTHLY"),
+			},
+			retErr: nil,
+		},
+		{
+			name: "When given bad values. Then a syntax error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusBadRequest,
+				Body:              "{\"error\":{\"message\":\"invalid request parameters\",\"code\":\"ClientError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPut,
+				Path:       "/principals/jdoe99",
+				Body:       "{ \"maxLeaseBudgetAmount: 5000 }",
+			},
+			retPrincipal: &principal.Principal{},
+			retErr:       nil,
+		},
+		{
+			name: "Given internal failure. Then an internal server error is returned.",
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPut,
+				Path:       "/principals/jdoe99",
+				Body:       "{ \"maxLeaseBudgetAmount\": 5000 }",
+			},
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusInternalServerError,
+				Body:              "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			retPrincipal: nil,
+			retErr:       fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			principalSvc := mocks.Servicer{}
+			principalSvc.On("Put", "jdoe99", mock.AnythingOfType("*principal.Principal")).Return(
+				tt.retPrincipal, tt.retErr,
+			)
+			svcBldr.Config.WithService(&principalSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			resp, err := Handler(context.TODO(), tt.request)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp, resp)
+		})
+	}
+
+}
@@ -6,21 +6,57 @@ import (
 	"context"
 	"encoding/json"
 	"log"
+	"time"
 
 	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/account/accountiface"
+	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface"
+	"github.com/Optum/dce/pkg/common"
 	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event/eventiface"
+	"github.com/Optum/dce/pkg/reset"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/codebuild"
 	"github.com/aws/aws-sdk-go/service/codebuild/codebuildiface"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// Reset modes
+const (
+	// Reset every account in the reset CodeBuild
+	resetModeCodeBuild = "CodeBuild"
+	// Reset accounts with short resets in this Lambda,
+	// and the rest in the reset CodeBuild
+	resetModeLambda = "Lambda"
 )
 
 type configuration struct {
 	Debug     string `env:"DEBUG" envDefault:"false"`
 	BuildName string `env:"RESET_BUILD_NAME" envDefault:"ResetCodeBuild"`
+	ResetMode string `env:"RESET_MODE" envDefault:"CodeBuild"`
+	// Longest reset to run in the Lambda, in seconds,
+	// based on how long the account's last reset took
+	LambdaMaxDuration     int64    `env:"RESET_LAMBDA_MAX_DURATION" envDefault:"600"`
+	ParentAccountID       string   `env:"ACCOUNT_ID"`
+	PrincipalPolicyName   string   `env:"RESET_ACCOUNT_PRINCIPAL_POLICY_NAME" envDefault:"DCEPrincipalDefaultPolicy"`
+	Regions               []string `env:"RESET_NUKE_REGIONS" envDefault:"us-east-1"`
+	Steps                 []string `env:"RESET_STEPS" envDefault:"Athena,AwsNuke"`
+	IsNukeEnabled         bool     `env:"RESET_NUKE_TOGGLE" envDefault:"true"`
+	IsVerifyEnabled       bool     `env:"RESET_VERIFY_TOGGLE" envDefault:"true"`
+	NukeTemplateBucket    string   `env:"RESET_NUKE_TEMPLATE_BUCKET" envDefault:"STUB"`
+	NukeTemplateKey       string   `env:"RESET_NUKE_TEMPLATE_KEY" envDefault:"STUB"`
+	ReportBucket          string   `env:"RESET_REPORT_BUCKET" envDefault:"DefaultArtifactBucket"`
+	NukeOverridesBucket   string   `env:"RESET_NUKE_OVERRIDES_BUCKET" envDefault:"DefaultArtifactBucket"`
+	MaxFailedAttempts     int      `env:"RESET_MAX_FAILED_ATTEMPTS" envDefault:"3"`
+	MaxConcurrentBuilds   int      `env:"RESET_MAX_CONCURRENT_BUILDS" envDefault:"0"`
+	ResetCompleteTopicArn string   `env:"RESET_COMPLETE_TOPIC_ARN" envDefault:"DefaultResetCompleteTopicArn"`
 }
 
 var (
@@ -35,6 +71,9 @@ func init() {
 	if err := cfgBldr.Unmarshal(settings); err != nil {
 		log.Fatalf("Could not load configuration: %s", err.Error())
 	}
+	if settings.ResetMode != resetModeCodeBuild && settings.ResetMode != resetModeLambda {
+		log.Fatalf("Invalid RESET_MODE %q, must be %s or %s", settings.ResetMode, resetModeCodeBuild, resetModeLambda)
+	}
 
 	// load up the values into the various settings...
 	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
@@ -46,6 +85,10 @@ func init() {
 	_, err = svcBldr.
 		// DCE services...
 		WithCodeBuild().
+		WithStorageService().
+		WithLeaseService().
+		WithAccountManagerService().
+		WithAccountService().
 		Build()
 	if err != nil {
 		panic(err)
@@ -81,6 +124,158 @@ func processMessage(codeBuildSvc codebuildiface.CodeBuildAPI, event events.SQSMe
 
 	log.Printf("Start Account: %s\nMessage ID: %s\n", *acct.ID, event.MessageId)
 
+	scheduler := &reset.Scheduler{
+		Builds:      codeBuildSvc,
+		BuildName:   settings.BuildName,
+		MaxInFlight: settings.MaxConcurrentBuilds,
+	}
+	ok, err := canStartReset(scheduler, services.AccountService(), acct)
+	if err != nil || !ok {
+		return err
+	}
+
+	if settings.ResetMode == resetModeLambda && isLambdaReset(acct, event, settings.LambdaMaxDuration) {
+		return resetInLambda(acct)
+	}
+	return startResetBuild(codeBuildSvc, acct)
+}
+
+// canStartReset checks whether there's room for another reset, in a reset build or in the Lambda.
+// Accounts which aren't reset stay NotReady, and are scheduled
+// by populate_reset_queue once running resets have finished.
+func canStartReset(scheduler *reset.Scheduler, accountSvc accountiface.Servicer, acct *account.Account) (bool, error) {
+	// Don't look for running resets, if there's no limit
+	if scheduler.MaxInFlight <= 0 {
+		return true, nil
+	}
+
+	// Resets in the Lambda are recorded on the NotReady accounts
+	notReady := []*account.Account{}
+	err := accountSvc.ListPages(
+		&account.Account{
+			Status: account.StatusNotReady.StatusPtr(),
+		},
+		func(accts *account.Accounts) bool {
+			for i := range *accts {
+				notReady = append(notReady, &(*accts)[i])
+			}
+			return true //always continue
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	inFlight, err := scheduler.InFlight(notReady)
+	if err != nil {
+		return false, errors.NewInternalServer("unexpected error finding resets in progress", err)
+	}
+	if inFlight[*acct.ID] {
+		log.Printf("Account %s is already being reset\n", *acct.ID)
+		return false, nil
+	}
+	if scheduler.Capacity(inFlight) == 0 {
+		log.Printf("%d resets are in progress, the most allowed. Account %s will be reset later\n",
+			len(inFlight), *acct.ID)
+		return false, nil
+	}
+	return true, nil
+}
+
+// isLambdaReset checks whether the account can be reset in the Lambda.
+// Accounts are reset in the Lambda if their last reset succeeded quickly,
+// so accounts with lots of resources are reset in CodeBuild.
+// A message which has been received before may have timed out in the Lambda,
+// so is reset in CodeBuild.
+func isLambdaReset(acct *account.Account, event events.SQSMessage, maxDuration int64) bool {
+	if event.Attributes["ApproximateReceiveCount"] != "1" {
+		return false
+	}
+	lastReset := acct.LastResetResult
+	return lastReset != nil && lastReset.Succeeded && lastReset.Duration <= maxDuration
+}
+
+// resetInLambda runs the same reset job as the reset CodeBuild
+func resetInLambda(acct *account.Account) error {
+	log.Printf("Resetting Account %s in Lambda\n", *acct.ID)
+
+	awsSession := session.Must(session.NewSession())
+	dbSvc, err := db.NewFromEnv()
+	if err != nil {
+		return errors.NewInternalServer("unexpected error initializing the DB service", err)
+	}
+	var storageSvc common.Storager
+	if err := services.Config.GetService(&storageSvc); err != nil {
+		return errors.NewInternalServer("unexpected error getting the storage service", err)
+	}
+	var accountManagerSvc accountmanageriface.Servicer
+	if err := services.Config.GetService(&accountManagerSvc); err != nil {
+		return errors.NewInternalServer("unexpected error getting the account manager service", err)
+	}
+	var eventSvc eventiface.Servicer
+	if err := services.Config.GetService(&eventSvc); err != nil {
+		return errors.NewInternalServer("unexpected error getting the event service", err)
+	}
+
+	// Record the reset on the account, so it counts towards the resets in progress.
+	// The reset runs regardless, as the account can't be leased until it's reset
+	err = recordResetStarted(services.AccountService(), *acct.ID)
+	if err != nil {
+		log.Printf("Failed to record the reset of account %s: %s\n", *acct.ID, err)
+	}
+
+	job := &reset.Job{
+		Config: &reset.JobConfig{
+			ParentAccountID:       settings.ParentAccountID,
+			ChildAccountID:        *acct.ID,
+			AdminRoleName:         acct.AdminRoleArn.IAMResourceName(),
+			PrincipalRoleName:     acct.PrincipalRoleArn.IAMResourceName(),
+			PrincipalPolicyName:   settings.PrincipalPolicyName,
+			Regions:               settings.Regions,
+			Steps:                 settings.Steps,
+			IsNukeEnabled:         settings.IsNukeEnabled,
+			IsVerifyEnabled:       settings.IsVerifyEnabled,
+			NukeTemplateBucket:    settings.NukeTemplateBucket,
+			NukeTemplateKey:       settings.NukeTemplateKey,
+			ReportBucket:          settings.ReportBucket,
+			NukeOverridesBucket:   settings.NukeOverridesBucket,
+			MaxFailedAttempts:     settings.MaxFailedAttempts,
+			ResetCompleteTopicArn: settings.ResetCompleteTopicArn,
+		},
+		Session: awsSession,
+		Token:   &common.STS{Client: sts.New(awsSession)},
+		Storage: storageSvc,
+		DB:      dbSvc,
+		SNS:     &common.SNS{Client: sns.New(awsSession)},
+		Leases:  services.LeaseService(),
+
+		AccountManager: accountManagerSvc,
+		Accounts:       services.AccountService(),
+		Events:         eventSvc,
+	}
+
+	// A failed reset is recorded on the account, and the account
+	// is reset again the next time the reset queue is populated
+	err = job.Run()
+	if err != nil {
+		log.Printf("%s\n", err)
+	}
+	return nil
+}
+
+// recordResetStarted sets the time the account's reset started in the Lambda
+func recordResetStarted(accountSvc accountiface.Servicer, accountID string) error {
+	acct, err := accountSvc.Get(accountID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Unix()
+	acct.ResetStartedOn = &now
+	return accountSvc.Save(acct)
+}
+
+// startResetBuild starts the reset CodeBuild for the account
+func startResetBuild(codeBuildSvc codebuildiface.CodeBuildAPI, acct *account.Account) error {
 	buildEnvironmentVars := []*codebuild.EnvironmentVariable{
 		{
 			Name:  aws.String("RESET_ACCOUNT"),
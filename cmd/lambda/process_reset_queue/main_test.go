@@ -0,0 +1,167 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	accountMocks "github.com/Optum/dce/pkg/account/accountiface/mocks"
+	"github.com/Optum/dce/pkg/reset"
+	resetMocks "github.com/Optum/dce/pkg/reset/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsLambdaReset(t *testing.T) {
+	firstReceive := events.SQSMessage{
+		Attributes: map[string]string{"ApproximateReceiveCount": "1"},
+	}
+
+	tests := []struct {
+		name      string
+		lastReset *account.ResetResult
+		event     events.SQSMessage
+		exp       bool
+	}{
+		{
+			name:      "should reset quick resets in the Lambda",
+			lastReset: &account.ResetResult{Succeeded: true, Duration: 300},
+			event:     firstReceive,
+			exp:       true,
+		},
+		{
+			name:      "should reset slow resets in CodeBuild",
+			lastReset: &account.ResetResult{Succeeded: true, Duration: 900},
+			event:     firstReceive,
+			exp:       false,
+		},
+		{
+			name:      "should reset failed resets in CodeBuild",
+			lastReset: &account.ResetResult{Succeeded: false, Duration: 300},
+			event:     firstReceive,
+			exp:       false,
+		},
+		{
+			name:  "should reset accounts which haven't been reset in CodeBuild",
+			event: firstReceive,
+			exp:   false,
+		},
+		{
+			name:      "should reset retried messages in CodeBuild",
+			lastReset: &account.ResetResult{Succeeded: true, Duration: 300},
+			event: events.SQSMessage{
+				Attributes: map[string]string{"ApproximateReceiveCount": "2"},
+			},
+			exp: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acct := &account.Account{LastResetResult: tt.lastReset}
+			assert.Equal(t, tt.exp, isLambdaReset(acct, tt.event, 600))
+		})
+	}
+}
+
+func TestCanStartReset(t *testing.T) {
+	inProgress := func(accountID string) *codebuild.Build {
+		return &codebuild.Build{
+			BuildStatus: aws.String("IN_PROGRESS"),
+			Environment: &codebuild.ProjectEnvironment{
+				EnvironmentVariables: []*codebuild.EnvironmentVariable{
+					{Name: aws.String("RESET_ACCOUNT"), Value: aws.String(accountID)},
+				},
+			},
+		}
+	}
+	inLambda := func(accountID string) account.Account {
+		return account.Account{
+			ID:             aws.String(accountID),
+			ResetStartedOn: aws.Int64(time.Now().Unix()),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		maxInFlight int
+		builds      []*codebuild.Build
+		notReady    account.Accounts
+		exp         bool
+	}{
+		{
+			name:        "should start resets without a limit",
+			maxInFlight: 0,
+			exp:         true,
+		},
+		{
+			name:        "should start resets under the limit",
+			maxInFlight: 2,
+			builds:      []*codebuild.Build{inProgress("222222222222")},
+			exp:         true,
+		},
+		{
+			name:        "should not start resets at the limit",
+			maxInFlight: 1,
+			builds:      []*codebuild.Build{inProgress("222222222222")},
+			exp:         false,
+		},
+		{
+			name:        "should count resets in the Lambda towards the limit",
+			maxInFlight: 2,
+			builds:      []*codebuild.Build{inProgress("222222222222")},
+			notReady:    account.Accounts{inLambda("333333333333")},
+			exp:         false,
+		},
+		{
+			name:        "should not start resets for accounts already being reset",
+			maxInFlight: 2,
+			builds:      []*codebuild.Build{inProgress("111111111111")},
+			exp:         false,
+		},
+		{
+			name:        "should not start resets for accounts already being reset in the Lambda",
+			maxInFlight: 2,
+			notReady:    account.Accounts{inLambda("111111111111")},
+			exp:         false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildSvc := &resetMocks.BuildService{}
+			buildSvc.On("ListBuildsForProject", mock.Anything).
+				Return(&codebuild.ListBuildsForProjectOutput{
+					Ids: aws.StringSlice([]string{"build-1"}),
+				}, nil)
+			buildSvc.On("BatchGetBuilds", mock.Anything).
+				Return(&codebuild.BatchGetBuildsOutput{Builds: tt.builds}, nil)
+
+			accountSvc := &accountMocks.Servicer{}
+			accountSvc.On("ListPages", mock.MatchedBy(func(query *account.Account) bool {
+				return *query.Status == account.StatusNotReady
+			}), mock.Anything).
+				Run(func(args mock.Arguments) {
+					fn := args.Get(1).(func(*account.Accounts) bool)
+					fn(&tt.notReady)
+				}).
+				Return(nil)
+
+			scheduler := &reset.Scheduler{
+				Builds:      buildSvc,
+				BuildName:   "ResetCodeBuild",
+				MaxInFlight: tt.maxInFlight,
+			}
+			ok, err := canStartReset(scheduler, accountSvc, &account.Account{ID: aws.String("111111111111")})
+			assert.Nil(t, err)
+			assert.Equal(t, tt.exp, ok)
+			if tt.maxInFlight == 0 {
+				accountSvc.AssertNotCalled(t, "ListPages", mock.Anything, mock.Anything)
+				buildSvc.AssertNotCalled(t, "ListBuildsForProject", mock.Anything)
+			}
+		})
+	}
+}
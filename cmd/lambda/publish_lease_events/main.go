@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/Optum/dce/pkg/account/accountiface"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/config"
 	"github.com/Optum/dce/pkg/db"
 	errors2 "github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event/eventiface"
+	dceLease "github.com/Optum/dce/pkg/lease"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws"
@@ -20,6 +24,27 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 )
 
+var services *config.ServiceBuilder
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithAccountService().
+		WithEventService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+}
+
 // Start the Lambda Handler
 func main() {
 	lambda.Start(handler)
@@ -41,6 +66,10 @@ func handler(ctx context.Context, event events.DynamoDBEvent) error {
 	if err != nil {
 		log.Fatalf("Failed to configure DB service %s", err)
 	}
+	var eventSvc eventiface.Servicer
+	if err := services.Config.GetService(&eventSvc); err != nil {
+		log.Fatalf("Failed to configure Event service %s", err)
+	}
 
 	// We get a stream of DynDB records, representing changes to the table
 	for _, record := range event.Records {
@@ -53,6 +82,8 @@ func handler(ctx context.Context, event events.DynamoDBEvent) error {
 			snsSvc:                &common.SNS{Client: sns.New(awsSession)},
 			sqsSvc:                &common.SQSQueue{Client: sqs.New(awsSession)},
 			dbSvc:                 dbSvc,
+			accountSvc:            services.AccountService(),
+			eventSvc:              eventSvc,
 		}
 		err := handleRecord(&input)
 		if err != nil {
@@ -72,6 +103,8 @@ type handleRecordInput struct {
 	snsSvc                common.Notificationer
 	sqsSvc                common.Queue
 	dbSvc                 db.DBer
+	accountSvc            accountiface.Servicer
+	eventSvc              eventiface.Servicer
 	leaseLockedTopicArn   string
 	leaseUnlockedTopicArn string
 	resetQueueURL         string
@@ -105,6 +138,13 @@ func handleRecord(input *handleRecordInput) error {
 			return nil
 		}
 
+		// Pending leases are waiting for an account, so there's nothing to
+		// reset or publish to SNS when one is cancelled
+		if prevLeaseStatus == string(db.Pending) {
+			log.Printf("Pending lease for %s went to %s", lease.PrincipalID, nextLeaseStatus)
+			return publishLeaseEnd(input, nextLeaseStatus)
+		}
+
 		log.Printf("Transitioning from %s to %s", prevLeaseStatus, nextLeaseStatus)
 
 		// Lease is now expired if it transitioned from "Active" --> "Inactive"
@@ -118,6 +158,11 @@ func handleRecord(input *handleRecordInput) error {
 			if err != nil {
 				log.Printf("ERROR: Failed to mark AccountStatus=NotReady for %s, after lease for %s became inactive",
 					lease.AccountID, lease.PrincipalID)
+			} else {
+				err = publishAccountUpdate(input, lease.AccountID)
+				if err != nil {
+					return err
+				}
 			}
 
 			if acct == nil {
@@ -166,6 +211,11 @@ func handleRecord(input *handleRecordInput) error {
 		if err != nil {
 			return err
 		}
+
+		err = publishLeaseEnd(input, nextLeaseStatus)
+		if err != nil {
+			return err
+		}
 	default:
 	}
 
@@ -211,8 +261,64 @@ func publishLease(input *publishLeaseInput) error {
 	return nil
 }
 
+// publishLeaseEnd publishes the lease ended event, if the lease became Inactive
+func publishLeaseEnd(input *handleRecordInput, nextLeaseStatus string) error {
+	if nextLeaseStatus != string(db.Inactive) {
+		return nil
+	}
+
+	dbAttrMap, err := streamImageAttributes(input.record.Change.NewImage)
+	if err != nil {
+		return err
+	}
+	lease := dceLease.Lease{}
+	err = dynamodbattribute.UnmarshalMap(dbAttrMap, &lease)
+	if err != nil {
+		return err
+	}
+
+	err = input.eventSvc.LeaseEnd(&lease)
+	if err != nil {
+		log.Printf("Failed to publish lease ended event for lease %s @ %s: %s",
+			aws.StringValue(lease.PrincipalID), aws.StringValue(lease.AccountID), err)
+		return err
+	}
+	return nil
+}
+
+// publishAccountUpdate publishes the account updated event, after the account's status has changed
+func publishAccountUpdate(input *handleRecordInput, accountID string) error {
+	acct, err := input.accountSvc.Get(accountID)
+	if err != nil {
+		return err
+	}
+
+	err = input.eventSvc.AccountUpdate(acct)
+	if err != nil {
+		log.Printf("Failed to publish account updated event for %s: %s", accountID, err)
+		return err
+	}
+	return nil
+}
+
 // UnmarshalStreamImage converts events.DynamoDBAttributeValue to struct
 func UnmarshalStreamImage(attribute map[string]events.DynamoDBAttributeValue) (*db.Lease, error) {
+	dbAttrMap, err := streamImageAttributes(attribute)
+	if err != nil {
+		return nil, err
+	}
+
+	out := db.Lease{}
+	err = dynamodbattribute.UnmarshalMap(dbAttrMap, &out)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+
+}
+
+// streamImageAttributes converts events.DynamoDBAttributeValue to dynamodb.AttributeValue
+func streamImageAttributes(attribute map[string]events.DynamoDBAttributeValue) (map[string]*dynamodb.AttributeValue, error) {
 
 	dbAttrMap := make(map[string]*dynamodb.AttributeValue)
 
@@ -234,11 +340,5 @@ func UnmarshalStreamImage(attribute map[string]events.DynamoDBAttributeValue) (*
 		dbAttrMap[k] = &dbAttr
 	}
 
-	out := db.Lease{}
-	err := dynamodbattribute.UnmarshalMap(dbAttrMap, &out)
-	if err != nil {
-		return nil, err
-	}
-	return &out, nil
-
+	return dbAttrMap, nil
 }
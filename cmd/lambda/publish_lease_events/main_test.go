@@ -5,9 +5,13 @@ import (
 	"log"
 	"testing"
 
+	"github.com/Optum/dce/pkg/account"
+	accountMocks "github.com/Optum/dce/pkg/account/accountiface/mocks"
 	commonMocks "github.com/Optum/dce/pkg/common/mocks"
 	"github.com/Optum/dce/pkg/db"
 	dbMocks "github.com/Optum/dce/pkg/db/mocks"
+	eventMocks "github.com/Optum/dce/pkg/event/eventiface/mocks"
+	"github.com/Optum/dce/pkg/lease"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
@@ -107,6 +111,8 @@ func Test_handleRecord(t *testing.T) {
 	sqsSvc := &commonMocks.Queue{}
 	snsSvc := &commonMocks.Notificationer{}
 	dbSvc := &dbMocks.DBer{}
+	accountSvc := &accountMocks.Servicer{}
+	eventSvc := &eventMocks.Servicer{}
 
 	tests := []struct {
 		name                 string
@@ -125,6 +131,8 @@ func Test_handleRecord(t *testing.T) {
 					snsSvc:                snsSvc,
 					sqsSvc:                sqsSvc,
 					dbSvc:                 dbSvc,
+					accountSvc:            accountSvc,
+					eventSvc:              eventSvc,
 					leaseLockedTopicArn:   LockedSnsTopic,
 					leaseUnlockedTopicArn: UnlockedSnsTopic,
 					resetQueueURL:         "sqs-queue",
@@ -145,6 +153,8 @@ func Test_handleRecord(t *testing.T) {
 					snsSvc:                snsSvc,
 					sqsSvc:                sqsSvc,
 					dbSvc:                 dbSvc,
+					accountSvc:            accountSvc,
+					eventSvc:              eventSvc,
 					leaseLockedTopicArn:   LockedSnsTopic,
 					leaseUnlockedTopicArn: UnlockedSnsTopic,
 					resetQueueURL:         "sqs-queue",
@@ -165,6 +175,8 @@ func Test_handleRecord(t *testing.T) {
 					snsSvc:                snsSvc,
 					sqsSvc:                sqsSvc,
 					dbSvc:                 dbSvc,
+					accountSvc:            accountSvc,
+					eventSvc:              eventSvc,
 					leaseLockedTopicArn:   LockedSnsTopic,
 					leaseUnlockedTopicArn: UnlockedSnsTopic,
 					resetQueueURL:         "sqs-err-queue",
@@ -193,6 +205,11 @@ func Test_handleRecord(t *testing.T) {
 
 				dbSvc.On("GetAccount", "123456789012").Return(tt.getAccount, nil)
 
+				// The account is now NotReady
+				acct := &account.Account{ID: aws.String("123456789012"), Status: account.StatusNotReady.StatusPtr()}
+				accountSvc.On("Get", "123456789012").Return(acct, nil)
+				eventSvc.On("AccountUpdate", acct).Return(nil)
+
 				if tt.shouldErrorOnEnqueue {
 					sqsSvc.On("SendMessage", aws.String(tt.args.input.resetQueueURL), aws.String("{\"Id\":\"123456789012\",\"AccountStatus\":\"\",\"LastModifiedOn\":0,\"CreatedOn\":0,\"AdminRoleArn\":\"\",\"PrincipalRoleArn\":\"\",\"PrincipalPolicyHash\":\"\",\"Metadata\":null}")).Return(errors.New("error enqueuing message"))
 				} else {
@@ -200,12 +217,18 @@ func Test_handleRecord(t *testing.T) {
 				}
 			}
 			snsSvc.On("PublishMessage", &tt.expectedSnsTopic, mock.Anything, true).Return(nil, nil)
+			if tt.shoudEnqueueReset && !tt.shouldErrorOnEnqueue {
+				eventSvc.On("LeaseEnd", mock.MatchedBy(func(l *lease.Lease) bool {
+					return *l.AccountID == "123456789012" && *l.Status == lease.StatusInactive
+				})).Return(nil)
+			}
 
 			err := handleRecord(tt.args.input)
 			log.Printf("Got err value from handleRecord: %s", err)
 			sqsSvc.AssertExpectations(t)
 			snsSvc.AssertExpectations(t)
 			dbSvc.AssertExpectations(t)
+			eventSvc.AssertExpectations(t)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("handleRecord() error = %v, wantErr %v", err, tt.wantErr)
@@ -213,3 +236,47 @@ func Test_handleRecord(t *testing.T) {
 		})
 	}
 }
+
+func Test_handleRecordEndsPendingLeases(t *testing.T) {
+	var pendingImage = map[string]events.DynamoDBAttributeValue{
+		"AccountId":   events.NewStringAttribute("Pending-70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+		"principalId": events.NewStringAttribute("TestPrincipalID"),
+		"LeaseStatus": events.NewStringAttribute("Pending"),
+	}
+	var inactiveImage = map[string]events.DynamoDBAttributeValue{
+		"AccountId":   events.NewStringAttribute("Pending-70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+		"principalId": events.NewStringAttribute("TestPrincipalID"),
+		"LeaseStatus": events.NewStringAttribute("Inactive"),
+	}
+
+	sqsSvc := &commonMocks.Queue{}
+	snsSvc := &commonMocks.Notificationer{}
+	dbSvc := &dbMocks.DBer{}
+	eventSvc := &eventMocks.Servicer{}
+	eventSvc.On("LeaseEnd", mock.MatchedBy(func(l *lease.Lease) bool {
+		return *l.Status == lease.StatusInactive
+	})).Return(nil)
+
+	err := handleRecord(&handleRecordInput{
+		record: events.DynamoDBEventRecord{
+			EventName: "MODIFY",
+			Change: events.DynamoDBStreamRecord{
+				OldImage: pendingImage,
+				NewImage: inactiveImage,
+			},
+		},
+		snsSvc:                snsSvc,
+		sqsSvc:                sqsSvc,
+		dbSvc:                 dbSvc,
+		eventSvc:              eventSvc,
+		leaseLockedTopicArn:   LockedSnsTopic,
+		leaseUnlockedTopicArn: UnlockedSnsTopic,
+		resetQueueURL:         "sqs-queue",
+	})
+
+	assert.Nil(t, err)
+	sqsSvc.AssertNotCalled(t, "SendMessage", mock.Anything, mock.Anything)
+	snsSvc.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+	dbSvc.AssertNotCalled(t, "TransitionAccountStatus", mock.Anything, mock.Anything, mock.Anything)
+	eventSvc.AssertExpectations(t)
+}
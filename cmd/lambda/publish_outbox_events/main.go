@@ -0,0 +1,168 @@
+// Package main sets the handler for the Publish Outbox Events AWS Lambda
+// Function, which relays the events written to the outbox table
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/data/dataiface"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event/eventiface"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+type configuration struct {
+	// Attempts to publish an event before its entry is marked Failed
+	MaxAttempts int64 `env:"OUTBOX_MAX_ATTEMPTS" envDefault:"5"`
+	// Days a published entry is kept, so redelivered stream records are discarded
+	RetentionDays int64 `env:"OUTBOX_RETENTION_DAYS" envDefault:"7"`
+}
+
+var (
+	services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	settings *configuration
+)
+
+func init() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	settings = &configuration{}
+	if err := cfgBldr.Unmarshal(settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithOutboxDataService().
+		WithEventService().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	services = svcBldr
+}
+
+// Start the Lambda Handler
+func main() {
+	lambda.Start(handler)
+}
+
+// handler publishes the events inserted into the outbox table.
+// If any event fails to publish an error is returned, so Lambda retries
+// the batch.  Entries which were already published are skipped on a retry.
+func handler(ctx context.Context, event events.DynamoDBEvent) error {
+	var outboxSvc dataiface.OutboxData
+	if err := services.Config.GetService(&outboxSvc); err != nil {
+		log.Fatalf("Failed to configure Outbox Data service %s", err)
+	}
+	var eventSvc eventiface.Servicer
+	if err := services.Config.GetService(&eventSvc); err != nil {
+		log.Fatalf("Failed to configure Event service %s", err)
+	}
+
+	r := &relay{
+		outboxSvc:   outboxSvc,
+		eventSvc:    eventSvc,
+		maxAttempts: settings.MaxAttempts,
+		retention:   time.Duration(settings.RetentionDays) * 24 * time.Hour,
+	}
+
+	// Defer errors for later
+	deferredErrors := []error{}
+	for _, record := range event.Records {
+		err := r.handleRecord(record)
+		if err != nil {
+			deferredErrors = append(deferredErrors, err)
+		}
+	}
+
+	if len(deferredErrors) > 0 {
+		return errors.NewMultiError("Failed to publish outbox events", deferredErrors)
+	}
+
+	return nil
+}
+
+// relay publishes outbox entries and records the result on the entry
+type relay struct {
+	outboxSvc   dataiface.OutboxData
+	eventSvc    eventiface.Servicer
+	maxAttempts int64
+	retention   time.Duration
+}
+
+func (r *relay) handleRecord(record events.DynamoDBEventRecord) error {
+	// Entries are published when they're inserted.
+	// The relay's own updates, and expired entries, are ignored
+	if record.EventName != "INSERT" {
+		return nil
+	}
+
+	id := record.Change.Keys["Id"].String()
+	// Read the entry, rather than the stream image, as a retried record
+	// may have been published or attempted since
+	entry, err := r.outboxSvc.Get(id)
+	if err != nil {
+		if errors.Is(err, errors.NewNotFound("outbox entry", id)) {
+			log.Printf("Outbox entry %s no longer exists", id)
+			return nil
+		}
+		return err
+	}
+
+	if entry.Status == nil || *entry.Status != outbox.StatusPending {
+		log.Printf("Skipping outbox entry %s, which is no longer pending", id)
+		return nil
+	}
+
+	return r.publish(entry)
+}
+
+// publish the entry's event, using the entry ID as the event ID so subscribers
+// can discard duplicates.  Failures are retried until the entry's attempts
+// reach the maximum, after which the entry is left Failed for an operator.
+func (r *relay) publish(entry *outbox.Entry) error {
+	publishErr := r.eventSvc.PublishEntry(entry)
+
+	prevLastModifiedOn := entry.LastModifiedOn
+	now := time.Now()
+	attempts := aws.Int64Value(entry.Attempts) + 1
+	entry.Attempts = &attempts
+	entry.LastModifiedOn = aws.Int64(now.Unix())
+
+	if publishErr == nil {
+		log.Printf("Published %s event %s", *entry.Type, *entry.ID)
+		entry.Status = outbox.StatusPublished.StatusPtr()
+		entry.LastError = nil
+		entry.ExpiresOn = aws.Int64(now.Add(r.retention).Unix())
+		return r.outboxSvc.Write(entry, prevLastModifiedOn)
+	}
+
+	entry.LastError = aws.String(publishErr.Error())
+	if attempts >= r.maxAttempts {
+		log.Printf("ERROR: Failed to publish %s event %s after %d attempts: %s",
+			*entry.Type, *entry.ID, attempts, publishErr)
+		entry.Status = outbox.StatusFailed.StatusPtr()
+		return r.outboxSvc.Write(entry, prevLastModifiedOn)
+	}
+
+	log.Printf("Failed to publish %s event %s on attempt %d of %d: %s",
+		*entry.Type, *entry.ID, attempts, r.maxAttempts, publishErr)
+	err := r.outboxSvc.Write(entry, prevLastModifiedOn)
+	if err != nil {
+		log.Printf("Failed to record the attempt to publish %s event %s: %s", *entry.Type, *entry.ID, err)
+	}
+	return publishErr
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	dataMocks "github.com/Optum/dce/pkg/data/dataiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	eventMocks "github.com/Optum/dce/pkg/event/eventiface/mocks"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleRecord(t *testing.T) {
+	pendingEntry := func(attempts int64) *outbox.Entry {
+		return &outbox.Entry{
+			ID:             aws.String("abc-123"),
+			Type:           aws.String(outbox.TypeAccountCreated),
+			Data:           aws.String(`{"id":"123456789012"}`),
+			Status:         outbox.StatusPending.StatusPtr(),
+			Attempts:       aws.Int64(attempts),
+			CreatedOn:      aws.Int64(1573592058),
+			LastModifiedOn: aws.Int64(1573592058),
+		}
+	}
+
+	tests := []struct {
+		name             string
+		eventName        string
+		entry            *outbox.Entry
+		getErr           error
+		publishErr       error
+		expectPublish    bool
+		expectedStatus   outbox.Status
+		expectedAttempts int64
+		expectedErr      error
+	}{
+		{
+			name:             "should publish a pending entry",
+			eventName:        "INSERT",
+			entry:            pendingEntry(0),
+			expectPublish:    true,
+			expectedStatus:   outbox.StatusPublished,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "should record a failed attempt and retry",
+			eventName:        "INSERT",
+			entry:            pendingEntry(1),
+			publishErr:       errors.NewInternalServer("failed to publish", nil),
+			expectPublish:    true,
+			expectedStatus:   outbox.StatusPending,
+			expectedAttempts: 2,
+			expectedErr:      errors.NewInternalServer("failed to publish", nil),
+		},
+		{
+			name:             "should mark the entry failed after the last attempt",
+			eventName:        "INSERT",
+			entry:            pendingEntry(2),
+			publishErr:       errors.NewInternalServer("failed to publish", nil),
+			expectPublish:    true,
+			expectedStatus:   outbox.StatusFailed,
+			expectedAttempts: 3,
+		},
+		{
+			name:      "should skip an entry which was already published",
+			eventName: "INSERT",
+			entry: &outbox.Entry{
+				ID:     aws.String("abc-123"),
+				Type:   aws.String(outbox.TypeAccountCreated),
+				Status: outbox.StatusPublished.StatusPtr(),
+			},
+		},
+		{
+			name:      "should skip an entry which no longer exists",
+			eventName: "INSERT",
+			getErr:    errors.NewNotFound("outbox entry", "abc-123"),
+		},
+		{
+			name:        "should fail when the entry can't be read",
+			eventName:   "INSERT",
+			getErr:      errors.NewInternalServer("failure", nil),
+			expectedErr: errors.NewInternalServer("failure", nil),
+		},
+		{
+			name:      "should ignore updates to entries",
+			eventName: "MODIFY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outboxSvc := &dataMocks.OutboxData{}
+			eventSvc := &eventMocks.Servicer{}
+
+			outboxSvc.On("Get", "abc-123").Return(tt.entry, tt.getErr)
+			eventSvc.On("PublishEntry", mock.AnythingOfType("*outbox.Entry")).Return(tt.publishErr)
+			outboxSvc.On("Write",
+				mock.MatchedBy(func(entry *outbox.Entry) bool {
+					return *entry.Status == tt.expectedStatus &&
+						*entry.Attempts == tt.expectedAttempts &&
+						(tt.expectedStatus != outbox.StatusPublished || entry.ExpiresOn != nil) &&
+						(tt.publishErr == nil || *entry.LastError == tt.publishErr.Error())
+				}),
+				aws.Int64(1573592058),
+			).Return(nil)
+
+			r := &relay{
+				outboxSvc:   outboxSvc,
+				eventSvc:    eventSvc,
+				maxAttempts: 3,
+				retention:   24 * time.Hour,
+			}
+			err := r.handleRecord(events.DynamoDBEventRecord{
+				EventName: tt.eventName,
+				Change: events.DynamoDBStreamRecord{
+					Keys: map[string]events.DynamoDBAttributeValue{
+						"Id": events.NewStringAttribute("abc-123"),
+					},
+				},
+			})
+
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			if tt.expectPublish {
+				eventSvc.AssertCalled(t, "PublishEntry", tt.entry)
+				outboxSvc.AssertNumberOfCalls(t, "Write", 1)
+			} else {
+				eventSvc.AssertNotCalled(t, "PublishEntry", mock.Anything)
+				outboxSvc.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/team"
+)
+
+// CreateTeam - Creates a team
+func CreateTeam(w http.ResponseWriter, r *http.Request) {
+	// Deserialize the request JSON as an request object
+	newTeam := &team.Team{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newTeam)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	result, err := Services.TeamService().Create(newTeam)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusCreated, result)
+}
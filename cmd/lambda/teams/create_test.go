@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/Optum/dce/pkg/team/teamiface/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func TestWhenCreate(t *testing.T) {
+	standardHeaders := map[string][]string{
+		"Access-Control-Allow-Origin": []string{"*"},
+		"Content-Type":                []string{"application/json"},
+	}
+
+	tests := []struct {
+		name    string
+		expResp events.APIGatewayProxyResponse
+		request events.APIGatewayProxyRequest
+		retTeam *team.Team
+		retErr  error
+	}{
+		{
+			name: "When given good values. Then the team is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusCreated,
+				Body:              "{\"id\":\"abc-123\",\"name\":\"platform\",\"members\":[\"user1\"],\"budgetCurrency\":\"USD\",\"budgetPeriod\":\"MONTHLY\"}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/teams",
+				Body:       "{ \"name\": \"platform\", \"members\": [\"user1\"] }",
+			},
+			retTeam: &team.Team{
+				ID:             ptrString("abc-123"),
+				Name:           ptrString("platform"),
+				Members:        []string{"user1"},
+				BudgetCurrency: ptrString("USD"),
+				BudgetPeriod:   ptrString("MONTHLY"),
+			},
+			retErr: nil,
+		},
+		{
+			name: "When given bad values. Then a syntax error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusBadRequest,
+				Body:              "{\"error\":{\"message\":\"invalid request parameters\",\"code\":\"ClientError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/teams",
+				Body:       "{ \"name: \"platform\" }",
+			},
+			retTeam: &team.Team{},
+			retErr:  nil,
+		},
+		{
+			name: "Given internal failure. Then an internal server error is returned.",
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/teams",
+				Body:       "{ \"name\": \"platform\" }",
+			},
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusInternalServerError,
+				Body:              "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			retTeam: nil,
+			retErr:  fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			teamSvc := mocks.Servicer{}
+			teamSvc.On("Create", mock.AnythingOfType("*team.Team")).Return(
+				tt.retTeam, tt.retErr,
+			)
+			svcBldr.Config.WithService(&teamSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			resp, err := Handler(context.TODO(), tt.request)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp, resp)
+		})
+	}
+
+}
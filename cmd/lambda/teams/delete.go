@@ -0,0 +1,29 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/gorilla/mux"
+)
+
+// DeleteTeam - Deletes the team.  Leases on the team are no longer
+// held to its budget
+func DeleteTeam(w http.ResponseWriter, r *http.Request) {
+
+	teamID := mux.Vars(r)["teamId"]
+
+	result, err := Services.TeamService().Get(teamID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	err = Services.TeamService().Delete(result)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/gorilla/mux"
+)
+
+// GetTeamByID - Returns the single team by ID
+func GetTeamByID(w http.ResponseWriter, r *http.Request) {
+
+	teamID := mux.Vars(r)["teamId"]
+
+	result, err := Services.TeamService().Get(teamID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	// Users who may not see every team may only see the teams they're on
+	if api.ScopeFor(r, api.PermissionTeamsRead) != api.ScopeAll &&
+		!result.HasMember(api.UserFromContext(r.Context()).Username) {
+		api.WriteAPIErrorResponse(w,
+			errors.NewForbidden(fmt.Sprintf("user is not authorized to view team %s", teamID)))
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, result)
+}
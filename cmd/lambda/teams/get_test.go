@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/Optum/dce/pkg/team/teamiface/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTeamByID(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name    string
+		user    *api.User
+		expResp response
+		teamID  string
+		retTeam *team.Team
+		retErr  error
+	}{
+		{
+			name:   "success",
+			user:   &api.User{Role: api.AdminGroupName},
+			teamID: "abc-123",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"id\":\"abc-123\",\"name\":\"platform\",\"members\":[\"user1\"]}\n",
+			},
+			retTeam: &team.Team{
+				ID:      ptrString("abc-123"),
+				Name:    ptrString("platform"),
+				Members: []string{"user1"},
+			},
+			retErr: nil,
+		},
+		{
+			name:   "success for a member of the team",
+			user:   &api.User{Username: "user1", Role: api.UserGroupName},
+			teamID: "abc-123",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"id\":\"abc-123\",\"name\":\"platform\",\"members\":[\"user1\"]}\n",
+			},
+			retTeam: &team.Team{
+				ID:      ptrString("abc-123"),
+				Name:    ptrString("platform"),
+				Members: []string{"user1"},
+			},
+			retErr: nil,
+		},
+		{
+			name:   "forbidden for a user who isn't on the team",
+			user:   &api.User{Username: "user2", Role: api.UserGroupName},
+			teamID: "abc-123",
+			expResp: response{
+				StatusCode: 403,
+				Body:       "{\"error\":{\"message\":\"user is not authorized to view team abc-123\",\"code\":\"ForbiddenError\"}}\n",
+			},
+			retTeam: &team.Team{
+				ID:      ptrString("abc-123"),
+				Name:    ptrString("platform"),
+				Members: []string{"user1"},
+			},
+			retErr: nil,
+		},
+		{
+			name:   "failure",
+			user:   &api.User{Role: api.AdminGroupName},
+			teamID: "abc-123",
+			expResp: response{
+				StatusCode: 500,
+				Body:       "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+			},
+			retTeam: nil,
+			retErr:  fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/teams/%s", tt.teamID), nil)
+
+			r = mux.SetURLVars(r, map[string]string{
+				"teamId": tt.teamID,
+			})
+			r = r.WithContext(api.WithUser(r.Context(), tt.user))
+			w := httptest.NewRecorder()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			teamSvc := mocks.Servicer{}
+			teamSvc.On("Get", tt.teamID).Return(
+				tt.retTeam, tt.retErr,
+			)
+			svcBldr.Config.WithService(&teamSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetTeamByID(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/team"
+)
+
+// GetTeams - Returns the teams
+func GetTeams(w http.ResponseWriter, r *http.Request) {
+
+	teams, err := Services.TeamService().List()
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	// Users who may not see every team are only shown the teams they're on
+	if api.ScopeFor(r, api.PermissionTeamsRead) != api.ScopeAll {
+		username := api.UserFromContext(r.Context()).Username
+		allowed := team.Teams{}
+		for _, t := range *teams {
+			if t.HasMember(username) {
+				allowed = append(allowed, t)
+			}
+		}
+		teams = &allowed
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, teams)
+}
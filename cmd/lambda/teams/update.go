@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/gorilla/mux"
+)
+
+// UpdateTeamByID updates a team's name, leads, members or budget
+func UpdateTeamByID(w http.ResponseWriter, r *http.Request) {
+	teamID := mux.Vars(r)["teamId"]
+
+	// Deserialize the request JSON as an request object
+	newTeam := &team.Team{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newTeam)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	result, err := Services.TeamService().Update(teamID, newTeam)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, result)
+}
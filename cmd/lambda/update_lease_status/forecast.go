@@ -0,0 +1,166 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/email"
+	"github.com/pkg/errors"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// forecastBurnRateDays is how many of the most recent days of usage the burn rate is averaged over
+const forecastBurnRateDays = 7
+
+// dailySpend is a lease's spend on each day, by the epoch of the start of the day (UTC)
+type dailySpend map[int64]float64
+
+// spendForecast projects a lease's spend to its expiration,
+// at the lease's recent average daily spend
+type spendForecast struct {
+	burnRate        float64   // Average spend per day
+	projectedSpend  float64   // Spend projected to the lease's expiration
+	exceedsBudgetOn time.Time // When the projected spend passes the budget. Zero if it never does
+}
+
+// exceedsBudget is true when the projected spend passes the budget before the lease expires
+func (f *spendForecast) exceedsBudget() bool {
+	return !f.exceedsBudgetOn.IsZero()
+}
+
+// forecastLeaseSpend projects the lease's spend to its expiration from its daily usage history.
+// The burn rate is the average spend of the most recent complete days since the lease became
+// active, including days without any usage.  On the lease's first day, today's spend so far is used.
+func forecastLeaseSpend(lease *db.Lease, actualSpend float64, daily dailySpend, now time.Time) *spendForecast {
+	today := now.Unix() - now.Unix()%secondsPerDay
+	leaseStartDay := lease.LeaseStatusModifiedOn - lease.LeaseStatusModifiedOn%secondsPerDay
+
+	burnRate := daily[today]
+	if leaseStartDay < today {
+		total, days := 0.0, 0
+		for day := today - secondsPerDay; day >= leaseStartDay && days < forecastBurnRateDays; day -= secondsPerDay {
+			total += daily[day]
+			days++
+		}
+		burnRate = total / float64(days)
+	}
+
+	remainingDays := float64(lease.ExpiresOn-now.Unix()) / secondsPerDay
+	if remainingDays < 0 {
+		remainingDays = 0
+	}
+
+	forecast := &spendForecast{
+		burnRate: burnRate,
+	}
+	forecast.projectedSpend = actualSpend + forecast.burnRate*remainingDays
+
+	if forecast.projectedSpend > lease.BudgetAmount && forecast.burnRate > 0 {
+		daysUntilOverBudget := (lease.BudgetAmount - actualSpend) / forecast.burnRate
+		if daysUntilOverBudget < 0 {
+			daysUntilOverBudget = 0
+		}
+		forecast.exceedsBudgetOn = now.Add(time.Duration(daysUntilOverBudget * secondsPerDay * float64(time.Second)))
+	}
+
+	return forecast
+}
+
+// isLeaseOverForecastBudget is true when the lease's projected spend is over its budget
+// by more than the multiplier. A multiplier of zero disables ending leases on their forecast
+func isLeaseOverForecastBudget(lease *db.Lease, forecast *spendForecast, multiplier float64) bool {
+	if multiplier <= 0 {
+		return false
+	}
+	return forecast.projectedSpend > lease.BudgetAmount*multiplier
+}
+
+type sendForecastNotificationEmailInput struct {
+	lease                               *db.Lease
+	dbSvc                               db.DBer
+	emailSvc                            email.Service
+	budgetNotificationFromEmail         string
+	budgetNotificationBCCEmails         []string
+	forecastNotificationTemplateHTML    string
+	forecastNotificationTemplateText    string
+	forecastNotificationTemplateSubject string
+	actualSpend                         float64
+	forecast                            *spendForecast
+	isEndedEarly                        bool
+}
+
+// sendForecastNotificationEmail warns the lease owner when their lease is projected
+// to go over budget before it expires, or tells them the lease was ended early.
+// The warning is only sent once per lease, and is recorded on the lease when sent
+func sendForecastNotificationEmail(input *sendForecastNotificationEmailInput) error {
+	// Leases already over budget get the budget notification instead
+	if !input.forecast.exceedsBudget() || input.actualSpend >= input.lease.BudgetAmount {
+		return nil
+	}
+	if input.lease.ForecastNotifiedOn != 0 && !input.isEndedEarly {
+		log.Printf("Skipping budget forecast notification emails: "+
+			"lease %s @ %s was already notified on %d",
+			input.lease.PrincipalID, input.lease.AccountID, input.lease.ForecastNotifiedOn)
+		return nil
+	}
+
+	if len(input.lease.BudgetNotificationEmails)+len(input.budgetNotificationBCCEmails) == 0 {
+		log.Printf("Skipping budget forecast notification emails: "+
+			"no notification emails addressses were provided for lease %s @ %s",
+			input.lease.PrincipalID, input.lease.AccountID)
+		return nil
+	}
+
+	log.Printf("Sending budget forecast notification emails for lease %s @ %s: projected spend is %.2f",
+		input.lease.PrincipalID, input.lease.AccountID, input.forecast.projectedSpend)
+
+	templateData := struct {
+		Lease           db.Lease
+		ActualSpend     float64
+		ProjectedSpend  float64
+		ExceedsBudgetBy float64
+		ExceedsBudgetOn string
+		IsEndedEarly    bool
+	}{
+		Lease:           *input.lease,
+		ActualSpend:     input.actualSpend,
+		ProjectedSpend:  input.forecast.projectedSpend,
+		ExceedsBudgetBy: input.forecast.projectedSpend - input.lease.BudgetAmount,
+		ExceedsBudgetOn: input.forecast.exceedsBudgetOn.UTC().Format("2006-01-02"),
+		IsEndedEarly:    input.isEndedEarly,
+	}
+	bodyHTML, err := renderTemplate("htmlEmail", input.forecastNotificationTemplateHTML, templateData)
+	if err != nil {
+		return err
+	}
+	bodyText, err := renderTemplate("textEmail", input.forecastNotificationTemplateText, templateData)
+	if err != nil {
+		return err
+	}
+	subject, err := renderTemplate("emailSubject", input.forecastNotificationTemplateSubject, templateData)
+	if err != nil {
+		return err
+	}
+
+	err = input.emailSvc.SendEmail(&email.SendEmailInput{
+		FromAddress:  input.budgetNotificationFromEmail,
+		ToAddresses:  input.lease.BudgetNotificationEmails,
+		BCCAddresses: input.budgetNotificationBCCEmails,
+		Subject:      subject,
+		BodyHTML:     bodyHTML,
+		BodyText:     bodyText,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Record the notification, so the lease isn't warned again on the next budget check
+	_, err = input.dbSvc.UpdateLeaseForecastNotifiedOn(input.lease.AccountID, input.lease.PrincipalID, time.Now().Unix())
+	if err != nil {
+		return errors.Wrapf(err, "Failed to record budget forecast notification for lease %s @ %s",
+			input.lease.PrincipalID, input.lease.AccountID)
+	}
+	return nil
+}
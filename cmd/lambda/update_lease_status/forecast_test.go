@@ -0,0 +1,239 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/db"
+	dbMocks "github.com/Optum/dce/pkg/db/mocks"
+	"github.com/Optum/dce/pkg/email"
+	emailMocks "github.com/Optum/dce/pkg/email/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_forecastLeaseSpend(t *testing.T) {
+	today := int64(1000 * secondsPerDay)
+	now := time.Unix(today+12*60*60, 0)
+	daysAgo := func(days int64) int64 {
+		return now.Unix() - days*secondsPerDay
+	}
+	daysFromNow := func(days int64) int64 {
+		return now.Unix() + days*secondsPerDay
+	}
+	dayStart := func(daysAgo int64) int64 {
+		return today - daysAgo*secondsPerDay
+	}
+
+	tests := []struct {
+		name              string
+		leaseStart        int64
+		expiresOn         int64
+		budgetAmount      float64
+		actualSpend       float64
+		daily             dailySpend
+		expBurnRate       float64
+		expProjectedSpend float64
+		expExceedsOn      time.Time
+	}{
+		{
+			name:              "should project spend under budget",
+			leaseStart:        daysAgo(2),
+			expiresOn:         daysFromNow(2),
+			budgetAmount:      100,
+			actualSpend:       20,
+			daily:             dailySpend{dayStart(2): 10, dayStart(1): 10},
+			expBurnRate:       10,
+			expProjectedSpend: 40,
+		},
+		{
+			name:              "should project spend over budget",
+			leaseStart:        daysAgo(2),
+			expiresOn:         daysFromNow(10),
+			budgetAmount:      100,
+			actualSpend:       40,
+			daily:             dailySpend{dayStart(2): 20, dayStart(1): 20},
+			expBurnRate:       20,
+			expProjectedSpend: 240,
+			expExceedsOn:      time.Unix(daysFromNow(3), 0),
+		},
+		{
+			name:              "should use today's spend on the first day of a lease",
+			leaseStart:        now.Unix() - 60,
+			expiresOn:         daysFromNow(4),
+			budgetAmount:      100,
+			actualSpend:       30,
+			daily:             dailySpend{dayStart(0): 30},
+			expBurnRate:       30,
+			expProjectedSpend: 150,
+			expExceedsOn:      now.Add(time.Duration(70.0 / 30.0 * secondsPerDay * float64(time.Second))),
+		},
+		{
+			name:         "should use the burn rate of the most recent days",
+			leaseStart:   daysAgo(20),
+			expiresOn:    daysFromNow(2),
+			budgetAmount: 200,
+			actualSpend:  140,
+			daily: dailySpend{
+				dayStart(10): 100,
+				dayStart(7):  5, dayStart(6): 5, dayStart(5): 5, dayStart(4): 5,
+				dayStart(3): 5, dayStart(2): 5, dayStart(1): 5,
+				dayStart(0): 5,
+			},
+			expBurnRate:       5,
+			expProjectedSpend: 150,
+		},
+		{
+			name:              "should count days without usage",
+			leaseStart:        daysAgo(4),
+			expiresOn:         daysFromNow(2),
+			budgetAmount:      100,
+			actualSpend:       40,
+			daily:             dailySpend{dayStart(1): 40},
+			expBurnRate:       10,
+			expProjectedSpend: 60,
+		},
+		{
+			name:              "should not project spend past expiration",
+			leaseStart:        daysAgo(2),
+			expiresOn:         daysAgo(1),
+			budgetAmount:      100,
+			actualSpend:       20,
+			daily:             dailySpend{dayStart(2): 10, dayStart(1): 10},
+			expBurnRate:       10,
+			expProjectedSpend: 20,
+		},
+		{
+			name:              "should not project spend without usage",
+			leaseStart:        daysAgo(2),
+			expiresOn:         daysFromNow(2),
+			budgetAmount:      100,
+			actualSpend:       0,
+			daily:             dailySpend{},
+			expBurnRate:       0,
+			expProjectedSpend: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast := forecastLeaseSpend(&db.Lease{
+				BudgetAmount:          tt.budgetAmount,
+				LeaseStatusModifiedOn: tt.leaseStart,
+				ExpiresOn:             tt.expiresOn,
+			}, tt.actualSpend, tt.daily, now)
+
+			assert.Equal(t, tt.expBurnRate, forecast.burnRate)
+			assert.Equal(t, tt.expProjectedSpend, forecast.projectedSpend)
+			assert.Equal(t, tt.expExceedsOn.Unix(), forecast.exceedsBudgetOn.Unix())
+			assert.Equal(t, !tt.expExceedsOn.IsZero(), forecast.exceedsBudget())
+		})
+	}
+}
+
+func Test_isLeaseOverForecastBudget(t *testing.T) {
+	lease := &db.Lease{BudgetAmount: 100}
+	forecast := &spendForecast{projectedSpend: 250}
+
+	assert.True(t, isLeaseOverForecastBudget(lease, forecast, 2))
+	assert.False(t, isLeaseOverForecastBudget(lease, forecast, 3))
+	assert.False(t, isLeaseOverForecastBudget(lease, forecast, 0))
+}
+
+func Test_sendForecastNotificationEmail(t *testing.T) {
+	templateText := `
+{{if .IsEndedEarly}}Lease ended early.{{else}}Lease will exceed its budget of ${{.Lease.BudgetAmount}} by ${{.ExceedsBudgetBy}} on {{.ExceedsBudgetOn}}.{{end}}
+`
+	templateSubject := `Lease forecast [{{.Lease.AccountID}}]`
+	exceedsOn := time.Date(2020, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name               string
+		actualSpend        float64
+		forecast           *spendForecast
+		forecastNotifiedOn int64
+		isEndedEarly       bool
+		expBody            string
+	}{
+		{
+			name:        "should warn of spend over budget",
+			actualSpend: 50,
+			forecast:    &spendForecast{projectedSpend: 150, exceedsBudgetOn: exceedsOn},
+			expBody:     "Lease will exceed its budget of $100 by $50 on 2020-03-15.",
+		},
+		{
+			name:         "should notify of leases ended early",
+			actualSpend:  50,
+			forecast:     &spendForecast{projectedSpend: 150, exceedsBudgetOn: exceedsOn},
+			isEndedEarly: true,
+			expBody:      "Lease ended early.",
+		},
+		{
+			name:        "should not notify of spend under budget",
+			actualSpend: 50,
+			forecast:    &spendForecast{projectedSpend: 90},
+		},
+		{
+			name:        "should not notify leases already over budget",
+			actualSpend: 120,
+			forecast:    &spendForecast{projectedSpend: 150, exceedsBudgetOn: exceedsOn},
+		},
+		{
+			name:               "should not warn leases that were already warned",
+			actualSpend:        50,
+			forecast:           &spendForecast{projectedSpend: 150, exceedsBudgetOn: exceedsOn},
+			forecastNotifiedOn: 1584230400,
+		},
+		{
+			name:               "should notify of leases ended early after they were warned",
+			actualSpend:        50,
+			forecast:           &spendForecast{projectedSpend: 150, exceedsBudgetOn: exceedsOn},
+			forecastNotifiedOn: 1584230400,
+			isEndedEarly:       true,
+			expBody:            "Lease ended early.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emailSvc := &emailMocks.Service{}
+			dbSvc := &dbMocks.DBer{}
+			if tt.expBody != "" {
+				dbSvc.On("UpdateLeaseForecastNotifiedOn", "1234567890", "test-user", mock.AnythingOfType("int64")).
+					Return(&db.Lease{}, nil)
+				emailSvc.On("SendEmail", &email.SendEmailInput{
+					FromAddress:  "from@example.com",
+					ToAddresses:  []string{"recipA@example.com"},
+					BCCAddresses: []string{"bcc@example.com"},
+					Subject:      "Lease forecast [1234567890]",
+					BodyHTML:     tt.expBody,
+					BodyText:     tt.expBody,
+				}).Return(nil)
+			}
+
+			err := sendForecastNotificationEmail(&sendForecastNotificationEmailInput{
+				lease: &db.Lease{
+					AccountID:                "1234567890",
+					PrincipalID:              "test-user",
+					BudgetAmount:             100,
+					BudgetNotificationEmails: []string{"recipA@example.com"},
+					ForecastNotifiedOn:       tt.forecastNotifiedOn,
+				},
+				dbSvc:                               dbSvc,
+				emailSvc:                            emailSvc,
+				budgetNotificationFromEmail:         "from@example.com",
+				budgetNotificationBCCEmails:         []string{"bcc@example.com"},
+				forecastNotificationTemplateHTML:    templateText,
+				forecastNotificationTemplateText:    templateText,
+				forecastNotificationTemplateSubject: templateSubject,
+				actualSpend:                         tt.actualSpend,
+				forecast:                            tt.forecast,
+				isEndedEarly:                        tt.isEndedEarly,
+			})
+			require.Nil(t, err)
+			emailSvc.AssertExpectations(t)
+			dbSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -9,12 +9,18 @@ import (
 	"github.com/Optum/dce/pkg/awsiface"
 	"github.com/Optum/dce/pkg/budget"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/currency"
 	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/email"
 	multierrors "github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/Optum/dce/pkg/principal/principaliface"
+	"github.com/Optum/dce/pkg/team/teamiface"
 	"github.com/Optum/dce/pkg/usage"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/ses"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -54,13 +60,32 @@ func main() {
 			log.Fatalf("Failed to configure Usage service %s", err)
 		}
 
+		rateProvider, err := newRateProvider(awsSession)
+		if err != nil {
+			log.Fatalf("Failed to configure exchange rates %s", err)
+		}
+
+		costMetric, err := budget.ParseCostMetric(common.GetEnv("BUDGET_COST_METRIC", ""))
+		if err != nil {
+			log.Fatalf("Failed to configure budget cost metric %s", err)
+		}
+		budgetSvc := &budget.AWSBudgetService{
+			CostMetric:          costMetric,
+			ExcludedRecordTypes: common.RequireEnvStringSlice("BUDGET_EXCLUDED_RECORD_TYPES", ","),
+		}
+
+		svcBldr := newServiceBuilder()
+
 		err = lambdaHandler(&lambdaHandlerInput{
 			dbSvc:                                  dbSvc,
+			teamSvc:                                svcBldr.TeamService(),
+			principalSvc:                           svcBldr.PrincipalService(),
 			lease:                                  lease,
 			awsSession:                             awsSession,
 			tokenSvc:                               tokenSvc,
-			budgetSvc:                              &budget.AWSBudgetService{},
+			budgetSvc:                              budgetSvc,
 			usageSvc:                               usageSvc,
+			rateProvider:                           rateProvider,
 			sqsSvc:                                 sqs.New(awsSession),
 			snsSvc:                                 &common.SNS{Client: sns.New(awsSession)},
 			leaseLockedTopicArn:                    common.RequireEnv("LEASE_LOCKED_TOPIC_ARN"),
@@ -73,6 +98,14 @@ func main() {
 			budgetNotificationThresholdPercentiles: common.RequireEnvFloatSlice("BUDGET_NOTIFICATION_THRESHOLD_PERCENTILES", ","),
 			principalBudgetAmount:                  common.RequireEnvFloat("PRINCIPAL_BUDGET_AMOUNT"),
 			principalBudgetPeriod:                  common.RequireEnv("PRINCIPAL_BUDGET_PERIOD"),
+			principalBudgetCurrency:                common.GetEnv("PRINCIPAL_BUDGET_CURRENCY", currency.USD),
+			usageBreakdownByRegion:                 common.DefaultEnvConfig{}.GetEnvBoolVar("USAGE_BREAKDOWN_BY_REGION", false),
+			costMetric:                             string(costMetric),
+			forecastNotificationEnabled:            common.DefaultEnvConfig{}.GetEnvBoolVar("BUDGET_FORECAST_NOTIFICATION_ENABLED", false),
+			forecastNotificationTemplateHTML:       common.GetEnv("BUDGET_FORECAST_NOTIFICATION_TEMPLATE_HTML", ""),
+			forecastNotificationTemplateText:       common.GetEnv("BUDGET_FORECAST_NOTIFICATION_TEMPLATE_TEXT", ""),
+			forecastNotificationTemplateSubject:    common.GetEnv("BUDGET_FORECAST_NOTIFICATION_TEMPLATE_SUBJECT", ""),
+			forecastTerminationMultiplier:          common.DefaultEnvConfig{}.GetEnvFloatVar("FORECAST_TERMINATION_MULTIPLIER", 0),
 			usageTTL:                               common.RequireEnvInt("USAGE_TTL"),
 		})
 		if err != nil {
@@ -83,6 +116,41 @@ func main() {
 	})
 }
 
+// newRateProvider loads the exchange rates for converting spend into budget currencies.
+// Without an exchange rates file, only USD budgets are supported
+func newRateProvider(awsSession *session.Session) (currency.RateProvider, error) {
+	key := common.GetEnv("EXCHANGE_RATES_S3_KEY", "")
+	if key == "" {
+		return &currency.StaticRateProvider{Base: currency.USD}, nil
+	}
+	return currency.NewStaticRateProviderFromS3(
+		&common.S3{Client: s3.New(awsSession)},
+		common.RequireEnv("ARTIFACTS_BUCKET"),
+		key,
+	)
+}
+
+// newServiceBuilder configures the services for the teams whose budgets leases share,
+// and for the profiles which override the principal budget
+func newServiceBuilder() *config.ServiceBuilder {
+	cfgBldr := &config.ConfigurationBuilder{}
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithTeamService().
+		WithPrincipalService().
+		Build()
+	if err != nil {
+		log.Fatalf("Failed to configure Team and Principal services %s", err)
+	}
+
+	return svcBldr
+}
+
 func eventToLease(leaseEvent interface{}) (*db.Lease, error) {
 	// Convert the interface to JSON
 	mapJSON, err := json.Marshal(leaseEvent)
@@ -101,11 +169,14 @@ func eventToLease(leaseEvent interface{}) (*db.Lease, error) {
 
 type lambdaHandlerInput struct {
 	dbSvc                                  db.DBer
+	teamSvc                                teamiface.Servicer
+	principalSvc                           principaliface.Servicer
 	lease                                  *db.Lease
 	awsSession                             awsiface.AwsSession
 	tokenSvc                               common.TokenService
 	budgetSvc                              budget.Service
 	usageSvc                               usage.DBer
+	rateProvider                           currency.RateProvider
 	snsSvc                                 common.Notificationer
 	leaseLockedTopicArn                    string
 	sqsSvc                                 awsiface.SQSAPI
@@ -118,7 +189,15 @@ type lambdaHandlerInput struct {
 	budgetNotificationThresholdPercentiles []float64
 	principalBudgetAmount                  float64
 	principalBudgetPeriod                  string
+	principalBudgetCurrency                string
+	usageBreakdownByRegion                 bool
+	costMetric                             string
 	usageTTL                               int // TTL in seconds for Usage DynamoDB records
+	forecastNotificationEnabled            bool
+	forecastNotificationTemplateHTML       string
+	forecastNotificationTemplateText       string
+	forecastNotificationTemplateSubject    string
+	forecastTerminationMultiplier          float64 // End leases early when their forecasted spend is over budget by this multiplier. Zero to disable
 }
 
 func lambdaHandler(input *lambdaHandlerInput) error {
@@ -136,16 +215,28 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 			input.lease.AccountID, input.lease.PrincipalID)
 	}
 
+	// The principal's profile may override the default principal budget
+	limits, err := input.principalSvc.Limits(input.lease.PrincipalID, principal.Limits{
+		PrincipalBudgetAmount: input.principalBudgetAmount,
+		PrincipalBudgetPeriod: input.principalBudgetPeriod,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to lookup limits for principal %s", leaseLogID)
+	}
+
 	// Calculate actual spend for the lease
-	actualLeaseSpend, err := calculateLeaseSpend(&calculateSpendInput{
-		account:               account,
-		lease:                 input.lease,
-		tokenSvc:              input.tokenSvc,
-		budgetSvc:             input.budgetSvc,
-		usageSvc:              input.usageSvc,
-		awsSession:            input.awsSession,
-		principalBudgetPeriod: input.principalBudgetPeriod,
-		usageTTL:              input.usageTTL,
+	actualLeaseSpend, leaseSpendBreakdown, leaseDailySpend, err := calculateLeaseSpend(&calculateSpendInput{
+		account:                account,
+		lease:                  input.lease,
+		tokenSvc:               input.tokenSvc,
+		budgetSvc:              input.budgetSvc,
+		usageSvc:               input.usageSvc,
+		rateProvider:           input.rateProvider,
+		awsSession:             input.awsSession,
+		principalBudgetPeriod:  limits.PrincipalBudgetPeriod,
+		usageBreakdownByRegion: input.usageBreakdownByRegion,
+		costMetric:             input.costMetric,
+		usageTTL:               input.usageTTL,
 	})
 	if err != nil {
 		return errors.Wrapf(err, "Failed to calculate spend for lease %s", leaseLogID)
@@ -153,23 +244,48 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 
 	// Calculate actual spend for the principal
 	actualPrincipalSpend, err := calculatePrincipalSpend(&calculateSpendInput{
-		account:               account,
-		lease:                 input.lease,
-		tokenSvc:              input.tokenSvc,
-		budgetSvc:             input.budgetSvc,
-		usageSvc:              input.usageSvc,
-		awsSession:            input.awsSession,
-		principalBudgetPeriod: input.principalBudgetPeriod,
+		account:                 account,
+		lease:                   input.lease,
+		tokenSvc:                input.tokenSvc,
+		budgetSvc:               input.budgetSvc,
+		usageSvc:                input.usageSvc,
+		rateProvider:            input.rateProvider,
+		awsSession:              input.awsSession,
+		principalBudgetPeriod:   limits.PrincipalBudgetPeriod,
+		principalBudgetCurrency: input.principalBudgetCurrency,
 	})
 	if err != nil {
 		return errors.Wrapf(err, "Failed to calculate spend for principal %s", leaseLogID)
 	}
 
+	// Calculate actual spend for the lease's team, which its leases share a budget with
+	var leaseTeamBudget *teamBudget
+	if input.lease.TeamID != "" {
+		leaseTeamBudget, err = calculateTeamSpend(&calculateSpendInput{
+			lease:        input.lease,
+			usageSvc:     input.usageSvc,
+			rateProvider: input.rateProvider,
+			dbSvc:        input.dbSvc,
+			teamSvc:      input.teamSvc,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Failed to calculate spend for team of lease %s", leaseLogID)
+		}
+	}
+
 	// Defer errors until the end, so we can continue on error
 	deferredErrors := []error{}
 	currentTimeEpoch := time.Now().Unix()
 
-	expired, reason := isLeaseExpired(input.lease, &leaseContext{currentTimeEpoch, actualLeaseSpend}, actualPrincipalSpend, input.principalBudgetAmount)
+	expired, reason := isLeaseExpired(input.lease, &leaseContext{currentTimeEpoch, actualLeaseSpend}, actualPrincipalSpend, limits.PrincipalBudgetAmount, leaseTeamBudget)
+
+	// Project spend to the lease's expiration, at its recent daily spend
+	forecast := forecastLeaseSpend(input.lease, actualLeaseSpend, leaseDailySpend, time.Unix(currentTimeEpoch, 0))
+	if !expired && isLeaseOverForecastBudget(input.lease, forecast, input.forecastTerminationMultiplier) {
+		log.Printf("Lease %s is projected to spend %.2f of its %.2f budget",
+			leaseLogID, forecast.projectedSpend, input.lease.BudgetAmount)
+		expired, reason = true, db.LeaseOverForecastBudget
+	}
 
 	if expired {
 		// Update the lease status with the inactive status and current end time.
@@ -192,6 +308,7 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 		budgetNotificationTemplateSubject:      input.budgetNotificationTemplateSubject,
 		budgetNotificationThresholdPercentiles: input.budgetNotificationThresholdPercentiles,
 		actualLeaseSpend:                       actualLeaseSpend,
+		leaseSpendBreakdown:                    leaseSpendBreakdown,
 		actualPrincipalSpend:                   actualPrincipalSpend,
 	})
 	if err != nil {
@@ -200,6 +317,28 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 		deferredErrors = append(deferredErrors, err)
 	}
 
+	// Send notification emails, for leases projected to go over budget
+	if input.forecastNotificationEnabled && (!expired || reason == db.LeaseOverForecastBudget) {
+		err = sendForecastNotificationEmail(&sendForecastNotificationEmailInput{
+			lease:                               input.lease,
+			dbSvc:                               input.dbSvc,
+			emailSvc:                            input.emailSvc,
+			budgetNotificationFromEmail:         input.budgetNotificationFromEmail,
+			budgetNotificationBCCEmails:         input.budgetNotificationBCCEmails,
+			forecastNotificationTemplateHTML:    input.forecastNotificationTemplateHTML,
+			forecastNotificationTemplateText:    input.forecastNotificationTemplateText,
+			forecastNotificationTemplateSubject: input.forecastNotificationTemplateSubject,
+			actualSpend:                         actualLeaseSpend,
+			forecast:                            forecast,
+			isEndedEarly:                        expired,
+		})
+		if err != nil {
+			log.Printf("Failed to send budget forecast notification emails for lease %s @ %s: %s",
+				input.lease.PrincipalID, input.lease.AccountID, err)
+			deferredErrors = append(deferredErrors, err)
+		}
+	}
+
 	// Return deferred errors
 	if len(deferredErrors) > 0 {
 		return multierrors.NewMultiError("Budget check failed: ", deferredErrors)
@@ -209,8 +348,9 @@ func lambdaHandler(input *lambdaHandlerInput) error {
 }
 
 // isLeaseExpried contains the logic for determining if a lease has already
-// expired, given the context.
-func isLeaseExpired(lease *db.Lease, context *leaseContext, actualPrincipalSpend float64, principalBudgetAmount float64) (bool, db.LeaseStatusReason) {
+// expired, given the context.  The team budget is nil for leases which aren't
+// held to a team's budget
+func isLeaseExpired(lease *db.Lease, context *leaseContext, actualPrincipalSpend float64, principalBudgetAmount float64, team *teamBudget) (bool, db.LeaseStatusReason) {
 
 	if context.expireDate >= lease.ExpiresOn {
 		return true, db.LeaseExpired
@@ -218,6 +358,8 @@ func isLeaseExpired(lease *db.Lease, context *leaseContext, actualPrincipalSpend
 		return true, db.LeaseOverBudget
 	} else if actualPrincipalSpend > principalBudgetAmount {
 		return true, db.LeaseOverPrincipalBudget
+	} else if team != nil && team.actualSpend > team.budgetAmount {
+		return true, db.LeaseOverTeamBudget
 	}
 
 	return false, db.LeaseActive
@@ -7,12 +7,19 @@ import (
 	"time"
 
 	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/budget"
 	budgetMocks "github.com/Optum/dce/pkg/budget/mocks"
 	commonMocks "github.com/Optum/dce/pkg/common/mocks"
+	"github.com/Optum/dce/pkg/currency"
 	"github.com/Optum/dce/pkg/db"
 	dbMocks "github.com/Optum/dce/pkg/db/mocks"
 	"github.com/Optum/dce/pkg/email"
 	emailMocks "github.com/Optum/dce/pkg/email/mocks"
+	apiErrors "github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	principalMocks "github.com/Optum/dce/pkg/principal/principaliface/mocks"
+	"github.com/Optum/dce/pkg/team"
+	teamMocks "github.com/Optum/dce/pkg/team/teamiface/mocks"
 	"github.com/Optum/dce/pkg/usage"
 	usageMocks "github.com/Optum/dce/pkg/usage/mocks"
 	"github.com/stretchr/testify/assert"
@@ -44,6 +51,8 @@ Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
 has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of ${{.Lease.BudgetAmount}}.
 Actual spend is ${{.ActualSpend}}
 {{end}}
+{{range .TopServices}}{{.Name}}: ${{.CostAmount}}
+{{end}}
 `
 	emailTemplateSubject := `
 Lease {{if .IsOverBudget}}over budget{{else}}at {{.ThresholdPercentile}}% of budget{{end}} [{{.Lease.AccountID}}]
@@ -60,11 +69,14 @@ has exceeded its budget of $100. Actual spend is $150
 	expectedOverBudgetEmailText := strings.TrimSpace(`
 Lease for principal test-user in AWS Account 1234567890
 has exceeded its budget of $100. Actual spend is $150
+
+Amazon EC2: $150
 `)
 	expectedOverBudgetText := "Lease over budget [1234567890]"
 
 	type checkBudgetTestInput struct {
 		budgetAmount                  float64
+		budgetCurrency                string
 		actualSpend                   float64
 		leaseStatus                   db.LeaseStatus
 		expectedLeaseStatusTransition db.LeaseStatus
@@ -87,22 +99,39 @@ has exceeded its budget of $100. Actual spend is $150
 		snsSvc := &commonMocks.Notificationer{}
 		sqsSvc := &awsMocks.SQSAPI{}
 		emailSvc := &emailMocks.Service{}
+		// The principal doesn't have a profile, so is held to the default principal budget
+		principalSvc := &principalMocks.Servicer{}
+		principalSvc.On("Limits", "test-user", mock.AnythingOfType("principal.Limits")).Return(
+			func(ID string, defaults principal.Limits) *principal.Limits {
+				return &defaults
+			}, nil)
+		budgetCurrency := test.budgetCurrency
+		if budgetCurrency == "" {
+			budgetCurrency = "USD"
+		}
 		input := &lambdaHandlerInput{
-			dbSvc: dbSvc,
+			dbSvc:        dbSvc,
+			principalSvc: principalSvc,
 			lease: &db.Lease{
 				AccountID:                "1234567890",
 				PrincipalID:              "test-user",
 				LeaseStatus:              test.leaseStatus,
 				BudgetAmount:             test.budgetAmount,
-				BudgetCurrency:           "USD",
+				BudgetCurrency:           budgetCurrency,
 				BudgetNotificationEmails: []string{"recipA@example.com", "recipB@example.com"},
 				LeaseStatusModifiedOn:    time.Unix(100, 0).Unix(),
 				ExpiresOn:                time.Now().AddDate(0, 0, +1000).Unix(), //Make sure it expires in the distant future as we aren't testing that
 			},
-			awsSession:                             &awsMocks.AwsSession{},
-			tokenSvc:                               tokenSvc,
-			budgetSvc:                              budgetSvc,
-			usageSvc:                               usageSvc,
+			awsSession: &awsMocks.AwsSession{},
+			tokenSvc:   tokenSvc,
+			budgetSvc:  budgetSvc,
+			usageSvc:   usageSvc,
+			rateProvider: &currency.StaticRateProvider{
+				Base: "USD",
+				Rates: map[string]float64{
+					"EUR": 0.5,
+				},
+			},
 			snsSvc:                                 snsSvc,
 			leaseLockedTopicArn:                    "lease-locked",
 			sqsSvc:                                 sqsSvc,
@@ -114,6 +143,8 @@ has exceeded its budget of $100. Actual spend is $150
 			budgetNotificationTemplateSubject:      emailTemplateSubject,
 			budgetNotificationThresholdPercentiles: []float64{75, 100},
 			principalBudgetAmount:                  1000,
+			principalBudgetCurrency:                "USD",
+			costMetric:                             "UnblendedCost",
 			usageTTL:                               3600,
 		}
 
@@ -137,7 +168,17 @@ has exceeded its budget of $100. Actual spend is $150
 		budgetSvc.On("CalculateTotalSpend",
 			startDate,
 			endDate,
-		).Return(test.actualSpend, nil)
+		).Return(test.actualSpend, "USD", nil)
+		breakdown := &budget.SpendBreakdown{
+			Services: map[string]float64{
+				"Amazon EC2": test.actualSpend,
+			},
+		}
+		budgetSvc.On("CalculateSpendBreakdown",
+			startDate,
+			endDate,
+			false,
+		).Return(breakdown, nil)
 
 		// Expected Usage DB entry
 		inputUsage, err := usage.NewUsage(
@@ -148,7 +189,11 @@ has exceeded its budget of $100. Actual spend is $150
 				EndDate:      usageEndDate.Unix(),
 				CostAmount:   test.actualSpend,
 				CostCurrency: "USD",
+				CostMetric:   "UnblendedCost",
 				TimeToLive:   startDate.Add(time.Duration(3600) * time.Second).Unix(),
+				Breakdown: &usage.CostBreakdown{
+					Services: breakdown.Services,
+				},
 			},
 		)
 		assert.Nil(t, err)
@@ -246,10 +291,55 @@ Actual spend is $76
 Lease for principal test-user in AWS Account 1234567890
 has exceeded the 75% threshold limit for its budget of $100.
 Actual spend is $76
+
+Amazon EC2: $76
+`),
+		})
+	})
+
+	t.Run("Scenario: Over Threshold Lease in another currency", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			// 150 USD is 75 EUR, >75% of budget
+			budgetAmount:   100,
+			budgetCurrency: "EUR",
+			actualSpend:    150,
+			leaseStatus:    db.Active,
+			// Should not finance lock or reset
+			shouldTransitionLeaseStatus: false,
+			shouldSNS:                   false,
+			shouldSQSReset:              false,
+			// Should send notification email
+			shouldSendEmail:      true,
+			expectedEmailSubject: "Lease at 75% of budget [1234567890]",
+			expectedEmailBodyHTML: strings.TrimSpace(`
+<p>
+
+Lease for principal test-user in AWS Account 1234567890
+has exceeded the 75% threshold limit for its budget of $100.
+Actual spend is $75
+
+</p>
+`),
+			expectedEmailBodyText: strings.TrimSpace(`
+Lease for principal test-user in AWS Account 1234567890
+has exceeded the 75% threshold limit for its budget of $100.
+Actual spend is $75
+
+Amazon EC2: $75
 `),
 		})
 	})
 
+	t.Run("should fail on a currency without an exchange rate", func(t *testing.T) {
+		checkBudgetTest(&checkBudgetTestInput{
+			budgetAmount:   100,
+			budgetCurrency: "JPY",
+			actualSpend:    50,
+			leaseStatus:    db.Active,
+			expectedError:  "no exchange rate for currency \"JPY\"",
+		})
+	})
+
 	t.Run("Scenario: Under Budget Lease", func(t *testing.T) {
 		checkBudgetTest(&checkBudgetTestInput{
 			// <75% of budget
@@ -297,6 +387,7 @@ func Test_isLeaseExpired(t *testing.T) {
 		lease                *db.Lease
 		context              *leaseContext
 		actualPrincipalSpend float64
+		team                 *teamBudget
 	}
 	emails := []string{"joe@example.com"}
 	principalBudgetAmount := 7000.00
@@ -318,28 +409,48 @@ func Test_isLeaseExpired(t *testing.T) {
 		&leaseContext{
 			time.Now().AddDate(0, 0, -1).Unix(),
 			10},
-		10}
+		10,
+		nil}
 
 	expiredLeaseTestArgs := &args{
 		lease,
 		&leaseContext{
 			time.Now().AddDate(0, 0, +1).Unix(),
 			10},
-		10}
+		10,
+		nil}
 
 	overBudgetTest := &args{
 		lease,
 		&leaseContext{
 			time.Now().AddDate(0, 0, -1).Unix(),
 			5000},
-		5000}
+		5000,
+		nil}
 
 	overPrincipalBudgetAmountTest := &args{
 		lease,
 		&leaseContext{
 			time.Now().AddDate(0, 0, -1).Unix(),
 			2500},
-		9000}
+		9000,
+		nil}
+
+	overTeamBudgetTest := &args{
+		lease,
+		&leaseContext{
+			time.Now().AddDate(0, 0, -1).Unix(),
+			2500},
+		2500,
+		&teamBudget{12000, 10000}}
+
+	underTeamBudgetTest := &args{
+		lease,
+		&leaseContext{
+			time.Now().AddDate(0, 0, -1).Unix(),
+			2500},
+		2500,
+		&teamBudget{8000, 10000}}
 
 	tests := []struct {
 		name  string
@@ -351,10 +462,12 @@ func Test_isLeaseExpired(t *testing.T) {
 		{"Expired lease test", *expiredLeaseTestArgs, true, db.LeaseExpired},
 		{"Over budget lease test", *overBudgetTest, true, db.LeaseOverBudget},
 		{"Over principal budget amount test", *overPrincipalBudgetAmountTest, true, db.LeaseOverPrincipalBudget},
+		{"Over team budget amount test", *overTeamBudgetTest, true, db.LeaseOverTeamBudget},
+		{"Under team budget amount test", *underTeamBudgetTest, false, db.LeaseActive},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, got1 := isLeaseExpired(tt.args.lease, tt.args.context, tt.args.actualPrincipalSpend, principalBudgetAmount)
+			got, got1 := isLeaseExpired(tt.args.lease, tt.args.context, tt.args.actualPrincipalSpend, principalBudgetAmount, tt.args.team)
 			if got != tt.want {
 				t.Errorf("isLeaseExpired() got = %v, want %v", got, tt.want)
 			}
@@ -365,6 +478,166 @@ func Test_isLeaseExpired(t *testing.T) {
 	}
 }
 
+func TestCalculateTeamSpend(t *testing.T) {
+	newInput := func(teamSvc *teamMocks.Servicer) *calculateSpendInput {
+		dbSvc := &dbMocks.DBer{}
+		dbSvc.On("FindLeasesByPrincipal", "user1").Return([]*db.Lease{
+			{PrincipalID: "user1", AccountID: "111111111111", TeamID: "team-1"},
+			{PrincipalID: "user1", AccountID: "222222222222"},
+		}, nil)
+		dbSvc.On("FindLeasesByPrincipal", "user2").Return([]*db.Lease{
+			{PrincipalID: "user2", AccountID: "333333333333", TeamID: "team-1"},
+			{PrincipalID: "user2", AccountID: "333333333333", TeamID: "team-1"},
+		}, nil)
+		dbSvc.On("FindLeasesByPrincipal", "former-member").Return([]*db.Lease{
+			{PrincipalID: "former-member", AccountID: "444444444444", TeamID: "team-1"},
+		}, nil)
+
+		usageSvc := &usageMocks.DBer{}
+		usageSvc.On("GetUsageByDateRange", mock.Anything, mock.Anything).Return([]*usage.Usage{
+			{
+				PrincipalID:  ptrString("user1"),
+				CostAmount:   ptrFloat64(300),
+				CostCurrency: ptrString("USD"),
+				AccountCosts: map[string]float64{"111111111111": 100, "222222222222": 200},
+			},
+			{
+				PrincipalID:  ptrString("user2"),
+				AccountID:    ptrString("333333333333"),
+				CostAmount:   ptrFloat64(50),
+				CostCurrency: ptrString("USD"),
+			},
+			{
+				PrincipalID:  ptrString("former-member"),
+				AccountID:    ptrString("444444444444"),
+				CostAmount:   ptrFloat64(25),
+				CostCurrency: ptrString("USD"),
+			},
+			{
+				PrincipalID:  ptrString("other"),
+				AccountID:    ptrString("555555555555"),
+				CostAmount:   ptrFloat64(1000),
+				CostCurrency: ptrString("USD"),
+			},
+		}, nil)
+
+		return &calculateSpendInput{
+			lease: &db.Lease{
+				PrincipalID: "former-member",
+				AccountID:   "444444444444",
+				TeamID:      "team-1",
+			},
+			usageSvc: usageSvc,
+			rateProvider: &currency.StaticRateProvider{
+				Base:  "USD",
+				Rates: map[string]float64{"EUR": 0.5},
+			},
+			dbSvc:   dbSvc,
+			teamSvc: teamSvc,
+		}
+	}
+
+	t.Run("should sum the spend of the leases on the team", func(t *testing.T) {
+		teamSvc := &teamMocks.Servicer{}
+		teamSvc.On("Get", "team-1").Return(&team.Team{
+			ID:             ptrString("team-1"),
+			Leads:          []string{"user1"},
+			Members:        []string{"user1", "user2"},
+			BudgetAmount:   ptrFloat64(500),
+			BudgetCurrency: ptrString("EUR"),
+			BudgetPeriod:   ptrString("MONTHLY"),
+		}, nil)
+
+		result, err := calculateTeamSpend(newInput(teamSvc))
+		require.Nil(t, err)
+		// Spend of 175 USD on the team's accounts, converted to EUR
+		require.Equal(t, &teamBudget{actualSpend: 87.5, budgetAmount: 500}, result)
+	})
+
+	t.Run("should not have a budget for a team without one", func(t *testing.T) {
+		teamSvc := &teamMocks.Servicer{}
+		teamSvc.On("Get", "team-1").Return(&team.Team{
+			ID:      ptrString("team-1"),
+			Members: []string{"user1", "user2"},
+		}, nil)
+
+		result, err := calculateTeamSpend(newInput(teamSvc))
+		require.Nil(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("should not have a budget for a deleted team", func(t *testing.T) {
+		teamSvc := &teamMocks.Servicer{}
+		teamSvc.On("Get", "team-1").Return(nil, apiErrors.NewNotFound("team", "team-1"))
+
+		result, err := calculateTeamSpend(newInput(teamSvc))
+		require.Nil(t, err)
+		require.Nil(t, result)
+	})
+
+	t.Run("should only sum spend while the team's leases held their accounts", func(t *testing.T) {
+		day := int64(24 * 60 * 60)
+		teamSvc := &teamMocks.Servicer{}
+		teamSvc.On("Get", "team-1").Return(&team.Team{
+			ID:           ptrString("team-1"),
+			Members:      []string{"user1"},
+			BudgetAmount: ptrFloat64(500),
+		}, nil)
+
+		dbSvc := &dbMocks.DBer{}
+		dbSvc.On("FindLeasesByPrincipal", "user1").Return([]*db.Lease{
+			{
+				PrincipalID:           "user1",
+				AccountID:             "111111111111",
+				TeamID:                "team-1",
+				LeaseStatus:           db.Inactive,
+				CreatedOn:             day,
+				LeaseStatusModifiedOn: 2*day + 100,
+			},
+			{
+				PrincipalID: "user1",
+				AccountID:   "111111111111",
+				LeaseStatus: db.Active,
+				CreatedOn:   4 * day,
+			},
+		}, nil)
+
+		usageRecord := func(startDate int64, costAmount float64) *usage.Usage {
+			return &usage.Usage{
+				PrincipalID:  ptrString("user1"),
+				AccountID:    ptrString("111111111111"),
+				StartDate:    &startDate,
+				CostAmount:   &costAmount,
+				CostCurrency: ptrString("USD"),
+			}
+		}
+		usageSvc := &usageMocks.DBer{}
+		usageSvc.On("GetUsageByDateRange", mock.Anything, mock.Anything).Return([]*usage.Usage{
+			usageRecord(day, 10),
+			usageRecord(2*day, 20),
+			usageRecord(4*day, 40),
+			usageRecord(5*day, 80),
+		}, nil)
+
+		result, err := calculateTeamSpend(&calculateSpendInput{
+			lease: &db.Lease{
+				PrincipalID: "user1",
+				AccountID:   "111111111111",
+				TeamID:      "team-1",
+			},
+			usageSvc: usageSvc,
+			rateProvider: &currency.StaticRateProvider{
+				Base: "USD",
+			},
+			dbSvc:   dbSvc,
+			teamSvc: teamSvc,
+		})
+		require.Nil(t, err)
+		// Spend on the days of the team lease, but not of the later personal lease
+		require.Equal(t, &teamBudget{actualSpend: 30, budgetAmount: 500}, result)
+	})
+}
+
 func TestGetBeginningOfCurrentBillingPeriod(t *testing.T) {
 
 	actualOutput := getBeginningOfCurrentBillingPeriod("WEEKLY")
@@ -379,3 +652,13 @@ func TestGetBeginningOfCurrentBillingPeriod(t *testing.T) {
 	require.NotNil(t, actualOutput)
 	require.Equal(t, expectedOutput, actualOutput)
 }
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func ptrFloat64(f float64) *float64 {
+	ptrF := f
+	return &ptrF
+}
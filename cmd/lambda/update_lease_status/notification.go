@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"github.com/Optum/dce/pkg/db"
 	"github.com/Optum/dce/pkg/email"
+	"github.com/Optum/dce/pkg/usage"
 	"html/template"
 	"log"
 	"sort"
 	"strings"
 )
 
+// budgetNotificationTopServices is the number of AWS services
+// listed in budget notification emails, most expensive first
+const budgetNotificationTopServices = 5
+
 type sendBudgetNotificationEmailInput struct {
 	lease                                  *db.Lease
 	emailSvc                               email.Service
@@ -21,6 +26,7 @@ type sendBudgetNotificationEmailInput struct {
 	budgetNotificationThresholdPercentiles []float64
 	actualLeaseSpend                       float64
 	actualPrincipalSpend                   float64
+	leaseSpendBreakdown                    usage.CostBreakdown
 }
 
 func sendBudgetNotificationEmail(input *sendBudgetNotificationEmailInput) error {
@@ -74,6 +80,7 @@ func sendBudgetNotificationEmail(input *sendBudgetNotificationEmailInput) error
 		budgetNotificationTemplateText:    input.budgetNotificationTemplateText,
 		budgetNotificationTemplateSubject: input.budgetNotificationTemplateSubject,
 		actualSpend:                       actualSpend,
+		topServices:                       input.leaseSpendBreakdown.TopServices(budgetNotificationTopServices),
 	}, thresholdPercentile)
 }
 
@@ -120,6 +127,7 @@ type sendEmailInput struct {
 	budgetNotificationTemplateText    string
 	budgetNotificationTemplateSubject string
 	actualSpend                       float64
+	topServices                       []usage.Cost
 }
 
 func sendEmail(input *sendEmailInput, thresholdPercentile float64) error {
@@ -130,11 +138,13 @@ func sendEmail(input *sendEmailInput, thresholdPercentile float64) error {
 		ActualSpend         float64
 		IsOverBudget        bool
 		ThresholdPercentile int
+		TopServices         []usage.Cost
 	}{
 		Lease:               *input.lease,
 		ActualSpend:         input.actualSpend,
 		IsOverBudget:        input.actualSpend >= input.lease.BudgetAmount,
 		ThresholdPercentile: int(thresholdPercentile),
+		TopServices:         input.topServices,
 	}
 	bodyHTML, err := renderTemplate("htmlEmail", input.budgetNotificationTemplateHTML, templateData)
 	if err != nil {
@@ -2,35 +2,54 @@ package main
 
 import (
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/Optum/dce/pkg/awsiface"
 	"github.com/Optum/dce/pkg/budget"
 	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/currency"
 	"github.com/Optum/dce/pkg/db"
+	apiErrors "github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/team/teamiface"
 	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/costexplorer"
 	"github.com/pkg/errors"
 )
 
 type calculateSpendInput struct {
-	account               *db.Account
-	lease                 *db.Lease
-	tokenSvc              common.TokenService
-	budgetSvc             budget.Service
-	usageSvc              usage.DBer
-	awsSession            awsiface.AwsSession
-	principalBudgetPeriod string
-	usageTTL              int // TTL in seconds for Usage DynamoDB records
+	account                 *db.Account
+	lease                   *db.Lease
+	tokenSvc                common.TokenService
+	budgetSvc               budget.Service
+	usageSvc                usage.DBer
+	rateProvider            currency.RateProvider
+	awsSession              awsiface.AwsSession
+	principalBudgetPeriod   string
+	principalBudgetCurrency string
+	usageBreakdownByRegion  bool
+	costMetric              string
+	usageTTL                int // TTL in seconds for Usage DynamoDB records
+	dbSvc                   db.DBer
+	teamSvc                 teamiface.Servicer
 }
 
-// calculateLeaseSpend calculates amount spent by User principal for current lease
-func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
+// teamBudget is the spend of the leases on a team, and the budget they share
+type teamBudget struct {
+	actualSpend  float64
+	budgetAmount float64
+}
+
+// calculateLeaseSpend calculates amount spent by User principal for current lease,
+// the AWS services and regions it was spent on, and what was spent each day,
+// in the lease's budget currency
+func calculateLeaseSpend(input *calculateSpendInput) (float64, usage.CostBreakdown, dailySpend, error) {
 	adminRoleArn := input.account.AdminRoleArn
 	log.Printf("Assuming role %s for budget check", adminRoleArn)
 	assumedSession, err := input.tokenSvc.NewSession(input.awsSession, adminRoleArn)
 	if err != nil {
-		return 0, errors.Wrapf(err, "Failed to assume role %s", adminRoleArn)
+		return 0, usage.CostBreakdown{}, nil, errors.Wrapf(err, "Failed to assume role %s", adminRoleArn)
 	}
 
 	// Configure the CostExplorer SDK for the Service
@@ -44,12 +63,25 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 	usageEndTime := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 23, 59, 59, 0, time.UTC)
 
 	log.Printf("usageStart: %d and usageEnd :%d", usageStartTime.Unix(), usageEndTime.Unix())
-	todayCostAmount, err := input.budgetSvc.CalculateTotalSpend(usageStartTime, usageStartTime.AddDate(0, 0, 1))
+	todayCostAmount, todayCostCurrency, err := input.budgetSvc.CalculateTotalSpend(usageStartTime, usageStartTime.AddDate(0, 0, 1))
 	if err != nil {
-		return 0, errors.Wrapf(err, "Failed to calculate spend for account %s", input.lease.AccountID)
+		return 0, usage.CostBreakdown{}, nil, errors.Wrapf(err, "Failed to calculate spend for account %s", input.lease.AccountID)
 	}
 
-	log.Printf("usage for today: %f", todayCostAmount)
+	log.Printf("usage for today: %f %s", todayCostAmount, todayCostCurrency)
+
+	// The breakdown only tells users where their spend went,
+	// so don't fail the budget check without it
+	var todayBreakdown *usage.CostBreakdown
+	spendBreakdown, err := input.budgetSvc.CalculateSpendBreakdown(usageStartTime, usageStartTime.AddDate(0, 0, 1), input.usageBreakdownByRegion)
+	if err != nil {
+		log.Printf("Failed to calculate spend breakdown for account %s: %s", input.lease.AccountID, err)
+	} else {
+		todayBreakdown = &usage.CostBreakdown{
+			Services: spendBreakdown.Services,
+			Regions:  spendBreakdown.Regions,
+		}
+	}
 
 	// Write today's usage to DynamoDB
 	usageItem, err := usage.NewUsage(usage.NewUsageInput{
@@ -58,16 +90,18 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 		PrincipalID:  input.lease.PrincipalID,
 		AccountID:    input.account.ID,
 		CostAmount:   todayCostAmount,
-		CostCurrency: "USD",
+		CostCurrency: todayCostCurrency,
+		CostMetric:   input.costMetric,
 		TimeToLive:   usageStartTime.Add(time.Duration(input.usageTTL) * time.Second).Unix(),
+		Breakdown:    todayBreakdown,
 	})
 	if err != nil {
-		return 0, nil
+		return 0, usage.CostBreakdown{}, nil, nil
 	}
 
 	err = input.usageSvc.PutUsage(*usageItem)
 	if err != nil {
-		return 0, nil
+		return 0, usage.CostBreakdown{}, nil, nil
 	}
 
 	// Budget period starts last time the lease was reset.
@@ -85,25 +119,52 @@ func calculateLeaseSpend(input *calculateSpendInput) (float64, error) {
 	// Query Usage cache DB
 	usageRecords, err := input.usageSvc.GetUsageByDateRange(budgetStartTime, budgetEndTime)
 	if err != nil {
-		return 0, errors.Wrapf(err, "Failed to retrieve usage for account %s", input.lease.AccountID)
+		return 0, usage.CostBreakdown{}, nil, errors.Wrapf(err, "Failed to retrieve usage for account %s", input.lease.AccountID)
 	}
 
 	// DynDB is eventually consistent. Pull cache DB for SUN-->yesterday, then add the known value for today
-	spend := todayCostAmount
-	for _, usage := range usageRecords {
-		log.Printf("usage records retrieved: %v", usage)
-		if *usage.PrincipalID == input.lease.PrincipalID && *usage.AccountID == input.lease.AccountID {
-			spend = spend + *usage.CostAmount
+	spend := 0.0
+	breakdown := usage.CostBreakdown{}
+	daily := dailySpend{}
+	addSpend := func(startDate int64, cost float64, costCurrency string, costBreakdown usage.CostBreakdown) error {
+		rate, err := currency.Convert(input.rateProvider, 1, costCurrency, input.lease.BudgetCurrency)
+		if err != nil {
+			return err
+		}
+		spend = spend + cost*rate
+		daily[startDate] = daily[startDate] + cost*rate
+		breakdown.Add(convertBreakdown(costBreakdown, rate))
+		return nil
+	}
+
+	todayCostBreakdown := usage.CostBreakdown{}
+	if todayBreakdown != nil {
+		todayCostBreakdown = *todayBreakdown
+	}
+	err = addSpend(usageStartTime.Unix(), todayCostAmount, todayCostCurrency, todayCostBreakdown)
+	if err != nil {
+		return 0, usage.CostBreakdown{}, nil, errors.Wrapf(err, "Failed to convert spend for account %s", input.lease.AccountID)
+	}
+	for _, usageRecord := range usageRecords {
+		log.Printf("usage records retrieved: %v", usageRecord)
+		// A principal's usage record includes the cost of each of their leased accounts
+		if *usageRecord.PrincipalID == input.lease.PrincipalID {
+			err = addSpend(aws.Int64Value(usageRecord.StartDate), usageRecord.AccountCost(input.lease.AccountID), usageCurrency(usageRecord), usageRecord.AccountBreakdowns[input.lease.AccountID])
+			if err != nil {
+				return 0, usage.CostBreakdown{}, nil, errors.Wrapf(err, "Failed to convert spend for account %s", input.lease.AccountID)
+			}
 		}
 	}
 
-	log.Printf("Lease for %s @ %s has spent $%.2f of their $%.2f budget",
-		input.lease.PrincipalID, input.lease.AccountID, spend, input.lease.BudgetAmount)
+	log.Printf("Lease for %s @ %s has spent %.2f %s of their %.2f %s budget",
+		input.lease.PrincipalID, input.lease.AccountID, spend, input.lease.BudgetCurrency,
+		input.lease.BudgetAmount, input.lease.BudgetCurrency)
 
-	return spend, nil
+	return spend, breakdown, daily, nil
 }
 
-// calculatePrincipalSpend calculates the amount spent by User principal for current billing period
+// calculatePrincipalSpend calculates the amount spent by User principal for current billing period,
+// in the principal budget currency
 func calculatePrincipalSpend(input *calculateSpendInput) (float64, error) {
 
 	// Budget period starts based on principal_budget_period variable value
@@ -122,19 +183,146 @@ func calculatePrincipalSpend(input *calculateSpendInput) (float64, error) {
 		return 0, errors.Wrapf(err, "Failed to retrieve usage for account %s", input.lease.AccountID)
 	}
 
+	// A principal's usage record includes the cost of all of their active leases
 	spend := 0.0
 	for _, usage := range usageRecords {
 		log.Printf("usage records retrieved: %v", usage)
 		if *usage.PrincipalID == input.lease.PrincipalID {
-			spend = spend + *usage.CostAmount
+			cost, err := currency.Convert(input.rateProvider, *usage.CostAmount, usageCurrency(usage), input.principalBudgetCurrency)
+			if err != nil {
+				return 0, errors.Wrapf(err, "Failed to convert spend for principal %s", input.lease.PrincipalID)
+			}
+			spend = spend + cost
 		}
 	}
 
-	log.Printf("Principal %s has spent $%.2f of their current principal budget amount",
-		input.lease.PrincipalID, spend)
+	log.Printf("Principal %s has spent %.2f %s of their current principal budget amount",
+		input.lease.PrincipalID, spend, input.principalBudgetCurrency)
 	return spend, nil
 }
 
+// calculateTeamSpend calculates the amount spent by the leases on the lease's team for the
+// team's current billing period, in the team's budget currency.  Returns nil when the team
+// doesn't have a budget, or has been deleted
+func calculateTeamSpend(input *calculateSpendInput) (*teamBudget, error) {
+	leaseTeam, err := input.teamSvc.Get(input.lease.TeamID)
+	if err != nil {
+		if apiErrors.HTTPCodeForError(err) == http.StatusNotFound {
+			log.Printf("Team %s of lease %s @ %s no longer exists",
+				input.lease.TeamID, input.lease.PrincipalID, input.lease.AccountID)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "Failed to get team %s", input.lease.TeamID)
+	}
+	if leaseTeam.BudgetAmount == nil {
+		return nil, nil
+	}
+	budgetCurrency := currency.USD
+	if leaseTeam.BudgetCurrency != nil {
+		budgetCurrency = *leaseTeam.BudgetCurrency
+	}
+	budgetPeriod := ""
+	if leaseTeam.BudgetPeriod != nil {
+		budgetPeriod = *leaseTeam.BudgetPeriod
+	}
+
+	// Find the accounts leased on the team by each principal.  The lease's
+	// principal is held to the team's budget, even if they've left the team
+	principalIDs := leaseTeam.Principals()
+	if !leaseTeam.HasMember(input.lease.PrincipalID) {
+		principalIDs = append(principalIDs, input.lease.PrincipalID)
+	}
+	teamAccounts := map[string]map[string][]leaseWindow{}
+	for _, principalID := range principalIDs {
+		leases, err := input.dbSvc.FindLeasesByPrincipal(principalID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to find leases for principal %s", principalID)
+		}
+		for _, l := range leases {
+			if l.TeamID != input.lease.TeamID || l.AccountID == "" {
+				continue
+			}
+			if teamAccounts[principalID] == nil {
+				teamAccounts[principalID] = map[string][]leaseWindow{}
+			}
+			teamAccounts[principalID][l.AccountID] = append(teamAccounts[principalID][l.AccountID], newLeaseWindow(l))
+		}
+	}
+
+	// Budget period starts based on the team's budget period
+	currentTime := time.Now()
+	budgetStartTime := getBeginningOfCurrentBillingPeriod(budgetPeriod)
+	budgetEndTime := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 23, 59, 59, 0, time.UTC)
+
+	log.Printf("Retrieving usage for team %s for period %s to %s...",
+		input.lease.TeamID,
+		budgetStartTime.Format("2006-01-02"), budgetEndTime.Format("2006-01-02"),
+	)
+
+	usageRecords, err := input.usageSvc.GetUsageByDateRange(budgetStartTime, budgetEndTime)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to retrieve usage for team %s", input.lease.TeamID)
+	}
+
+	// A principal's usage record includes the cost of each of their leased accounts,
+	// only some of which may be leased on the team.  The same account may be leased
+	// by the principal off the team at other times, so only the days the team's
+	// leases held the account count toward the team
+	spend := 0.0
+	for _, usageRecord := range usageRecords {
+		for accountID, windows := range teamAccounts[*usageRecord.PrincipalID] {
+			if !anyLeaseWindowCovers(windows, usageRecord) {
+				continue
+			}
+			cost, err := currency.Convert(input.rateProvider, usageRecord.AccountCost(accountID), usageCurrency(usageRecord), budgetCurrency)
+			if err != nil {
+				return nil, errors.Wrapf(err, "Failed to convert spend for team %s", input.lease.TeamID)
+			}
+			spend = spend + cost
+		}
+	}
+
+	log.Printf("Team %s has spent %.2f %s of their %.2f %s team budget",
+		input.lease.TeamID, spend, budgetCurrency, *leaseTeam.BudgetAmount, budgetCurrency)
+	return &teamBudget{
+		actualSpend:  spend,
+		budgetAmount: *leaseTeam.BudgetAmount,
+	}, nil
+}
+
+// leaseWindow is the time a lease held its account
+type leaseWindow struct {
+	start int64
+	// end is zero while the lease still holds its account
+	end int64
+}
+
+// newLeaseWindow returns the time the lease held its account.  Inactive
+// leases released their account when their status last changed
+func newLeaseWindow(l *db.Lease) leaseWindow {
+	window := leaseWindow{start: l.CreatedOn}
+	if l.LeaseStatus == db.Inactive {
+		window.end = l.LeaseStatusModifiedOn
+	}
+	return window
+}
+
+// anyLeaseWindowCovers returns true if the usage record is for a day one of
+// the leases held the account
+func anyLeaseWindowCovers(windows []leaseWindow, usageRecord *usage.Usage) bool {
+	dayStart := aws.Int64Value(usageRecord.StartDate)
+	dayEnd := dayStart + 24*60*60 - 1
+	if usageRecord.EndDate != nil {
+		dayEnd = *usageRecord.EndDate
+	}
+	for _, window := range windows {
+		if dayEnd >= window.start && (window.end == 0 || dayStart <= window.end) {
+			return true
+		}
+	}
+	return false
+}
+
 // getBeginningOfCurrentBillingPeriod returns starts of the billing period based on budget period
 func getBeginningOfCurrentBillingPeriod(input string) time.Time {
 	currentTime := time.Now()
@@ -149,3 +337,31 @@ func getBeginningOfCurrentBillingPeriod(input string) time.Time {
 
 	return time.Date(currentTime.Year(), currentTime.Month(), 1, 0, 0, 0, 0, time.UTC)
 }
+
+// usageCurrency returns the currency of a usage record's cost amount.
+// Records without a currency are treated as USD
+func usageCurrency(u *usage.Usage) string {
+	if u.CostCurrency == nil {
+		return currency.USD
+	}
+	return *u.CostCurrency
+}
+
+// convertBreakdown converts the costs of a breakdown with an exchange rate
+func convertBreakdown(breakdown usage.CostBreakdown, rate float64) usage.CostBreakdown {
+	return usage.CostBreakdown{
+		Services: convertCosts(breakdown.Services, rate),
+		Regions:  convertCosts(breakdown.Regions, rate),
+	}
+}
+
+func convertCosts(costs map[string]float64, rate float64) map[string]float64 {
+	if costs == nil {
+		return nil
+	}
+	converted := map[string]float64{}
+	for name, cost := range costs {
+		converted[name] = cost * rate
+	}
+	return converted
+}
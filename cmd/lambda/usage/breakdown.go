@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/currency"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/gorilla/mux"
+)
+
+// GetUsageBreakdownByPrincipalID - Returns a principal's usage by AWS service and region,
+// starting from start date to current date.  Start date defaults to the beginning of the current month
+func GetUsageBreakdownByPrincipalID(w http.ResponseWriter, r *http.Request) {
+
+	principalID := mux.Vars(r)[PrincipalIDParam]
+
+	if !api.AllowedFor(r, api.PermissionUsageRead, principalID) {
+		api.WriteAPIErrorResponse(w,
+			errors.NewForbidden(fmt.Sprintf("user is not authorized to view usage for principal %s", principalID)))
+		return
+	}
+
+	now := time.Now().UTC()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if r.FormValue(StartDateParam) != "" {
+		i, err := strconv.ParseInt(r.FormValue(StartDateParam), 10, 64)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to parse usage start date: %s", err)
+			log.Println(errorMsg)
+			response.WriteRequestValidationError(w, errorMsg)
+			return
+		}
+		startDate = time.Unix(i, 0)
+	}
+
+	usageRecords, err := UsageSvc.GetUsageByPrincipal(startDate, principalID)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error getting usage breakdown for given start date %d and principalID %s: %s", startDate.Unix(), principalID, err.Error())
+		log.Println(errMsg)
+		response.WriteServerErrorWithResponse(w, errMsg)
+		return
+	}
+
+	breakdownResponse, err := SumUsageBreakdown(usageRecords, rateProvider, principalBudgetCurrency)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error converting usage breakdown for principalID %s to %s: %s", principalID, principalBudgetCurrency, err.Error())
+		log.Println(errMsg)
+		response.WriteServerErrorWithResponse(w, errMsg)
+		return
+	}
+	breakdownResponse.PrincipalID = principalID
+	breakdownResponse.StartDate = startDate.Unix()
+	breakdownResponse.EndDate = time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, time.UTC).Unix()
+
+	err = json.NewEncoder(w).Encode(breakdownResponse)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error getting usage breakdown for given start date %d and principalID %s: %s", startDate.Unix(), principalID, err.Error())
+		log.Println(errMsg)
+		response.WriteServerErrorWithResponse(w, errMsg)
+		return
+	}
+}
+
+// SumUsageBreakdown adds up the cost amount, and the cost by AWS service and region, of usage records.
+// Costs are converted to the given currency, as records may be in different currencies
+func SumUsageBreakdown(usageRecords []*usage.Usage, rates currency.RateProvider, toCurrency string) (*response.UsageBreakdownResponse, error) {
+	costAmount := 0.0
+	breakdown := usage.CostBreakdown{}
+	for _, usageRecord := range usageRecords {
+		fromCurrency := currency.USD
+		if usageRecord.CostCurrency != nil {
+			fromCurrency = *usageRecord.CostCurrency
+		}
+		rate, err := currency.Convert(rates, 1, fromCurrency, toCurrency)
+		if err != nil {
+			return nil, err
+		}
+		if usageRecord.CostAmount != nil {
+			costAmount = costAmount + *usageRecord.CostAmount*rate
+		}
+		breakdown.Add(convertBreakdown(usageRecord.Breakdown(), rate))
+	}
+
+	return &response.UsageBreakdownResponse{
+		CostAmount:   costAmount,
+		CostCurrency: toCurrency,
+		Services:     breakdown.TopServices(-1),
+		Regions:      breakdown.TopRegions(-1),
+	}, nil
+}
+
+// convertBreakdown multiplies the costs of a breakdown by an exchange rate
+func convertBreakdown(breakdown usage.CostBreakdown, rate float64) usage.CostBreakdown {
+	converted := usage.CostBreakdown{}
+	if breakdown.Services != nil {
+		converted.Services = map[string]float64{}
+		for name, cost := range breakdown.Services {
+			converted.Services[name] = cost * rate
+		}
+	}
+	if breakdown.Regions != nil {
+		converted.Regions = map[string]float64{}
+		for name, cost := range breakdown.Regions {
+			converted.Regions[name] = cost * rate
+		}
+	}
+	return converted
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/currency"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumUsageBreakdown(t *testing.T) {
+	costAmount := 30.0
+	costCurrency := "USD"
+
+	usageRecords := []*usage.Usage{
+		{
+			CostAmount:   &costAmount,
+			CostCurrency: &costCurrency,
+			AccountBreakdowns: map[string]usage.CostBreakdown{
+				"123456789012": {
+					Services: map[string]float64{
+						"Amazon EC2": 20,
+						"Amazon S3":  10,
+					},
+					Regions: map[string]float64{
+						"us-east-1": 30,
+					},
+				},
+			},
+		},
+		{
+			CostAmount:   &costAmount,
+			CostCurrency: &costCurrency,
+			AccountBreakdowns: map[string]usage.CostBreakdown{
+				"123456789012": {
+					Services: map[string]float64{
+						"Amazon EC2": 30,
+					},
+				},
+			},
+		},
+		{
+			// Records written before breakdowns were added
+			CostAmount:   &costAmount,
+			CostCurrency: &costCurrency,
+		},
+	}
+
+	breakdown, err := SumUsageBreakdown(usageRecords, &currency.StaticRateProvider{Base: currency.USD}, "USD")
+	require.Nil(t, err)
+	assert.Equal(t, &response.UsageBreakdownResponse{
+		CostAmount:   90,
+		CostCurrency: "USD",
+		Services: []usage.Cost{
+			{Name: "Amazon EC2", CostAmount: 50},
+			{Name: "Amazon S3", CostAmount: 10},
+		},
+		Regions: []usage.Cost{
+			{Name: "us-east-1", CostAmount: 30},
+		},
+	}, breakdown)
+}
+
+func TestSumUsageBreakdownConvertsCurrency(t *testing.T) {
+	usdAmount := 30.0
+	usdCurrency := "USD"
+	eurAmount := 10.0
+	eurCurrency := "EUR"
+	rates := &currency.StaticRateProvider{
+		Base:  currency.USD,
+		Rates: map[string]float64{"EUR": 0.5},
+	}
+
+	usageRecords := []*usage.Usage{
+		{
+			CostAmount:   &usdAmount,
+			CostCurrency: &usdCurrency,
+			AccountBreakdowns: map[string]usage.CostBreakdown{
+				"123456789012": {
+					Services: map[string]float64{"Amazon EC2": 30},
+				},
+			},
+		},
+		{
+			CostAmount:   &eurAmount,
+			CostCurrency: &eurCurrency,
+			AccountBreakdowns: map[string]usage.CostBreakdown{
+				"123456789012": {
+					Services: map[string]float64{"Amazon EC2": 10},
+				},
+			},
+		},
+	}
+
+	breakdown, err := SumUsageBreakdown(usageRecords, rates, "EUR")
+	require.Nil(t, err)
+	assert.Equal(t, &response.UsageBreakdownResponse{
+		CostAmount:   25,
+		CostCurrency: "EUR",
+		Services: []usage.Cost{
+			{Name: "Amazon EC2", CostAmount: 25},
+		},
+		Regions: []usage.Cost{},
+	}, breakdown)
+
+	_, err = SumUsageBreakdown(usageRecords, rates, "GBP")
+	assert.NotNil(t, err)
+}
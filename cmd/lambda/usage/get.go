@@ -8,7 +8,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/aws/aws-sdk-go/aws"
 )
 
 // GetUsageByStartDateAndEndDate - Returns a list of usage by startDate and endDate
@@ -44,15 +48,11 @@ func GetUsageByStartDateAndEndDate(w http.ResponseWriter, r *http.Request) {
 	usageResponseItems := []*response.UsageResponse{}
 
 	for _, a := range usageRecords {
-		usageRes := response.UsageResponse{
-			PrincipalID:  *a.PrincipalID,
-			AccountID:    *a.AccountID,
-			StartDate:    *a.StartDate,
-			EndDate:      *a.EndDate,
-			CostAmount:   *a.CostAmount,
-			CostCurrency: *a.CostCurrency,
-			TimeToLive:   *a.TimeToLive,
+		// Only include the usage of principals the user may see
+		if !api.AllowedFor(r, api.PermissionUsageRead, *a.PrincipalID) {
+			continue
 		}
+		usageRes := newUsageResponse(a)
 		usageRes.StartDate = startDate.Unix()
 		usageRes.EndDate = endDate.Unix()
 		log.Printf("usage: %v", usageRes)
@@ -84,6 +84,12 @@ func GetUsageByStartDateAndPrincipalID(w http.ResponseWriter, r *http.Request) {
 
 	principalID := r.FormValue(PrincipalIDParam)
 
+	if !api.AllowedFor(r, api.PermissionUsageRead, principalID) {
+		api.WriteAPIErrorResponse(w,
+			errors.NewForbidden(fmt.Sprintf("user is not authorized to view usage for principal %s", principalID)))
+		return
+	}
+
 	usageRecords, err := UsageSvc.GetUsageByPrincipal(startDate, principalID)
 	if err != nil {
 		errMsg := fmt.Sprintf("Error getting usage for given start date %s and principalID %s: %s", r.FormValue(StartDateParam), principalID, err.Error())
@@ -96,15 +102,7 @@ func GetUsageByStartDateAndPrincipalID(w http.ResponseWriter, r *http.Request) {
 	usageResponseItems := []*response.UsageResponse{}
 
 	for _, a := range usageRecords {
-		usageRes := response.UsageResponse{
-			PrincipalID:  *a.PrincipalID,
-			AccountID:    *a.AccountID,
-			StartDate:    *a.StartDate,
-			EndDate:      *a.EndDate,
-			CostAmount:   *a.CostAmount,
-			CostCurrency: *a.CostCurrency,
-			TimeToLive:   *a.TimeToLive,
-		}
+		usageRes := newUsageResponse(a)
 		usageResponseItems = append(usageResponseItems, &usageRes)
 	}
 
@@ -132,6 +130,15 @@ func SumCostAmountByPrincipalID(input []*response.UsageResponse) []*response.Usa
 					log.Printf("item: %v", item)
 					log.Printf("val: %v", val)
 					u[i].CostAmount = u[i].CostAmount + val.CostAmount
+					if u[i].AccountID != val.AccountID {
+						u[i].AccountID = ""
+					}
+					if u[i].AccountCosts == nil {
+						u[i].AccountCosts = map[string]float64{}
+					}
+					for accountID, cost := range val.AccountCosts {
+						u[i].AccountCosts[accountID] = u[i].AccountCosts[accountID] + cost
+					}
 					break
 				}
 
@@ -141,3 +148,25 @@ func SumCostAmountByPrincipalID(input []*response.UsageResponse) []*response.Usa
 
 	return u
 }
+
+// newUsageResponse returns the response for a usage record
+func newUsageResponse(u *usage.Usage) response.UsageResponse {
+	usageRes := response.UsageResponse{
+		PrincipalID:  *u.PrincipalID,
+		AccountID:    aws.StringValue(u.AccountID),
+		StartDate:    *u.StartDate,
+		EndDate:      *u.EndDate,
+		CostAmount:   *u.CostAmount,
+		CostCurrency: *u.CostCurrency,
+		TimeToLive:   *u.TimeToLive,
+		AccountCosts: map[string]float64{},
+	}
+	for accountID, cost := range u.AccountCosts {
+		usageRes.AccountCosts[accountID] = cost
+	}
+	// Records written before AccountCosts was added only have a single account
+	if len(usageRes.AccountCosts) == 0 && u.AccountID != nil {
+		usageRes.AccountCosts[*u.AccountID] = *u.CostAmount
+	}
+	return usageRes
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/api/response"
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUsageResponse(t *testing.T) {
+	principalID := "user1"
+	accountID := "123456789012"
+	startDate := int64(1580515200)
+	endDate := int64(1580601599)
+	costAmount := 30.0
+	costCurrency := "USD"
+	timeToLive := int64(1583020800)
+
+	t.Run("should leave out the account of usage for several accounts", func(t *testing.T) {
+		usageRes := newUsageResponse(&usage.Usage{
+			PrincipalID:  &principalID,
+			StartDate:    &startDate,
+			EndDate:      &endDate,
+			CostAmount:   &costAmount,
+			CostCurrency: &costCurrency,
+			TimeToLive:   &timeToLive,
+			AccountCosts: map[string]float64{
+				"123456789012": 10,
+				"210987654321": 20,
+			},
+		})
+
+		assert.Equal(t, "", usageRes.AccountID)
+		assert.Equal(t, 30.0, usageRes.CostAmount)
+		assert.Equal(t, map[string]float64{
+			"123456789012": 10,
+			"210987654321": 20,
+		}, usageRes.AccountCosts)
+	})
+
+	t.Run("should break down usage written before account costs", func(t *testing.T) {
+		usageRes := newUsageResponse(&usage.Usage{
+			PrincipalID:  &principalID,
+			AccountID:    &accountID,
+			StartDate:    &startDate,
+			EndDate:      &endDate,
+			CostAmount:   &costAmount,
+			CostCurrency: &costCurrency,
+			TimeToLive:   &timeToLive,
+		})
+
+		assert.Equal(t, accountID, usageRes.AccountID)
+		assert.Equal(t, map[string]float64{
+			"123456789012": 30,
+		}, usageRes.AccountCosts)
+	})
+}
+
+func TestSumCostAmountByPrincipalID(t *testing.T) {
+	usageResponses := []*response.UsageResponse{
+		{
+			PrincipalID:  "user1",
+			AccountID:    "123456789012",
+			CostAmount:   10,
+			AccountCosts: map[string]float64{"123456789012": 10},
+		},
+		{
+			PrincipalID:  "user1",
+			AccountID:    "210987654321",
+			CostAmount:   20,
+			AccountCosts: map[string]float64{"210987654321": 20},
+		},
+		{
+			PrincipalID:  "user1",
+			AccountID:    "123456789012",
+			CostAmount:   5,
+			AccountCosts: map[string]float64{"123456789012": 5},
+		},
+		{
+			PrincipalID:  "user2",
+			AccountID:    "333333333333",
+			CostAmount:   40,
+			AccountCosts: map[string]float64{"333333333333": 40},
+		},
+	}
+
+	summed := SumCostAmountByPrincipalID(usageResponses)
+
+	assert.Len(t, summed, 2)
+	assert.Equal(t, "", summed[0].AccountID)
+	assert.Equal(t, 35.0, summed[0].CostAmount)
+	assert.Equal(t, map[string]float64{
+		"123456789012": 15,
+		"210987654321": 20,
+	}, summed[0].AccountCosts)
+	assert.Equal(t, "333333333333", summed[1].AccountID)
+	assert.Equal(t, 40.0, summed[1].CostAmount)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/api/response"
 	"github.com/Optum/dce/pkg/usage"
 )
@@ -25,6 +26,11 @@ func GetUsage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Users who may only see their own usage are only shown their own
+	if api.ScopeFor(r, api.PermissionUsageRead) == api.ScopeOwn {
+		getUsageInput.PrincipalID = api.UserFromContext(r.Context()).Username
+	}
+
 	result, err := UsageSvc.GetUsage(getUsageInput)
 
 	if err != nil {
@@ -35,15 +41,10 @@ func GetUsage(w http.ResponseWriter, r *http.Request) {
 	// Serialize them for the JSON response.
 	usageResponseItems := []response.UsageResponse{}
 	for _, usageItem := range result.Results {
-		usageResponseItems = append(usageResponseItems, response.UsageResponse{
-			PrincipalID:  *usageItem.PrincipalID,
-			AccountID:    *usageItem.AccountID,
-			StartDate:    *usageItem.StartDate,
-			EndDate:      *usageItem.EndDate,
-			CostAmount:   *usageItem.CostAmount,
-			CostCurrency: *usageItem.CostCurrency,
-			TimeToLive:   *usageItem.TimeToLive,
-		})
+		if !api.AllowedFor(r, api.PermissionUsageRead, *usageItem.PrincipalID) {
+			continue
+		}
+		usageResponseItems = append(usageResponseItems, newUsageResponse(usageItem))
 	}
 
 	// If the DB result has next keys, then the URL to retrieve the next page is put into the Link header.
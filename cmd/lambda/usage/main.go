@@ -8,9 +8,14 @@ import (
 	"log"
 
 	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/currency"
 	"github.com/Optum/dce/pkg/usage"
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
 
 	"github.com/awslabs/aws-lambda-go-api-proxy/gorillamux"
 )
@@ -31,6 +36,11 @@ var (
 	// UsageSvc - Service for getting usage
 	UsageSvc    *usage.DB
 	baseRequest url.URL
+	// userDetails identifies the user making each request
+	userDetails api.UserDetailer
+	// rateProvider converts usage into the currency of the principal budget
+	rateProvider            currency.RateProvider
+	principalBudgetCurrency string
 )
 
 func init() {
@@ -44,6 +54,7 @@ func init() {
 			"/usage",
 			[]string{StartDateParam, EndDateParam},
 			GetUsageByStartDateAndEndDate,
+			api.PermissionUsageRead,
 		},
 		api.Route{
 			"GetUsageByStartDateAndPrincipalID",
@@ -51,6 +62,15 @@ func init() {
 			"/usage",
 			[]string{StartDateParam, PrincipalIDParam},
 			GetUsageByStartDateAndPrincipalID,
+			api.PermissionUsageRead,
+		},
+		api.Route{
+			"GetUsageBreakdownByPrincipalID",
+			"GET",
+			"/usage/{principalId}/breakdown",
+			api.EmptyQueryString,
+			GetUsageBreakdownByPrincipalID,
+			api.PermissionUsageRead,
 		},
 		api.Route{
 			"GetAllUsage",
@@ -58,6 +78,7 @@ func init() {
 			"/usage",
 			api.EmptyQueryString,
 			GetUsage,
+			api.PermissionUsageRead,
 		},
 	}
 	r := api.NewRouter(usageRoutes)
@@ -74,16 +95,43 @@ func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.API
 	baseRequest.Host = req.Headers["Host"]
 	baseRequest.Path = req.RequestContext.Stage
 
-	return muxLambda.ProxyWithContext(ctx, req)
+	// Routes are authorized for the user making the request
+	ctxWithUser := api.WithUser(ctx, userDetails.GetUser(&req))
+	return muxLambda.ProxyWithContext(ctxWithUser, req)
 }
 
 func main() {
 
 	UsageSvc = newUsage()
+	svcBldr := newServiceBuilder()
+	userDetails = svcBldr.UserDetailer()
+	// Team leads may view the usage of principals on the teams they lead
+	api.DefaultAuthorizer.Teams = svcBldr.TeamService()
+
+	principalBudgetCurrency = common.GetEnv("PRINCIPAL_BUDGET_CURRENCY", currency.USD)
+	var err error
+	rateProvider, err = newRateProvider()
+	if err != nil {
+		log.Fatalf("Failed to load exchange rates: %s", err)
+	}
 
 	lambda.Start(Handler)
 }
 
+// newRateProvider loads the exchange rates for converting usage into the principal budget currency.
+// Without an exchange rates file, only USD budgets are supported
+func newRateProvider() (currency.RateProvider, error) {
+	key := common.GetEnv("EXCHANGE_RATES_S3_KEY", "")
+	if key == "" {
+		return &currency.StaticRateProvider{Base: currency.USD}, nil
+	}
+	return currency.NewStaticRateProviderFromS3(
+		&common.S3{Client: s3.New(session.Must(session.NewSession()))},
+		common.RequireEnv("ARTIFACTS_BUCKET"),
+		key,
+	)
+}
+
 func newUsage() *usage.DB {
 	usageSvc, err := usage.NewFromEnv()
 	if err != nil {
@@ -93,3 +141,23 @@ func newUsage() *usage.DB {
 
 	return usageSvc
 }
+
+func newServiceBuilder() *config.ServiceBuilder {
+	cfgBldr := &config.ConfigurationBuilder{}
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithUserDetailer().
+		WithTeamService().
+		Build()
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed to initialize services: %s", err)
+		log.Fatal(errorMessage)
+	}
+
+	return svcBldr
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/webhook"
+)
+
+// CreateWebhook - Registers a webhook.  The response is the only time the
+// webhook's secret is returned
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	// Deserialize the request JSON as an request object
+	newWebhook := &webhook.Webhook{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newWebhook)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	hook, err := Services.WebhookService().Create(newWebhook)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusCreated, hook)
+}
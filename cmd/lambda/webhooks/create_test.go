@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/Optum/dce/pkg/webhook/webhookiface/mocks"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func TestWhenCreate(t *testing.T) {
+	standardHeaders := map[string][]string{
+		"Access-Control-Allow-Origin": []string{"*"},
+		"Content-Type":                []string{"application/json"},
+	}
+
+	tests := []struct {
+		name       string
+		expResp    events.APIGatewayProxyResponse
+		request    events.APIGatewayProxyRequest
+		retWebhook *webhook.Webhook
+		retErr     error
+	}{
+		{
+			name: "When given good values. Then the webhook and its secret are returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusCreated,
+				Body:              "{\"id\":\"abc-123\",\"url\":\"https://example.com/hook\",\"secret\":\"0123456789abcdef\"}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/webhooks",
+				Body:       "{ \"url\": \"https://example.com/hook\" }",
+			},
+			retWebhook: &webhook.Webhook{
+				ID:     ptrString("abc-123"),
+				URL:    ptrString("https://example.com/hook"),
+				Secret: ptrString("0123456789abcdef"),
+			},
+			retErr: nil,
+		},
+		{
+			name: "When given bad values. Then a syntax error is returned.",
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusBadRequest,
+				Body:              "{\"error\":{\"message\":\"invalid request parameters\",\"code\":\"ClientError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/webhooks",
+				Body:       "{ \"url: \"https://example.com/hook\" }",
+			},
+			retWebhook: &webhook.Webhook{},
+			retErr:     nil,
+		},
+		{
+			name: "Given internal failure. Then an internal server error is returned.",
+			request: events.APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/webhooks",
+				Body:       "{ \"url\": \"https://example.com/hook\" }",
+			},
+			expResp: events.APIGatewayProxyResponse{
+				StatusCode:        http.StatusInternalServerError,
+				Body:              "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+				MultiValueHeaders: standardHeaders,
+			},
+			retWebhook: nil,
+			retErr:     fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			webhookSvc := mocks.Servicer{}
+			webhookSvc.On("Create", mock.AnythingOfType("*webhook.Webhook")).Return(
+				tt.retWebhook, tt.retErr,
+			)
+			svcBldr.Config.WithService(&webhookSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			resp, err := Handler(context.TODO(), tt.request)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp, resp)
+		})
+	}
+
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/gorilla/mux"
+)
+
+// DeleteWebhook - Deletes the webhook
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+
+	webhookID := mux.Vars(r)["webhookId"]
+
+	hook, err := Services.WebhookService().Get(webhookID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	err = Services.WebhookService().Delete(hook)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusNoContent, nil)
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/gorilla/mux"
+)
+
+// GetWebhookByID - Returns the single webhook by ID, without its secret
+func GetWebhookByID(w http.ResponseWriter, r *http.Request) {
+
+	webhookID := mux.Vars(r)["webhookId"]
+
+	hook, err := Services.WebhookService().Get(webhookID)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, hook.Redacted())
+}
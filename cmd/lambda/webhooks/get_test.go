@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/config"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/Optum/dce/pkg/webhook/webhookiface/mocks"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWebhookByID(t *testing.T) {
+
+	type response struct {
+		StatusCode int
+		Body       string
+	}
+	tests := []struct {
+		name       string
+		expResp    response
+		webhookID  string
+		retWebhook *webhook.Webhook
+		retErr     error
+	}{
+		{
+			name:      "success without the secret",
+			webhookID: "abc-123",
+			expResp: response{
+				StatusCode: 200,
+				Body:       "{\"id\":\"abc-123\",\"url\":\"https://example.com/hook\"}\n",
+			},
+			retWebhook: &webhook.Webhook{
+				ID:     ptrString("abc-123"),
+				URL:    ptrString("https://example.com/hook"),
+				Secret: ptrString("0123456789abcdef"),
+			},
+			retErr: nil,
+		},
+		{
+			name:      "failure",
+			webhookID: "abc-123",
+			expResp: response{
+				StatusCode: 500,
+				Body:       "{\"error\":{\"message\":\"unknown error\",\"code\":\"ServerError\"}}\n",
+			},
+			retWebhook: nil,
+			retErr:     fmt.Errorf("failure"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", fmt.Sprintf("http://example.com/webhooks/%s", tt.webhookID), nil)
+
+			r = mux.SetURLVars(r, map[string]string{
+				"webhookId": tt.webhookID,
+			})
+			w := httptest.NewRecorder()
+
+			cfgBldr := &config.ConfigurationBuilder{}
+			svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+			webhookSvc := mocks.Servicer{}
+			webhookSvc.On("Get", tt.webhookID).Return(
+				tt.retWebhook, tt.retErr,
+			)
+			svcBldr.Config.WithService(&webhookSvc)
+			_, err := svcBldr.Build()
+
+			assert.Nil(t, err)
+			if err == nil {
+				Services = svcBldr
+			}
+
+			GetWebhookByID(w, r)
+
+			resp := w.Result()
+			body, err := ioutil.ReadAll(resp.Body)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expResp.StatusCode, resp.StatusCode)
+			assert.Equal(t, tt.expResp.Body, string(body))
+		})
+	}
+
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+)
+
+// GetWebhooks - Returns the webhooks, without their secrets
+func GetWebhooks(w http.ResponseWriter, r *http.Request) {
+
+	hooks, err := Services.WebhookService().List()
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, hooks.Redacted())
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/config"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/awslabs/aws-lambda-go-api-proxy/gorillamux"
+)
+
+type webhookControllerConfiguration struct {
+	Debug string `env:"DEBUG" envDefault:"false"`
+}
+
+var (
+	muxLambda *gorillamux.GorillaMuxAdapter
+	// Services handles the configuration of the AWS services
+	Services *config.ServiceBuilder
+	// Settings - the configuration settings for the controller
+	Settings *webhookControllerConfiguration
+	// userDetails identifies the user making each request
+	userDetails api.UserDetailer
+)
+
+func init() {
+	initConfig()
+
+	log.Println("Cold start; creating router for /webhooks")
+	webhookRoutes := api.Routes{
+		api.Route{
+			"GetWebhooks",
+			"GET",
+			"/webhooks",
+			api.EmptyQueryString,
+			GetWebhooks,
+			api.PermissionWebhooksRead,
+		},
+		api.Route{
+			"GetWebhookByID",
+			"GET",
+			"/webhooks/{webhookId}",
+			api.EmptyQueryString,
+			GetWebhookByID,
+			api.PermissionWebhooksRead,
+		},
+		api.Route{
+			"UpdateWebhookByID",
+			"PUT",
+			"/webhooks/{webhookId}",
+			api.EmptyQueryString,
+			UpdateWebhookByID,
+			api.PermissionWebhooksWrite,
+		},
+		api.Route{
+			"DeleteWebhook",
+			"DELETE",
+			"/webhooks/{webhookId}",
+			api.EmptyQueryString,
+			DeleteWebhook,
+			api.PermissionWebhooksWrite,
+		},
+		api.Route{
+			"CreateWebhook",
+			"POST",
+			"/webhooks",
+			api.EmptyQueryString,
+			CreateWebhook,
+			api.PermissionWebhooksWrite,
+		},
+	}
+	r := api.NewRouter(webhookRoutes)
+	muxLambda = gorillamux.New(r)
+}
+
+// initConfig configures package-level variables
+// loaded from env vars.
+func initConfig() {
+	cfgBldr := &config.ConfigurationBuilder{}
+	Settings = &webhookControllerConfiguration{}
+	if err := cfgBldr.Unmarshal(Settings); err != nil {
+		log.Fatalf("Could not load configuration: %s", err.Error())
+	}
+
+	// load up the values into the various settings...
+	err := cfgBldr.WithEnv("AWS_CURRENT_REGION", "AWS_CURRENT_REGION", "us-east-1").Build()
+	if err != nil {
+		log.Printf("Error: %+v", err)
+	}
+	svcBldr := &config.ServiceBuilder{Config: cfgBldr}
+
+	_, err = svcBldr.
+		WithWebhookService().
+		WithUserDetailer().
+		Build()
+	if err != nil {
+		panic(err)
+	}
+
+	Services = svcBldr
+	userDetails = svcBldr.UserDetailer()
+}
+
+// Handler - Handle the lambda function
+func Handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	// Routes are authorized for the user making the request
+	ctxWithUser := api.WithUser(ctx, userDetails.GetUser(&req))
+	return muxLambda.ProxyWithContext(ctxWithUser, req)
+}
+
+func main() {
+	// Send Lambda requests to the router
+	lambda.Start(Handler)
+}
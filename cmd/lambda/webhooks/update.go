@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/gorilla/mux"
+)
+
+// UpdateWebhookByID updates a webhook's URL, event types or secret.
+// Replacing the secret requires the current secret
+func UpdateWebhookByID(w http.ResponseWriter, r *http.Request) {
+	webhookID := mux.Vars(r)["webhookId"]
+
+	// Deserialize the request JSON as an request object
+	newWebhook := &webhook.Webhook{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(newWebhook)
+	if err != nil {
+		api.WriteAPIErrorResponse(w,
+			errors.NewBadRequest("invalid request parameters"))
+		return
+	}
+
+	hook, err := Services.WebhookService().Update(webhookID, newWebhook)
+	if err != nil {
+		api.WriteAPIErrorResponse(w, err)
+		return
+	}
+
+	api.WriteAPIResponse(w, http.StatusOK, hook.Redacted())
+}
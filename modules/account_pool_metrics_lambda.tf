@@ -57,6 +57,20 @@ resource "aws_cloudwatch_metric_alarm" "orphaned_accounts" {
   insufficient_data_actions = []
 }
 
+resource "aws_cloudwatch_metric_alarm" "reset_failed_accounts" {
+  count                     = local.account_pool_metrics_count
+  alarm_name                = "reset-failed-accounts"
+  comparison_operator       = "GreaterThanOrEqualToThreshold"
+  evaluation_periods        = "2"
+  metric_name               = "ResetFailedAccounts"
+  namespace                 = local.metrics_namespace
+  period                    = "3600"
+  statistic                 = "Average"
+  threshold                 = var.reset_failed_accounts_alarm_threshold
+  alarm_description         = "Alarm for accounts that failed to reset"
+  insufficient_data_actions = []
+}
+
 resource "aws_cloudwatch_metric_alarm" "too_few_ready_accounts" {
   count                     = local.account_pool_metrics_count
   alarm_name                = "ready-accounts"
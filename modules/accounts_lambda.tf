@@ -13,24 +13,36 @@ module "accounts_lambda" {
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
 
   environment = {
-    DEBUG                          = "false"
-    ACCOUNT_ID                     = local.account_id
-    NAMESPACE                      = var.namespace
-    AWS_CURRENT_REGION             = var.aws_region
-    ACCOUNT_DB                     = aws_dynamodb_table.accounts.id
-    ARTIFACTS_BUCKET               = aws_s3_bucket.artifacts.id
-    LEASE_DB                       = aws_dynamodb_table.leases.id
-    RESET_SQS_URL                  = aws_sqs_queue.account_reset.id
-    ACCOUNT_CREATED_TOPIC_ARN      = aws_sns_topic.account_created.arn
-    ACCOUNT_DELETED_TOPIC_ARN      = aws_sns_topic.account_deleted.arn
-    PRINCIPAL_ROLE_NAME            = local.principal_role_name
-    PRINCIPAL_POLICY_NAME          = local.principal_policy_name
-    PRINCIPAL_IAM_DENY_TAGS        = join(",", var.principal_iam_deny_tags)
-    ALLOWED_REGIONS                = join(",", var.allowed_regions)
-    PRINCIPAL_MAX_SESSION_DURATION = 14400
-    TAG_ENVIRONMENT                = var.namespace == "prod" ? "PROD" : "NON-PROD"
-    TAG_APP_NAME                   = lookup(var.global_tags, "AppName")
-    PRINCIPAL_POLICY_S3_KEY        = aws_s3_bucket_object.principal_policy.key
+    DEBUG                              = "false"
+    ACCOUNT_ID                         = local.account_id
+    NAMESPACE                          = var.namespace
+    AWS_CURRENT_REGION                 = var.aws_region
+    ACCOUNT_DB                         = aws_dynamodb_table.accounts.id
+    OUTBOX_DB                          = aws_dynamodb_table.outbox.id
+    ARTIFACTS_BUCKET                   = aws_s3_bucket.artifacts.id
+    LEASE_DB                           = aws_dynamodb_table.leases.id
+    RESET_SQS_URL                      = aws_sqs_queue.account_reset.id
+    ACCOUNT_CREATED_TOPIC_ARN          = aws_sns_topic.account_created.arn
+    ACCOUNT_DELETED_TOPIC_ARN          = aws_sns_topic.account_deleted.arn
+    PRINCIPAL_ROLE_NAME                = local.principal_role_name
+    PRINCIPAL_POLICY_NAME              = local.principal_policy_name
+    PRINCIPAL_IAM_DENY_TAGS            = join(",", var.principal_iam_deny_tags)
+    ALLOWED_REGIONS                    = join(",", var.allowed_regions)
+    PRINCIPAL_MAX_SESSION_DURATION     = 14400
+    TAG_ENVIRONMENT                    = var.namespace == "prod" ? "PROD" : "NON-PROD"
+    TAG_APP_NAME                       = lookup(var.global_tags, "AppName")
+    PRINCIPAL_POLICY_S3_KEY            = aws_s3_bucket_object.principal_policy.key
+    EVENT_BUS_NAME                     = var.event_bus_name
+    EVENT_SOURCE                       = "dce.${var.namespace}"
+    COGNITO_USER_POOL_ID               = module.api_gateway_authorizer.user_pool_id
+    COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME = var.cognito_roles_attribute_admin_name
+    IDENTITY_PROVIDER                  = var.identity_provider
+    ROLE_MAPPINGS                      = jsonencode(var.role_mappings)
+    OIDC_JWKS_URL                      = var.oidc_jwks_url
+    OIDC_ISSUER                        = var.oidc_issuer
+    OIDC_AUDIENCE                      = var.oidc_audience
+    OIDC_USERNAME_CLAIM                = var.oidc_username_claim
+    IAM_DEFAULT_ROLE                   = var.iam_default_role
   }
 }
 
@@ -0,0 +1,51 @@
+module "activate_scheduled_leases_lambda" {
+  source          = "./lambda"
+  name            = "activate_scheduled_leases-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Claim accounts for scheduled leases which have reached their start time."
+  global_tags     = var.global_tags
+  handler         = "activate_scheduled_leases"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG                          = "false"
+    NAMESPACE                      = var.namespace
+    AWS_CURRENT_REGION             = var.aws_region
+    ACCOUNT_DB                     = aws_dynamodb_table.accounts.id
+    LEASE_DB                       = aws_dynamodb_table.leases.id
+    LEASE_ADDED_TOPIC              = aws_sns_topic.lease_added.arn
+    LEASE_RESERVATION_FAILED_TOPIC = aws_sns_topic.lease_reservation_failed.arn
+    ACCOUNT_SELECTOR_STRATEGY      = var.account_selector_strategy
+    LEASE_WAITLIST_ENABLED         = var.lease_waitlist_enabled
+    EVENT_BUS_NAME                 = var.event_bus_name
+    EVENT_SOURCE                   = "dce.${var.namespace}"
+  }
+}
+
+# Notified when a scheduled lease reaches its start time,
+# but there are no accounts available
+resource "aws_sns_topic" "lease_reservation_failed" {
+  name = "lease-reservation-failed-${var.namespace}"
+  tags = var.global_tags
+}
+
+# Trigger the lambda function on a periodic basis
+resource "aws_cloudwatch_event_rule" "activate_scheduled_leases" {
+  name                = "activate-scheduled-leases-${var.namespace}"
+  description         = "Trigger activate_scheduled_leases Lambda function"
+  schedule_expression = var.activate_scheduled_leases_schedule_expression
+}
+
+resource "aws_cloudwatch_event_target" "activate_scheduled_leases" {
+  rule      = aws_cloudwatch_event_rule.activate_scheduled_leases.name
+  target_id = "activate_scheduled_leases_${var.namespace}"
+  arn       = module.activate_scheduled_leases_lambda.arn
+}
+
+resource "aws_lambda_permission" "allow_activate_scheduled_leases" {
+  statement_id  = "AllowCloudWatchActivateScheduledLeases${title(var.namespace)}"
+  action        = "lambda:InvokeFunction"
+  function_name = module.activate_scheduled_leases_lambda.name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.activate_scheduled_leases.arn
+}
@@ -1,6 +1,8 @@
 locals {
   principal_policy     = var.principal_policy == "" ? "${path.module}/fixtures/policies/principal_policy.tmpl" : var.principal_policy
   artifact_bucket_name = "${local.account_id}-dce-artifacts-${var.namespace}"
+
+  reset_nuke_template_default = "${path.module}/../cmd/codebuild/reset/default-nuke-config-template.yml"
 }
 
 
@@ -64,3 +66,21 @@ resource "aws_s3_bucket_object" "principal_policy" {
   source = local.principal_policy
   etag   = filemd5(local.principal_policy)
 }
+
+// Default aws-nuke configuration, for resets run in the process_reset_queue Lambda
+resource "aws_s3_bucket_object" "reset_nuke_template_default" {
+  bucket = aws_s3_bucket.artifacts.id
+  key    = "fixtures/reset/default-nuke-config-template.yml"
+  source = local.reset_nuke_template_default
+  etag   = filemd5(local.reset_nuke_template_default)
+}
+
+// Exchange rates for converting spend into lease budget currencies
+resource "aws_s3_bucket_object" "exchange_rates" {
+  bucket = aws_s3_bucket.artifacts.id
+  key    = "fixtures/exchange_rates.json"
+  content = jsonencode({
+    base  = "USD"
+    rates = var.exchange_rates
+  })
+}
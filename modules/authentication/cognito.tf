@@ -68,6 +68,50 @@ resource "aws_cognito_identity_pool_roles_attachment" "_" {
       role_arn   = aws_iam_role.admin.arn
       value      = "Admin"
     }
+
+    # Other roles may invoke the API as well. Their requests are authorized
+    # for each route by the DCE lambdas
+    mapping_rule {
+      claim      = "cognito:groups"
+      match_type = "Contains"
+      role_arn   = aws_iam_role.admin.arn
+      value      = "PoolOperators"
+    }
+
+    mapping_rule {
+      claim      = "cognito:groups"
+      match_type = "Contains"
+      role_arn   = aws_iam_role.admin.arn
+      value      = "TeamLeads"
+    }
+
+    mapping_rule {
+      claim      = "cognito:groups"
+      match_type = "Contains"
+      role_arn   = aws_iam_role.admin.arn
+      value      = "Auditors"
+    }
+
+    mapping_rule {
+      claim      = "custom:roles"
+      match_type = "Contains"
+      role_arn   = aws_iam_role.admin.arn
+      value      = "PoolOperator"
+    }
+
+    mapping_rule {
+      claim      = "custom:roles"
+      match_type = "Contains"
+      role_arn   = aws_iam_role.admin.arn
+      value      = "TeamLead"
+    }
+
+    mapping_rule {
+      claim      = "custom:roles"
+      match_type = "Contains"
+      role_arn   = aws_iam_role.admin.arn
+      value      = "Auditor"
+    }
   }
 
   roles = {
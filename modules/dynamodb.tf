@@ -157,3 +157,169 @@ resource "aws_dynamodb_table" "usage" {
 
   tags = var.global_tags
 }
+
+# Outbox table
+# Holds account events written in the same transaction as the account change,
+# until they're published by the publish_outbox_events Lambda
+resource "aws_dynamodb_table" "outbox" {
+  name             = "Outbox${local.table_suffix}"
+  read_capacity    = var.outbox_table_rcu
+  write_capacity   = var.outbox_table_wcu
+  hash_key         = "Id"
+  stream_enabled   = true
+  stream_view_type = "KEYS_ONLY"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Outbox entry ID, which is the ID of the published event
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  # Published entries expire after var.outbox_retention_days
+  ttl {
+    attribute_name = "ExpiresOn"
+    enabled        = true
+  }
+
+  tags = var.global_tags
+  /*
+  Other attributes:
+    - EventType (string)
+    - Data (string, JSON of the account)
+    - EntryStatus (string, Pending, Published or Failed)
+    - Attempts (Integer)
+    - LastError (string)
+    - CreatedOn (Integer, epoch timestamps)
+    - LastModifiedOn (Integer, epoch timestamps)
+  */
+}
+
+# Webhooks table
+# Holds the webhooks which lease and account events are delivered to
+resource "aws_dynamodb_table" "webhooks" {
+  name           = "Webhooks${local.table_suffix}"
+  read_capacity  = var.webhooks_table_rcu
+  write_capacity = var.webhooks_table_wcu
+  hash_key       = "Id"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Webhook ID
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  tags = var.global_tags
+  /*
+  Other attributes:
+    - Url (string)
+    - EventTypes (list of strings, every type when empty)
+    - Secret (string, key the payloads are signed with)
+    - CreatedOn (Integer, epoch timestamps)
+    - LastModifiedOn (Integer, epoch timestamps)
+  */
+}
+
+# Teams table
+# Holds the teams of principals, whose leases share a budget
+resource "aws_dynamodb_table" "teams" {
+  name           = "Teams${local.table_suffix}"
+  read_capacity  = var.teams_table_rcu
+  write_capacity = var.teams_table_wcu
+  hash_key       = "Id"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Team ID
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  tags = var.global_tags
+  /*
+  Other attributes:
+    - Name (string)
+    - Leads (list of strings, principal IDs)
+    - Members (list of strings, principal IDs)
+    - BudgetAmount (Number)
+    - BudgetCurrency (string)
+    - BudgetPeriod (string, WEEKLY or MONTHLY)
+    - CreatedOn (Integer, epoch timestamps)
+    - LastModifiedOn (Integer, epoch timestamps)
+  */
+}
+
+# Principals table
+# Holds the profiles which override the default lease limits for a principal
+resource "aws_dynamodb_table" "principals" {
+  name           = "Principals${local.table_suffix}"
+  read_capacity  = var.principals_table_rcu
+  write_capacity = var.principals_table_wcu
+  hash_key       = "Id"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  # Principal ID
+  attribute {
+    name = "Id"
+    type = "S"
+  }
+
+  tags = var.global_tags
+  /*
+  Other attributes:
+    - MaxLeaseBudgetAmount (Number, overrides MAX_LEASE_BUDGET_AMOUNT)
+    - MaxLeasePeriod (Integer, seconds, overrides MAX_LEASE_PERIOD)
+    - PrincipalBudgetAmount (Number, overrides PRINCIPAL_BUDGET_AMOUNT)
+    - PrincipalBudgetPeriod (string, WEEKLY or MONTHLY, overrides PRINCIPAL_BUDGET_PERIOD)
+    - CreatedOn (Integer, epoch timestamps)
+    - LastModifiedOn (Integer, epoch timestamps)
+  */
+}
+
+# Webhook Dead Letters table
+# Records the events which couldn't be delivered to a webhook
+resource "aws_dynamodb_table" "webhook_dead_letters" {
+  name           = "WebhookDeadLetters${local.table_suffix}"
+  read_capacity  = var.webhook_dead_letters_table_rcu
+  write_capacity = var.webhook_dead_letters_table_wcu
+  hash_key       = "WebhookId"
+  range_key      = "EventId"
+
+  server_side_encryption {
+    enabled = true
+  }
+
+  attribute {
+    name = "WebhookId"
+    type = "S"
+  }
+
+  attribute {
+    name = "EventId"
+    type = "S"
+  }
+
+  tags = var.global_tags
+  /*
+  Other attributes:
+    - EventType (string)
+    - Url (string)
+    - Payload (string, JSON body of the delivery)
+    - Attempts (Integer)
+    - LastError (string)
+    - CreatedOn (Integer, epoch timestamps)
+  */
+}
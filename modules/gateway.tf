@@ -54,6 +54,8 @@ resource "aws_ssm_parameter" "user_pool_endpoint" {
   value = module.api_gateway_authorizer.user_pool_endpoint
 }
 
+// The OIDC identity provider's bearer tokens are authorized by the
+// OIDC authorizer, instead of requests being signed with IAM credentials
 data "template_file" "api_swagger" {
   template = file("${path.module}/swagger.yaml")
 
@@ -63,7 +65,12 @@ data "template_file" "api_swagger" {
     accounts_lambda             = module.accounts_lambda.invoke_arn
     usages_lambda               = module.usage_lambda.invoke_arn
     credentials_web_page_lambda = module.credentials_web_page_lambda.invoke_arn
+    webhooks_lambda             = module.webhooks_lambda.invoke_arn
+    teams_lambda                = module.teams_lambda.invoke_arn
+    principals_lambda           = module.principals_lambda.invoke_arn
+    oidc_authorizer_lambda      = module.oidc_authorizer_lambda.invoke_arn
     namespace                   = "${var.namespace_prefix}-${var.namespace}"
+    security_scheme             = var.identity_provider == "OIDC" ? "oidc" : "sigv4"
   }
 }
 
@@ -101,6 +108,38 @@ resource "aws_lambda_permission" "allow_api_gateway_usages_lambda" {
 
 
 
+resource "aws_lambda_permission" "allow_api_gateway_webhooks_lambda" {
+  function_name = module.webhooks_lambda.arn
+  statement_id  = "AllowExecutionFromApiGateway"
+  action        = "lambda:InvokeFunction"
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.gateway_api.execution_arn}/*/*"
+}
+
+resource "aws_lambda_permission" "allow_api_gateway_teams_lambda" {
+  function_name = module.teams_lambda.arn
+  statement_id  = "AllowExecutionFromApiGateway"
+  action        = "lambda:InvokeFunction"
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.gateway_api.execution_arn}/*/*"
+}
+
+resource "aws_lambda_permission" "allow_api_gateway_principals_lambda" {
+  function_name = module.principals_lambda.arn
+  statement_id  = "AllowExecutionFromApiGateway"
+  action        = "lambda:InvokeFunction"
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.gateway_api.execution_arn}/*/*"
+}
+
+resource "aws_lambda_permission" "allow_api_gateway_oidc_authorizer_lambda" {
+  function_name = module.oidc_authorizer_lambda.arn
+  statement_id  = "AllowExecutionFromApiGateway"
+  action        = "lambda:InvokeFunction"
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.gateway_api.execution_arn}/authorizers/*"
+}
+
 resource "aws_lambda_permission" "allow_api_gateway_credentials_web_page_lambda" {
   function_name = module.credentials_web_page_lambda.arn
   statement_id  = "AllowExecutionFromApiGateway"
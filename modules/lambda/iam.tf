@@ -58,6 +58,26 @@ resource "aws_iam_role_policy_attachment" "lambda_sns" {
   policy_arn = "arn:aws:iam::aws:policy/AmazonSNSFullAccess"
 }
 
+# Allow Lambdas to publish events to EventBridge
+resource "aws_iam_role_policy" "lambda_eventbridge" {
+  role   = aws_iam_role.lambda_execution.name
+  policy = <<JSON
+{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Sid": "PutEvents",
+            "Effect": "Allow",
+            "Action": [
+                "events:PutEvents"
+            ],
+            "Resource": "*"
+        }
+    ]
+}
+JSON
+}
+
 # Allow Lambdas to work with S3
 resource "aws_iam_role_policy_attachment" "lambda_s3" {
   role       = aws_iam_role.lambda_execution.name
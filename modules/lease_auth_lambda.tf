@@ -15,5 +15,12 @@ module "lease_auth_lambda" {
     LEASE_DB                           = aws_dynamodb_table.leases.id
     COGNITO_USER_POOL_ID               = module.api_gateway_authorizer.user_pool_id
     COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME = var.cognito_roles_attribute_admin_name
+    IDENTITY_PROVIDER                  = var.identity_provider
+    ROLE_MAPPINGS                      = jsonencode(var.role_mappings)
+    OIDC_JWKS_URL                      = var.oidc_jwks_url
+    OIDC_ISSUER                        = var.oidc_issuer
+    OIDC_AUDIENCE                      = var.oidc_audience
+    OIDC_USERNAME_CLAIM                = var.oidc_username_claim
+    IAM_DEFAULT_ROLE                   = var.iam_default_role
   }
 }
@@ -22,10 +22,44 @@ module "leases_lambda" {
     MAX_LEASE_PERIOD                   = var.max_lease_period
     PRINCIPAL_BUDGET_AMOUNT            = var.principal_budget_amount
     PRINCIPAL_BUDGET_PERIOD            = var.principal_budget_period
+    PRINCIPAL_BUDGET_CURRENCY          = var.principal_budget_currency
+    ARTIFACTS_BUCKET                   = aws_s3_bucket.artifacts.id
+    EXCHANGE_RATES_S3_KEY              = aws_s3_bucket_object.exchange_rates.key
+    MAX_ACTIVE_LEASES_PER_PRINCIPAL    = var.max_active_leases_per_principal
+    ACCOUNT_SELECTOR_STRATEGY          = var.account_selector_strategy
+    LEASE_WAITLIST_ENABLED             = var.lease_waitlist_enabled
     USAGE_CACHE_DB                     = aws_dynamodb_table.usage.id
+    EVENT_BUS_NAME                     = var.event_bus_name
+    EVENT_SOURCE                       = "dce.${var.namespace}"
+    IDENTITY_PROVIDER                  = var.identity_provider
+    ROLE_MAPPINGS                      = jsonencode(var.role_mappings)
+    OIDC_JWKS_URL                      = var.oidc_jwks_url
+    OIDC_ISSUER                        = var.oidc_issuer
+    OIDC_AUDIENCE                      = var.oidc_audience
+    OIDC_USERNAME_CLAIM                = var.oidc_username_claim
+    IAM_DEFAULT_ROLE                   = var.iam_default_role
+    TEAM_DB                            = aws_dynamodb_table.teams.id
+    PRINCIPAL_DB                       = aws_dynamodb_table.principals.id
+    LEASE_APPROVAL_EMAILS              = join(",", var.lease_approval_emails)
+    LEASE_APPROVAL_FROM_EMAIL          = var.budget_notification_from_email
   }
 }
 
+// Allow leases lambda to email lease requests to approvers with SES
+resource "aws_iam_role_policy" "leases_approval_ses" {
+  role   = module.leases_lambda.execution_role_name
+  policy = <<POLICY
+{
+    "Version": "2012-10-17",
+    "Statement": [{
+      "Effect": "Allow",
+      "Action": ["ses:SendEmail"],
+      "Resource": "*"
+    }]
+}
+POLICY
+}
+
 resource "aws_sns_topic" "lease_added" {
   name = "lease-added-${var.namespace}"
   tags = var.global_tags
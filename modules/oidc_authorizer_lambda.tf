@@ -0,0 +1,19 @@
+module "oidc_authorizer_lambda" {
+  source          = "./lambda"
+  name            = "oidc_authorizer-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Authorizes API requests with the bearer tokens of the OIDC identity provider"
+  global_tags     = var.global_tags
+  handler         = "oidc_authorizer"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG               = "false"
+    NAMESPACE           = var.namespace
+    AWS_CURRENT_REGION  = var.aws_region
+    OIDC_JWKS_URL       = var.oidc_jwks_url
+    OIDC_ISSUER         = var.oidc_issuer
+    OIDC_AUDIENCE       = var.oidc_audience
+    OIDC_USERNAME_CLAIM = var.oidc_username_claim
+  }
+}
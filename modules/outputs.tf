@@ -22,6 +22,46 @@ output "usage_table_arn" {
   value = aws_dynamodb_table.usage.arn
 }
 
+output "outbox_table_name" {
+  value = aws_dynamodb_table.outbox.name
+}
+
+output "outbox_table_arn" {
+  value = aws_dynamodb_table.outbox.arn
+}
+
+output "webhooks_table_name" {
+  value = aws_dynamodb_table.webhooks.name
+}
+
+output "webhooks_table_arn" {
+  value = aws_dynamodb_table.webhooks.arn
+}
+
+output "teams_table_name" {
+  value = aws_dynamodb_table.teams.name
+}
+
+output "teams_table_arn" {
+  value = aws_dynamodb_table.teams.arn
+}
+
+output "principals_table_name" {
+  value = aws_dynamodb_table.principals.name
+}
+
+output "principals_table_arn" {
+  value = aws_dynamodb_table.principals.arn
+}
+
+output "webhook_dead_letters_table_name" {
+  value = aws_dynamodb_table.webhook_dead_letters.name
+}
+
+output "webhook_dead_letters_table_arn" {
+  value = aws_dynamodb_table.webhook_dead_letters.arn
+}
+
 output "sqs_reset_queue_url" {
   value = aws_sqs_queue.account_reset.id
 }
@@ -54,6 +94,14 @@ output "lease_added_topic_arn" {
   value = aws_sns_topic.lease_added.arn
 }
 
+output "lease_reservation_failed_topic_id" {
+  value = aws_sns_topic.lease_reservation_failed.id
+}
+
+output "lease_reservation_failed_topic_arn" {
+  value = aws_sns_topic.lease_reservation_failed.arn
+}
+
 output "lease_removed_topic_id" {
   value = aws_sns_topic.lease_removed.id
 }
@@ -14,6 +14,8 @@ module "publish_lease_events_lambda" {
     LEASE_LOCKED_TOPIC_ARN   = aws_sns_topic.lease_locked.arn
     LEASE_UNLOCKED_TOPIC_ARN = aws_sns_topic.lease_unlocked.arn
     RESET_QUEUE_URL          = aws_sqs_queue.account_reset.id
+    EVENT_BUS_NAME           = var.event_bus_name
+    EVENT_SOURCE             = "dce.${var.namespace}"
   }
 }
 
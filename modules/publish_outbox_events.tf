@@ -0,0 +1,50 @@
+module "publish_outbox_events_lambda" {
+  source          = "./lambda"
+  name            = "publish_outbox_events-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Publishes the account events written to the outbox table"
+  global_tags     = var.global_tags
+  handler         = "publish_outbox_events"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    AWS_CURRENT_REGION        = var.aws_region
+    OUTBOX_DB                 = aws_dynamodb_table.outbox.id
+    USE_CONSISTENT_READS      = "true"
+    OUTBOX_MAX_ATTEMPTS       = var.outbox_max_attempts
+    OUTBOX_RETENTION_DAYS     = var.outbox_retention_days
+    RESET_SQS_URL             = aws_sqs_queue.account_reset.id
+    ACCOUNT_CREATED_TOPIC_ARN = aws_sns_topic.account_created.arn
+    ACCOUNT_DELETED_TOPIC_ARN = aws_sns_topic.account_deleted.arn
+    EVENT_BUS_NAME            = var.event_bus_name
+    EVENT_SOURCE              = "dce.${var.namespace}"
+  }
+}
+
+resource "aws_lambda_event_source_mapping" "publish_outbox_events_from_dynamo_db" {
+  event_source_arn  = aws_dynamodb_table.outbox.stream_arn
+  function_name     = module.publish_outbox_events_lambda.name
+  batch_size        = 10
+  starting_position = "LATEST"
+}
+
+resource "aws_iam_role_policy" "publish_outbox_events_lambda_dynamo_db" {
+  role   = module.publish_outbox_events_lambda.execution_role_name
+  policy = <<POLICY
+{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+        "Effect": "Allow",
+        "Action": [
+            "dynamodb:DescribeStream",
+            "dynamodb:GetRecords",
+            "dynamodb:GetShardIterator",
+            "dynamodb:ListStreams"
+        ],
+        "Resource": "${aws_dynamodb_table.outbox.stream_arn}"
+    }
+  ]
+}
+POLICY
+}
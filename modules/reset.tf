@@ -1,8 +1,15 @@
 # SQS Queue, for triggering account reset
 resource "aws_sqs_queue" "account_reset" {
-  name                       = "account-reset-${var.namespace}"
-  tags                       = var.global_tags
-  visibility_timeout_seconds = 30
+  name = "account-reset-${var.namespace}"
+  tags = var.global_tags
+
+  // Must be at least as long as the process_reset_queue Lambda timeout
+  visibility_timeout_seconds = local.process_reset_queue_timeout
+}
+
+locals {
+  // Resets run in the Lambda may take up to the maximum Lambda timeout
+  process_reset_queue_timeout = var.reset_mode == "Lambda" ? 900 : 30
 }
 
 # Lambda function to add all NotReady accounts to the reset queue
@@ -10,19 +17,24 @@ module "populate_reset_queue" {
   source          = "./lambda"
   name            = "populate_reset_queue-${var.namespace}"
   namespace       = var.namespace
-  description     = "Enqueue all NotReady accounts to be reset."
+  description     = "Enqueue NotReady accounts to be reset."
   global_tags     = var.global_tags
   handler         = "populate_reset_queue"
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
 
   environment = {
-    DEBUG              = "false"
-    NAMESPACE          = var.namespace
-    ICP_REGION         = var.aws_region
-    RESET_SQS_URL      = aws_sqs_queue.account_reset.id
-    ACCOUNT_DB         = aws_dynamodb_table.accounts.id
-    LEASE_DB           = aws_dynamodb_table.leases.id
-    AWS_CURRENT_REGION = var.aws_region
+    DEBUG                       = "false"
+    NAMESPACE                   = var.namespace
+    ICP_REGION                  = var.aws_region
+    RESET_SQS_URL               = aws_sqs_queue.account_reset.id
+    ACCOUNT_DB                  = aws_dynamodb_table.accounts.id
+    LEASE_DB                    = aws_dynamodb_table.leases.id
+    AWS_CURRENT_REGION          = var.aws_region
+    RESET_BUILD_NAME            = aws_codebuild_project.reset_build.id
+    RESET_MAX_CONCURRENT_BUILDS = var.reset_max_concurrent_builds
+    RESET_POOL_METADATA_KEY     = var.reset_pool_metadata_key
+    EVENT_BUS_NAME              = var.event_bus_name
+    EVENT_SOURCE                = "dce.${var.namespace}"
   }
 }
 
@@ -50,7 +62,8 @@ resource "aws_lambda_permission" "allow_populate_reset_queue" {
 
 # Lambda function to execute account reset
 # Will poll SQS on a schedule, and execute a CodePipline
-# for each account that needs to be reset
+# for each account that needs to be reset,
+# or reset the account itself when `reset_mode` is "Lambda"
 module "process_reset_queue" {
   source          = "./lambda"
   name            = "process_reset_queue-${var.namespace}"
@@ -59,15 +72,36 @@ module "process_reset_queue" {
   global_tags     = var.global_tags
   handler         = "process_reset_queue"
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
-  timeout         = 30
+  timeout         = local.process_reset_queue_timeout
 
   environment = {
-    DEBUG              = "false"
-    RESET_BUILD_NAME   = aws_codebuild_project.reset_build.id
-    RESET_SQS_URL      = aws_sqs_queue.account_reset.id
-    ACCOUNT_DB         = aws_dynamodb_table.accounts.id
-    LEASE_DB           = aws_dynamodb_table.leases.id
-    AWS_CURRENT_REGION = var.aws_region
+    DEBUG                               = "false"
+    ACCOUNT_ID                          = local.account_id
+    RESET_BUILD_NAME                    = aws_codebuild_project.reset_build.id
+    RESET_SQS_URL                       = aws_sqs_queue.account_reset.id
+    ACCOUNT_DB                          = aws_dynamodb_table.accounts.id
+    LEASE_DB                            = aws_dynamodb_table.leases.id
+    AWS_CURRENT_REGION                  = var.aws_region
+    RESET_MODE                          = var.reset_mode
+    RESET_LAMBDA_MAX_DURATION           = var.reset_lambda_max_duration
+    RESET_ACCOUNT_PRINCIPAL_POLICY_NAME = local.principal_policy_name
+    RESET_NUKE_TOGGLE                   = var.reset_nuke_toggle
+    RESET_VERIFY_TOGGLE                 = var.reset_verify_toggle
+    RESET_NUKE_REGIONS                  = join(",", var.allowed_regions)
+    RESET_NUKE_TEMPLATE_BUCKET          = var.reset_nuke_template_bucket == "STUB" ? aws_s3_bucket.artifacts.id : var.reset_nuke_template_bucket
+    RESET_NUKE_TEMPLATE_KEY             = var.reset_nuke_template_key == "STUB" ? aws_s3_bucket_object.reset_nuke_template_default.key : var.reset_nuke_template_key
+    RESET_STEPS                         = join(",", var.reset_steps)
+    RESET_REPORT_BUCKET                 = aws_s3_bucket.artifacts.id
+    RESET_NUKE_OVERRIDES_BUCKET         = aws_s3_bucket.artifacts.id
+    RESET_MAX_FAILED_ATTEMPTS           = var.reset_max_failed_attempts
+    RESET_MAX_CONCURRENT_BUILDS         = var.reset_max_concurrent_builds
+    RESET_COMPLETE_TOPIC_ARN            = aws_sns_topic.reset_complete.arn
+    ARTIFACTS_BUCKET                    = aws_s3_bucket.artifacts.id
+    PRINCIPAL_POLICY_S3_KEY             = aws_s3_bucket_object.principal_policy.key
+    PRINCIPAL_IAM_DENY_TAGS             = join(",", var.principal_iam_deny_tags)
+    ALLOWED_REGIONS                     = join(",", var.allowed_regions)
+    EVENT_BUS_NAME                      = var.event_bus_name
+    EVENT_SOURCE                        = "dce.${var.namespace}"
   }
 }
 
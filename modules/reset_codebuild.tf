@@ -107,17 +107,90 @@ resource "aws_codebuild_project" "reset_build" {
       type  = "PLAINTEXT"
     }
 
+    environment_variable {
+      name  = "RESET_VERIFY_TOGGLE"
+      value = var.reset_verify_toggle // "false" to skip verifying accounts after reset
+      type  = "PLAINTEXT"
+    }
+
     environment_variable {
       name  = "RESET_NUKE_REGIONS"
       value = join(",", var.allowed_regions)
       type  = "PLAINTEXT"
     }
 
+    environment_variable {
+      name  = "RESET_REPORT_BUCKET"
+      value = aws_s3_bucket.artifacts.id
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "RESET_NUKE_OVERRIDES_BUCKET"
+      value = aws_s3_bucket.artifacts.id
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "RESET_STEPS"
+      value = join(",", var.reset_steps)
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "RESET_MAX_FAILED_ATTEMPTS"
+      value = var.reset_max_failed_attempts
+      type  = "PLAINTEXT"
+    }
+
     environment_variable {
       name  = "RESET_COMPLETE_TOPIC_ARN"
       value = aws_sns_topic.reset_complete.arn
       type  = "PLAINTEXT"
     }
+
+    environment_variable {
+      name  = "LEASE_ADDED_TOPIC"
+      value = aws_sns_topic.lease_added.arn
+      type  = "PLAINTEXT"
+    }
+
+    // Used to verify the principal role and policy after reset
+    environment_variable {
+      name  = "ARTIFACTS_BUCKET"
+      value = aws_s3_bucket.artifacts.id
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "PRINCIPAL_POLICY_S3_KEY"
+      value = aws_s3_bucket_object.principal_policy.key
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "PRINCIPAL_IAM_DENY_TAGS"
+      value = join(",", var.principal_iam_deny_tags)
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "ALLOWED_REGIONS"
+      value = join(",", var.allowed_regions)
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "EVENT_BUS_NAME"
+      value = var.event_bus_name
+      type  = "PLAINTEXT"
+    }
+
+    environment_variable {
+      name  = "EVENT_SOURCE"
+      value = "dce.${var.namespace}"
+      type  = "PLAINTEXT"
+    }
   }
 
   tags = var.global_tags
@@ -175,7 +248,10 @@ resource "aws_iam_role_policy" "codebuild_reset" {
         "dynamodb:Scan",
         "dynamodb:Query",
         "dynamodb:UpdateItem",
-        "sns:Publish"
+        "dynamodb:PutItem",
+        "dynamodb:DeleteItem",
+        "sns:Publish",
+        "events:PutEvents"
       ]
     },
     {
@@ -16,4 +16,8 @@ output user_pool_id {
 
 output user_pool_endpoint {
   value = "/${var.namespace}/auth/user_pool_endpoint"
+}
+
+output webhook_secrets {
+  value = "/${var.namespace}/webhooks/secrets"
 }
\ No newline at end of file
@@ -31,21 +31,34 @@ module "update_lease_status_lambda" {
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
 
   environment = {
-    AWS_CURRENT_REGION                        = var.aws_region
-    ACCOUNT_DB                                = aws_dynamodb_table.accounts.id
-    LEASE_DB                                  = aws_dynamodb_table.leases.id
-    USAGE_CACHE_DB                            = aws_dynamodb_table.usage.id
-    RESET_QUEUE_URL                           = aws_sqs_queue.account_reset.id
-    LEASE_LOCKED_TOPIC_ARN                    = aws_sns_topic.lease_locked.arn
-    BUDGET_NOTIFICATION_FROM_EMAIL            = var.budget_notification_from_email
-    BUDGET_NOTIFICATION_BCC_EMAILS            = join(",", var.budget_notification_bcc_emails)
-    BUDGET_NOTIFICATION_TEMPLATE_HTML         = var.budget_notification_template_html
-    BUDGET_NOTIFICATION_TEMPLATE_TEXT         = var.budget_notification_template_text
-    BUDGET_NOTIFICATION_TEMPLATE_SUBJECT      = var.budget_notification_template_subject
-    BUDGET_NOTIFICATION_THRESHOLD_PERCENTILES = join(",", var.budget_notification_threshold_percentiles)
-    PRINCIPAL_BUDGET_AMOUNT                   = var.principal_budget_amount
-    PRINCIPAL_BUDGET_PERIOD                   = var.principal_budget_period
-    USAGE_TTL                                 = var.usage_ttl
+    AWS_CURRENT_REGION                            = var.aws_region
+    ACCOUNT_DB                                    = aws_dynamodb_table.accounts.id
+    LEASE_DB                                      = aws_dynamodb_table.leases.id
+    USAGE_CACHE_DB                                = aws_dynamodb_table.usage.id
+    RESET_QUEUE_URL                               = aws_sqs_queue.account_reset.id
+    LEASE_LOCKED_TOPIC_ARN                        = aws_sns_topic.lease_locked.arn
+    BUDGET_NOTIFICATION_FROM_EMAIL                = var.budget_notification_from_email
+    BUDGET_NOTIFICATION_BCC_EMAILS                = join(",", var.budget_notification_bcc_emails)
+    BUDGET_NOTIFICATION_TEMPLATE_HTML             = var.budget_notification_template_html
+    BUDGET_NOTIFICATION_TEMPLATE_TEXT             = var.budget_notification_template_text
+    BUDGET_NOTIFICATION_TEMPLATE_SUBJECT          = var.budget_notification_template_subject
+    BUDGET_NOTIFICATION_THRESHOLD_PERCENTILES     = join(",", var.budget_notification_threshold_percentiles)
+    BUDGET_COST_METRIC                            = var.budget_cost_metric
+    BUDGET_EXCLUDED_RECORD_TYPES                  = join(",", var.budget_excluded_record_types)
+    BUDGET_FORECAST_NOTIFICATION_ENABLED          = var.budget_forecast_notification_enabled
+    BUDGET_FORECAST_NOTIFICATION_TEMPLATE_HTML    = var.budget_forecast_notification_template_html
+    BUDGET_FORECAST_NOTIFICATION_TEMPLATE_TEXT    = var.budget_forecast_notification_template_text
+    BUDGET_FORECAST_NOTIFICATION_TEMPLATE_SUBJECT = var.budget_forecast_notification_template_subject
+    FORECAST_TERMINATION_MULTIPLIER               = var.forecast_termination_multiplier
+    PRINCIPAL_BUDGET_AMOUNT                       = var.principal_budget_amount
+    PRINCIPAL_BUDGET_PERIOD                       = var.principal_budget_period
+    PRINCIPAL_BUDGET_CURRENCY                     = var.principal_budget_currency
+    ARTIFACTS_BUCKET                              = aws_s3_bucket.artifacts.id
+    EXCHANGE_RATES_S3_KEY                         = aws_s3_bucket_object.exchange_rates.key
+    USAGE_TTL                                     = var.usage_ttl
+    USAGE_BREAKDOWN_BY_REGION                     = var.usage_breakdown_by_region
+    TEAM_DB                                       = aws_dynamodb_table.teams.id
+    PRINCIPAL_DB                                  = aws_dynamodb_table.principals.id
   }
 }
 
@@ -8,9 +8,22 @@ module "usage_lambda" {
   alarm_topic_arn = aws_sns_topic.alarms_topic.arn
 
   environment = {
-    DEBUG              = "false"
-    NAMESPACE          = var.namespace
-    AWS_CURRENT_REGION = var.aws_region
-    USAGE_CACHE_DB     = aws_dynamodb_table.usage.id
+    DEBUG                              = "false"
+    NAMESPACE                          = var.namespace
+    AWS_CURRENT_REGION                 = var.aws_region
+    USAGE_CACHE_DB                     = aws_dynamodb_table.usage.id
+    COGNITO_USER_POOL_ID               = module.api_gateway_authorizer.user_pool_id
+    COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME = var.cognito_roles_attribute_admin_name
+    IDENTITY_PROVIDER                  = var.identity_provider
+    ROLE_MAPPINGS                      = jsonencode(var.role_mappings)
+    OIDC_JWKS_URL                      = var.oidc_jwks_url
+    OIDC_ISSUER                        = var.oidc_issuer
+    OIDC_AUDIENCE                      = var.oidc_audience
+    OIDC_USERNAME_CLAIM                = var.oidc_username_claim
+    IAM_DEFAULT_ROLE                   = var.iam_default_role
+    TEAM_DB                            = aws_dynamodb_table.teams.id
+    PRINCIPAL_BUDGET_CURRENCY          = var.principal_budget_currency
+    ARTIFACTS_BUCKET                   = aws_s3_bucket.artifacts.id
+    EXCHANGE_RATES_S3_KEY              = aws_s3_bucket_object.exchange_rates.key
   }
 }
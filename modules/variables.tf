@@ -67,6 +67,47 @@ variable "reset_nuke_toggle" {
   default     = "true"
 }
 
+variable "reset_verify_toggle" {
+  description = "Verify account health after each reset, before returning the account to Ready. Accounts which fail verification are marked Orphaned. Use 'false' to skip verification. Accounts aren't verified when reset_nuke_toggle is 'false'."
+  default     = "true"
+}
+
+variable "reset_mode" {
+  type        = string
+  description = "Where to reset accounts. \"CodeBuild\" runs each reset in the reset CodeBuild project. \"Lambda\" runs resets which are expected to be quick in the process_reset_queue Lambda, and the rest in CodeBuild."
+  default     = "CodeBuild"
+}
+
+variable "reset_lambda_max_duration" {
+  type        = number
+  description = "When reset_mode is \"Lambda\", accounts are reset in the Lambda if their last reset succeeded in this many seconds or less"
+  default     = 600
+}
+
+variable "reset_max_concurrent_builds" {
+  type        = number
+  description = "Most resets to run at once, in CodeBuild builds or in the process_reset_queue Lambda. Accounts over the limit stay NotReady, and are reset by a later run of populate_reset_queue. 0 for no limit"
+  default     = 0
+}
+
+variable "reset_pool_metadata_key" {
+  type        = string
+  description = "Account metadata key which groups accounts into pools, eg. \"accountTier\". Accounts in pools with the fewest Ready accounts are reset first"
+  default     = ""
+}
+
+variable "reset_steps" {
+  type        = list(string)
+  description = "Steps to run when resetting an account, in addition to aws-nuke. Steps run in a fixed order: Athena, S3VersionedBuckets, ServiceCatalog, Route53Domains, AwsNuke. Include AwsNuke to run aws-nuke."
+  default     = ["Athena", "AwsNuke"]
+}
+
+variable "reset_max_failed_attempts" {
+  type        = number
+  description = "Number of consecutive failed resets before an account is set to ResetFailed, and is no longer reset. Use 0 to keep retrying failed resets."
+  default     = 3
+}
+
 variable "cloudwatch_dashboard_toggle" {
   description = "Set to 'true' to enable an out of the box cloudwatch dashboard. Defaults to 'false."
   default     = "false"
@@ -77,6 +118,11 @@ variable "populate_reset_queue_schedule_expression" {
   default     = "rate(6 hours)" // Runs every six hours
 }
 
+variable "activate_scheduled_leases_schedule_expression" {
+  description = "The schedule used with CloudWatch to activate scheduled leases which have reached their start time."
+  default     = "rate(5 minutes)"
+}
+
 variable "principal_iam_deny_tags" {
   type        = list(string)
   description = "IAM principal roles will be denied access to resources with the `AppName` tag set to this value"
@@ -110,13 +156,19 @@ variable "budget_notification_template_html" {
 <p>
 {{if .IsOverBudget}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
-has exceeded its budget of $${{.Lease.BudgetAmount}}. Actual spend is $${{.ActualSpend}}
+has exceeded its budget of {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}}. Actual spend is {{.ActualSpend}} {{.Lease.BudgetCurrency}}
 {{else}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
-has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of $${{.Lease.BudgetAmount}}.
-Actual spend is $${{.ActualSpend}}
+has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}}.
+Actual spend is {{.ActualSpend}} {{.Lease.BudgetCurrency}}
 {{end}}
 </p>
+{{if .TopServices}}
+<p>Top AWS services by spend:</p>
+<ul>
+{{range .TopServices}}<li>{{.Name}}: {{printf "%.2f" .CostAmount}} {{$.Lease.BudgetCurrency}}</li>
+{{end}}</ul>
+{{end}}
 TMPL
 }
 
@@ -126,12 +178,16 @@ variable "budget_notification_template_text" {
   default     = <<TMPL
 {{if .IsOverBudget}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
-has exceeded its budget of $${{.Lease.BudgetAmount}}. Actual spend is $${{.ActualSpend}}
+has exceeded its budget of {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}}. Actual spend is {{.ActualSpend}} {{.Lease.BudgetCurrency}}
 {{else}}
 Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
-has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of $${{.Lease.BudgetAmount}}.
-Actual spend is $${{.ActualSpend}}
+has exceeded the {{.ThresholdPercentile}}% threshold limit for its budget of {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}}.
+Actual spend is {{.ActualSpend}} {{.Lease.BudgetCurrency}}
 {{end}}
+{{if .TopServices}}
+Top AWS services by spend:
+{{range .TopServices}}- {{.Name}}: {{printf "%.2f" .CostAmount}} {{$.Lease.BudgetCurrency}}
+{{end}}{{end}}
 TMPL
 }
 
@@ -143,6 +199,60 @@ Lease {{if .IsOverBudget}}over budget{{else}}at {{.ThresholdPercentile}}% of bud
 SUBJ
 }
 
+variable "budget_forecast_notification_enabled" {
+  type        = bool
+  description = "Send a notification email when a lease is projected to go over budget before it expires"
+  default     = true
+}
+
+variable "budget_forecast_notification_template_html" {
+  type        = string
+  description = "HTML template for budget forecast notification emails"
+  default     = <<TMPL
+<p>
+{{if .IsEndedEarly}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+has been ended early. At its current rate of spend, it would have reached {{printf "%.2f" .ProjectedSpend}} {{.Lease.BudgetCurrency}}
+of its {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}} budget.
+{{else}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+will exceed its budget of {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}} by {{printf "%.2f" .ExceedsBudgetBy}} {{.Lease.BudgetCurrency}},
+on {{.ExceedsBudgetOn}}. Actual spend is {{.ActualSpend}} {{.Lease.BudgetCurrency}}
+{{end}}
+</p>
+TMPL
+}
+
+variable "budget_forecast_notification_template_text" {
+  type        = string
+  description = "Text template for budget forecast notification emails"
+  default     = <<TMPL
+{{if .IsEndedEarly}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+has been ended early. At its current rate of spend, it would have reached {{printf "%.2f" .ProjectedSpend}} {{.Lease.BudgetCurrency}}
+of its {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}} budget.
+{{else}}
+Lease for principal {{.Lease.PrincipalID}} in AWS Account {{.Lease.AccountID}}
+will exceed its budget of {{.Lease.BudgetAmount}} {{.Lease.BudgetCurrency}} by {{printf "%.2f" .ExceedsBudgetBy}} {{.Lease.BudgetCurrency}},
+on {{.ExceedsBudgetOn}}. Actual spend is {{.ActualSpend}} {{.Lease.BudgetCurrency}}
+{{end}}
+TMPL
+}
+
+variable "budget_forecast_notification_template_subject" {
+  type        = string
+  description = "Template for budget forecast notification email subject"
+  default     = <<SUBJ
+Lease {{if .IsEndedEarly}}ended early{{else}}projected to go over budget on {{.ExceedsBudgetOn}}{{end}} [{{.Lease.AccountID}}]
+SUBJ
+}
+
+variable "forecast_termination_multiplier" {
+  type        = number
+  description = "End leases early when their forecasted spend is over budget by this multiplier, eg. 2 ends leases projected to spend twice their budget. Set to 0 to disable"
+  default     = 0
+}
+
 variable "budget_notification_threshold_percentiles" {
   type        = list(number)
   description = "Thresholds (percentiles) at which budget notification emails will be sent to users."
@@ -216,6 +326,42 @@ variable "principal_budget_period" {
   default     = "WEEKLY"
 }
 
+variable "principal_budget_currency" {
+  type        = string
+  description = "Currency of the principal_budget_amount"
+  default     = "USD"
+}
+
+variable "exchange_rates" {
+  type        = map(number)
+  description = "Exchange rates from USD to each supported lease budget currency, eg. { EUR = 0.92 }"
+  default     = {}
+}
+
+variable "max_active_leases_per_principal" {
+  type        = number
+  description = "Maximum number of active leases a User Principal may have at once"
+  default     = 1
+}
+
+variable "account_selector_strategy" {
+  type        = string
+  description = "How to choose the account for a new lease from the Ready accounts. One of LeastRecentlyLeased or Random"
+  default     = "LeastRecentlyLeased"
+}
+
+variable "lease_waitlist_enabled" {
+  type        = bool
+  description = "Put lease requests on a waitlist when no accounts are available, instead of rejecting them. Pending leases get the next account to finish resetting"
+  default     = false
+}
+
+variable "lease_approval_emails" {
+  type        = list(string)
+  description = "Lease requests over the max lease budget amount or max lease period are emailed to these addresses for approval"
+  default     = []
+}
+
 variable "allowed_regions" {
   type = list(string)
   default = [
@@ -230,6 +376,12 @@ variable "orphaned_accounts_alarm_threshold" {
   default     = "1"
 }
 
+variable "reset_failed_accounts_alarm_threshold" {
+  type        = string
+  description = "Alarm when number of accounts that failed to reset is greater than or equal to this threshold."
+  default     = "1"
+}
+
 variable "ready_accounts_alarm_threshold" {
   type        = string
   description = "Alarm when number of ready accounts is less than or equal to this threshold."
@@ -243,6 +395,24 @@ variable "usage_ttl" {
   description = "TTL in seconds for records in the Usage DynamoDB table. Records older than this TTL will be automatically deleted."
 }
 
+variable "budget_cost_metric" {
+  type        = string
+  description = "Cost Explorer metric used to calculate spend for budget checks. One of UnblendedCost, NetUnblendedCost, AmortizedCost, NetAmortizedCost or BlendedCost"
+  default     = "UnblendedCost"
+}
+
+variable "budget_excluded_record_types" {
+  type        = list(string)
+  description = "Cost Explorer record types to leave out of spend for budget checks, eg. [\"Credit\", \"Refund\"]"
+  default     = []
+}
+
+variable "usage_breakdown_by_region" {
+  type        = bool
+  description = "Break down usage records by AWS region, as well as by AWS service"
+  default     = false
+}
+
 variable "accounts_table_rcu" {
   type        = number
   default     = 5
@@ -277,4 +447,152 @@ variable "usage_table_wcu" {
   type        = number
   default     = 5
   description = "DynamoDB Usage table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
-}
\ No newline at end of file
+}
+
+variable "outbox_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Outbox table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "outbox_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Outbox table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "outbox_max_attempts" {
+  type        = number
+  default     = 5
+  description = "Attempts to publish an account event from the outbox, before the outbox entry is marked Failed"
+}
+
+variable "outbox_retention_days" {
+  type        = number
+  default     = 7
+  description = "Days to keep published outbox entries, before they expire"
+}
+
+variable "webhooks_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Webhooks table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "webhooks_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Webhooks table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "teams_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Teams table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "teams_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Teams table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "principals_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Principals table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "principals_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB Principals table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "webhook_dead_letters_table_rcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB WebhookDeadLetters table provisioned Read Capacity Units (RCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "webhook_dead_letters_table_wcu" {
+  type        = number
+  default     = 5
+  description = "DynamoDB WebhookDeadLetters table provisioned Write Capacity Units (WCUs). See https://aws.amazon.com/dynamodb/pricing/provisioned/"
+}
+
+variable "webhook_max_attempts" {
+  type        = number
+  default     = 5
+  description = "Attempts to deliver an event to a webhook, before the delivery is recorded as a dead letter"
+}
+
+variable "webhook_backoff_base_seconds" {
+  type        = number
+  default     = 1
+  description = "Seconds to wait before retrying a failed webhook delivery. The wait doubles after each attempt"
+}
+
+variable "webhook_backoff_max_seconds" {
+  type        = number
+  default     = 30
+  description = "Maximum seconds to wait between attempts to deliver an event to a webhook"
+}
+
+variable "webhook_timeout_seconds" {
+  type        = number
+  default     = 10
+  description = "Seconds to wait for a webhook to respond to a delivery"
+}
+
+variable "event_bus_name" {
+  type        = string
+  default     = ""
+  description = "EventBridge event bus to publish account and lease events to, as CloudEvents. eg. \"default\". Events aren't published to EventBridge if empty."
+}
+
+variable "identity_provider" {
+  type        = string
+  default     = "Cognito"
+  description = "Identifies the users making API requests. One of: Cognito, OIDC (JWTs in an `Authorization: Bearer` header), IAM (the IAM principal which signed the request)"
+}
+
+variable "role_mappings" {
+  type = list(object({
+    claim = string
+    value = string
+    role  = string
+  }))
+  default     = []
+  description = "Gives users of the OIDC and IAM identity providers a role when a claim matches the value, which may use `*` wildcards. IAM callers have `arn` and `accountId` claims. eg. [{claim = \"groups\", value = \"dce-auditors\", role = \"Auditor\"}]"
+}
+
+variable "oidc_jwks_url" {
+  type        = string
+  default     = ""
+  description = "URL of the JSON Web Key Set which bearer tokens are signed with, for the OIDC identity provider"
+}
+
+variable "oidc_issuer" {
+  type        = string
+  default     = ""
+  description = "Issuer (`iss`) bearer tokens must have. Required by the OIDC identity provider"
+}
+
+variable "oidc_audience" {
+  type        = string
+  default     = ""
+  description = "Audience (`aud`) bearer tokens must have, e.g. the client ID DCE is registered with. Required by the OIDC identity provider"
+}
+
+variable "oidc_username_claim" {
+  type        = string
+  default     = "sub"
+  description = "Claim of bearer tokens used as the username (principal ID), for the OIDC identity provider"
+}
+
+variable "iam_default_role" {
+  type        = string
+  default     = "User"
+  description = "Role of IAM callers who don't match any of the `role_mappings`, for the IAM identity provider. Callers who don't match are denied if empty"
+}
@@ -0,0 +1,124 @@
+module "webhooks_lambda" {
+  source          = "./lambda"
+  name            = "webhooks-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Handles API requests to the /webhooks endpoint"
+  global_tags     = var.global_tags
+  handler         = "webhooks"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    DEBUG                              = "false"
+    NAMESPACE                          = var.namespace
+    AWS_CURRENT_REGION                 = var.aws_region
+    WEBHOOK_DB                         = aws_dynamodb_table.webhooks.id
+    WEBHOOK_DEAD_LETTER_DB             = aws_dynamodb_table.webhook_dead_letters.id
+    WEBHOOK_SECRET_PARAMETER_PATH      = module.ssm_parameter_names.webhook_secrets
+    COGNITO_USER_POOL_ID               = module.api_gateway_authorizer.user_pool_id
+    COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME = var.cognito_roles_attribute_admin_name
+    IDENTITY_PROVIDER                  = var.identity_provider
+    ROLE_MAPPINGS                      = jsonencode(var.role_mappings)
+    OIDC_JWKS_URL                      = var.oidc_jwks_url
+    OIDC_ISSUER                        = var.oidc_issuer
+    OIDC_AUDIENCE                      = var.oidc_audience
+    OIDC_USERNAME_CLAIM                = var.oidc_username_claim
+    IAM_DEFAULT_ROLE                   = var.iam_default_role
+  }
+}
+
+module "deliver_webhooks_lambda" {
+  source          = "./lambda"
+  name            = "deliver_webhooks-${var.namespace}"
+  namespace       = var.namespace
+  description     = "Delivers lease and account events to the registered webhooks"
+  global_tags     = var.global_tags
+  handler         = "deliver_webhooks"
+  alarm_topic_arn = aws_sns_topic.alarms_topic.arn
+
+  environment = {
+    AWS_CURRENT_REGION             = var.aws_region
+    WEBHOOK_DB                     = aws_dynamodb_table.webhooks.id
+    WEBHOOK_DEAD_LETTER_DB         = aws_dynamodb_table.webhook_dead_letters.id
+    WEBHOOK_SECRET_PARAMETER_PATH  = module.ssm_parameter_names.webhook_secrets
+    WEBHOOK_MAX_ATTEMPTS           = var.webhook_max_attempts
+    WEBHOOK_BACKOFF_BASE_SECONDS   = var.webhook_backoff_base_seconds
+    WEBHOOK_BACKOFF_MAX_SECONDS    = var.webhook_backoff_max_seconds
+    WEBHOOK_TIMEOUT_SECONDS        = var.webhook_timeout_seconds
+    ACCOUNT_CREATED_TOPIC_ARN      = aws_sns_topic.account_created.arn
+    ACCOUNT_DELETED_TOPIC_ARN      = aws_sns_topic.account_deleted.arn
+    LEASE_ADDED_TOPIC              = aws_sns_topic.lease_added.arn
+    LEASE_LOCKED_TOPIC_ARN         = aws_sns_topic.lease_locked.arn
+    LEASE_RESERVATION_FAILED_TOPIC = aws_sns_topic.lease_reservation_failed.arn
+    EVENT_SOURCE                   = "dce.${var.namespace}"
+  }
+}
+
+resource "aws_sns_topic_subscription" "deliver_webhooks_on_account_created" {
+  topic_arn = aws_sns_topic.account_created.arn
+  protocol  = "lambda"
+  endpoint  = module.deliver_webhooks_lambda.arn
+}
+
+resource "aws_lambda_permission" "deliver_webhooks_on_account_created" {
+  statement_id  = "AllowInvokeFromAccountCreatedTopic"
+  action        = "lambda:InvokeFunction"
+  function_name = module.deliver_webhooks_lambda.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.account_created.arn
+}
+
+resource "aws_sns_topic_subscription" "deliver_webhooks_on_account_deleted" {
+  topic_arn = aws_sns_topic.account_deleted.arn
+  protocol  = "lambda"
+  endpoint  = module.deliver_webhooks_lambda.arn
+}
+
+resource "aws_lambda_permission" "deliver_webhooks_on_account_deleted" {
+  statement_id  = "AllowInvokeFromAccountDeletedTopic"
+  action        = "lambda:InvokeFunction"
+  function_name = module.deliver_webhooks_lambda.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.account_deleted.arn
+}
+
+resource "aws_sns_topic_subscription" "deliver_webhooks_on_lease_added" {
+  topic_arn = aws_sns_topic.lease_added.arn
+  protocol  = "lambda"
+  endpoint  = module.deliver_webhooks_lambda.arn
+}
+
+resource "aws_lambda_permission" "deliver_webhooks_on_lease_added" {
+  statement_id  = "AllowInvokeFromLeaseAddedTopic"
+  action        = "lambda:InvokeFunction"
+  function_name = module.deliver_webhooks_lambda.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.lease_added.arn
+}
+
+resource "aws_sns_topic_subscription" "deliver_webhooks_on_lease_locked" {
+  topic_arn = aws_sns_topic.lease_locked.arn
+  protocol  = "lambda"
+  endpoint  = module.deliver_webhooks_lambda.arn
+}
+
+resource "aws_lambda_permission" "deliver_webhooks_on_lease_locked" {
+  statement_id  = "AllowInvokeFromLeaseLockedTopic"
+  action        = "lambda:InvokeFunction"
+  function_name = module.deliver_webhooks_lambda.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.lease_locked.arn
+}
+
+resource "aws_sns_topic_subscription" "deliver_webhooks_on_lease_reservation_failed" {
+  topic_arn = aws_sns_topic.lease_reservation_failed.arn
+  protocol  = "lambda"
+  endpoint  = module.deliver_webhooks_lambda.arn
+}
+
+resource "aws_lambda_permission" "deliver_webhooks_on_lease_reservation_failed" {
+  statement_id  = "AllowInvokeFromLeaseReservationFailedTopic"
+  action        = "lambda:InvokeFunction"
+  function_name = module.deliver_webhooks_lambda.name
+  principal     = "sns.amazonaws.com"
+  source_arn    = aws_sns_topic.lease_reservation_failed.arn
+}
@@ -4,6 +4,7 @@ package mocks
 
 import account "github.com/Optum/dce/pkg/account"
 import mock "github.com/stretchr/testify/mock"
+import outbox "github.com/Optum/dce/pkg/outbox"
 
 // Deleter is an autogenerated mock type for the Deleter type
 type Deleter struct {
@@ -23,3 +24,17 @@ func (_m *Deleter) Delete(i *account.Account) error {
 
 	return r0
 }
+
+// DeleteWithEvents provides a mock function with given fields: i, entries
+func (_m *Deleter) DeleteWithEvents(i *account.Account, entries []*outbox.Entry) error {
+	ret := _m.Called(i, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account, []*outbox.Entry) error); ok {
+		r0 = rf(i, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -4,6 +4,7 @@ package mocks
 
 import account "github.com/Optum/dce/pkg/account"
 import mock "github.com/stretchr/testify/mock"
+import outbox "github.com/Optum/dce/pkg/outbox"
 
 // ReaderWriterDeleter is an autogenerated mock type for the ReaderWriterDeleter type
 type ReaderWriterDeleter struct {
@@ -24,6 +25,20 @@ func (_m *ReaderWriterDeleter) Delete(i *account.Account) error {
 	return r0
 }
 
+// DeleteWithEvents provides a mock function with given fields: i, entries
+func (_m *ReaderWriterDeleter) DeleteWithEvents(i *account.Account, entries []*outbox.Entry) error {
+	ret := _m.Called(i, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account, []*outbox.Entry) error); ok {
+		r0 = rf(i, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ID
 func (_m *ReaderWriterDeleter) Get(ID string) (*account.Account, error) {
 	ret := _m.Called(ID)
@@ -83,3 +98,17 @@ func (_m *ReaderWriterDeleter) Write(i *account.Account, lastModifiedOn *int64)
 
 	return r0
 }
+
+// WriteWithEvents provides a mock function with given fields: i, lastModifiedOn, entries
+func (_m *ReaderWriterDeleter) WriteWithEvents(i *account.Account, lastModifiedOn *int64, entries []*outbox.Entry) error {
+	ret := _m.Called(i, lastModifiedOn, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account, *int64, []*outbox.Entry) error); ok {
+		r0 = rf(i, lastModifiedOn, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -4,6 +4,7 @@ package mocks
 
 import account "github.com/Optum/dce/pkg/account"
 import mock "github.com/stretchr/testify/mock"
+import outbox "github.com/Optum/dce/pkg/outbox"
 
 // Writer is an autogenerated mock type for the Writer type
 type Writer struct {
@@ -23,3 +24,17 @@ func (_m *Writer) Write(i *account.Account, lastModifiedOn *int64) error {
 
 	return r0
 }
+
+// WriteWithEvents provides a mock function with given fields: i, lastModifiedOn, entries
+func (_m *Writer) WriteWithEvents(i *account.Account, lastModifiedOn *int64, entries []*outbox.Entry) error {
+	ret := _m.Called(i, lastModifiedOn, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account, *int64, []*outbox.Entry) error); ok {
+		r0 = rf(i, lastModifiedOn, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
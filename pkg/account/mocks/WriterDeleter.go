@@ -4,6 +4,7 @@ package mocks
 
 import account "github.com/Optum/dce/pkg/account"
 import mock "github.com/stretchr/testify/mock"
+import outbox "github.com/Optum/dce/pkg/outbox"
 
 // WriterDeleter is an autogenerated mock type for the WriterDeleter type
 type WriterDeleter struct {
@@ -24,6 +25,20 @@ func (_m *WriterDeleter) Delete(i *account.Account) error {
 	return r0
 }
 
+// DeleteWithEvents provides a mock function with given fields: i, entries
+func (_m *WriterDeleter) DeleteWithEvents(i *account.Account, entries []*outbox.Entry) error {
+	ret := _m.Called(i, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account, []*outbox.Entry) error); ok {
+		r0 = rf(i, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Write provides a mock function with given fields: i, lastModifiedOn
 func (_m *WriterDeleter) Write(i *account.Account, lastModifiedOn *int64) error {
 	ret := _m.Called(i, lastModifiedOn)
@@ -37,3 +52,17 @@ func (_m *WriterDeleter) Write(i *account.Account, lastModifiedOn *int64) error
 
 	return r0
 }
+
+// WriteWithEvents provides a mock function with given fields: i, lastModifiedOn, entries
+func (_m *WriterDeleter) WriteWithEvents(i *account.Account, lastModifiedOn *int64, entries []*outbox.Entry) error {
+	ret := _m.Called(i, lastModifiedOn, entries)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*account.Account, *int64, []*outbox.Entry) error); ok {
+		r0 = rf(i, lastModifiedOn, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
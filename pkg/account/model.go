@@ -18,12 +18,13 @@ var (
 )
 
 // ValidStatuses has the valid status options
-var ValidStatuses = [5]Status{
+var ValidStatuses = [6]Status{
 	StatusNone,
 	StatusLeased,
 	StatusNotReady,
 	StatusOrphaned,
 	StatusReady,
+	StatusResetFailed,
 }
 
 func init() {
@@ -44,6 +45,8 @@ type Account struct {
 	PrincipalRoleArn    *arn.ARN               `json:"principalRoleArn,omitempty"  dynamodbav:"PrincipalRoleArn,omitempty" schema:"principalRoleArn,omitempty"`         // Assumed by principal users
 	PrincipalPolicyHash *string                `json:"principalPolicyHash,omitempty" dynamodbav:"PrincipalPolicyHash,omitempty" schema:"principalPolicyHash,omitempty"` // The the hash of the policy version deployed
 	Metadata            map[string]interface{} `json:"metadata,omitempty"  dynamodbav:"Metadata,omitempty" schema:"-"`                                                  // Any org specific metadata pertaining to the account
+	LastResetResult     *ResetResult           `json:"lastResetResult,omitempty" dynamodbav:"LastResetResult,omitempty" schema:"-"`                                     // Result of the last time the account was reset
+	ResetStartedOn      *int64                 `json:"resetStartedOn,omitempty" dynamodbav:"ResetStartedOn,omitempty" schema:"-"`                                       // Start Epoch Timestamp of the last reset run in the process_reset_queue Lambda
 	Limit               *int64                 `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
 	NextID              *string                `json:"-" dynamodbav:"-" schema:"nextId,omitempty"`
 	PrincipalPolicyArn  *arn.ARN               `json:"-"  dynamodbav:"-" schema:"-"`
@@ -83,6 +86,8 @@ func (a *Account) UnmarshalJSON(data []byte) error {
 	a.AdminRoleArn = alias.AdminRoleArn
 	a.Metadata = alias.Metadata
 	a.PrincipalPolicyHash = alias.PrincipalPolicyHash
+	a.LastResetResult = alias.LastResetResult
+	a.ResetStartedOn = alias.ResetStartedOn
 
 	if alias.ID != nil {
 		principalPolicyArn := arn.New("aws", "iam", "", *alias.ID, fmt.Sprintf("policy/%s", PrincipalPolicyName))
@@ -110,6 +115,8 @@ func (a *Account) UnmarshalDynamoDBAttributeValue(av *dynamodb.AttributeValue) e
 	a.AdminRoleArn = alias.AdminRoleArn
 	a.Metadata = alias.Metadata
 	a.PrincipalPolicyHash = alias.PrincipalPolicyHash
+	a.LastResetResult = alias.LastResetResult
+	a.ResetStartedOn = alias.ResetStartedOn
 
 	if a.ID != nil {
 		principalPolicyArn := arn.New("aws", "iam", "", *alias.ID, fmt.Sprintf("policy/%s", PrincipalPolicyName))
@@ -146,6 +153,20 @@ func NewAccount(input NewAccountInput) (*Account, error) {
 // Accounts is a list of type Account
 type Accounts []Account
 
+// ResetResult is the outcome of an account reset
+type ResetResult struct {
+	Attempt           int      `json:"attempt" dynamodbav:"Attempt"`                                         // Attempt number, counting consecutive failed resets
+	Succeeded         bool     `json:"succeeded" dynamodbav:"Succeeded"`                                     // Whether the reset deleted all of the account's resources
+	StartedOn         int64    `json:"startedOn" dynamodbav:"StartedOn"`                                     // Reset start Epoch Timestamp
+	Duration          int64    `json:"duration" dynamodbav:"Duration"`                                       // Reset duration, in seconds
+	ResourcesDeleted  []string `json:"resourcesDeleted" dynamodbav:"ResourcesDeleted"`                       // Resources deleted by the reset
+	ResourcesFailed   []string `json:"resourcesFailed" dynamodbav:"ResourcesFailed"`                         // Resources the reset failed to delete
+	Error             string   `json:"error,omitempty" dynamodbav:"Error,omitempty"`                         // Why the reset failed
+	ReportKey         string   `json:"reportKey,omitempty" dynamodbav:"ReportKey,omitempty"`                 // S3 key of the JSON report of a dry run reset
+	ReportCSVKey      string   `json:"reportCsvKey,omitempty" dynamodbav:"ReportCSVKey,omitempty"`           // S3 key of the CSV report of a dry run reset
+	VerificationError string   `json:"verificationError,omitempty" dynamodbav:"VerificationError,omitempty"` // Why the account failed verification after the reset
+}
+
 // Status is an account status type
 type Status string
 
@@ -160,6 +181,8 @@ const (
 	StatusLeased Status = "Leased"
 	// StatusOrphaned status
 	StatusOrphaned Status = "Orphaned"
+	// StatusResetFailed status
+	StatusResetFailed Status = "ResetFailed"
 )
 
 // String returns the string value of AccountStatus
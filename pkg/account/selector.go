@@ -0,0 +1,123 @@
+package account
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MetadataHintPrefix is the prefix for selector hints matching on account metadata
+// e.g. `metadata.accountTier=gpu` only selects accounts with a metadata `accountTier` of `gpu`
+const MetadataHintPrefix = "metadata."
+
+// SelectorStrategy is the name of a strategy for ordering the accounts that can be leased
+type SelectorStrategy string
+
+const (
+	// SelectorStrategyLeastRecentlyLeased prefers the accounts which have been unchanged the longest
+	SelectorStrategyLeastRecentlyLeased SelectorStrategy = "LeastRecentlyLeased"
+	// SelectorStrategyRandom spreads leases randomly across the accounts
+	SelectorStrategyRandom SelectorStrategy = "Random"
+)
+
+// Selector orders a list of accounts so the best candidate to lease is first.
+// Hints narrow down the accounts which may be selected
+type Selector interface {
+	Select(accounts Accounts, hints map[string]string) Accounts
+}
+
+// LeastRecentlyLeasedSelector orders accounts by LastModifiedOn, oldest first.
+// An account is modified every time it's leased or reset, so the oldest account
+// is the one that was leased the longest time ago
+type LeastRecentlyLeasedSelector struct{}
+
+// Select orders the accounts, least recently leased first
+func (s *LeastRecentlyLeasedSelector) Select(accounts Accounts, hints map[string]string) Accounts {
+	selected := make(Accounts, len(accounts))
+	copy(selected, accounts)
+	sort.SliceStable(selected, func(i, j int) bool {
+		return lastModifiedOn(selected[i]) < lastModifiedOn(selected[j])
+	})
+	return selected
+}
+
+// RandomSelector shuffles the accounts
+type RandomSelector struct {
+	Rand *rand.Rand
+}
+
+// Select orders the accounts randomly
+func (s *RandomSelector) Select(accounts Accounts, hints map[string]string) Accounts {
+	selected := make(Accounts, len(accounts))
+	copy(selected, accounts)
+	s.Rand.Shuffle(len(selected), func(i, j int) {
+		selected[i], selected[j] = selected[j], selected[i]
+	})
+	return selected
+}
+
+// MetadataSelector only selects the accounts with metadata matching the
+// `metadata.<key>=<value>` hints.  Matching accounts are ordered by the Next selector
+type MetadataSelector struct {
+	Next Selector
+}
+
+// Select filters the accounts on the metadata hints
+func (s *MetadataSelector) Select(accounts Accounts, hints map[string]string) Accounts {
+	selected := Accounts{}
+	for _, account := range accounts {
+		if matchesMetadata(account, hints) {
+			selected = append(selected, account)
+		}
+	}
+	return s.Next.Select(selected, hints)
+}
+
+// NewSelector creates the Selector for a strategy, defaulting to least recently leased.
+// Every strategy supports metadata hints
+func NewSelector(strategy SelectorStrategy) (Selector, error) {
+	var next Selector
+	switch strategy {
+	case SelectorStrategyLeastRecentlyLeased, "":
+		next = &LeastRecentlyLeasedSelector{}
+	case SelectorStrategyRandom:
+		next = &RandomSelector{
+			Rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+	default:
+		return nil, fmt.Errorf("unknown account selector strategy %q", strategy)
+	}
+	return &MetadataSelector{Next: next}, nil
+}
+
+// ValidateSelectorHints checks that all hints are supported by the selectors
+func ValidateSelectorHints(hints map[string]string) error {
+	for key := range hints {
+		if !strings.HasPrefix(key, MetadataHintPrefix) || len(key) == len(MetadataHintPrefix) {
+			return fmt.Errorf("unsupported account selector %q", key)
+		}
+	}
+	return nil
+}
+
+func lastModifiedOn(account Account) int64 {
+	if account.LastModifiedOn == nil {
+		return 0
+	}
+	return *account.LastModifiedOn
+}
+
+func matchesMetadata(account Account, hints map[string]string) bool {
+	for key, value := range hints {
+		if !strings.HasPrefix(key, MetadataHintPrefix) {
+			continue
+		}
+		actual, ok := account.Metadata[strings.TrimPrefix(key, MetadataHintPrefix)]
+		if !ok || fmt.Sprint(actual) != value {
+			return false
+		}
+	}
+	return true
+}
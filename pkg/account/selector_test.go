@@ -0,0 +1,142 @@
+package account_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/stretchr/testify/assert"
+)
+
+func ptrInt64(i int64) *int64 {
+	ptrI := i
+	return &ptrI
+}
+
+func accountIDs(accounts account.Accounts) []string {
+	ids := []string{}
+	for _, a := range accounts {
+		ids = append(ids, *a.ID)
+	}
+	return ids
+}
+
+func TestSelectorSelect(t *testing.T) {
+	accounts := account.Accounts{
+		{
+			ID:             ptrString("111111111111"),
+			LastModifiedOn: ptrInt64(300),
+			Metadata: map[string]interface{}{
+				"accountTier": "gpu",
+			},
+		},
+		{
+			ID:             ptrString("222222222222"),
+			LastModifiedOn: ptrInt64(100),
+		},
+		{
+			ID:             ptrString("333333333333"),
+			LastModifiedOn: ptrInt64(200),
+			Metadata: map[string]interface{}{
+				"accountTier": "gpu",
+				"size":        float64(2),
+			},
+		},
+		{
+			ID: ptrString("444444444444"),
+			Metadata: map[string]interface{}{
+				"accountTier": "standard",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		strategy account.SelectorStrategy
+		hints    map[string]string
+		expIDs   []string
+	}{
+		{
+			name:     "should select the least recently leased account first",
+			strategy: account.SelectorStrategyLeastRecentlyLeased,
+			expIDs:   []string{"444444444444", "222222222222", "333333333333", "111111111111"},
+		},
+		{
+			name:     "should default to least recently leased",
+			strategy: "",
+			expIDs:   []string{"444444444444", "222222222222", "333333333333", "111111111111"},
+		},
+		{
+			name:     "should only select accounts matching metadata hints",
+			strategy: account.SelectorStrategyLeastRecentlyLeased,
+			hints: map[string]string{
+				"metadata.accountTier": "gpu",
+			},
+			expIDs: []string{"333333333333", "111111111111"},
+		},
+		{
+			name:     "should match all metadata hints",
+			strategy: account.SelectorStrategyLeastRecentlyLeased,
+			hints: map[string]string{
+				"metadata.accountTier": "gpu",
+				"metadata.size":        "2",
+			},
+			expIDs: []string{"333333333333"},
+		},
+		{
+			name:     "should select nothing when no accounts match",
+			strategy: account.SelectorStrategyRandom,
+			hints: map[string]string{
+				"metadata.accountTier": "tpu",
+			},
+			expIDs: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := account.NewSelector(tt.strategy)
+			assert.Nil(t, err)
+
+			selected := selector.Select(accounts, tt.hints)
+			assert.Equal(t, tt.expIDs, accountIDs(selected))
+		})
+	}
+
+	t.Run("should shuffle accounts randomly", func(t *testing.T) {
+		selector := &account.RandomSelector{
+			Rand: rand.New(rand.NewSource(1)),
+		}
+
+		selected := selector.Select(accounts, nil)
+		assert.ElementsMatch(t, accountIDs(accounts), accountIDs(selected))
+		// The original order is left alone
+		assert.Equal(t, "111111111111", *accounts[0].ID)
+	})
+
+	t.Run("should fail on an unknown strategy", func(t *testing.T) {
+		selector, err := account.NewSelector("MostExpensive")
+		assert.Nil(t, selector)
+		assert.Equal(t, fmt.Errorf("unknown account selector strategy \"MostExpensive\""), err)
+	})
+}
+
+func TestValidateSelectorHints(t *testing.T) {
+	assert.Nil(t, account.ValidateSelectorHints(nil))
+	assert.Nil(t, account.ValidateSelectorHints(map[string]string{
+		"metadata.accountTier": "gpu",
+	}))
+	assert.Equal(t,
+		fmt.Errorf("unsupported account selector \"accountTier\""),
+		account.ValidateSelectorHints(map[string]string{
+			"accountTier": "gpu",
+		}),
+	)
+	assert.Equal(t,
+		fmt.Errorf("unsupported account selector \"metadata.\""),
+		account.ValidateSelectorHints(map[string]string{
+			"metadata.": "gpu",
+		}),
+	)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/Optum/dce/pkg/arn"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/imdario/mergo"
 )
@@ -13,11 +14,13 @@ import (
 // Writer put an item into the data store
 type Writer interface {
 	Write(i *Account, lastModifiedOn *int64) error
+	WriteWithEvents(i *Account, lastModifiedOn *int64, entries []*outbox.Entry) error
 }
 
 // Deleter Deletes an Account from the data store
 type Deleter interface {
 	Delete(i *Account) error
+	DeleteWithEvents(i *Account, entries []*outbox.Entry) error
 }
 
 // SingleReader Reads Account information from the data store
@@ -84,6 +87,39 @@ func (a *Service) Get(ID string) (*Account, error) {
 
 // Save writes the record to the dataSvc
 func (a *Service) Save(data *Account) error {
+	lastModifiedOn, err := prepareSave(data)
+	if err != nil {
+		return err
+	}
+	err = a.dataSvc.Write(data, lastModifiedOn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// saveWithEvents writes the record to the dataSvc along with an outbox entry
+// for each type of event, so the events are published if and only if the
+// record is saved
+func (a *Service) saveWithEvents(data *Account, eventTypes ...string) error {
+	lastModifiedOn, err := prepareSave(data)
+	if err != nil {
+		return err
+	}
+	entries, err := outbox.NewEntries(data, eventTypes...)
+	if err != nil {
+		return err
+	}
+	err = a.dataSvc.WriteWithEvents(data, lastModifiedOn, entries)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// prepareSave sets the timestamps of the record and validates it.
+// Returns the original lastModifiedOn, which is nil on a create
+func prepareSave(data *Account) (*int64, error) {
 	var lastModifiedOn *int64
 	now := time.Now().Unix()
 	if data.LastModifiedOn == nil {
@@ -97,13 +133,9 @@ func (a *Service) Save(data *Account) error {
 
 	err := data.Validate()
 	if err != nil {
-		return err
-	}
-	err = a.dataSvc.Write(data, lastModifiedOn)
-	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return lastModifiedOn, nil
 }
 
 // Update the Account record in DynamoDB
@@ -114,6 +146,7 @@ func (a *Service) Update(ID string, data *Account) (*Account, error) {
 		validation.Field(&data.AdminRoleArn, validation.By(isNilOrUsableAdminRole(a.managerSvc))),
 		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
 		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.ResetStartedOn, validation.By(isNil)),
 	)
 	if err != nil {
 		return nil, errors.NewValidation("account", err)
@@ -129,7 +162,7 @@ func (a *Service) Update(ID string, data *Account) (*Account, error) {
 		return nil, errors.NewInternalServer("unexpected error updating account", err)
 	}
 
-	err = a.Save(account)
+	err = a.saveWithEvents(account, outbox.TypeAccountUpdated)
 	if err != nil {
 		return nil, err
 	}
@@ -180,17 +213,8 @@ func (a *Service) Create(data *Account) (*Account, error) {
 		return nil, err
 	}
 
-	err = a.Save(new)
-	if err != nil {
-		return nil, err
-	}
-
-	err = a.eventSvc.AccountCreate(new)
-	if err != nil {
-		return nil, err
-	}
-
-	err = a.eventSvc.AccountReset(new)
+	// The account is reset before it's added to the pool
+	err = a.saveWithEvents(new, outbox.TypeAccountCreated, outbox.TypeAccountReset)
 	if err != nil {
 		return nil, err
 	}
@@ -210,22 +234,17 @@ func (a *Service) Delete(data *Account) error {
 		return errors.NewConflict("account", *data.ID, err)
 	}
 
-	err = a.dataSvc.Delete(data)
+	// The account is reset so it's clean when it leaves the pool
+	entries, err := outbox.NewEntries(data, outbox.TypeAccountDeleted, outbox.TypeAccountReset)
 	if err != nil {
 		return err
 	}
-
-	err = a.managerSvc.DeletePrincipalAccess(data)
+	err = a.dataSvc.DeleteWithEvents(data, entries)
 	if err != nil {
 		return err
 	}
 
-	err = a.eventSvc.AccountDelete(data)
-	if err != nil {
-		return err
-	}
-
-	err = a.Reset(data)
+	err = a.managerSvc.DeletePrincipalAccess(data)
 	if err != nil {
 		return err
 	}
@@ -9,6 +9,7 @@ import (
 	"github.com/Optum/dce/pkg/account/mocks"
 	"github.com/Optum/dce/pkg/arn"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -83,12 +84,10 @@ func TestGetAccountByID(t *testing.T) {
 
 func TestDelete(t *testing.T) {
 	tests := []struct {
-		name              string
-		expErr            error
-		returnErr         error
-		accountDeletedErr error
-		accountResetErr   error
-		account           account.Account
+		name      string
+		expErr    error
+		returnErr error
+		account   account.Account
 	}{
 		{
 			name: "should delete an account",
@@ -127,15 +126,17 @@ func TestDelete(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mocksRwd := &mocks.ReaderWriterDeleter{}
-			mocksRwd.On("Delete", mock.Anything).
-				Return(tt.returnErr)
+			// The deleted and reset events are written with the delete
+			mocksRwd.On("DeleteWithEvents", mock.Anything, mock.MatchedBy(func(entries []*outbox.Entry) bool {
+				return len(entries) == 2 &&
+					*entries[0].Type == outbox.TypeAccountDeleted &&
+					*entries[1].Type == outbox.TypeAccountReset
+			})).Return(tt.returnErr)
 
 			mocksManager := &mocks.Manager{}
 			mocksEventer := &mocks.Eventer{}
 
 			mocksManager.On("DeletePrincipalAccess", mock.AnythingOfType("*account.Account")).Return(nil)
-			mocksEventer.On("AccountDelete", mock.AnythingOfType("*account.Account")).Return(tt.accountDeletedErr)
-			mocksEventer.On("AccountReset", mock.AnythingOfType("*account.Account")).Return(tt.accountResetErr)
 
 			accountSvc := account.NewService(
 				account.NewServiceInput{
@@ -236,9 +237,14 @@ func TestUpdate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mocksRwd := &mocks.ReaderWriterDeleter{}
 			mocksManager := &mocks.Manager{}
+			mocksEventer := &mocks.Eventer{}
 
 			mocksRwd.On("Get", *tt.origAccount.ID).Return(&tt.origAccount, tt.returnErr)
-			mocksRwd.On("Write", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64")).Return(tt.returnErr)
+			mocksRwd.On("WriteWithEvents", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64"),
+				mock.MatchedBy(func(entries []*outbox.Entry) bool {
+					return len(entries) == 1 && *entries[0].Type == outbox.TypeAccountUpdated
+				}),
+			).Return(tt.returnErr)
 
 			mocksManager.On("ValidateAccess", mock.AnythingOfType("*arn.ARN")).Return(tt.amReturnErr)
 
@@ -246,6 +252,7 @@ func TestUpdate(t *testing.T) {
 				account.NewServiceInput{
 					DataSvc:    mocksRwd,
 					ManagerSvc: mocksManager,
+					EventSvc:   mocksEventer,
 				},
 			)
 
@@ -253,6 +260,9 @@ func TestUpdate(t *testing.T) {
 
 			assert.Truef(t, errors.Is(err, tt.exp.err), "actual error %q doesn't match expected error %q", err, tt.exp.err)
 			assert.Equal(t, tt.exp.data, result)
+			if tt.exp.err == nil {
+				mocksRwd.AssertCalled(t, "WriteWithEvents", result, mock.Anything, mock.Anything)
+			}
 
 		})
 	}
@@ -446,13 +456,12 @@ func TestCreate(t *testing.T) {
 	}
 
 	tests := []struct {
-		name             string
-		req              *account.Account
-		exp              response
-		getResponse      response
-		writeErr         error
-		accountCreateErr error
-		accountResetErr  error
+		name              string
+		req               *account.Account
+		exp               response
+		getResponse       response
+		writeErr          error
+		writeWithEventErr error
 	}{
 		{
 			name: "should create",
@@ -476,9 +485,8 @@ func TestCreate(t *testing.T) {
 				data: nil,
 				err:  errors.NewNotFound("account", "123456789012"),
 			},
-			writeErr:         nil,
-			accountCreateErr: nil,
-			accountResetErr:  nil,
+			writeErr:          nil,
+			writeWithEventErr: nil,
 		},
 		{
 			name: "should fail on account already exists",
@@ -533,23 +541,7 @@ func TestCreate(t *testing.T) {
 			writeErr: errors.NewInternalServer("error", nil),
 		},
 		{
-			name: "should fail on publish AccountCreate event error",
-			req: &account.Account{
-				ID:           ptrString("123456789012"),
-				AdminRoleArn: arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
-			},
-			exp: response{
-				data: nil,
-				err:  errors.NewInternalServer("error", nil),
-			},
-			getResponse: response{
-				data: nil,
-				err:  errors.NewNotFound("account", "123456789012"),
-			},
-			accountCreateErr: errors.NewInternalServer("error", nil),
-		},
-		{
-			name: "should fail on publish AccountReset event error",
+			name: "should fail on writing the account with its events",
 			req: &account.Account{
 				ID:           ptrString("123456789012"),
 				AdminRoleArn: arn.New("aws", "iam", "", "123456789012", "role/AdminRole"),
@@ -562,7 +554,7 @@ func TestCreate(t *testing.T) {
 				data: nil,
 				err:  errors.NewNotFound("account", "123456789012"),
 			},
-			accountResetErr: errors.NewInternalServer("error", nil),
+			writeWithEventErr: errors.NewInternalServer("error", nil),
 		},
 	}
 
@@ -574,9 +566,15 @@ func TestCreate(t *testing.T) {
 
 			mocksRwd.On("Get", *tt.req.ID).Return(tt.getResponse.data, tt.getResponse.err)
 			mocksRwd.On("Write", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64")).Return(tt.writeErr)
+			// The created and reset events are written with the account
+			mocksRwd.On("WriteWithEvents", mock.AnythingOfType("*account.Account"), mock.AnythingOfType("*int64"),
+				mock.MatchedBy(func(entries []*outbox.Entry) bool {
+					return len(entries) == 2 &&
+						*entries[0].Type == outbox.TypeAccountCreated &&
+						*entries[1].Type == outbox.TypeAccountReset
+				}),
+			).Return(tt.writeWithEventErr)
 			mocksManager.On("UpsertPrincipalAccess", mock.AnythingOfType("*account.Account")).Return(nil)
-			mocksEventer.On("AccountCreate", mock.AnythingOfType("*account.Account")).Return(tt.accountCreateErr)
-			mocksEventer.On("AccountReset", mock.AnythingOfType("*account.Account")).Return(tt.accountResetErr)
 
 			accountSvc := account.NewService(
 				account.NewServiceInput{
@@ -13,6 +13,8 @@ type Servicer interface {
 	ValidateAccess(role *arn.ARN) error
 	// UpsertPrincipalAccess creates roles, policies and update them as needed
 	UpsertPrincipalAccess(account *account.Account) error
+	// ValidatePrincipalAccess checks the principal role and policy exist and are up to date
+	ValidatePrincipalAccess(account *account.Account) error
 	// DeletePrincipalAccess removes all the principal roles and policies
 	DeletePrincipalAccess(account *account.Account) error
 }
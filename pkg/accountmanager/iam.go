@@ -41,6 +41,21 @@ func (p *principalService) MergeRole() error {
 	return nil
 }
 
+func (p *principalService) ValidateRole() error {
+
+	_, err := p.iamSvc.GetRole(&iam.GetRoleInput{
+		RoleName: p.account.PrincipalRoleArn.IAMResourceName(),
+	})
+	if err != nil {
+		if isAWSNoSuchEntityError(err) {
+			return errors.NewValidation("account", fmt.Errorf("principal role %q does not exist", p.account.PrincipalRoleArn.String()))
+		}
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error getting role %q", p.account.PrincipalRoleArn.String()), err)
+	}
+
+	return nil
+}
+
 func (p *principalService) DeleteRole() error {
 
 	_, err := p.iamSvc.DeleteRole(&iam.DeleteRoleInput{
@@ -108,6 +123,48 @@ func (p *principalService) MergePolicy() error {
 	return nil
 }
 
+func (p *principalService) ValidatePolicy() error {
+
+	_, err := p.iamSvc.GetPolicy(&iam.GetPolicyInput{
+		PolicyArn: aws.String(p.account.PrincipalPolicyArn.String()),
+	})
+	if err != nil {
+		if isAWSNoSuchEntityError(err) {
+			return errors.NewValidation("account", fmt.Errorf("principal policy %q does not exist", p.account.PrincipalPolicyArn.String()))
+		}
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error getting policy %q", p.account.PrincipalPolicyArn.String()), err)
+	}
+
+	// The policy must still be attached to the role.
+	// Roles have at most 20 managed policies, so they're listed in a single page
+	attachedPolicies, err := p.iamSvc.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+		RoleName: p.account.PrincipalRoleArn.IAMResourceName(),
+	})
+	if err != nil {
+		return errors.NewInternalServer(fmt.Sprintf("unexpected error listing policies attached to role %q", p.account.PrincipalRoleArn.String()), err)
+	}
+	attached := false
+	for _, policy := range attachedPolicies.AttachedPolicies {
+		if aws.StringValue(policy.PolicyArn) == p.account.PrincipalPolicyArn.String() {
+			attached = true
+		}
+	}
+	if !attached {
+		return errors.NewValidation("account", fmt.Errorf("principal policy %q is not attached to role %q", p.account.PrincipalPolicyArn.String(), p.account.PrincipalRoleArn.String()))
+	}
+
+	// The policy must be the latest version of the policy template
+	_, policyHash, err := p.buildPolicy()
+	if err != nil {
+		return err
+	}
+	if aws.StringValue(p.account.PrincipalPolicyHash) != aws.StringValue(policyHash) {
+		return errors.NewValidation("account", fmt.Errorf("principal policy hash %q does not match the expected hash %q", aws.StringValue(p.account.PrincipalPolicyHash), aws.StringValue(policyHash)))
+	}
+
+	return nil
+}
+
 func (p *principalService) DeletePolicy() error {
 
 	versions, err := p.iamSvc.ListPolicyVersions(&iam.ListPolicyVersionsInput{
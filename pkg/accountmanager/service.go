@@ -87,6 +87,40 @@ func (s *Service) UpsertPrincipalAccess(account *account.Account) error {
 	return nil
 }
 
+// ValidatePrincipalAccess checks the principal role and policy exist,
+// the policy is attached to the role, and the policy is up to date
+func (s *Service) ValidatePrincipalAccess(account *account.Account) error {
+	err := validation.ValidateStruct(account,
+		validation.Field(&account.AdminRoleArn, validation.NotNil),
+		validation.Field(&account.PrincipalRoleArn, validation.NotNil),
+		validation.Field(&account.PrincipalPolicyArn, validation.NotNil),
+	)
+	if err != nil {
+		return errors.NewValidation("account", err)
+	}
+
+	iamSvc := s.client.IAM(account.AdminRoleArn)
+
+	principalSvc := principalService{
+		iamSvc:   iamSvc,
+		storager: s.storager,
+		account:  account,
+		config:   s.config,
+	}
+
+	err = principalSvc.ValidateRole()
+	if err != nil {
+		return err
+	}
+
+	err = principalSvc.ValidatePolicy()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeletePrincipalAccess removes all the principal roles and policies
 func (s *Service) DeletePrincipalAccess(account *account.Account) error {
 	err := validation.ValidateStruct(account,
@@ -479,3 +479,99 @@ func TestDeletePrincipalAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePrincipalAccess(t *testing.T) {
+
+	principalRoleArn := arn.New("aws", "iam", "", "123456789012", "role/DCEPrincipal")
+	principalPolicyArn := arn.New("aws", "iam", "", "123456789012", "policy/DCEPrincipalDefaultPolicy")
+
+	tests := []struct {
+		name                 string
+		exp                  error
+		policyHash           *string
+		getRoleErr           error
+		getPolicyErr         error
+		attachedPolicies     []*iam.AttachedPolicy
+		listAttachedPolicies error
+	}{
+		{
+			name:       "should pass when the role and policy are up to date",
+			policyHash: aws.String("123"),
+			attachedPolicies: []*iam.AttachedPolicy{
+				{PolicyArn: aws.String(principalPolicyArn.String())},
+			},
+		},
+		{
+			name:       "should fail when the role doesn't exist",
+			policyHash: aws.String("123"),
+			getRoleErr: awserr.New(iam.ErrCodeNoSuchEntityException, "Not Found", nil),
+			exp:        errors.NewValidation("account", fmt.Errorf("principal role \"arn:aws:iam::123456789012:role/DCEPrincipal\" does not exist")),
+		},
+		{
+			name:         "should fail when the policy doesn't exist",
+			policyHash:   aws.String("123"),
+			getPolicyErr: awserr.New(iam.ErrCodeNoSuchEntityException, "Not Found", nil),
+			exp:          errors.NewValidation("account", fmt.Errorf("principal policy \"arn:aws:iam::123456789012:policy/DCEPrincipalDefaultPolicy\" does not exist")),
+		},
+		{
+			name:       "should fail when the policy isn't attached to the role",
+			policyHash: aws.String("123"),
+			attachedPolicies: []*iam.AttachedPolicy{
+				{PolicyArn: aws.String("arn:aws:iam::123456789012:policy/Other")},
+			},
+			exp: errors.NewValidation("account", fmt.Errorf("principal policy \"arn:aws:iam::123456789012:policy/DCEPrincipalDefaultPolicy\" is not attached to role \"arn:aws:iam::123456789012:role/DCEPrincipal\"")),
+		},
+		{
+			name:       "should fail when the policy hash is out of date",
+			policyHash: aws.String("122"),
+			attachedPolicies: []*iam.AttachedPolicy{
+				{PolicyArn: aws.String(principalPolicyArn.String())},
+			},
+			exp: errors.NewValidation("account", fmt.Errorf("principal policy hash \"122\" does not match the expected hash \"123\"")),
+		},
+		{
+			name:       "should return unexpected IAM errors",
+			policyHash: aws.String("123"),
+			getRoleErr: awserr.New(iam.ErrCodeServiceFailureException, "Failure", nil),
+			exp:        errors.NewInternalServer("unexpected error getting role \"arn:aws:iam::123456789012:role/DCEPrincipal\"", awserr.New(iam.ErrCodeServiceFailureException, "Failure", nil)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iamSvc := &awsMocks.IAM{}
+			iamSvc.On("GetRole", mock.AnythingOfType("*iam.GetRoleInput")).
+				Return(&iam.GetRoleOutput{}, tt.getRoleErr)
+			iamSvc.On("GetPolicy", mock.AnythingOfType("*iam.GetPolicyInput")).
+				Return(&iam.GetPolicyOutput{}, tt.getPolicyErr)
+			iamSvc.On("ListAttachedRolePolicies", mock.AnythingOfType("*iam.ListAttachedRolePoliciesInput")).
+				Return(&iam.ListAttachedRolePoliciesOutput{AttachedPolicies: tt.attachedPolicies}, tt.listAttachedPolicies)
+
+			storagerSvc := &commonMocks.Storager{}
+			storagerSvc.On(
+				"GetTemplateObject", "DefaultArtifactBucket", "DefaultPrincipalPolicyS3Key",
+				mock.Anything).Return("", "123", nil)
+
+			clientSvc := &mocks.Clienter{}
+			clientSvc.On("IAM", mock.Anything).Return(iamSvc)
+
+			amSvc, err := NewService(NewServiceInput{
+				Session:  session.Must(session.NewSession()),
+				Storager: storagerSvc,
+				Config:   testConfig,
+			})
+			amSvc.client = clientSvc
+
+			assert.Nil(t, err)
+
+			err = amSvc.ValidatePrincipalAccess(&account.Account{
+				ID:                  aws.String("123456789012"),
+				PrincipalRoleArn:    principalRoleArn,
+				AdminRoleArn:        arn.New("aws", "iam", "", "123456789012", "role/AdminAccess"),
+				PrincipalPolicyArn:  principalPolicyArn,
+				PrincipalPolicyHash: tt.policyHash,
+			})
+			assert.True(t, errors.Is(err, tt.exp), "actual error %+v doesn't match expected error %+v", err, tt.exp)
+		})
+	}
+}
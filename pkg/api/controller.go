@@ -18,7 +18,8 @@ type Controller interface {
 	Call(ctx context.Context, req *events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
 }
 
-// Router structure holds AccountController instance for request
+// Router structure holds AccountController instance for request.  Requests
+// are only routed for users granted the Permission
 type Router struct {
 	ResourceName     string
 	ListController   Controller
@@ -26,6 +27,7 @@ type Router struct {
 	GetController    Controller
 	CreateController Controller
 	UserDetails      UserDetailer
+	Permission       Permission
 }
 
 // Route - provides a router for the given resource
@@ -38,6 +40,11 @@ func (router *Router) Route(ctx context.Context, req *events.APIGatewayProxyRequ
 	requestUser := router.UserDetails.GetUser(req)
 	ctxWithUser := context.WithValue(ctx, DceCtxKey, *requestUser)
 
+	if router.Permission != PermissionNone && !DefaultAuthorizer.Allowed(requestUser, router.Permission) {
+		log.Printf("User %q with role %q doesn't have permission %s", requestUser.Username, requestUser.Role, router.Permission)
+		return response.ForbiddenError(), nil
+	}
+
 	switch {
 	case req.HTTPMethod == http.MethodGet && strings.HasSuffix(req.Path, router.ResourceName):
 		res, err = router.ListController.Call(ctxWithUser, req)
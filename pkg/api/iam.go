@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// IAMUserDetails - Gets User information for callers who sign requests with
+// the credentials of an IAM principal (SigV4).  Callers are given a role by
+// the role mappings of the `arn` and `accountId` of their principal.  Callers
+// who aren't mapped are given the default role, or denied if it's empty
+type IAMUserDetails struct {
+	DefaultRole  string `env:"IAM_DEFAULT_ROLE"`
+	RoleMappings RoleMappings
+}
+
+// GetUser - Gets the username and role of the IAM principal which signed the
+// request.  The username is the ARN of the principal, which is the ARN of the
+// role for assumed roles.  Session names are chosen by the caller, so they
+// don't identify the user
+func (u *IAMUserDetails) GetUser(event *events.APIGatewayProxyRequest) *User {
+
+	identity := event.RequestContext.Identity
+	if identity.UserArn == "" {
+		log.Printf("Request isn't signed by an IAM principal")
+		return &User{}
+	}
+
+	claims := map[string]interface{}{
+		"arn":       identity.UserArn,
+		"accountId": identity.AccountID,
+	}
+
+	return newUser(principalARN(identity.UserArn), u.RoleMappings.Roles(claims, u.DefaultRole))
+}
+
+// principalARN returns the ARN of the IAM principal which signed a request,
+// e.g. `arn:aws:iam::123456789012:role/Role` for
+// `arn:aws:sts::123456789012:assumed-role/Role/session`
+func principalARN(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" || !strings.HasPrefix(parts[5], "assumed-role/") {
+		return arn
+	}
+
+	resource := strings.Split(strings.TrimPrefix(parts[5], "assumed-role/"), "/")
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], resource[0])
+}
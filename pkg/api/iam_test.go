@@ -0,0 +1,75 @@
+package api_test
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIAMUser(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		identity events.APIGatewayRequestIdentity
+		expUser  *api.User
+	}{
+		{
+			name: "should map an assumed role to a role",
+			identity: events.APIGatewayRequestIdentity{
+				AccountID: "123456789012",
+				UserArn:   "arn:aws:sts::123456789012:assumed-role/Auditors/jdoe",
+			},
+			expUser: &api.User{Username: "arn:aws:iam::123456789012:role/Auditors", Role: api.AuditorGroupName, Roles: []string{api.AuditorGroupName}},
+		},
+		{
+			name: "should map an account to a role",
+			identity: events.APIGatewayRequestIdentity{
+				AccountID: "210987654321",
+				UserArn:   "arn:aws:iam::210987654321:user/ci/deployer",
+			},
+			expUser: &api.User{Username: "arn:aws:iam::210987654321:user/ci/deployer", Role: api.PoolOperatorGroupName, Roles: []string{api.PoolOperatorGroupName}},
+		},
+		{
+			name: "should use the default role",
+			identity: events.APIGatewayRequestIdentity{
+				AccountID: "123456789012",
+				UserArn:   "arn:aws:iam::123456789012:root",
+			},
+			expUser: &api.User{Username: "arn:aws:iam::123456789012:root", Role: api.UserGroupName, Roles: []string{api.UserGroupName}},
+		},
+		{
+			name: "should not take the username from the session name",
+			identity: events.APIGatewayRequestIdentity{
+				AccountID: "123456789012",
+				UserArn:   "arn:aws:sts::123456789012:assumed-role/Developers/admin",
+			},
+			expUser: &api.User{Username: "arn:aws:iam::123456789012:role/Developers", Role: api.UserGroupName, Roles: []string{api.UserGroupName}},
+		},
+		{
+			name:     "should not get a user for unsigned requests",
+			identity: events.APIGatewayRequestIdentity{},
+			expUser:  &api.User{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userGetter := &api.IAMUserDetails{
+				DefaultRole: api.UserGroupName,
+				RoleMappings: api.RoleMappings{
+					{Claim: "arn", Value: "arn:aws:sts::*:assumed-role/Auditors/*", Role: api.AuditorGroupName},
+					{Claim: "accountId", Value: "210987654321", Role: api.PoolOperatorGroupName},
+				},
+			}
+
+			user := userGetter.GetUser(&events.APIGatewayProxyRequest{
+				RequestContext: events.APIGatewayProxyRequestContext{
+					Identity: tt.identity,
+				},
+			})
+			assert.Equal(t, tt.expUser, user)
+		})
+	}
+}
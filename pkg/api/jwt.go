@@ -0,0 +1,358 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwtLeeway allows for clock skew when checking the times in JWT claims
+const jwtLeeway = time.Minute
+
+// KeySet - The public keys which JWTs are signed with
+type KeySet interface {
+	Key(kid string) (crypto.PublicKey, error)
+}
+
+// jsonWebKey - A key in a JSON Web Key Set
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// LocalKeySet - A JSON Web Key Set held in memory
+type LocalKeySet struct {
+	keys map[string]crypto.PublicKey
+}
+
+// NewLocalKeySet parses a JSON Web Key Set, e.g. {"keys": [{"kty": "RSA", ...}]}.
+// Only RSA and EC signing keys are used
+func NewLocalKeySet(jwksJSON []byte) (*LocalKeySet, error) {
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	err := json.Unmarshal(jwksJSON, &jwks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON Web Key Set: %s", err)
+	}
+
+	keySet := &LocalKeySet{keys: map[string]crypto.PublicKey{}}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		var key crypto.PublicKey
+		switch jwk.Kty {
+		case "RSA":
+			key, err = rsaPublicKey(jwk)
+		case "EC":
+			key, err = ecdsaPublicKey(jwk)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse key %q: %s", jwk.Kid, err)
+		}
+		keySet.keys[jwk.Kid] = key
+	}
+
+	return keySet, nil
+}
+
+// Key returns the public key with the key ID
+func (k *LocalKeySet) Key(kid string) (crypto.PublicKey, error) {
+	key, ok := k.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no key with ID %q", kid)
+	}
+	return key, nil
+}
+
+func rsaPublicKey(jwk jsonWebKey) (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeBigInt(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid RSA exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func ecdsaPublicKey(jwk jsonWebKey) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch jwk.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+	}
+	x, err := decodeBigInt(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := decodeBigInt(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("point isn't on curve %q", jwk.Crv)
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
+
+// RemoteKeySet - A JSON Web Key Set fetched from a URL, such as an OpenID
+// Connect provider's `jwks_uri`.  The keys are fetched again once they're
+// stale, or when a JWT is signed with a key which isn't in the set
+type RemoteKeySet struct {
+	URL        string
+	HTTPClient *http.Client
+	MaxAge     time.Duration
+	mutex      sync.Mutex
+	keys       *LocalKeySet
+	fetchedOn  time.Time
+}
+
+// NewRemoteKeySet creates a key set which fetches keys from the URL, and
+// caches them for an hour
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		URL:        url,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		MaxAge:     time.Hour,
+	}
+}
+
+// Key returns the public key with the key ID
+func (r *RemoteKeySet) Key(kid string) (crypto.PublicKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.keys == nil || time.Since(r.fetchedOn) > r.MaxAge {
+		err := r.fetch()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := r.keys.Key(kid)
+	// Keys may have been rotated, but don't fetch them more than once a minute
+	if err != nil && time.Since(r.fetchedOn) > time.Minute {
+		err = r.fetch()
+		if err != nil {
+			return nil, err
+		}
+		key, err = r.keys.Key(kid)
+	}
+	return key, err
+}
+
+func (r *RemoteKeySet) fetch() error {
+	res, err := r.HTTPClient.Get(r.URL)
+	if err != nil {
+		return fmt.Errorf("failed to get JSON Web Key Set from %s: %s", r.URL, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get JSON Web Key Set from %s: %s", r.URL, res.Status)
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read JSON Web Key Set from %s: %s", r.URL, err)
+	}
+
+	keys, err := NewLocalKeySet(body)
+	if err != nil {
+		return err
+	}
+	r.keys = keys
+	r.fetchedOn = time.Now()
+	return nil
+}
+
+// JWTVerifier - Verifies the signature of JWTs, and that they're valid for
+// the issuer and audience
+type JWTVerifier struct {
+	Keys     KeySet
+	Issuer   string
+	Audience string
+}
+
+// Verify returns the claims of the JWT if it's signed by a key in the key
+// set, hasn't expired, and is for the issuer and audience.  The issuer and
+// audience aren't checked if they're empty
+func (v *JWTVerifier) Verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT")
+	}
+
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT header: %s", err)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT signature: %s", err)
+	}
+
+	key, err := v.Keys.Key(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	err = verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := map[string]interface{}{}
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, fmt.Errorf("malformed JWT claims: %s", err)
+	}
+	err = v.verifyClaims(claims, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (v *JWTVerifier) verifyClaims(claims map[string]interface{}, now time.Time) error {
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("JWT doesn't have an expiration time")
+	}
+	if now.Add(-jwtLeeway).Unix() >= int64(exp) {
+		return fmt.Errorf("JWT has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(jwtLeeway).Unix() < int64(nbf) {
+		return fmt.Errorf("JWT isn't valid yet")
+	}
+
+	if v.Issuer != "" && claims["iss"] != v.Issuer {
+		return fmt.Errorf("JWT has issuer %v instead of %s", claims["iss"], v.Issuer)
+	}
+	if v.Audience != "" {
+		for _, aud := range claimValues(claims["aud"]) {
+			if aud == v.Audience {
+				return nil
+			}
+		}
+		return fmt.Errorf("JWT isn't for audience %s", v.Audience)
+	}
+
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	bytes, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, v)
+}
+
+// verifySignature checks the signature with the algorithm in the JWT header.
+// Symmetric algorithms and `none` aren't supported, so tokens can only be
+// signed by the holder of a private key
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	if len(alg) != 5 {
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+	var hashFunc crypto.Hash
+	var hasher hash.Hash
+	switch alg[2:] {
+	case "256":
+		hashFunc, hasher = crypto.SHA256, sha256.New()
+	case "384":
+		hashFunc, hasher = crypto.SHA384, sha512.New384()
+	case "512":
+		hashFunc, hasher = crypto.SHA512, sha512.New()
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+	hasher.Write([]byte(signingInput))
+	digest := hasher.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("JWT algorithm %q doesn't match its key", alg)
+		}
+		var err error
+		if alg[:2] == "RS" {
+			err = rsa.VerifyPKCS1v15(rsaKey, hashFunc, digest, signature)
+		} else {
+			err = rsa.VerifyPSS(rsaKey, hashFunc, digest, signature, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid JWT signature")
+		}
+	case "ES":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve.Params().BitSize != curveBitSizes[alg] {
+			return fmt.Errorf("JWT algorithm %q doesn't match its key", alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid JWT signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return fmt.Errorf("invalid JWT signature")
+		}
+	default:
+		return fmt.Errorf("unsupported JWT algorithm %q", alg)
+	}
+
+	return nil
+}
+
+// curveBitSizes are the sizes of the curves used by each ECDSA algorithm
+var curveBitSizes = map[string]int{
+	"ES256": 256,
+	"ES384": 384,
+	"ES512": 521,
+}
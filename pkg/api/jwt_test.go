@@ -0,0 +1,252 @@
+package api_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeys are the private keys of the local key set tokens are signed with
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	return &testKeys{rsa: rsaKey, ec: ecKey}
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// jwks returns the JSON Web Key Set of the public keys
+func (k *testKeys) jwks() []byte {
+	jwks, _ := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{
+				"kty": "RSA",
+				"kid": "rsa-key",
+				"use": "sig",
+				"n":   encodeSegment(k.rsa.N.Bytes()),
+				"e":   encodeSegment(big.NewInt(int64(k.rsa.E)).Bytes()),
+			},
+			{
+				"kty": "EC",
+				"kid": "ec-key",
+				"crv": "P-256",
+				"x":   encodeSegment(k.ec.X.Bytes()),
+				"y":   encodeSegment(k.ec.Y.Bytes()),
+			},
+			{
+				"kty": "oct",
+				"kid": "symmetric-key",
+				"k":   "c2VjcmV0",
+			},
+		},
+	})
+	return jwks
+}
+
+// sign creates a JWT with the claims, signed by the key with the algorithm
+func (k *testKeys) sign(t *testing.T, alg string, kid string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	var err error
+	switch alg {
+	case "RS256":
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k.rsa, crypto.SHA256, digest[:])
+	case "ES256":
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k.ec, digest[:])
+		signature = make([]byte, 64)
+		if err == nil {
+			rBytes, sBytes := r.Bytes(), s.Bytes()
+			copy(signature[32-len(rBytes):32], rBytes)
+			copy(signature[64-len(sBytes):], sBytes)
+		}
+	case "none":
+	default:
+		t.Fatalf("unexpected algorithm %s", alg)
+	}
+	require.Nil(t, err)
+
+	return signingInput + "." + encodeSegment(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub": "user1",
+		"iss": "https://idp.example.com",
+		"aud": []string{"dce", "other"},
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTVerifier(t *testing.T) {
+	keys := newTestKeys(t)
+	keySet, err := api.NewLocalKeySet(keys.jwks())
+	require.Nil(t, err)
+
+	withClaim := func(name string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		claims[name] = value
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		expErr error
+	}{
+		{
+			name:  "should verify an RSA signed token",
+			token: keys.sign(t, "RS256", "rsa-key", validClaims()),
+		},
+		{
+			name:  "should verify an EC signed token",
+			token: keys.sign(t, "ES256", "ec-key", validClaims()),
+		},
+		{
+			name:  "should verify a token which expired within the leeway",
+			token: keys.sign(t, "RS256", "rsa-key", withClaim("exp", time.Now().Add(-30*time.Second).Unix())),
+		},
+		{
+			name:   "should fail on an expired token",
+			token:  keys.sign(t, "RS256", "rsa-key", withClaim("exp", time.Now().Add(-time.Hour).Unix())),
+			expErr: fmt.Errorf("JWT has expired"),
+		},
+		{
+			name:   "should fail on a token without an expiration time",
+			token:  keys.sign(t, "RS256", "rsa-key", withClaim("exp", nil)),
+			expErr: fmt.Errorf("JWT doesn't have an expiration time"),
+		},
+		{
+			name:   "should fail on a token which isn't valid yet",
+			token:  keys.sign(t, "RS256", "rsa-key", withClaim("nbf", time.Now().Add(time.Hour).Unix())),
+			expErr: fmt.Errorf("JWT isn't valid yet"),
+		},
+		{
+			name:   "should fail on another issuer",
+			token:  keys.sign(t, "RS256", "rsa-key", withClaim("iss", "https://other.example.com")),
+			expErr: fmt.Errorf("JWT has issuer https://other.example.com instead of https://idp.example.com"),
+		},
+		{
+			name:   "should fail on another audience",
+			token:  keys.sign(t, "RS256", "rsa-key", withClaim("aud", "other")),
+			expErr: fmt.Errorf("JWT isn't for audience dce"),
+		},
+		{
+			name:   "should fail on an unknown key",
+			token:  keys.sign(t, "RS256", "other-key", validClaims()),
+			expErr: fmt.Errorf("no key with ID \"other-key\""),
+		},
+		{
+			name:   "should fail on a key which doesn't match the algorithm",
+			token:  keys.sign(t, "ES256", "rsa-key", validClaims()),
+			expErr: fmt.Errorf("JWT algorithm \"ES256\" doesn't match its key"),
+		},
+		{
+			name:   "should fail on an unsigned token",
+			token:  keys.sign(t, "none", "rsa-key", validClaims()),
+			expErr: fmt.Errorf("unsupported JWT algorithm \"none\""),
+		},
+		{
+			name:   "should fail on a symmetric key",
+			token:  keys.sign(t, "none", "symmetric-key", validClaims()),
+			expErr: fmt.Errorf("no key with ID \"symmetric-key\""),
+		},
+		{
+			name: "should fail on a token with modified claims",
+			token: func() string {
+				token := strings.Split(keys.sign(t, "RS256", "rsa-key", validClaims()), ".")
+				other := strings.Split(keys.sign(t, "RS256", "rsa-key", withClaim("sub", "admin")), ".")
+				return strings.Join([]string{token[0], other[1], token[2]}, ".")
+			}(),
+			expErr: fmt.Errorf("invalid JWT signature"),
+		},
+		{
+			name:   "should fail on a malformed token",
+			token:  "abc.def",
+			expErr: fmt.Errorf("malformed JWT"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := &api.JWTVerifier{
+				Keys:     keySet,
+				Issuer:   "https://idp.example.com",
+				Audience: "dce",
+			}
+
+			claims, err := verifier.Verify(tt.token)
+			assert.Equal(t, tt.expErr, err)
+			if tt.expErr == nil {
+				assert.Equal(t, "user1", claims["sub"])
+			}
+		})
+	}
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	keys := newTestKeys(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write(keys.jwks())
+	}))
+	defer server.Close()
+
+	keySet := api.NewRemoteKeySet(server.URL)
+
+	t.Run("should fetch keys once", func(t *testing.T) {
+		key, err := keySet.Key("rsa-key")
+		assert.Nil(t, err)
+		assert.Equal(t, &keys.rsa.PublicKey, key)
+
+		key, err = keySet.Key("ec-key")
+		assert.Nil(t, err)
+		assert.Equal(t, &keys.ec.PublicKey, key)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("should not fetch keys again for an unknown key", func(t *testing.T) {
+		_, err := keySet.Key("other-key")
+		assert.Equal(t, fmt.Errorf("no key with ID \"other-key\""), err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("should fetch stale keys again", func(t *testing.T) {
+		keySet.MaxAge = 0
+		_, err := keySet.Key("rsa-key")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+}
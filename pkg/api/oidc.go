@@ -0,0 +1,99 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// OIDCUserDetails - Gets User information from the claims of an OpenID
+// Connect ID token, or another JWT, sent in an `Authorization: Bearer` header.
+// Users are given a role by the role mappings of their claims
+type OIDCUserDetails struct {
+	JWKSURL       string `env:"OIDC_JWKS_URL"`
+	Issuer        string `env:"OIDC_ISSUER"`
+	Audience      string `env:"OIDC_AUDIENCE"`
+	UsernameClaim string `env:"OIDC_USERNAME_CLAIM" envDefault:"sub"`
+	DefaultRole   string `env:"OIDC_DEFAULT_ROLE" envDefault:"User"`
+	RoleMappings  RoleMappings
+	Keys          KeySet
+}
+
+// Validate returns an error unless bearer tokens are verified with a key set,
+// for an issuer and audience.  Without an issuer and audience, tokens issued
+// to any client of the identity provider would be accepted
+func (u *OIDCUserDetails) Validate() error {
+	if u.JWKSURL == "" {
+		return fmt.Errorf("OIDC_JWKS_URL is required by the %s identity provider", IdentityProviderOIDC)
+	}
+	if u.Issuer == "" {
+		return fmt.Errorf("OIDC_ISSUER is required by the %s identity provider", IdentityProviderOIDC)
+	}
+	if u.Audience == "" {
+		return fmt.Errorf("OIDC_AUDIENCE is required by the %s identity provider", IdentityProviderOIDC)
+	}
+	if _, ok := DefaultPolicy[u.DefaultRole]; u.DefaultRole != "" && !ok {
+		return fmt.Errorf("invalid OIDC_DEFAULT_ROLE %q", u.DefaultRole)
+	}
+	return nil
+}
+
+// GetUser - Gets the username and roles out of the claims of the bearer token
+func (u *OIDCUserDetails) GetUser(event *events.APIGatewayProxyRequest) *User {
+
+	token := bearerToken(event.Headers)
+	if token == "" {
+		log.Printf("Request doesn't have a bearer token")
+		return &User{}
+	}
+
+	username, claims, err := u.Authenticate(token)
+	if err != nil {
+		log.Printf("Failed to authenticate bearer token: %s", err)
+		return &User{}
+	}
+
+	return newUser(username, u.RoleMappings.Roles(claims, u.DefaultRole))
+}
+
+// Authenticate returns the username and claims of the token, once it's verified
+func (u *OIDCUserDetails) Authenticate(token string) (string, map[string]interface{}, error) {
+	verifier := &JWTVerifier{
+		Keys:     u.Keys,
+		Issuer:   u.Issuer,
+		Audience: u.Audience,
+	}
+	claims, err := verifier.Verify(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	username, ok := claims[u.UsernameClaim].(string)
+	if !ok || username == "" {
+		return "", nil, fmt.Errorf("token doesn't have a %q claim", u.UsernameClaim)
+	}
+	return username, claims, nil
+}
+
+// bearerToken returns the token from the request's `Authorization` header,
+// or an empty string if it doesn't have a bearer token
+func bearerToken(headers map[string]string) string {
+	for name, value := range headers {
+		if strings.EqualFold(name, "Authorization") {
+			return BearerToken(value)
+		}
+	}
+	return ""
+}
+
+// BearerToken returns the token from the value of an `Authorization` header,
+// or an empty string if it isn't a bearer token
+func BearerToken(authorization string) string {
+	parts := strings.SplitN(strings.TrimSpace(authorization), " ", 2)
+	if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+		return strings.TrimSpace(parts[1])
+	}
+	return ""
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/Optum/dce/pkg/errors"
+)
+
+// PoolOperatorGroupName - Has a string to define Pool Operators, who manage
+// the account pool without access to lease data
+const PoolOperatorGroupName = "PoolOperator"
+
+// AuditorGroupName - Has a string to define Auditors, who have read-only
+// access to everything
+const AuditorGroupName = "Auditor"
+
+// TeamLeadGroupName - Has a string to define Team Leads, who manage the
+// leases of their team's principals
+const TeamLeadGroupName = "TeamLead"
+
+// Permission - An action on a type of resource, required by a route
+type Permission string
+
+// Permissions required by the routes of the API
+const (
+	// PermissionNone - The route doesn't require authorization
+	PermissionNone Permission = ""
+	// PermissionAccountsRead - View accounts in the pool
+	PermissionAccountsRead Permission = "accounts:read"
+	// PermissionAccountsWrite - Add, update and remove accounts in the pool
+	PermissionAccountsWrite Permission = "accounts:write"
+	// PermissionLeasesRead - View leases
+	PermissionLeasesRead Permission = "leases:read"
+	// PermissionLeasesWrite - Create, update and end leases
+	PermissionLeasesWrite Permission = "leases:write"
+	// PermissionLeasesApprove - Approve and reject leases requested over the max lease budget or period
+	PermissionLeasesApprove Permission = "leases:approve"
+	// PermissionLeasesLogin - Get credentials for the account of a lease
+	PermissionLeasesLogin Permission = "leases:login"
+	// PermissionUsageRead - View the usage of principals
+	PermissionUsageRead Permission = "usage:read"
+	// PermissionWebhooksRead - View webhooks
+	PermissionWebhooksRead Permission = "webhooks:read"
+	// PermissionWebhooksWrite - Add, update and remove webhooks
+	PermissionWebhooksWrite Permission = "webhooks:write"
+	// PermissionTeamsRead - View teams
+	PermissionTeamsRead Permission = "teams:read"
+	// PermissionTeamsWrite - Add, update and remove teams
+	PermissionTeamsWrite Permission = "teams:write"
+	// PermissionPrincipalsRead - View principal profiles
+	PermissionPrincipalsRead Permission = "principals:read"
+	// PermissionPrincipalsWrite - Add, replace and remove principal profiles
+	PermissionPrincipalsWrite Permission = "principals:write"
+)
+
+// Scope - The principals whose resources a permission is granted on
+type Scope int
+
+// Scopes, from narrowest to widest
+const (
+	// ScopeNone - The permission isn't granted
+	ScopeNone Scope = iota
+	// ScopeOwn - The permission is granted on the user's own resources
+	ScopeOwn
+	// ScopeTeam - The permission is granted on the resources of the user, and
+	// of the principals on teams the user leads
+	ScopeTeam
+	// ScopeAll - The permission is granted on every resource
+	ScopeAll
+)
+
+// Grants - The scope a role is granted for each permission
+type Grants map[Permission]Scope
+
+// Policy - The grants of each role
+type Policy map[string]Grants
+
+// DefaultPolicy - The grants of the roles DCE users are assigned
+var DefaultPolicy = Policy{
+	AdminGroupName: Grants{
+		PermissionAccountsRead:    ScopeAll,
+		PermissionAccountsWrite:   ScopeAll,
+		PermissionLeasesRead:      ScopeAll,
+		PermissionLeasesWrite:     ScopeAll,
+		PermissionLeasesApprove:   ScopeAll,
+		PermissionLeasesLogin:     ScopeAll,
+		PermissionUsageRead:       ScopeAll,
+		PermissionWebhooksRead:    ScopeAll,
+		PermissionWebhooksWrite:   ScopeAll,
+		PermissionTeamsRead:       ScopeAll,
+		PermissionTeamsWrite:      ScopeAll,
+		PermissionPrincipalsRead:  ScopeAll,
+		PermissionPrincipalsWrite: ScopeAll,
+	},
+	PoolOperatorGroupName: Grants{
+		PermissionAccountsRead:  ScopeAll,
+		PermissionAccountsWrite: ScopeAll,
+	},
+	AuditorGroupName: Grants{
+		PermissionAccountsRead:   ScopeAll,
+		PermissionLeasesRead:     ScopeAll,
+		PermissionUsageRead:      ScopeAll,
+		PermissionWebhooksRead:   ScopeAll,
+		PermissionTeamsRead:      ScopeAll,
+		PermissionPrincipalsRead: ScopeAll,
+	},
+	TeamLeadGroupName: Grants{
+		PermissionLeasesRead:  ScopeTeam,
+		PermissionLeasesWrite: ScopeTeam,
+		PermissionLeasesLogin: ScopeOwn,
+		PermissionUsageRead:   ScopeTeam,
+		PermissionTeamsRead:   ScopeOwn,
+	},
+	UserGroupName: Grants{
+		PermissionLeasesRead:  ScopeOwn,
+		PermissionLeasesWrite: ScopeOwn,
+		PermissionLeasesLogin: ScopeOwn,
+		PermissionUsageRead:   ScopeOwn,
+		PermissionTeamsRead:   ScopeOwn,
+	},
+}
+
+// TeamLister - Lists the principals on the teams led by a principal
+type TeamLister interface {
+	TeamMembers(leadPrincipalID string) ([]string, error)
+}
+
+// teamMembers holds the principals on the teams a user leads, so they're
+// only listed once for each request
+type teamMembers struct {
+	once       sync.Once
+	principals []string
+	err        error
+}
+
+// Authorizer - Decides which requests users may make, according to a policy
+type Authorizer struct {
+	Policy Policy
+	// Teams is used to authorize grants with ScopeTeam.  Without it, those
+	// grants only cover the user's own resources
+	Teams TeamLister
+}
+
+// DefaultAuthorizer - The authorizer used by routers and handlers
+var DefaultAuthorizer = &Authorizer{
+	Policy: DefaultPolicy,
+}
+
+// Scope returns the widest scope the user's roles are granted for the permission
+func (a *Authorizer) Scope(user *User, permission Permission) Scope {
+	if user == nil {
+		return ScopeNone
+	}
+	roles := user.Roles
+	if len(roles) == 0 {
+		roles = []string{user.Role}
+	}
+	scope := ScopeNone
+	for _, role := range roles {
+		if roleScope := a.Policy[role][permission]; roleScope > scope {
+			scope = roleScope
+		}
+	}
+	return scope
+}
+
+// Allowed returns true if the user is granted the permission on any resources
+func (a *Authorizer) Allowed(user *User, permission Permission) bool {
+	return a.Scope(user, permission) != ScopeNone
+}
+
+// AllowedFor returns true if the user is granted the permission on the
+// resources of the principal
+func (a *Authorizer) AllowedFor(user *User, permission Permission, principalID string) bool {
+	switch a.Scope(user, permission) {
+	case ScopeAll:
+		return true
+	case ScopeTeam:
+		if user.Username == "" {
+			return false
+		}
+		principals, err := a.TeamMembers(user)
+		if err != nil {
+			log.Printf("Failed to list the principals on teams led by %s: %s", user.Username, err)
+			return false
+		}
+		for _, p := range principals {
+			if p == principalID {
+				return true
+			}
+		}
+		return false
+	case ScopeOwn:
+		return user.Username != "" && principalID == user.Username
+	}
+	return false
+}
+
+// TeamMembers returns the user and the principals on the teams they lead.
+// Users carried by a request context only have their teams listed once
+func (a *Authorizer) TeamMembers(user *User) ([]string, error) {
+	list := func() ([]string, error) {
+		principals := []string{user.Username}
+		if a.Teams == nil {
+			return principals, nil
+		}
+		members, err := a.Teams.TeamMembers(user.Username)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member != user.Username {
+				principals = append(principals, member)
+			}
+		}
+		return principals, nil
+	}
+
+	if user.teamMembers == nil {
+		return list()
+	}
+	user.teamMembers.once.Do(func() {
+		user.teamMembers.principals, user.teamMembers.err = list()
+	})
+	return user.teamMembers.principals, user.teamMembers.err
+}
+
+// WithUser returns a copy of the context which carries the user making the request
+func WithUser(ctx context.Context, user *User) context.Context {
+	ctxUser := *user
+	if ctxUser.teamMembers == nil {
+		ctxUser.teamMembers = &teamMembers{}
+	}
+	return context.WithValue(ctx, DceCtxKey, ctxUser)
+}
+
+// UserFromContext returns the user making the request, or nil if the
+// context doesn't carry one
+func UserFromContext(ctx context.Context) *User {
+	user, ok := ctx.Value(DceCtxKey).(User)
+	if !ok {
+		return nil
+	}
+	return &user
+}
+
+// AllowedFor returns true if the user making the request is granted the
+// permission on the resources of the principal
+func AllowedFor(r *http.Request, permission Permission, principalID string) bool {
+	return DefaultAuthorizer.AllowedFor(UserFromContext(r.Context()), permission, principalID)
+}
+
+// TeamMembersFor returns the user making the request and the principals on
+// the teams they lead
+func TeamMembersFor(r *http.Request) ([]string, error) {
+	user := UserFromContext(r.Context())
+	if user == nil {
+		return []string{}, nil
+	}
+	return DefaultAuthorizer.TeamMembers(user)
+}
+
+// ScopeFor returns the scope the user making the request is granted for the permission
+func ScopeFor(r *http.Request, permission Permission) Scope {
+	return DefaultAuthorizer.Scope(UserFromContext(r.Context()), permission)
+}
+
+// authorize only calls the handler if the user making the request is granted
+// the permission on some resources.  Handlers check the user is granted it
+// on the resources they access
+func authorize(permission Permission, next http.Handler) http.Handler {
+	if permission == PermissionNone {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := UserFromContext(r.Context())
+		if !DefaultAuthorizer.Allowed(user, permission) {
+			username, role := "", ""
+			if user != nil {
+				username, role = user.Username, user.Role
+			}
+			log.Printf("User %q with role %q doesn't have permission %s", username, role, permission)
+			WriteAPIErrorResponse(w,
+				errors.NewForbidden("user is not authorized to perform this operation"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
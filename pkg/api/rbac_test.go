@@ -0,0 +1,237 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/Optum/dce/pkg/api/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizerAllowedFor(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		user        *api.User
+		permission  api.Permission
+		principalID string
+		isOnTeam    bool
+		teamErr     error
+		expAllowed  bool
+	}{
+		{
+			name:        "should allow admins everything",
+			user:        &api.User{Username: "admin", Role: api.AdminGroupName},
+			permission:  api.PermissionLeasesWrite,
+			principalID: "user1",
+			expAllowed:  true,
+		},
+		{
+			name:        "should allow users their own leases",
+			user:        &api.User{Username: "user1", Role: api.UserGroupName},
+			permission:  api.PermissionLeasesWrite,
+			principalID: "user1",
+			expAllowed:  true,
+		},
+		{
+			name:        "should not allow users the leases of others",
+			user:        &api.User{Username: "user1", Role: api.UserGroupName},
+			permission:  api.PermissionLeasesRead,
+			principalID: "user2",
+		},
+		{
+			name:        "should not allow users to approve their own leases",
+			user:        &api.User{Username: "user1", Role: api.UserGroupName},
+			permission:  api.PermissionLeasesApprove,
+			principalID: "user1",
+		},
+		{
+			name:       "should not allow users to manage accounts",
+			user:       &api.User{Username: "user1", Role: api.UserGroupName},
+			permission: api.PermissionAccountsWrite,
+		},
+		{
+			name:       "should allow pool operators to manage accounts",
+			user:       &api.User{Username: "operator", Role: api.PoolOperatorGroupName},
+			permission: api.PermissionAccountsWrite,
+			expAllowed: true,
+		},
+		{
+			name:        "should not allow pool operators to read leases",
+			user:        &api.User{Username: "operator", Role: api.PoolOperatorGroupName},
+			permission:  api.PermissionLeasesRead,
+			principalID: "operator",
+		},
+		{
+			name:        "should allow auditors to read leases",
+			user:        &api.User{Username: "auditor", Role: api.AuditorGroupName},
+			permission:  api.PermissionLeasesRead,
+			principalID: "user1",
+			expAllowed:  true,
+		},
+		{
+			name:        "should not allow auditors to write leases",
+			user:        &api.User{Username: "auditor", Role: api.AuditorGroupName},
+			permission:  api.PermissionLeasesWrite,
+			principalID: "auditor",
+		},
+		{
+			name:        "should allow team leads the leases of their team",
+			user:        &api.User{Username: "lead", Role: api.TeamLeadGroupName},
+			permission:  api.PermissionLeasesWrite,
+			principalID: "user1",
+			isOnTeam:    true,
+			expAllowed:  true,
+		},
+		{
+			name:        "should not allow team leads the leases of other teams",
+			user:        &api.User{Username: "lead", Role: api.TeamLeadGroupName},
+			permission:  api.PermissionLeasesWrite,
+			principalID: "user1",
+		},
+		{
+			name:        "should not allow team leads when teams can't be checked",
+			user:        &api.User{Username: "lead", Role: api.TeamLeadGroupName},
+			permission:  api.PermissionLeasesRead,
+			principalID: "user1",
+			isOnTeam:    true,
+			teamErr:     fmt.Errorf("failure"),
+		},
+		{
+			name:       "should allow users the permissions of all of their roles",
+			user:       &api.User{Username: "operator", Roles: []string{api.PoolOperatorGroupName, api.AuditorGroupName}},
+			permission: api.PermissionUsageRead,
+			expAllowed: true,
+		},
+		{
+			name:        "should allow users the widest scope of their roles",
+			user:        &api.User{Username: "auditor", Roles: []string{api.AuditorGroupName, api.UserGroupName}},
+			permission:  api.PermissionLeasesRead,
+			principalID: "user1",
+			expAllowed:  true,
+		},
+		{
+			name:        "should not allow users permissions none of their roles have",
+			user:        &api.User{Username: "auditor", Roles: []string{api.AuditorGroupName, api.UserGroupName}},
+			permission:  api.PermissionLeasesWrite,
+			principalID: "user1",
+		},
+		{
+			name:        "should not allow requests without a user",
+			permission:  api.PermissionLeasesRead,
+			principalID: "user1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := []string{}
+			if tt.isOnTeam {
+				members = append(members, tt.principalID)
+			}
+			teams := &mocks.TeamLister{}
+			teams.On("TeamMembers", "lead").Return(members, tt.teamErr)
+
+			authorizer := &api.Authorizer{
+				Policy: api.DefaultPolicy,
+				Teams:  teams,
+			}
+
+			assert.Equal(t, tt.expAllowed, authorizer.AllowedFor(tt.user, tt.permission, tt.principalID))
+		})
+	}
+}
+
+func TestAuthorizerListsTeamsOncePerRequest(t *testing.T) {
+	teams := &mocks.TeamLister{}
+	teams.On("TeamMembers", "lead").Return([]string{"user1", "user2"}, nil).Once()
+
+	authorizer := &api.Authorizer{
+		Policy: api.DefaultPolicy,
+		Teams:  teams,
+	}
+
+	ctx := api.WithUser(context.Background(), &api.User{Username: "lead", Role: api.TeamLeadGroupName})
+	user := api.UserFromContext(ctx)
+
+	assert.True(t, authorizer.AllowedFor(user, api.PermissionLeasesRead, "user1"))
+	assert.True(t, authorizer.AllowedFor(api.UserFromContext(ctx), api.PermissionLeasesWrite, "user2"))
+	assert.False(t, authorizer.AllowedFor(user, api.PermissionLeasesRead, "user3"))
+
+	members, err := authorizer.TeamMembers(user)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"lead", "user1", "user2"}, members)
+	teams.AssertNumberOfCalls(t, "TeamMembers", 1)
+}
+
+func TestNewRouterAuthorizesRoutes(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		user       *api.User
+		method     string
+		expStatus  int
+		expHandled bool
+	}{
+		{
+			name:       "should handle routes the user is allowed",
+			user:       &api.User{Username: "auditor", Role: api.AuditorGroupName},
+			method:     http.MethodGet,
+			expStatus:  http.StatusOK,
+			expHandled: true,
+		},
+		{
+			name:      "should forbid routes the user isn't allowed",
+			user:      &api.User{Username: "auditor", Role: api.AuditorGroupName},
+			method:    http.MethodPost,
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "should forbid requests without a user",
+			method:    http.MethodGet,
+			expStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handled := false
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				handled = true
+				w.WriteHeader(http.StatusOK)
+			}
+			router := api.NewRouter(api.Routes{
+				api.Route{
+					Name:        "GetAccounts",
+					Method:      "GET",
+					Pattern:     "/accounts",
+					Queries:     api.EmptyQueryString,
+					HandlerFunc: handler,
+					Permission:  api.PermissionAccountsRead,
+				},
+				api.Route{
+					Name:        "CreateAccount",
+					Method:      "POST",
+					Pattern:     "/accounts",
+					Queries:     api.EmptyQueryString,
+					HandlerFunc: handler,
+					Permission:  api.PermissionAccountsWrite,
+				},
+			})
+
+			r := httptest.NewRequest(tt.method, "http://example.com/accounts", nil)
+			if tt.user != nil {
+				r = r.WithContext(api.WithUser(r.Context(), tt.user))
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.expStatus, w.Code)
+			assert.Equal(t, tt.expHandled, handled)
+		})
+	}
+}
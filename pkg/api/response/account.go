@@ -26,4 +26,5 @@ type AccountResponse struct {
 	PrincipalRoleArn    string                 `json:"principalRoleArn"`    // Assumed by principal users
 	PrincipalPolicyHash string                 `json:"principalPolicyHash"` // The policy used by the PrincipalRoleArn
 	Metadata            map[string]interface{} `json:"metadata"`
+	LastResetResult     *db.ResetResult        `json:"lastResetResult,omitempty"`
 }
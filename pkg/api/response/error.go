@@ -126,6 +126,13 @@ func UnauthorizedError() events.APIGatewayProxyResponse {
 	)
 }
 
+func ForbiddenError() events.APIGatewayProxyResponse {
+	return CreateAPIGatewayErrorResponse(
+		http.StatusForbidden,
+		CreateErrorResponse("Forbidden", "User is not authorized to perform this operation."),
+	)
+}
+
 // WriteServerError - Writes a server error with the specific message.
 func WriteServerError(w http.ResponseWriter) {
 	WriteServerErrorWithResponse(w, "Internal server error")
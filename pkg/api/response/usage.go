@@ -1,13 +1,28 @@
 package response
 
+import "github.com/Optum/dce/pkg/usage"
+
 // UsageResponse is the serialized JSON Response for an account usage
 // to be returned by usage API
 type UsageResponse struct {
-	PrincipalID  string  `json:"principalId"`  // User Principal ID
-	AccountID    string  `json:"accountId"`    // AWS Account ID
-	StartDate    int64   `json:"startDate"`    // Usage start date Epoch Timestamp
-	EndDate      int64   `json:"endDate"`      // Usage ends date Epoch Timestamp
-	CostAmount   float64 `json:"costAmount"`   // Cost Amount for given period
-	CostCurrency string  `json:"costCurrency"` // Cost currency
-	TimeToLive   int64   `json:"timeToLive"`   // ttl attribute
+	PrincipalID  string             `json:"principalId"`            // User Principal ID
+	AccountID    string             `json:"accountId,omitempty"`    // AWS Account ID, if the usage is for a single account
+	StartDate    int64              `json:"startDate"`              // Usage start date Epoch Timestamp
+	EndDate      int64              `json:"endDate"`                // Usage ends date Epoch Timestamp
+	CostAmount   float64            `json:"costAmount"`             // Cost Amount for given period
+	CostCurrency string             `json:"costCurrency"`           // Cost currency
+	TimeToLive   int64              `json:"timeToLive"`             // ttl attribute
+	AccountCosts map[string]float64 `json:"accountCosts,omitempty"` // Cost Amount for given period, by AWS Account ID
+}
+
+// UsageBreakdownResponse is the serialized JSON Response for a principal's usage
+// by AWS service and region, most expensive first
+type UsageBreakdownResponse struct {
+	PrincipalID  string       `json:"principalId"`  // User Principal ID
+	StartDate    int64        `json:"startDate"`    // Usage start date Epoch Timestamp
+	EndDate      int64        `json:"endDate"`      // Usage ends date Epoch Timestamp
+	CostAmount   float64      `json:"costAmount"`   // Cost Amount for given period
+	CostCurrency string       `json:"costCurrency"` // Cost currency
+	Services     []usage.Cost `json:"services"`     // Cost Amount by AWS service
+	Regions      []usage.Cost `json:"regions"`      // Cost Amount by AWS region
 }
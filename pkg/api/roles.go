@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RoleMapping - Gives users a role when one of their claims matches the
+// value.  Values may use `*` to match any characters, e.g.
+// `arn:aws:sts::*:assumed-role/Auditors/*`
+type RoleMapping struct {
+	Claim string `json:"claim"`
+	Value string `json:"value"`
+	Role  string `json:"role"`
+}
+
+// RoleMappings - The mappings of claims to roles
+type RoleMappings []RoleMapping
+
+// ParseRoleMappings parses role mappings from a JSON list, e.g.
+// [{"claim": "groups", "value": "dce-auditors", "role": "Auditor"}]
+func ParseRoleMappings(mappingsJSON string) (RoleMappings, error) {
+	mappings := RoleMappings{}
+	if strings.TrimSpace(mappingsJSON) == "" {
+		return mappings, nil
+	}
+
+	err := json.Unmarshal([]byte(mappingsJSON), &mappings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse role mappings: %s", err)
+	}
+	for _, mapping := range mappings {
+		if mapping.Claim == "" || mapping.Value == "" {
+			return nil, fmt.Errorf("role mappings must have a claim and a value")
+		}
+		if _, ok := DefaultPolicy[mapping.Role]; !ok {
+			return nil, fmt.Errorf("role mapping for claim %q has unknown role %q", mapping.Claim, mapping.Role)
+		}
+	}
+
+	return mappings, nil
+}
+
+// Roles returns the roles the claims are mapped to, ordered from the most to
+// the least access, or the default role if none of the claims are mapped.
+// Claims which aren't mapped have no roles without a default role
+func (m RoleMappings) Roles(claims map[string]interface{}, defaultRole string) []string {
+	roles := map[string]bool{}
+	for _, mapping := range m {
+		for _, value := range claimValues(claims[mapping.Claim]) {
+			if matchPattern(mapping.Value, value) {
+				roles[mapping.Role] = true
+			}
+		}
+	}
+
+	if len(roles) == 0 {
+		if defaultRole == "" {
+			return []string{}
+		}
+		return []string{defaultRole}
+	}
+	return orderRoles(roles)
+}
+
+// claimValues returns the values of a claim which is either a single value,
+// or a list of values such as groups
+func claimValues(claim interface{}) []string {
+	switch value := claim.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		values := []string{}
+		for _, item := range value {
+			values = append(values, claimValues(item)...)
+		}
+		return values
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
+// matchPattern returns true if the value matches the pattern, where `*`
+// matches any characters
+func matchPattern(pattern string, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
@@ -0,0 +1,106 @@
+package api_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Optum/dce/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoleMappings(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		json        string
+		expMappings api.RoleMappings
+		expErr      error
+	}{
+		{
+			name: "should parse role mappings",
+			json: `[{"claim": "groups", "value": "dce-auditors", "role": "Auditor"}]`,
+			expMappings: api.RoleMappings{
+				{Claim: "groups", Value: "dce-auditors", Role: api.AuditorGroupName},
+			},
+		},
+		{
+			name:        "should parse no role mappings",
+			json:        "",
+			expMappings: api.RoleMappings{},
+		},
+		{
+			name:   "should fail on an unknown role",
+			json:   `[{"claim": "groups", "value": "dce-auditors", "role": "Auditors"}]`,
+			expErr: fmt.Errorf("role mapping for claim \"groups\" has unknown role \"Auditors\""),
+		},
+		{
+			name:   "should fail on a mapping without a claim",
+			json:   `[{"value": "dce-auditors", "role": "Auditor"}]`,
+			expErr: fmt.Errorf("role mappings must have a claim and a value"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mappings, err := api.ParseRoleMappings(tt.json)
+			assert.Equal(t, tt.expErr, err)
+			assert.Equal(t, tt.expMappings, mappings)
+		})
+	}
+}
+
+func TestRoleMappingsRoles(t *testing.T) {
+	mappings := api.RoleMappings{
+		{Claim: "groups", Value: "dce-auditors", Role: api.AuditorGroupName},
+		{Claim: "groups", Value: "dce-admins", Role: api.AdminGroupName},
+		{Claim: "arn", Value: "arn:aws:sts::*:assumed-role/PoolOperators/*", Role: api.PoolOperatorGroupName},
+		{Claim: "email", Value: "*@contractor.example.com", Role: api.UserGroupName},
+	}
+
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		expRoles []string
+	}{
+		{
+			name:     "should map a claim in a list",
+			claims:   map[string]interface{}{"groups": []interface{}{"other", "dce-auditors"}},
+			expRoles: []string{api.AuditorGroupName},
+		},
+		{
+			name:     "should map every role, with the most access first",
+			claims:   map[string]interface{}{"groups": []interface{}{"dce-auditors", "dce-admins"}},
+			expRoles: []string{api.AdminGroupName, api.AuditorGroupName},
+		},
+		{
+			name:     "should map a claim matching a pattern",
+			claims:   map[string]interface{}{"arn": "arn:aws:sts::123456789012:assumed-role/PoolOperators/jdoe"},
+			expRoles: []string{api.PoolOperatorGroupName},
+		},
+		{
+			name:     "should not map a claim which doesn't match a pattern",
+			claims:   map[string]interface{}{"arn": "arn:aws:sts::123456789012:assumed-role/Developers/jdoe"},
+			expRoles: []string{api.AdminGroupName},
+		},
+		{
+			name:     "should map users instead of the default role",
+			claims:   map[string]interface{}{"email": "jdoe@contractor.example.com"},
+			expRoles: []string{api.UserGroupName},
+		},
+		{
+			name:     "should use the default role",
+			claims:   map[string]interface{}{},
+			expRoles: []string{api.AdminGroupName},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expRoles, mappings.Roles(tt.claims, api.AdminGroupName))
+		})
+	}
+
+	t.Run("should not give a role without a default role", func(t *testing.T) {
+		assert.Empty(t, mappings.Roles(map[string]interface{}{}, ""))
+	})
+}
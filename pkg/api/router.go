@@ -13,13 +13,14 @@ import (
 // EmptyQueryString - Empty query string to prevent
 var EmptyQueryString []string
 
-// Route - A route
+// Route - A route.  Requests are only handled for users granted the Permission
 type Route struct {
 	Name        string
 	Method      string
 	Pattern     string
 	Queries     []string
 	HandlerFunc http.HandlerFunc
+	Permission  Permission
 }
 
 var (
@@ -38,7 +39,7 @@ type Routes []Route
 func NewRouter(routes Routes) *mux.Router {
 	router := mux.NewRouter().StrictSlash(true)
 	for _, route := range routes {
-		handler := route.HandlerFunc
+		handler := authorize(route.Permission, route.HandlerFunc)
 
 		if len(route.Queries) == 0 {
 
@@ -22,10 +22,29 @@ const UserGroupName = "User"
 // AdminGroupName - Has a string to define Admins
 const AdminGroupName = "Admin"
 
-// User - Has the username and their role
+// User - Has the username and their roles
 type User struct {
 	Username string
-	Role     string
+	// Role is the user's role with the most access
+	Role string
+	// Roles are all of the user's roles.  Users are granted the permissions
+	// of every one of their roles
+	Roles []string
+	// teamMembers are the principals on the teams the user leads, once they're listed
+	teamMembers *teamMembers
+}
+
+// newUser returns a user with the roles, which are ordered from the most to
+// the least access
+func newUser(username string, roles []string) *User {
+	user := &User{
+		Username: username,
+		Roles:    roles,
+	}
+	if len(roles) > 0 {
+		user.Role = roles[0]
+	}
+	return user
 }
 
 // UserDetailer - used for mocking tests
@@ -33,14 +52,30 @@ type UserDetailer interface {
 	GetUser(event *events.APIGatewayProxyRequest) *User
 }
 
+// Identity providers of the users making API requests
+const (
+	IdentityProviderCognito = "Cognito"
+	IdentityProviderOIDC    = "OIDC"
+	IdentityProviderIAM     = "IAM"
+)
+
+// IdentityProviderSettings - Chooses the UserDetailer for API requests.  Role
+// mappings are a JSON list, and apply to the OIDC and IAM identity providers
+type IdentityProviderSettings struct {
+	IdentityProvider string `env:"IDENTITY_PROVIDER" envDefault:"Cognito"`
+	RoleMappings     string `env:"ROLE_MAPPINGS" envDefault:"[]"`
+}
+
 // UserDetails - Gets User information
 type UserDetails struct {
-	CognitoUserPoolID        string
-	RolesAttributesAdminName string
+	CognitoUserPoolID        string `env:"COGNITO_USER_POOL_ID" envDefault:"DefaultCognitoUserPoolId"`
+	RolesAttributesAdminName string `env:"COGNITO_ROLES_ATTRIBUTE_ADMIN_NAME" envDefault:"DefaultCognitoAdminName"`
 	CognitoClient            awsiface.CognitoIdentityProviderAPI
 }
 
-// GetUser - Gets the username and role out of an event
+// GetUser - Gets the username and roles out of an event.  Cognito users are
+// given roles by the Cognito groups they're in, and the roles listed in their
+// `custom:roles` attribute
 func (u *UserDetails) GetUser(event *events.APIGatewayProxyRequest) *User {
 
 	if event.RequestContext.Identity.CognitoIdentityPoolID == "" {
@@ -66,34 +101,70 @@ func (u *UserDetails) GetUser(event *events.APIGatewayProxyRequest) *User {
 		return &User{}
 	}
 
-	user := &User{
-		Role:     UserGroupName,
-		Username: *users.Users[0].Username,
-	}
+	username := *users.Users[0].Username
 
+	roles := map[string]bool{}
 	for _, attribute := range users.Users[0].Attributes {
 		if *attribute.Name == "custom:roles" {
 			if u.isUserInAdminFromList(*attribute.Value) {
-				user.Role = AdminGroupName
-				return user
+				return newUser(username, []string{AdminGroupName})
+			}
+			// Admins are only named by RolesAttributesAdminName
+			for _, role := range strings.Split(*attribute.Value, ",") {
+				if role = strings.TrimSpace(role); role != AdminGroupName {
+					roles[role] = true
+				}
 			}
 		}
 	}
 
-	isAdmin, err := u.isUserInAdminGroup(user.Username)
+	groups, err := u.listGroupsForUser(username)
 	if err != nil {
 		log.Printf("Got an error when quering groups for user: %s", err)
-		return user
 	}
-	if isAdmin {
-		user.Role = AdminGroupName
-		return user
+	for _, group := range groups {
+		if role, ok := groupRoles[group]; ok {
+			roles[role] = true
+		}
 	}
 
-	return user
+	// Users without another role are Users
+	ordered := orderRoles(roles)
+	if len(ordered) == 0 {
+		ordered = []string{UserGroupName}
+	}
+	return newUser(username, ordered)
+}
+
+// groupRoles maps the Cognito groups users are placed into to their roles
+var groupRoles = map[string]string{
+	"Admins":        AdminGroupName,
+	"PoolOperators": PoolOperatorGroupName,
+	"TeamLeads":     TeamLeadGroupName,
+	"Auditors":      AuditorGroupName,
+}
+
+// orderRoles returns the roles, ordered from the most to the least access
+func orderRoles(roles map[string]bool) []string {
+	ordered := []string{}
+	for _, role := range rolePrecedence {
+		if roles[role] {
+			ordered = append(ordered, role)
+		}
+	}
+	return ordered
 }
 
-func (u *UserDetails) isUserInAdminGroup(username string) (bool, error) {
+// rolePrecedence lists the roles from the most to the least access
+var rolePrecedence = []string{
+	AdminGroupName,
+	PoolOperatorGroupName,
+	TeamLeadGroupName,
+	AuditorGroupName,
+	UserGroupName,
+}
+
+func (u *UserDetails) listGroupsForUser(username string) ([]string, error) {
 
 	groups, err := u.CognitoClient.AdminListGroupsForUser(&cognitoidentityprovider.AdminListGroupsForUserInput{
 		Username:   aws.String(username),
@@ -101,14 +172,13 @@ func (u *UserDetails) isUserInAdminGroup(username string) (bool, error) {
 	})
 	if err != nil {
 		log.Printf("Was not abile to query a users for its groups: %s", err)
-		return false, fmt.Errorf("Was not abile to query a users for its groups: %s", err)
+		return nil, fmt.Errorf("Was not abile to query a users for its groups: %s", err)
 	}
+	groupNames := []string{}
 	for _, group := range groups.Groups {
-		if *group.GroupName == "Admins" {
-			return true, nil
-		}
+		groupNames = append(groupNames, *group.GroupName)
 	}
-	return false, nil
+	return groupNames, nil
 }
 
 func (u *UserDetails) isUserInAdminFromList(groups string) bool {
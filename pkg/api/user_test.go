@@ -387,4 +387,95 @@ func TestUser(t *testing.T) {
 		require.Equal(t, user.Username, "testuser")
 		require.Equal(t, user.Role, api.UserGroupName)
 	})
+	t.Run("CognitoAuthInTeamLeadsGroup, Output", func(t *testing.T) {
+
+		mockCognitoIdp := &mocks.CognitoIdentityProviderAPI{}
+		userGetter := api.UserDetails{
+			CognitoUserPoolID:        "us_east_1-test",
+			RolesAttributesAdminName: "admins",
+			CognitoClient:            mockCognitoIdp,
+		}
+
+		mockCognitoIdp.On("ListUsers", &cognitoidentityprovider.ListUsersInput{
+			Filter:     aws.String("sub = \"abcdef-123456\""),
+			UserPoolId: aws.String("us_east_1-test"),
+		}).Return(&cognitoidentityprovider.ListUsersOutput{
+			Users: []*cognitoidentityprovider.UserType{
+				{
+					Username: aws.String("testuser"),
+					Attributes: []*cognitoidentityprovider.AttributeType{
+						{
+							Name:  aws.String("custom:roles"),
+							Value: aws.String("Auditor"),
+						},
+					},
+				},
+			},
+		}, nil)
+		mockCognitoIdp.On("AdminListGroupsForUser", &cognitoidentityprovider.AdminListGroupsForUserInput{
+			Username:   aws.String("testuser"),
+			UserPoolId: aws.String("us_east_1-test"),
+		}).Return(&cognitoidentityprovider.AdminListGroupsForUserOutput{
+			Groups: []*cognitoidentityprovider.GroupType{
+				{
+					GroupName: aws.String("TeamLeads"),
+				},
+			},
+		}, nil)
+
+		user := userGetter.GetUser(&events.APIGatewayProxyRequest{
+			RequestContext: events.APIGatewayProxyRequestContext{
+				Identity: events.APIGatewayRequestIdentity{
+					CognitoIdentityPoolID:         "us_east_1-test",
+					CognitoAuthenticationProvider: "UserPoolID:CognitoSignIn:abcdef-123456",
+				},
+			},
+		})
+		require.Equal(t, user.Username, "testuser")
+		require.Equal(t, user.Role, api.TeamLeadGroupName)
+		require.Equal(t, []string{api.TeamLeadGroupName, api.AuditorGroupName}, user.Roles)
+	})
+	t.Run("CognitoAuthInAuditorRoleAttributes, Output", func(t *testing.T) {
+
+		mockCognitoIdp := &mocks.CognitoIdentityProviderAPI{}
+		userGetter := api.UserDetails{
+			CognitoUserPoolID:        "us_east_1-test",
+			RolesAttributesAdminName: "admins",
+			CognitoClient:            mockCognitoIdp,
+		}
+
+		mockCognitoIdp.On("ListUsers", &cognitoidentityprovider.ListUsersInput{
+			Filter:     aws.String("sub = \"abcdef-123456\""),
+			UserPoolId: aws.String("us_east_1-test"),
+		}).Return(&cognitoidentityprovider.ListUsersOutput{
+			Users: []*cognitoidentityprovider.UserType{
+				{
+					Username: aws.String("testuser"),
+					Attributes: []*cognitoidentityprovider.AttributeType{
+						{
+							Name:  aws.String("custom:roles"),
+							Value: aws.String("Group1, Auditor, Admin"),
+						},
+					},
+				},
+			},
+		}, nil)
+		mockCognitoIdp.On("AdminListGroupsForUser", &cognitoidentityprovider.AdminListGroupsForUserInput{
+			Username:   aws.String("testuser"),
+			UserPoolId: aws.String("us_east_1-test"),
+		}).Return(&cognitoidentityprovider.AdminListGroupsForUserOutput{
+			Groups: []*cognitoidentityprovider.GroupType{},
+		}, nil)
+
+		user := userGetter.GetUser(&events.APIGatewayProxyRequest{
+			RequestContext: events.APIGatewayProxyRequestContext{
+				Identity: events.APIGatewayRequestIdentity{
+					CognitoIdentityPoolID:         "us_east_1-test",
+					CognitoAuthenticationProvider: "UserPoolID:CognitoSignIn:abcdef-123456",
+				},
+			},
+		})
+		require.Equal(t, user.Username, "testuser")
+		require.Equal(t, user.Role, api.AuditorGroupName)
+	})
 }
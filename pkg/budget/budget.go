@@ -1,6 +1,7 @@
 package budget
 
 import (
+	"fmt"
 	"github.com/Optum/dce/pkg/awsiface"
 	"strconv"
 	"time"
@@ -13,21 +14,82 @@ import (
 // (eg, if I'm testing a Lambda controller that uses this Service)
 //go:generate mockery -name Service
 type Service interface {
-	CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, error)
+	CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, string, error)
+	CalculateSpendBreakdown(startDate time.Time, endDate time.Time, byRegion bool) (*SpendBreakdown, error)
 	SetCostExplorer(costExplorer awsiface.CostExplorerAPI)
 }
 
+// SpendBreakdown is spend grouped by AWS service, and optionally by AWS region
+type SpendBreakdown struct {
+	Services map[string]float64
+	Regions  map[string]float64
+}
+
+// CostMetric is the Cost Explorer metric used to calculate spend
+type CostMetric string
+
+const (
+	// CostMetricUnblended is the cost of usage when it's charged, and the default metric
+	CostMetricUnblended CostMetric = "UnblendedCost"
+	// CostMetricNetUnblended is the unblended cost after discounts
+	CostMetricNetUnblended CostMetric = "NetUnblendedCost"
+	// CostMetricAmortized spreads the upfront cost of Reserved Instances and Savings Plans over their term
+	CostMetricAmortized CostMetric = "AmortizedCost"
+	// CostMetricNetAmortized is the amortized cost after discounts
+	CostMetricNetAmortized CostMetric = "NetAmortizedCost"
+	// CostMetricBlended averages the cost of usage across the accounts in an organization
+	CostMetricBlended CostMetric = "BlendedCost"
+)
+
+// ParseCostMetric parses the name of a Cost Explorer metric, defaulting to UnblendedCost
+func ParseCostMetric(metric string) (CostMetric, error) {
+	switch CostMetric(metric) {
+	case "":
+		return CostMetricUnblended, nil
+	case CostMetricUnblended, CostMetricNetUnblended, CostMetricAmortized, CostMetricNetAmortized, CostMetricBlended:
+		return CostMetric(metric), nil
+	}
+	return "", fmt.Errorf("unsupported cost metric %q", metric)
+}
+
 // Define a concrete implementation of the Service interface
 type AWSBudgetService struct {
 	CostExplorer awsiface.CostExplorerAPI
+	// Cost Explorer metric used to calculate spend. Defaults to UnblendedCost
+	CostMetric CostMetric
+	// Cost Explorer record types to leave out of spend, eg. "Credit" or "Refund"
+	ExcludedRecordTypes []string
+}
+
+func (budgetSvc *AWSBudgetService) costMetric() string {
+	if budgetSvc.CostMetric == "" {
+		return string(CostMetricUnblended)
+	}
+	return string(budgetSvc.CostMetric)
+}
+
+// filter leaves the excluded record types out of Cost Explorer results
+func (budgetSvc *AWSBudgetService) filter() *costexplorer.Expression {
+	if len(budgetSvc.ExcludedRecordTypes) == 0 {
+		return nil
+	}
+	return &costexplorer.Expression{
+		Not: &costexplorer.Expression{
+			Dimensions: &costexplorer.DimensionValues{
+				Key:    aws.String("RECORD_TYPE"),
+				Values: aws.StringSlice(budgetSvc.ExcludedRecordTypes),
+			},
+		},
+	}
 }
 
 func (budgetSvc *AWSBudgetService) SetCostExplorer(costExplorer awsiface.CostExplorerAPI) {
 	budgetSvc.CostExplorer = costExplorer
 }
 
-// Implement the CalculateTotalSpend method of the Service interface
-func (budgetSvc *AWSBudgetService) CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, error) {
+// Implement the CalculateTotalSpend method of the Service interface.
+// Returns the total spend, and the currency Cost Explorer reported it in
+func (budgetSvc *AWSBudgetService) CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, string, error) {
 
 	// CostExplorer uses strings for dates, in the format
 	// of "2017-01-01"
@@ -39,30 +101,113 @@ func (budgetSvc *AWSBudgetService) CalculateTotalSpend(startDate time.Time, endD
 		End:   aws.String(endDate.UTC().Format(timeFormat)),
 	}
 
-	metrics := []*string{aws.String("UnblendedCost")}
+	metric := budgetSvc.costMetric()
+	metrics := []*string{aws.String(metric)}
 	granularity := aws.String("DAILY")
 
 	getCostAndUsageInput := costexplorer.GetCostAndUsageInput{
 		Metrics:     metrics,
 		TimePeriod:  &timePeriod,
 		Granularity: granularity,
+		Filter:      budgetSvc.filter(),
 	}
 
 	output, err := budgetSvc.CostExplorer.GetCostAndUsage(&getCostAndUsageInput)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	var totalCost float64
+	// Cost Explorer reports spend in USD,
+	// unless the results say otherwise
+	currency := "USD"
 
 	for _, result := range output.ResultsByTime {
-		cost, err := strconv.ParseFloat(*result.Total["UnblendedCost"].Amount, 64)
+		total, ok := result.Total[metric]
+		if !ok || total.Amount == nil {
+			continue
+		}
+		cost, err := strconv.ParseFloat(*total.Amount, 64)
 		if err != nil {
-			return 0, err
+			return 0, "", err
+		}
+		if unit := total.Unit; unit != nil && *unit != "" {
+			currency = *unit
 		}
 
 		totalCost = totalCost + cost
 
 	}
-	return totalCost, nil
+	return totalCost, currency, nil
+}
+
+// CalculateSpendBreakdown groups spend by AWS service, and by AWS region when byRegion is set
+func (budgetSvc *AWSBudgetService) CalculateSpendBreakdown(startDate time.Time, endDate time.Time, byRegion bool) (*SpendBreakdown, error) {
+	timeFormat := "2006-01-02"
+	metric := budgetSvc.costMetric()
+	groupBy := []*costexplorer.GroupDefinition{
+		{
+			Type: aws.String("DIMENSION"),
+			Key:  aws.String("SERVICE"),
+		},
+	}
+	if byRegion {
+		groupBy = append(groupBy, &costexplorer.GroupDefinition{
+			Type: aws.String("DIMENSION"),
+			Key:  aws.String("REGION"),
+		})
+	}
+
+	getCostAndUsageInput := costexplorer.GetCostAndUsageInput{
+		Metrics: []*string{aws.String(metric)},
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(startDate.UTC().Format(timeFormat)),
+			End:   aws.String(endDate.UTC().Format(timeFormat)),
+		},
+		Granularity: aws.String("DAILY"),
+		GroupBy:     groupBy,
+		Filter:      budgetSvc.filter(),
+	}
+
+	breakdown := &SpendBreakdown{
+		Services: map[string]float64{},
+	}
+	if byRegion {
+		breakdown.Regions = map[string]float64{}
+	}
+
+	// Grouped results are paginated
+	for {
+		output, err := budgetSvc.CostExplorer.GetCostAndUsage(&getCostAndUsageInput)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range output.ResultsByTime {
+			for _, group := range result.Groups {
+				value, ok := group.Metrics[metric]
+				if !ok || value.Amount == nil || len(group.Keys) == 0 {
+					continue
+				}
+				cost, err := strconv.ParseFloat(*value.Amount, 64)
+				if err != nil {
+					return nil, err
+				}
+
+				service := aws.StringValue(group.Keys[0])
+				breakdown.Services[service] = breakdown.Services[service] + cost
+				if byRegion && len(group.Keys) > 1 {
+					region := aws.StringValue(group.Keys[1])
+					breakdown.Regions[region] = breakdown.Regions[region] + cost
+				}
+			}
+		}
+
+		if output.NextPageToken == nil {
+			break
+		}
+		getCostAndUsageInput.NextPageToken = output.NextPageToken
+	}
+
+	return breakdown, nil
 }
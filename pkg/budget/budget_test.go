@@ -1,6 +1,7 @@
 package budget
 
 import (
+	"fmt"
 	"github.com/Optum/dce/pkg/awsiface/mocks"
 	"testing"
 	"time"
@@ -44,10 +45,145 @@ func TestCalculateTotalSpend(t *testing.T) {
 	budgetSvc := AWSBudgetService{
 		CostExplorer: costExplorer,
 	}
-	cost, err := budgetSvc.CalculateTotalSpend(
+	cost, currency, err := budgetSvc.CalculateTotalSpend(
 		time.Unix(0, 0),
 		time.Unix(0, 0).Add(time.Hour*24),
 	)
 	assert.Nil(t, err, "There should be no errors")
 	assert.Equal(t, cost, float64(150))
+	assert.Equal(t, "USD", currency)
+}
+
+func TestCalculateSpendBreakdown(t *testing.T) {
+	// Mock the CostExplorer SDK
+	costExplorer := &mocks.CostExplorerAPI{}
+	input := &costexplorer.GetCostAndUsageInput{
+		Metrics:     []*string{aws.String("UnblendedCost")},
+		Granularity: aws.String("DAILY"),
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String("1970-01-01"),
+			End:   aws.String("1970-01-02"),
+		},
+		GroupBy: []*costexplorer.GroupDefinition{
+			{Type: aws.String("DIMENSION"), Key: aws.String("SERVICE")},
+			{Type: aws.String("DIMENSION"), Key: aws.String("REGION")},
+		},
+	}
+	costExplorer.On("GetCostAndUsage", input).Return(&costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []*costexplorer.ResultByTime{
+			{
+				Groups: []*costexplorer.Group{
+					{
+						Keys: []*string{aws.String("Amazon EC2"), aws.String("us-east-1")},
+						Metrics: map[string]*costexplorer.MetricValue{
+							"UnblendedCost": {Amount: aws.String("100"), Unit: aws.String("USD")},
+						},
+					},
+					{
+						Keys: []*string{aws.String("Amazon S3"), aws.String("us-east-1")},
+						Metrics: map[string]*costexplorer.MetricValue{
+							"UnblendedCost": {Amount: aws.String("10"), Unit: aws.String("USD")},
+						},
+					},
+				},
+			},
+		},
+		NextPageToken: aws.String("next"),
+	}, nil)
+	nextInput := *input
+	nextInput.NextPageToken = aws.String("next")
+	costExplorer.On("GetCostAndUsage", &nextInput).Return(&costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []*costexplorer.ResultByTime{
+			{
+				Groups: []*costexplorer.Group{
+					{
+						Keys: []*string{aws.String("Amazon EC2"), aws.String("us-west-2")},
+						Metrics: map[string]*costexplorer.MetricValue{
+							"UnblendedCost": {Amount: aws.String("50"), Unit: aws.String("USD")},
+						},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	budgetSvc := AWSBudgetService{
+		CostExplorer: costExplorer,
+	}
+	breakdown, err := budgetSvc.CalculateSpendBreakdown(
+		time.Unix(0, 0),
+		time.Unix(0, 0).Add(time.Hour*24),
+		true,
+	)
+	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, &SpendBreakdown{
+		Services: map[string]float64{
+			"Amazon EC2": 150,
+			"Amazon S3":  10,
+		},
+		Regions: map[string]float64{
+			"us-east-1": 110,
+			"us-west-2": 50,
+		},
+	}, breakdown)
+	costExplorer.AssertNumberOfCalls(t, "GetCostAndUsage", 2)
+}
+
+func TestCalculateTotalSpendWithCostMetric(t *testing.T) {
+	// Mock the CostExplorer SDK
+	costExplorer := &mocks.CostExplorerAPI{}
+	costExplorer.On("GetCostAndUsage", &costexplorer.GetCostAndUsageInput{
+		Metrics:     []*string{aws.String("AmortizedCost")},
+		Granularity: aws.String("DAILY"),
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String("1970-01-01"),
+			End:   aws.String("1970-01-02"),
+		},
+		Filter: &costexplorer.Expression{
+			Not: &costexplorer.Expression{
+				Dimensions: &costexplorer.DimensionValues{
+					Key:    aws.String("RECORD_TYPE"),
+					Values: []*string{aws.String("Credit"), aws.String("Refund")},
+				},
+			},
+		},
+	}).Return(&costexplorer.GetCostAndUsageOutput{
+		ResultsByTime: []*costexplorer.ResultByTime{
+			{
+				Total: map[string]*costexplorer.MetricValue{
+					"AmortizedCost": {
+						Amount: aws.String("75"),
+						Unit:   aws.String("USD"),
+					},
+				},
+			},
+		},
+	}, nil)
+
+	budgetSvc := AWSBudgetService{
+		CostExplorer:        costExplorer,
+		CostMetric:          CostMetricAmortized,
+		ExcludedRecordTypes: []string{"Credit", "Refund"},
+	}
+	cost, currency, err := budgetSvc.CalculateTotalSpend(
+		time.Unix(0, 0),
+		time.Unix(0, 0).Add(time.Hour*24),
+	)
+	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, float64(75), cost)
+	assert.Equal(t, "USD", currency)
+}
+
+func TestParseCostMetric(t *testing.T) {
+	metric, err := ParseCostMetric("")
+	assert.Nil(t, err)
+	assert.Equal(t, CostMetricUnblended, metric)
+
+	metric, err = ParseCostMetric("NetAmortizedCost")
+	assert.Nil(t, err)
+	assert.Equal(t, CostMetricNetAmortized, metric)
+
+	metric, err = ParseCostMetric("ListCost")
+	assert.Equal(t, CostMetric(""), metric)
+	assert.Equal(t, fmt.Errorf("unsupported cost metric \"ListCost\""), err)
 }
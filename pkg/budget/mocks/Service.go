@@ -3,6 +3,7 @@
 package mocks
 
 import awsiface "github.com/Optum/dce/pkg/awsiface"
+import budget "github.com/Optum/dce/pkg/budget"
 
 import mock "github.com/stretchr/testify/mock"
 import time "time"
@@ -12,8 +13,31 @@ type Service struct {
 	mock.Mock
 }
 
+// CalculateSpendBreakdown provides a mock function with given fields: startDate, endDate, byRegion
+func (_m *Service) CalculateSpendBreakdown(startDate time.Time, endDate time.Time, byRegion bool) (*budget.SpendBreakdown, error) {
+	ret := _m.Called(startDate, endDate, byRegion)
+
+	var r0 *budget.SpendBreakdown
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, bool) *budget.SpendBreakdown); ok {
+		r0 = rf(startDate, endDate, byRegion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*budget.SpendBreakdown)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, bool) error); ok {
+		r1 = rf(startDate, endDate, byRegion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CalculateTotalSpend provides a mock function with given fields: startDate, endDate
-func (_m *Service) CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, error) {
+func (_m *Service) CalculateTotalSpend(startDate time.Time, endDate time.Time) (float64, string, error) {
 	ret := _m.Called(startDate, endDate)
 
 	var r0 float64
@@ -23,14 +47,21 @@ func (_m *Service) CalculateTotalSpend(startDate time.Time, endDate time.Time) (
 		r0 = ret.Get(0).(float64)
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(time.Time, time.Time) error); ok {
+	var r1 string
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time) string); ok {
 		r1 = rf(startDate, endDate)
 	} else {
-		r1 = ret.Error(1)
+		r1 = ret.Get(1).(string)
 	}
 
-	return r0, r1
+	var r2 error
+	if rf, ok := ret.Get(2).(func(time.Time, time.Time) error); ok {
+		r2 = rf(startDate, endDate)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
 // SetCostExplorer provides a mock function with given fields: costExplorer
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"log"
 	"reflect"
@@ -10,6 +11,7 @@ import (
 	"github.com/Optum/dce/pkg/account/accountiface"
 	"github.com/Optum/dce/pkg/accountmanager"
 	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface"
+	"github.com/Optum/dce/pkg/api"
 	"github.com/Optum/dce/pkg/common"
 	"github.com/Optum/dce/pkg/data"
 	"github.com/Optum/dce/pkg/data/dataiface"
@@ -17,10 +19,17 @@ import (
 	"github.com/Optum/dce/pkg/event/eventiface"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/Optum/dce/pkg/lease/leaseiface"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/Optum/dce/pkg/principal/principaliface"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/Optum/dce/pkg/team/teamiface"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/Optum/dce/pkg/webhook/webhookiface"
 
 	"github.com/aws/aws-sdk-go/service/codebuild/codebuildiface"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider/cognitoidentityprovideriface"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/lambda"
 	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -36,6 +45,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/codebuild"
 	"github.com/aws/aws-sdk-go/service/cognitoidentityprovider"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
@@ -87,6 +97,12 @@ func (bldr *ServiceBuilder) WithSQS() *ServiceBuilder {
 	return bldr
 }
 
+// WithEventBridge tells the builder to add an AWS EventBridge service to the `DefaultConfigurater`
+func (bldr *ServiceBuilder) WithEventBridge() *ServiceBuilder {
+	bldr.handlers = append(bldr.handlers, bldr.createEventBridge)
+	return bldr
+}
+
 // WithDynamoDB tells the builder to add an AWS DynamoDB service to the `DefaultConfigurater`
 func (bldr *ServiceBuilder) WithDynamoDB() *ServiceBuilder {
 	bldr.handlers = append(bldr.handlers, bldr.createDynamoDB)
@@ -150,6 +166,35 @@ func (bldr *ServiceBuilder) WithLeaseDataService() *ServiceBuilder {
 	return bldr
 }
 
+// WithOutboxDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithOutboxDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createOutboxDataService)
+	return bldr
+}
+
+// WithWebhookDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithWebhookDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.WithSSM()
+	bldr.handlers = append(bldr.handlers, bldr.createWebhookDataService)
+	return bldr
+}
+
+// WithTeamDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithTeamDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createTeamDataService)
+	return bldr
+}
+
+// WithPrincipalDataService tells the builder to add the Data service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithPrincipalDataService() *ServiceBuilder {
+	bldr.WithDynamoDB()
+	bldr.handlers = append(bldr.handlers, bldr.createPrincipalDataService)
+	return bldr
+}
+
 // WithAccountManagerService tells the builder to add the Data service to the `ConfigurationBuilder`
 func (bldr *ServiceBuilder) WithAccountManagerService() *ServiceBuilder {
 	bldr.WithSTS().WithStorageService()
@@ -177,7 +222,7 @@ func (bldr *ServiceBuilder) AccountService() accountiface.Servicer {
 
 // WithLeaseService tells the builder to add the Account service to the `ConfigurationBuilder`
 func (bldr *ServiceBuilder) WithLeaseService() *ServiceBuilder {
-	bldr.WithLeaseDataService().WithEventService()
+	bldr.WithLeaseDataService().WithAccountDataService().WithEventService()
 	bldr.handlers = append(bldr.handlers, bldr.createLeaseService)
 	return bldr
 }
@@ -193,9 +238,81 @@ func (bldr *ServiceBuilder) LeaseService() leaseiface.Servicer {
 	return leaseSvc
 }
 
+// WithWebhookService tells the builder to add the Webhook service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithWebhookService() *ServiceBuilder {
+	bldr.WithWebhookDataService()
+	bldr.handlers = append(bldr.handlers, bldr.createWebhookService)
+	return bldr
+}
+
+// WebhookService returns the webhook Service for you
+func (bldr *ServiceBuilder) WebhookService() webhookiface.Servicer {
+
+	var webhookSvc webhookiface.Servicer
+	if err := bldr.Config.GetService(&webhookSvc); err != nil {
+		panic(err)
+	}
+
+	return webhookSvc
+}
+
+// WithTeamService tells the builder to add the Team service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithTeamService() *ServiceBuilder {
+	bldr.WithTeamDataService()
+	bldr.handlers = append(bldr.handlers, bldr.createTeamService)
+	return bldr
+}
+
+// TeamService returns the team Service for you
+func (bldr *ServiceBuilder) TeamService() teamiface.Servicer {
+
+	var teamSvc teamiface.Servicer
+	if err := bldr.Config.GetService(&teamSvc); err != nil {
+		panic(err)
+	}
+
+	return teamSvc
+}
+
+// WithPrincipalService tells the builder to add the Principal service to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithPrincipalService() *ServiceBuilder {
+	bldr.WithPrincipalDataService()
+	bldr.handlers = append(bldr.handlers, bldr.createPrincipalService)
+	return bldr
+}
+
+// PrincipalService returns the principal Service for you
+func (bldr *ServiceBuilder) PrincipalService() principaliface.Servicer {
+
+	var principalSvc principaliface.Servicer
+	if err := bldr.Config.GetService(&principalSvc); err != nil {
+		panic(err)
+	}
+
+	return principalSvc
+}
+
+// WithUserDetailer tells the builder to add the API user details to the `ConfigurationBuilder`
+func (bldr *ServiceBuilder) WithUserDetailer() *ServiceBuilder {
+	bldr.WithCognito()
+	bldr.handlers = append(bldr.handlers, bldr.createUserDetailer)
+	return bldr
+}
+
+// UserDetailer returns the API user details for you
+func (bldr *ServiceBuilder) UserDetailer() api.UserDetailer {
+
+	var userDetailer api.UserDetailer
+	if err := bldr.Config.GetService(&userDetailer); err != nil {
+		panic(err)
+	}
+
+	return userDetailer
+}
+
 // WithEventService tells the builder to add the Account service to the `ConfigurationBuilder`
 func (bldr *ServiceBuilder) WithEventService() *ServiceBuilder {
-	bldr.WithSQS().WithSNS()
+	bldr.WithSQS().WithSNS().WithEventBridge()
 	bldr.handlers = append(bldr.handlers, bldr.createEventService)
 	return bldr
 }
@@ -292,6 +409,19 @@ func (bldr *ServiceBuilder) createSQS(config ConfigurationServiceBuilder) error
 	return nil
 }
 
+func (bldr *ServiceBuilder) createEventBridge(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api eventbridgeiface.EventBridgeAPI
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added EventBridge service")
+		return nil
+	}
+	eventBridgeSvc := eventbridge.New(bldr.awsSession)
+	config.WithService(eventBridgeSvc)
+	return nil
+}
+
 func (bldr *ServiceBuilder) createDynamoDB(config ConfigurationServiceBuilder) error {
 	// Don't add the service twice
 	var api dynamodbiface.DynamoDBAPI
@@ -427,6 +557,12 @@ func (bldr *ServiceBuilder) createEventService(config ConfigurationServiceBuilde
 		return err
 	}
 
+	var eventBridgeService eventbridgeiface.EventBridgeAPI
+	err = bldr.Config.GetService(&eventBridgeService)
+	if err != nil {
+		return err
+	}
+
 	eventSvcInput := event.NewServiceInput{}
 	err = bldr.Config.Unmarshal(&eventSvcInput)
 	if err != nil {
@@ -435,6 +571,7 @@ func (bldr *ServiceBuilder) createEventService(config ConfigurationServiceBuilde
 
 	eventSvcInput.SqsClient = sqsService
 	eventSvcInput.SnsClient = snsService
+	eventSvcInput.EventBridgeClient = eventBridgeService
 	eventSvc, err := event.NewService(eventSvcInput)
 	if err != nil {
 		return err
@@ -472,6 +609,125 @@ func (bldr *ServiceBuilder) createAccountDataService(config ConfigurationService
 	return nil
 }
 
+func (bldr *ServiceBuilder) createOutboxDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.OutboxData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Outbox Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.Outbox{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createWebhookDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.WebhookData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Webhook Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+	if err != nil {
+		return err
+	}
+
+	var ssmSvc ssmiface.SSMAPI
+	err = bldr.Config.GetService(&ssmSvc)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.Webhook{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+	dataSvcImpl.SSM = ssmSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createTeamDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.TeamData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Team Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.Team{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createPrincipalDataService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api dataiface.PrincipalData
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Principal Data service")
+		return nil
+	}
+
+	var dynamodbSvc dynamodbiface.DynamoDBAPI
+	err = bldr.Config.GetService(&dynamodbSvc)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl := &data.Principal{}
+
+	err = bldr.Config.Unmarshal(dataSvcImpl)
+	if err != nil {
+		return err
+	}
+
+	dataSvcImpl.DynamoDB = dynamodbSvc
+
+	config.WithService(dataSvcImpl)
+	return nil
+}
+
 func (bldr *ServiceBuilder) createAccountManagerService(config ConfigurationServiceBuilder) error {
 	// Don't add the service twice
 	var api accountmanageriface.Servicer
@@ -601,12 +857,176 @@ func (bldr *ServiceBuilder) createLeaseService(config ConfigurationServiceBuilde
 		return err
 	}
 
-	leaseSvc := lease.NewService(
-		lease.NewServiceInput{
-			DataSvc: dataSvc,
-		},
-	)
+	var accountDataSvc dataiface.AccountData
+	err = bldr.Config.GetService(&accountDataSvc)
+	if err != nil {
+		return err
+	}
+
+	var eventSvc eventiface.Servicer
+	err = bldr.Config.GetService(&eventSvc)
+	if err != nil {
+		return err
+	}
+
+	leaseSvcInput := lease.NewServiceInput{}
+	err = bldr.Config.Unmarshal(&leaseSvcInput)
+	if err != nil {
+		return err
+	}
+
+	leaseSvcInput.DataSvc = dataSvc
+	leaseSvcInput.AccountSvc = accountDataSvc
+	leaseSvcInput.EventSvc = eventSvc
+
+	leaseSvc := lease.NewService(leaseSvcInput)
 
 	config.WithService(leaseSvc)
 	return nil
 }
+
+func (bldr *ServiceBuilder) createWebhookService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api webhookiface.Servicer
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Webhook service")
+		return nil
+	}
+
+	var dataSvc dataiface.WebhookData
+	err = bldr.Config.GetService(&dataSvc)
+	if err != nil {
+		return err
+	}
+
+	webhookSvcInput := webhook.NewServiceInput{}
+	err = bldr.Config.Unmarshal(&webhookSvcInput)
+	if err != nil {
+		return err
+	}
+
+	webhookSvcInput.DataSvc = dataSvc
+
+	webhookSvc := webhook.NewService(webhookSvcInput)
+
+	config.WithService(webhookSvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createTeamService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api teamiface.Servicer
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Team service")
+		return nil
+	}
+
+	var dataSvc dataiface.TeamData
+	err = bldr.Config.GetService(&dataSvc)
+	if err != nil {
+		return err
+	}
+
+	teamSvc := team.NewService(team.NewServiceInput{
+		DataSvc: dataSvc,
+	})
+
+	config.WithService(teamSvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createPrincipalService(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var api principaliface.Servicer
+	err := bldr.Config.GetService(&api)
+	if err == nil {
+		log.Printf("Already added Principal service")
+		return nil
+	}
+
+	var dataSvc dataiface.PrincipalData
+	err = bldr.Config.GetService(&dataSvc)
+	if err != nil {
+		return err
+	}
+
+	principalSvc := principal.NewService(principal.NewServiceInput{
+		DataSvc: dataSvc,
+	})
+
+	config.WithService(principalSvc)
+	return nil
+}
+
+func (bldr *ServiceBuilder) createUserDetailer(config ConfigurationServiceBuilder) error {
+	// Don't add the service twice
+	var userDetailer api.UserDetailer
+	err := bldr.Config.GetService(&userDetailer)
+	if err == nil {
+		log.Printf("Already added User Detailer")
+		return nil
+	}
+
+	settings := &api.IdentityProviderSettings{}
+	err = bldr.Config.Unmarshal(settings)
+	if err != nil {
+		return err
+	}
+	roleMappings, err := api.ParseRoleMappings(settings.RoleMappings)
+	if err != nil {
+		return err
+	}
+
+	switch settings.IdentityProvider {
+	case api.IdentityProviderCognito:
+		var cognitoSvc cognitoidentityprovideriface.CognitoIdentityProviderAPI
+		err = bldr.Config.GetService(&cognitoSvc)
+		if err != nil {
+			return err
+		}
+
+		userDetails := &api.UserDetails{}
+		err = bldr.Config.Unmarshal(userDetails)
+		if err != nil {
+			return err
+		}
+
+		userDetails.CognitoClient = cognitoSvc
+		userDetailer = userDetails
+	case api.IdentityProviderOIDC:
+		oidcUserDetails := &api.OIDCUserDetails{}
+		err = bldr.Config.Unmarshal(oidcUserDetails)
+		if err != nil {
+			return err
+		}
+		err = oidcUserDetails.Validate()
+		if err != nil {
+			return err
+		}
+
+		oidcUserDetails.Keys = api.NewRemoteKeySet(oidcUserDetails.JWKSURL)
+		oidcUserDetails.RoleMappings = roleMappings
+		userDetailer = oidcUserDetails
+	case api.IdentityProviderIAM:
+		iamUserDetails := &api.IAMUserDetails{}
+		err = bldr.Config.Unmarshal(iamUserDetails)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := api.DefaultPolicy[iamUserDetails.DefaultRole]; iamUserDetails.DefaultRole != "" && !ok {
+			return fmt.Errorf("invalid IAM_DEFAULT_ROLE %q", iamUserDetails.DefaultRole)
+		}
+
+		iamUserDetails.RoleMappings = roleMappings
+		userDetailer = iamUserDetails
+	default:
+		return fmt.Errorf("invalid IDENTITY_PROVIDER %q, must be %s, %s or %s", settings.IdentityProvider,
+			api.IdentityProviderCognito, api.IdentityProviderOIDC, api.IdentityProviderIAM)
+	}
+
+	config.WithService(userDetailer)
+	return nil
+}
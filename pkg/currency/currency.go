@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Optum/dce/pkg/common"
+)
+
+// USD is the currency AWS Cost Explorer reports spend in,
+// and the default currency for budgets
+const USD = "USD"
+
+//go:generate mockery -name RateProvider
+
+// RateProvider looks up the exchange rate between two currencies
+type RateProvider interface {
+	Rate(from string, to string) (float64, error)
+}
+
+// Convert an amount from one currency to another.
+// Empty currencies are treated as USD.
+func Convert(rates RateProvider, amount float64, from string, to string) (float64, error) {
+	from = normalize(from)
+	to = normalize(to)
+	if from == to {
+		return amount, nil
+	}
+	rate, err := rates.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// StaticRateProvider is a fixed table of exchange rates.
+// Rates are the value of one unit of the Base currency in each other currency,
+// e.g. a Base of USD with a rate of 0.92 for EUR
+type StaticRateProvider struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// Rate returns the exchange rate from one currency to another
+func (s *StaticRateProvider) Rate(from string, to string) (float64, error) {
+	from = normalize(from)
+	to = normalize(to)
+	if from == to {
+		return 1, nil
+	}
+	fromRate, err := s.baseRate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := s.baseRate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+func (s *StaticRateProvider) baseRate(currency string) (float64, error) {
+	if currency == normalize(s.Base) {
+		return 1, nil
+	}
+	rate, ok := s.Rates[currency]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for currency %q", currency)
+	}
+	return rate, nil
+}
+
+// NewStaticRateProviderFromS3 loads a table of exchange rates from a JSON object in S3, e.g.
+// {"base": "USD", "rates": {"EUR": 0.92, "GBP": 0.79}}
+func NewStaticRateProviderFromS3(storager common.Storager, bucket string, key string) (*StaticRateProvider, error) {
+	object, err := storager.GetObject(bucket, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates from s3://%s/%s: %s", bucket, key, err)
+	}
+
+	rates := &StaticRateProvider{}
+	err = json.Unmarshal([]byte(object), rates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates from s3://%s/%s: %s", bucket, key, err)
+	}
+	if rates.Base == "" {
+		rates.Base = USD
+	}
+	normalized := map[string]float64{}
+	for currency, rate := range rates.Rates {
+		normalized[normalize(currency)] = rate
+	}
+	rates.Rates = normalized
+
+	return rates, nil
+}
+
+func normalize(currency string) string {
+	if currency == "" {
+		return USD
+	}
+	return strings.ToUpper(currency)
+}
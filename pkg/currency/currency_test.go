@@ -0,0 +1,117 @@
+package currency_test
+
+import (
+	"fmt"
+	"testing"
+
+	commonMocks "github.com/Optum/dce/pkg/common/mocks"
+	"github.com/Optum/dce/pkg/currency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	rates := &currency.StaticRateProvider{
+		Base: "USD",
+		Rates: map[string]float64{
+			"EUR": 0.5,
+			"GBP": 0.25,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		amount    float64
+		from      string
+		to        string
+		expAmount float64
+		expErr    error
+	}{
+		{
+			name:      "should not convert the same currency",
+			amount:    100,
+			from:      "EUR",
+			to:        "EUR",
+			expAmount: 100,
+		},
+		{
+			name:      "should default to USD",
+			amount:    100,
+			from:      "",
+			to:        "usd",
+			expAmount: 100,
+		},
+		{
+			name:      "should convert from the base currency",
+			amount:    100,
+			from:      "USD",
+			to:        "EUR",
+			expAmount: 50,
+		},
+		{
+			name:      "should convert to the base currency",
+			amount:    100,
+			from:      "EUR",
+			to:        "USD",
+			expAmount: 200,
+		},
+		{
+			name:      "should convert between other currencies",
+			amount:    100,
+			from:      "EUR",
+			to:        "GBP",
+			expAmount: 50,
+		},
+		{
+			name:   "should fail on an unknown currency",
+			amount: 100,
+			from:   "USD",
+			to:     "JPY",
+			expErr: fmt.Errorf("no exchange rate for currency \"JPY\""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := currency.Convert(rates, tt.amount, tt.from, tt.to)
+			assert.Equal(t, tt.expErr, err)
+			assert.Equal(t, tt.expAmount, amount)
+		})
+	}
+}
+
+func TestNewStaticRateProviderFromS3(t *testing.T) {
+	t.Run("should load exchange rates", func(t *testing.T) {
+		storager := &commonMocks.Storager{}
+		storager.On("GetObject", "bucket", "exchange_rates.json").
+			Return(`{"rates": {"eur": 0.92}}`, nil)
+
+		rates, err := currency.NewStaticRateProviderFromS3(storager, "bucket", "exchange_rates.json")
+		assert.Nil(t, err)
+		assert.Equal(t, &currency.StaticRateProvider{
+			Base: "USD",
+			Rates: map[string]float64{
+				"EUR": 0.92,
+			},
+		}, rates)
+	})
+
+	t.Run("should fail on invalid json", func(t *testing.T) {
+		storager := &commonMocks.Storager{}
+		storager.On("GetObject", "bucket", "exchange_rates.json").
+			Return(`not json`, nil)
+
+		rates, err := currency.NewStaticRateProviderFromS3(storager, "bucket", "exchange_rates.json")
+		assert.Nil(t, rates)
+		assert.Regexp(t, "failed to parse exchange rates from s3://bucket/exchange_rates.json", err.Error())
+	})
+
+	t.Run("should fail on s3 error", func(t *testing.T) {
+		storager := &commonMocks.Storager{}
+		storager.On("GetObject", "bucket", "exchange_rates.json").
+			Return("", fmt.Errorf("access denied"))
+
+		rates, err := currency.NewStaticRateProviderFromS3(storager, "bucket", "exchange_rates.json")
+		assert.Nil(t, rates)
+		assert.Equal(t, fmt.Errorf("failed to get exchange rates from s3://bucket/exchange_rates.json: access denied"), err)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -16,10 +17,11 @@ import (
 
 // Account - Data Layer Struct
 type Account struct {
-	DynamoDB       dynamodbiface.DynamoDBAPI
-	TableName      string `env:"ACCOUNT_DB"`
-	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
-	Limit          int64  `env:"LIMIT" envDefault:"25"`
+	DynamoDB        dynamodbiface.DynamoDBAPI
+	TableName       string `env:"ACCOUNT_DB"`
+	OutboxTableName string `env:"OUTBOX_DB"`
+	ConsistentRead  bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+	Limit           int64  `env:"LIMIT" envDefault:"25"`
 }
 
 // Write the Account record in DynamoDB
@@ -79,6 +81,74 @@ func (a *Account) Write(account *account.Account, prevLastModifiedOn *int64) err
 	return nil
 }
 
+// WriteWithEvents writes the Account record, and an outbox entry for each
+// event about the change, in a single DynamoDB transaction.
+// prevLastModifiedOn parameter is the original lastModifiedOn
+func (a *Account) WriteWithEvents(account *account.Account, prevLastModifiedOn *int64, entries []*outbox.Entry) error {
+
+	var cond expression.ConditionBuilder
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		cond = expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+	} else {
+		cond = expression.Name("LastModifiedOn").AttributeNotExists()
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	putMap, err := dynamodbattribute.Marshal(account)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failure marshaling account %q", *account.ID),
+			err,
+		)
+	}
+	items := []*dynamodb.TransactWriteItem{
+		{
+			Put: &dynamodb.Put{
+				TableName:                 aws.String(a.TableName),
+				Item:                      putMap.M,
+				ConditionExpression:       expr.Condition(),
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+			},
+		},
+	}
+	outboxItems, err := outboxPuts(a.OutboxTableName, entries)
+	if err != nil {
+		return err
+	}
+
+	items = append(items, outboxItems...)
+
+	_, err = a.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == dynamodb.ErrCodeTransactionCanceledException {
+			reasons := cancellationReasons(awsErr.Message(), len(items))
+			// Cancellation reasons are in the same order as the transaction items
+			if cancelledBy(reasons, 0) {
+				return errors.NewConflict(
+					"account",
+					*account.ID,
+					fmt.Errorf("unable to update account: accounts has been modified since request was made"))
+			}
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for account %q", *account.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
 // Delete the Account record in DynamoDB
 func (a *Account) Delete(account *account.Account) error {
 
@@ -106,6 +176,42 @@ func (a *Account) Delete(account *account.Account) error {
 	return nil
 }
 
+// DeleteWithEvents deletes the Account record, and writes an outbox entry
+// for each event about the deletion, in a single DynamoDB transaction
+func (a *Account) DeleteWithEvents(account *account.Account, entries []*outbox.Entry) error {
+
+	items := []*dynamodb.TransactWriteItem{
+		{
+			Delete: &dynamodb.Delete{
+				TableName: aws.String(a.TableName),
+				Key: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: account.ID,
+					},
+				},
+			},
+		},
+	}
+	outboxItems, err := outboxPuts(a.OutboxTableName, entries)
+	if err != nil {
+		return err
+	}
+
+	items = append(items, outboxItems...)
+
+	_, err = a.DynamoDB.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: items,
+	})
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("delete failed for account %q", *account.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
 // Get the Account record by ID
 func (a *Account) Get(ID string) (*account.Account, error) {
 	res, err := a.DynamoDB.GetItem(
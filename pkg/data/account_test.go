@@ -10,6 +10,7 @@ import (
 	"github.com/Optum/dce/pkg/arn"
 	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
 	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
@@ -261,3 +262,142 @@ func TestUpdate(t *testing.T) {
 	}
 
 }
+
+func TestWriteWithEvents(t *testing.T) {
+	entry := &outbox.Entry{
+		ID:     ptrString("abc-123"),
+		Type:   ptrString(outbox.TypeAccountCreated),
+		Data:   ptrString("{}"),
+		Status: outbox.StatusPending.StatusPtr(),
+	}
+
+	tests := []struct {
+		name              string
+		account           *account.Account
+		oldLastModifiedOn *int64
+		dynamoErr         error
+		expectedErr       error
+	}{
+		{
+			name: "should write the account and its events",
+			account: &account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			oldLastModifiedOn: ptrInt64(1573592057),
+		},
+		{
+			name: "should conflict when the account has been modified",
+			account: &account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			oldLastModifiedOn: ptrInt64(1573592057),
+			dynamoErr: awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, None]", nil),
+			expectedErr: errors.NewConflict(
+				"account",
+				"123456789012",
+				fmt.Errorf("unable to update account: accounts has been modified since request was made")),
+		},
+		{
+			name: "other dynamo error",
+			account: &account.Account{
+				ID:             ptrString("123456789012"),
+				Status:         account.StatusReady.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("update failed for account \"123456789012\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				if len(input.TransactItems) != 2 {
+					return false
+				}
+				accountPut := input.TransactItems[0].Put
+				entryPut := input.TransactItems[1].Put
+				return *accountPut.TableName == "Accounts" &&
+					*accountPut.Item["Id"].S == *tt.account.ID &&
+					*entryPut.TableName == "Outbox" &&
+					*entryPut.Item["Id"].S == "abc-123" &&
+					*entryPut.Item["EventType"].S == outbox.TypeAccountCreated &&
+					*entryPut.ConditionExpression == "attribute_not_exists(Id)"
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			accountData := &Account{
+				DynamoDB:        &mockDynamo,
+				TableName:       "Accounts",
+				OutboxTableName: "Outbox",
+			}
+
+			err := accountData.WriteWithEvents(tt.account, tt.oldLastModifiedOn, []*outbox.Entry{entry})
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestDeleteWithEvents(t *testing.T) {
+	entries := []*outbox.Entry{
+		{
+			ID:   ptrString("abc-123"),
+			Type: ptrString(outbox.TypeAccountDeleted),
+		},
+		{
+			ID:   ptrString("abc-456"),
+			Type: ptrString(outbox.TypeAccountReset),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		dynamoErr   error
+		expectedErr error
+	}{
+		{
+			name: "should delete the account and write its events",
+		},
+		{
+			name:        "should fail on dynamo error",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("delete failed for account \"123456789012\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				if len(input.TransactItems) != 3 {
+					return false
+				}
+				del := input.TransactItems[0].Delete
+				return *del.TableName == "Accounts" &&
+					*del.Key["Id"].S == "123456789012" &&
+					*input.TransactItems[1].Put.Item["Id"].S == "abc-123" &&
+					*input.TransactItems[2].Put.Item["Id"].S == "abc-456"
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			accountData := &Account{
+				DynamoDB:        &mockDynamo,
+				TableName:       "Accounts",
+				OutboxTableName: "Outbox",
+			}
+
+			err := accountData.DeleteWithEvents(&account.Account{ID: ptrString("123456789012")}, entries)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
@@ -45,11 +45,18 @@ func (a *Account) queryAccounts(query *account.Account, keyName string, index st
 	queryInput.SetLimit(*query.Limit)
 	if query.NextID != nil {
 		// Should be more dynamic
-		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+		startKey := map[string]*dynamodb.AttributeValue{
 			"Id": &dynamodb.AttributeValue{
 				S: query.NextID,
 			},
-		})
+		}
+		// Index queries have to start from the index key as well
+		if keyName == "AccountStatus" && query.Status != nil {
+			startKey[keyName] = &dynamodb.AttributeValue{
+				S: query.Status.StringPtr(),
+			}
+		}
+		queryInput.SetExclusiveStartKey(startKey)
 	}
 
 	res, err = a.DynamoDB.Query(queryInput)
@@ -130,7 +137,7 @@ func (a *Account) List(query *account.Account) (*account.Accounts, error) {
 	}
 
 	query.NextID = nil
-	for _, v := range outputs.lastEvaluatedKey {
+	if v, ok := outputs.lastEvaluatedKey["Id"]; ok {
 		query.NextID = v.S
 	}
 
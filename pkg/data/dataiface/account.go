@@ -4,6 +4,7 @@ package dataiface
 
 import (
 	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/outbox"
 )
 
 // AccountData makes working with the Account Data Layer easier
@@ -13,8 +14,14 @@ type AccountData interface {
 	// be inserted or updated
 	// prevLastModifiedOn parameter is the original lastModifiedOn
 	Write(account *account.Account, prevLastModifiedOn *int64) error
+	// WriteWithEvents writes the Account record, and an outbox entry for each
+	// event about the change, in a single transaction
+	WriteWithEvents(account *account.Account, prevLastModifiedOn *int64, entries []*outbox.Entry) error
 	// Delete the Account record in DynamoDB
 	Delete(account *account.Account) error
+	// DeleteWithEvents deletes the Account record, and writes an outbox entry
+	// for each event about the deletion, in a single transaction
+	DeleteWithEvents(account *account.Account, entries []*outbox.Entry) error
 	// Get the Account record by ID
 	Get(ID string) (*account.Account, error)
 	// List Get a list of accounts
@@ -3,6 +3,7 @@
 package dataiface
 
 import (
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/lease"
 )
 
@@ -19,4 +20,10 @@ type LeaseData interface {
 	// be inserted or updated
 	// prevLastModifiedOn parameter is the original lastModifiedOn
 	Write(lease *lease.Lease, prevLastModifiedOn *int64) error
+	// WriteWithAccountStatus writes the Lease record and transitions the status
+	// of its account in a single transaction
+	WriteWithAccountStatus(lease *lease.Lease, prevLastModifiedOn *int64, prevAccountStatus account.Status, nextAccountStatus account.Status) error
+	// ActivatePending moves a pending lease onto its account, making the lease
+	// Active and the account Leased in a single transaction
+	ActivatePending(lease *lease.Lease, prevLastModifiedOn *int64) error
 }
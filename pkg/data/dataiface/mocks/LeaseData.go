@@ -2,6 +2,7 @@
 
 package mocks
 
+import account "github.com/Optum/dce/pkg/account"
 import lease "github.com/Optum/dce/pkg/lease"
 import mock "github.com/stretchr/testify/mock"
 
@@ -10,6 +11,20 @@ type LeaseData struct {
 	mock.Mock
 }
 
+// ActivatePending provides a mock function with given fields: _a0, prevLastModifiedOn
+func (_m *LeaseData) ActivatePending(_a0 *lease.Lease, prevLastModifiedOn *int64) error {
+	ret := _m.Called(_a0, prevLastModifiedOn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease, *int64) error); ok {
+		r0 = rf(_a0, prevLastModifiedOn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ID
 func (_m *LeaseData) Get(ID string) (*lease.Lease, error) {
 	ret := _m.Called(ID)
@@ -69,3 +84,17 @@ func (_m *LeaseData) Write(_a0 *lease.Lease, prevLastModifiedOn *int64) error {
 
 	return r0
 }
+
+// WriteWithAccountStatus provides a mock function with given fields: _a0, prevLastModifiedOn, prevAccountStatus, nextAccountStatus
+func (_m *LeaseData) WriteWithAccountStatus(_a0 *lease.Lease, prevLastModifiedOn *int64, prevAccountStatus account.Status, nextAccountStatus account.Status) error {
+	ret := _m.Called(_a0, prevLastModifiedOn, prevAccountStatus, nextAccountStatus)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease, *int64, account.Status, account.Status) error); ok {
+		r0 = rf(_a0, prevLastModifiedOn, prevAccountStatus, nextAccountStatus)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -0,0 +1,16 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/outbox"
+)
+
+// OutboxData makes working with the Outbox Data Layer easier
+type OutboxData interface {
+	// Write the outbox entry in DynamoDB
+	// prevLastModifiedOn parameter is the original lastModifiedOn
+	Write(entry *outbox.Entry, prevLastModifiedOn *int64) error
+	// Get the outbox entry by ID
+	Get(ID string) (*outbox.Entry, error)
+}
@@ -0,0 +1,20 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/principal"
+)
+
+// PrincipalData makes working with the Principal Data Layer easier
+type PrincipalData interface {
+	// Write the Principal record in DynamoDB
+	// prevLastModifiedOn parameter is the original lastModifiedOn
+	Write(principal *principal.Principal, prevLastModifiedOn *int64) error
+	// Delete the Principal record in DynamoDB
+	Delete(principal *principal.Principal) error
+	// Get the Principal record by ID
+	Get(ID string) (*principal.Principal, error)
+	// List Get the list of principal profiles
+	List() (*principal.Principals, error)
+}
@@ -0,0 +1,20 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/team"
+)
+
+// TeamData makes working with the Team Data Layer easier
+type TeamData interface {
+	// Write the Team record in DynamoDB
+	// prevLastModifiedOn parameter is the original lastModifiedOn
+	Write(team *team.Team, prevLastModifiedOn *int64) error
+	// Delete the Team record in DynamoDB
+	Delete(team *team.Team) error
+	// Get the Team record by ID
+	Get(ID string) (*team.Team, error)
+	// List Get the list of teams
+	List() (*team.Teams, error)
+}
@@ -0,0 +1,22 @@
+//
+
+package dataiface
+
+import (
+	"github.com/Optum/dce/pkg/webhook"
+)
+
+// WebhookData makes working with the Webhook Data Layer easier
+type WebhookData interface {
+	// Write the Webhook record in DynamoDB
+	// prevLastModifiedOn parameter is the original lastModifiedOn
+	Write(webhook *webhook.Webhook, prevLastModifiedOn *int64) error
+	// Delete the Webhook record in DynamoDB
+	Delete(webhook *webhook.Webhook) error
+	// Get the Webhook record by ID
+	Get(ID string) (*webhook.Webhook, error)
+	// List Get the list of webhooks
+	List() (*webhook.Webhooks, error)
+	// WriteDeadLetter records an event which couldn't be delivered to a webhook
+	WriteDeadLetter(deadLetter *webhook.DeadLetter) error
+}
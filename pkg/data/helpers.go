@@ -65,3 +65,32 @@ func getItem(input *dynamodb.GetItemInput, dataInterface dynamodbiface.DynamoDBA
 	output, err := dataInterface.GetItem(input)
 	return output, err
 }
+
+// cancellationReasons returns the reason each item of a cancelled transaction
+// was cancelled for, in the same order as the items in the transaction.  This
+// version of the SDK only has the reasons in the TransactionCanceledException message
+// e.g. "Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]"
+// If the message doesn't have a reason for each item, every item is given an
+// empty reason, as any of them may have cancelled the transaction
+func cancellationReasons(message string, items int) []string {
+	reasons := make([]string, items)
+	start := strings.LastIndex(message, "[")
+	end := strings.LastIndex(message, "]")
+	if start < 0 || end < start {
+		return reasons
+	}
+	parsed := strings.Split(message[start+1:end], ",")
+	if len(parsed) != items {
+		return reasons
+	}
+	for i, reason := range parsed {
+		reasons[i] = strings.TrimSpace(reason)
+	}
+	return reasons
+}
+
+// cancelledBy returns true if the item may have cancelled the transaction.
+// Items which didn't cancel it have a reason of "None"
+func cancelledBy(reasons []string, item int) bool {
+	return item < len(reasons) && reasons[item] != "None"
+}
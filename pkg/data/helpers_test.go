@@ -56,3 +56,53 @@ func TestHelpersBuildFilter(t *testing.T) {
 	}
 
 }
+
+func TestCancellationReasons(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		message      string
+		items        int
+		expReasons   []string
+		expCancelled []bool
+	}{
+		{
+			name:         "should read the reason of each item",
+			message:      "Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]",
+			items:        2,
+			expReasons:   []string{"None", "ConditionalCheckFailed"},
+			expCancelled: []bool{false, true},
+		},
+		{
+			name:         "should treat other reasons as cancelling the transaction",
+			message:      "Transaction cancelled, please refer cancellation reasons for specific reasons [TransactionConflict, None]",
+			items:        2,
+			expReasons:   []string{"TransactionConflict", "None"},
+			expCancelled: []bool{true, false},
+		},
+		{
+			name:         "should treat every item as cancelling the transaction without reasons",
+			message:      "Transaction cancelled",
+			items:        2,
+			expReasons:   []string{"", ""},
+			expCancelled: []bool{true, true},
+		},
+		{
+			name:         "should treat every item as cancelling the transaction without a reason for each",
+			message:      "Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed]",
+			items:        2,
+			expReasons:   []string{"", ""},
+			expCancelled: []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reasons := cancellationReasons(tt.message, tt.items)
+			assert.Equal(t, tt.expReasons, reasons)
+			for i, expCancelled := range tt.expCancelled {
+				assert.Equal(t, expCancelled, cancelledBy(reasons, i))
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package data
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/aws/aws-sdk-go/aws"
@@ -13,12 +15,16 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 )
 
+// pendingAccountIDPrefix prefixes the placeholder AccountId key of pending leases
+const pendingAccountIDPrefix = "Pending-"
+
 // Lease - Data Layer Struct
 type Lease struct {
-	DynamoDB       dynamodbiface.DynamoDBAPI
-	TableName      string `env:"LEASE_DB"`
-	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
-	Limit          int64  `env:"LIMIT" envDefault:"25"`
+	DynamoDB         dynamodbiface.DynamoDBAPI
+	TableName        string `env:"LEASE_DB"`
+	AccountTableName string `env:"ACCOUNT_DB"`
+	ConsistentRead   bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+	Limit            int64  `env:"LIMIT" envDefault:"25"`
 }
 
 // Write the Lease record in DynamoDB
@@ -45,10 +51,10 @@ func (a *Lease) Write(lease *lease.Lease, prevLastModifiedOn *int64) error {
 		}
 	}
 
-	putMap, _ := dynamodbattribute.Marshal(lease)
+	item := marshalLease(lease)
 	input := &dynamodb.PutItemInput{
 		TableName:                 aws.String(a.TableName),
-		Item:                      putMap.M,
+		Item:                      item,
 		ConditionExpression:       expr.Condition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
@@ -60,13 +66,13 @@ func (a *Lease) Write(lease *lease.Lease, prevLastModifiedOn *int64) error {
 		if awsErr.Code() == "ConditionalCheckFailedException" {
 			return errors.NewConflict(
 				"lease",
-				*lease.AccountID,
+				*item["AccountId"].S,
 				fmt.Errorf("unable to update lease: leases has been modified since request was made"))
 		}
 	}
 	if err != nil {
 		return errors.NewInternalServer(
-			fmt.Sprintf("update failed for lease with AccountID %q and PrincipalID %q", *lease.AccountID, *lease.PrincipalID),
+			fmt.Sprintf("update failed for lease with AccountID %q and PrincipalID %q", *item["AccountId"].S, *lease.PrincipalID),
 			err,
 		)
 	}
@@ -106,7 +112,7 @@ func (a *Lease) GetByAccountIDAndPrincipalID(accountID string, principalID strin
 	}
 
 	lease := lease.Lease{}
-	err = dynamodbattribute.UnmarshalMap(res.Item, &lease)
+	err = unmarshalLease(res.Item, &lease)
 	if err != nil {
 		return nil, errors.NewInternalServer(
 			fmt.Sprintf("failure unmarshaling lease with account %q and princiapl %q", accountID, principalID),
@@ -151,7 +157,7 @@ func (a *Lease) Get(leaseID string) (*lease.Lease, error) {
 	}
 
 	lease := lease.Lease{}
-	err = dynamodbattribute.UnmarshalMap(res.Items[0], &lease)
+	err = unmarshalLease(res.Items[0], &lease)
 	if err != nil {
 		return nil, errors.NewInternalServer(
 			fmt.Sprintf("failure unmarshaling lease with id %q", leaseID),
@@ -161,3 +167,248 @@ func (a *Lease) Get(leaseID string) (*lease.Lease, error) {
 
 	return &lease, nil
 }
+
+// WriteWithAccountStatus writes the Lease record and transitions the status
+// of its account in a single DynamoDB transaction.
+// prevLastModifiedOn parameter is the original lastModifiedOn of the lease
+// and is nil when the lease is being created.
+// If either the lease or the account was changed since the request was made
+// nothing is written and a conflict error is returned
+func (a *Lease) WriteWithAccountStatus(l *lease.Lease, prevLastModifiedOn *int64, prevAccountStatus account.Status, nextAccountStatus account.Status) error {
+
+	var leaseCond expression.ConditionBuilder
+	// lastModifiedOn is nil on a create.  The table is keyed on account and principal,
+	// so a create may replace a previous lease as long as it's no longer active
+	if prevLastModifiedOn != nil {
+		leaseCond = expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+	} else {
+		leaseCond = expression.Name("LastModifiedOn").AttributeNotExists().Or(
+			expression.Name("LeaseStatus").Equal(expression.Value(lease.StatusInactive.String())),
+		)
+	}
+	leaseExpr, err := expression.NewBuilder().WithCondition(leaseCond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	accountExpr, err := expression.NewBuilder().
+		WithCondition(
+			expression.Name("AccountStatus").Equal(expression.Value(prevAccountStatus.String())),
+		).
+		WithUpdate(
+			expression.Set(
+				expression.Name("AccountStatus"), expression.Value(nextAccountStatus.String()),
+			).Set(
+				expression.Name("LastModifiedOn"), expression.Value(*l.LastModifiedOn),
+			),
+		).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					TableName:                 aws.String(a.TableName),
+					Item:                      marshalLease(l),
+					ConditionExpression:       leaseExpr.Condition(),
+					ExpressionAttributeNames:  leaseExpr.Names(),
+					ExpressionAttributeValues: leaseExpr.Values(),
+				},
+			},
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(a.AccountTableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"Id": {
+							S: l.AccountID,
+						},
+					},
+					ConditionExpression:       accountExpr.Condition(),
+					UpdateExpression:          accountExpr.Update(),
+					ExpressionAttributeNames:  accountExpr.Names(),
+					ExpressionAttributeValues: accountExpr.Values(),
+				},
+			},
+		},
+	}
+
+	_, err = a.DynamoDB.TransactWriteItems(input)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == dynamodb.ErrCodeTransactionCanceledException {
+			reasons := cancellationReasons(awsErr.Message(), len(input.TransactItems))
+			// Cancellation reasons are in the same order as the transaction items
+			if cancelledBy(reasons, 1) {
+				return errors.NewConflict(
+					"account",
+					*l.AccountID,
+					fmt.Errorf("unable to update account: account status is not %q", prevAccountStatus))
+			}
+			if cancelledBy(reasons, 0) {
+				return errors.NewConflict(
+					"lease",
+					*l.AccountID,
+					fmt.Errorf("unable to update lease: leases has been modified since request was made"))
+			}
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for lease with AccountID %q and PrincipalID %q", *l.AccountID, *l.PrincipalID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// ActivatePending moves a Pending lease onto its account, making the lease Active
+// and the account Leased in a single DynamoDB transaction.
+// The pending lease is stored under a placeholder account, so it's replaced
+// with a lease keyed on the account being claimed.
+// prevLastModifiedOn parameter is the lastModifiedOn of the pending lease
+func (a *Lease) ActivatePending(l *lease.Lease, prevLastModifiedOn *int64) error {
+
+	pendingExpr, err := expression.NewBuilder().WithCondition(
+		expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn)).And(
+			expression.Name("LeaseStatus").Equal(expression.Value(lease.StatusPending.String())),
+		),
+	).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	// The account may have been leased to the principal before
+	leaseExpr, err := expression.NewBuilder().WithCondition(
+		expression.Name("LastModifiedOn").AttributeNotExists().Or(
+			expression.Name("LeaseStatus").Equal(expression.Value(lease.StatusInactive.String())),
+		),
+	).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	accountExpr, err := expression.NewBuilder().
+		WithCondition(
+			expression.Name("AccountStatus").Equal(expression.Value(account.StatusReady.String())),
+		).
+		WithUpdate(
+			expression.Set(
+				expression.Name("AccountStatus"), expression.Value(account.StatusLeased.String()),
+			).Set(
+				expression.Name("LastModifiedOn"), expression.Value(*l.LastModifiedOn),
+			),
+		).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Delete: &dynamodb.Delete{
+					TableName: aws.String(a.TableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: aws.String(pendingAccountID(*l.ID)),
+						},
+						"PrincipalId": {
+							S: l.PrincipalID,
+						},
+					},
+					ConditionExpression:       pendingExpr.Condition(),
+					ExpressionAttributeNames:  pendingExpr.Names(),
+					ExpressionAttributeValues: pendingExpr.Values(),
+				},
+			},
+			{
+				Put: &dynamodb.Put{
+					TableName:                 aws.String(a.TableName),
+					Item:                      marshalLease(l),
+					ConditionExpression:       leaseExpr.Condition(),
+					ExpressionAttributeNames:  leaseExpr.Names(),
+					ExpressionAttributeValues: leaseExpr.Values(),
+				},
+			},
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(a.AccountTableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"Id": {
+							S: l.AccountID,
+						},
+					},
+					ConditionExpression:       accountExpr.Condition(),
+					UpdateExpression:          accountExpr.Update(),
+					ExpressionAttributeNames:  accountExpr.Names(),
+					ExpressionAttributeValues: accountExpr.Values(),
+				},
+			},
+		},
+	}
+
+	_, err = a.DynamoDB.TransactWriteItems(input)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == dynamodb.ErrCodeTransactionCanceledException {
+			reasons := cancellationReasons(awsErr.Message(), len(input.TransactItems))
+			// Cancellation reasons are in the same order as the transaction items
+			if cancelledBy(reasons, 2) {
+				return errors.NewConflict(
+					"account",
+					*l.AccountID,
+					fmt.Errorf("unable to update account: account status is not %q", account.StatusReady))
+			}
+			if cancelledBy(reasons, 1) {
+				return errors.NewConflict(
+					"lease",
+					*l.AccountID,
+					fmt.Errorf("unable to update lease: principal already has a lease for the account"))
+			}
+			if cancelledBy(reasons, 0) {
+				return lease.NewPendingModifiedConflict(*l.ID)
+			}
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for lease with AccountID %q and PrincipalID %q", *l.AccountID, *l.PrincipalID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// pendingAccountID is the placeholder for the AccountId key of a
+// pending lease, which doesn't have an account yet
+func pendingAccountID(leaseID string) string {
+	return fmt.Sprintf("%s%s", pendingAccountIDPrefix, leaseID)
+}
+
+// marshalLease marshals the lease into a DynamoDB item, filling in the
+// placeholder AccountId key of pending leases
+func marshalLease(l *lease.Lease) map[string]*dynamodb.AttributeValue {
+	putMap, _ := dynamodbattribute.Marshal(l)
+	if l.AccountID == nil && l.ID != nil {
+		putMap.M["AccountId"] = &dynamodb.AttributeValue{
+			S: aws.String(pendingAccountID(*l.ID)),
+		}
+	}
+	return putMap.M
+}
+
+// unmarshalLease unmarshals a DynamoDB item into the lease, removing the
+// placeholder AccountId key of pending leases
+func unmarshalLease(item map[string]*dynamodb.AttributeValue, l *lease.Lease) error {
+	err := dynamodbattribute.UnmarshalMap(item, l)
+	if err != nil {
+		return err
+	}
+	if l.AccountID != nil && strings.HasPrefix(*l.AccountID, pendingAccountIDPrefix) {
+		l.AccountID = nil
+	}
+	return nil
+}
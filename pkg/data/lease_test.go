@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/Optum/dce/pkg/account"
 	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
 	"github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/lease"
@@ -199,6 +200,279 @@ func TestLeaseUpdate(t *testing.T) {
 
 }
 
+func TestLeaseWriteWithAccountStatus(t *testing.T) {
+	hasValue := func(values map[string]*dynamodb.AttributeValue, value string) bool {
+		for _, v := range values {
+			if (v.S != nil && *v.S == value) || (v.N != nil && *v.N == value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name              string
+		lease             *lease.Lease
+		oldLastModifiedOn *int64
+		prevAccountStatus account.Status
+		nextAccountStatus account.Status
+		dynamoErr         error
+		expectedErr       error
+	}{
+		{
+			name: "should create the lease and claim the account",
+			lease: &lease.Lease{
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			prevAccountStatus: account.StatusReady,
+			nextAccountStatus: account.StatusLeased,
+		},
+		{
+			name: "should update the lease and release the account",
+			lease: &lease.Lease{
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusInactive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			oldLastModifiedOn: ptrInt64(1573592057),
+			prevAccountStatus: account.StatusLeased,
+			nextAccountStatus: account.StatusReady,
+		},
+		{
+			name: "should conflict when the account is no longer ready",
+			lease: &lease.Lease{
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			prevAccountStatus: account.StatusReady,
+			nextAccountStatus: account.StatusLeased,
+			dynamoErr: awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]", nil),
+			expectedErr: errors.NewConflict(
+				"account",
+				"123456789012",
+				fmt.Errorf("unable to update account: account status is not \"Ready\"")),
+		},
+		{
+			name: "should conflict when the lease has been modified",
+			lease: &lease.Lease{
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			prevAccountStatus: account.StatusReady,
+			nextAccountStatus: account.StatusLeased,
+			dynamoErr: awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, None]", nil),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"123456789012",
+				fmt.Errorf("unable to update lease: leases has been modified since request was made")),
+		},
+		{
+			name: "should conflict when the transaction is cancelled for unknown reasons",
+			lease: &lease.Lease{
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			prevAccountStatus: account.StatusReady,
+			nextAccountStatus: account.StatusLeased,
+			dynamoErr:         awserr.New(dynamodb.ErrCodeTransactionCanceledException, "Transaction cancelled", nil),
+			expectedErr: errors.NewConflict(
+				"account",
+				"123456789012",
+				fmt.Errorf("unable to update account: account status is not \"Ready\"")),
+		},
+		{
+			name: "other dynamo error",
+			lease: &lease.Lease{
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User2"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			prevAccountStatus: account.StatusReady,
+			nextAccountStatus: account.StatusLeased,
+			dynamoErr:         gErrors.New("failure"),
+			expectedErr:       errors.NewInternalServer("update failed for lease with AccountID \"123456789012\" and PrincipalID \"User2\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				if len(input.TransactItems) != 2 {
+					return false
+				}
+				put := input.TransactItems[0].Put
+				update := input.TransactItems[1].Update
+				leaseMatches := *put.TableName == "Leases" &&
+					*put.Item["AccountId"].S == *tt.lease.AccountID &&
+					*put.Item["PrincipalId"].S == *tt.lease.PrincipalID &&
+					*put.Item["LeaseStatus"].S == tt.lease.Status.String() &&
+					*put.Item["LastModifiedOn"].N == strconv.FormatInt(*tt.lease.LastModifiedOn, 10)
+				if tt.oldLastModifiedOn == nil {
+					// A create may only replace an inactive lease
+					leaseMatches = leaseMatches && hasValue(put.ExpressionAttributeValues, lease.StatusInactive.String())
+				} else {
+					leaseMatches = leaseMatches && hasValue(put.ExpressionAttributeValues, strconv.FormatInt(*tt.oldLastModifiedOn, 10))
+				}
+				accountMatches := *update.TableName == "Accounts" &&
+					*update.Key["Id"].S == *tt.lease.AccountID &&
+					hasValue(update.ExpressionAttributeValues, tt.prevAccountStatus.String()) &&
+					hasValue(update.ExpressionAttributeValues, tt.nextAccountStatus.String())
+				return leaseMatches && accountMatches
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			leaseData := &Lease{
+				DynamoDB:         &mockDynamo,
+				TableName:        "Leases",
+				AccountTableName: "Accounts",
+			}
+
+			err := leaseData.WriteWithAccountStatus(tt.lease, tt.oldLastModifiedOn, tt.prevAccountStatus, tt.nextAccountStatus)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+
+}
+
+func TestLeaseWritePending(t *testing.T) {
+	mockDynamo := awsmocks.DynamoDBAPI{}
+	mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return *input.TableName == "Leases" &&
+			*input.Item["AccountId"].S == "Pending-70c2d96d-7938-4ec9-917d-476f2b09cc04" &&
+			*input.Item["PrincipalId"].S == "User1" &&
+			*input.Item["LeaseStatus"].S == "Pending"
+	})).Return(&dynamodb.PutItemOutput{}, nil)
+
+	leaseData := &Lease{
+		DynamoDB:  &mockDynamo,
+		TableName: "Leases",
+	}
+
+	err := leaseData.Write(&lease.Lease{
+		ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+		PrincipalID:    ptrString("User1"),
+		Status:         lease.StatusPending.StatusPtr(),
+		LastModifiedOn: ptrInt64(1573592058),
+	}, nil)
+	assert.Nil(t, err)
+	mockDynamo.AssertExpectations(t)
+}
+
+func TestLeaseActivatePending(t *testing.T) {
+	hasValue := func(values map[string]*dynamodb.AttributeValue, value string) bool {
+		for _, v := range values {
+			if (v.S != nil && *v.S == value) || (v.N != nil && *v.N == value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name        string
+		dynamoErr   error
+		expectedErr error
+	}{
+		{
+			name: "should move the pending lease onto the account",
+		},
+		{
+			name: "should conflict when the account is no longer ready",
+			dynamoErr: awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [None, None, ConditionalCheckFailed]", nil),
+			expectedErr: errors.NewConflict(
+				"account",
+				"123456789012",
+				fmt.Errorf("unable to update account: account status is not \"Ready\"")),
+		},
+		{
+			name: "should conflict when the principal already has a lease for the account",
+			dynamoErr: awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed, None]", nil),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"123456789012",
+				fmt.Errorf("unable to update lease: principal already has a lease for the account")),
+		},
+		{
+			name: "should conflict when the pending lease has been modified",
+			dynamoErr: awserr.New(dynamodb.ErrCodeTransactionCanceledException,
+				"Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, None, None]", nil),
+			expectedErr: errors.NewConflict(
+				"lease",
+				"70c2d96d-7938-4ec9-917d-476f2b09cc04",
+				fmt.Errorf("unable to update lease: leases has been modified since request was made")),
+		},
+		{
+			name:        "other dynamo error",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("update failed for lease with AccountID \"123456789012\" and PrincipalID \"User1\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusActive.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			}
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("TransactWriteItems", mock.MatchedBy(func(input *dynamodb.TransactWriteItemsInput) bool {
+				if len(input.TransactItems) != 3 {
+					return false
+				}
+				del := input.TransactItems[0].Delete
+				put := input.TransactItems[1].Put
+				update := input.TransactItems[2].Update
+				pendingMatches := *del.TableName == "Leases" &&
+					*del.Key["AccountId"].S == "Pending-70c2d96d-7938-4ec9-917d-476f2b09cc04" &&
+					*del.Key["PrincipalId"].S == "User1" &&
+					hasValue(del.ExpressionAttributeValues, "1573592057") &&
+					hasValue(del.ExpressionAttributeValues, lease.StatusPending.String())
+				leaseMatches := *put.TableName == "Leases" &&
+					*put.Item["AccountId"].S == "123456789012" &&
+					*put.Item["LeaseStatus"].S == lease.StatusActive.String()
+				accountMatches := *update.TableName == "Accounts" &&
+					*update.Key["Id"].S == "123456789012" &&
+					hasValue(update.ExpressionAttributeValues, account.StatusReady.String()) &&
+					hasValue(update.ExpressionAttributeValues, account.StatusLeased.String())
+				return pendingMatches && leaseMatches && accountMatches
+			})).Return(
+				&dynamodb.TransactWriteItemsOutput{}, tt.dynamoErr,
+			)
+			leaseData := &Lease{
+				DynamoDB:         &mockDynamo,
+				TableName:        "Leases",
+				AccountTableName: "Accounts",
+			}
+
+			err := leaseData.ActivatePending(l, ptrInt64(1573592057))
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetLeaseByID(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -242,6 +516,39 @@ func TestGetLeaseByID(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name:    "should return a pending lease without an account",
+			leaseID: "123",
+			expectedLease: &lease.Lease{
+				ID:             ptrString("123"),
+				PrincipalID:    ptrString("User1"),
+				Status:         lease.StatusPending.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+			dynamoErr: nil,
+			dynamoOutput: &dynamodb.QueryOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					{
+						"Id": {
+							S: aws.String("123"),
+						},
+						"AccountId": {
+							S: aws.String("Pending-123"),
+						},
+						"PrincipalId": {
+							S: aws.String("User1"),
+						},
+						"LeaseStatus": {
+							S: aws.String("Pending"),
+						},
+						"LastModifiedOn": {
+							N: aws.String(strconv.Itoa(1573592058)),
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+		},
 		{
 			name:          "should return nil when more than one found",
 			leaseID:       "123",
@@ -5,7 +5,6 @@ import (
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
-	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"strings"
 )
@@ -18,6 +17,7 @@ func (a *Lease) queryLeases(query *lease.Lease, keyName string, index string) (*
 	var res *dynamodb.QueryOutput
 
 	keyCondition, filters := getFiltersFromStruct(query, &keyName)
+	filters = withPrincipalIDsFilter(filters, query.PrincipalIDs)
 	bldr = expression.NewBuilder().WithKeyCondition(*keyCondition)
 	if filters != nil {
 		bldr = bldr.WithFilter(*filters)
@@ -41,14 +41,21 @@ func (a *Lease) queryLeases(query *lease.Lease, keyName string, index string) (*
 	queryInput.SetLimit(*query.Limit)
 	if query.NextAccountID != nil && query.NextPrincipalID != nil {
 		// Should be more dynamic
-		queryInput.SetExclusiveStartKey(map[string]*dynamodb.AttributeValue{
+		startKey := map[string]*dynamodb.AttributeValue{
 			"AccountId": &dynamodb.AttributeValue{
 				S: query.NextAccountID,
 			},
 			"PrincipalId": &dynamodb.AttributeValue{
 				S: query.NextPrincipalID,
 			},
-		})
+		}
+		// Queries on the status index need the index key to continue
+		if keyName == "LeaseStatus" && query.Status != nil {
+			startKey["LeaseStatus"] = &dynamodb.AttributeValue{
+				S: query.Status.StringPtr(),
+			}
+		}
+		queryInput.SetExclusiveStartKey(startKey)
 	}
 
 	res, err = a.DynamoDB.Query(queryInput)
@@ -65,6 +72,41 @@ func (a *Lease) queryLeases(query *lease.Lease, keyName string, index string) (*
 	}, nil
 }
 
+// maxInOperands is the most values DynamoDB compares an attribute to with IN
+const maxInOperands = 100
+
+// withPrincipalIDsFilter adds a filter for leases of any of the principals to
+// the filters
+func withPrincipalIDsFilter(filters *expression.ConditionBuilder, principalIDs []string) *expression.ConditionBuilder {
+	if len(principalIDs) == 0 {
+		return filters
+	}
+
+	var principalFilter *expression.ConditionBuilder
+	for start := 0; start < len(principalIDs); start += maxInOperands {
+		end := start + maxInOperands
+		if end > len(principalIDs) {
+			end = len(principalIDs)
+		}
+		values := []expression.OperandBuilder{}
+		for _, principalID := range principalIDs[start:end] {
+			values = append(values, expression.Value(principalID))
+		}
+		in := expression.Name("PrincipalId").In(values[0], values[1:]...)
+		if principalFilter == nil {
+			principalFilter = &in
+		} else {
+			*principalFilter = principalFilter.Or(in)
+		}
+	}
+
+	if filters == nil {
+		return principalFilter
+	}
+	*filters = filters.And(*principalFilter)
+	return filters
+}
+
 // scanLeases for doing a scan against dynamodb
 func (a *Lease) scanLeases(query *lease.Lease) (*queryScanOutput, error) {
 	var expr expression.Expression
@@ -72,6 +114,7 @@ func (a *Lease) scanLeases(query *lease.Lease) (*queryScanOutput, error) {
 	var res *dynamodb.ScanOutput
 
 	_, filters := getFiltersFromStruct(query, nil)
+	filters = withPrincipalIDsFilter(filters, query.PrincipalIDs)
 	if filters != nil {
 		expr, err = expression.NewBuilder().WithFilter(*filters).Build()
 		if err != nil {
@@ -146,11 +189,15 @@ func (a *Lease) List(query *lease.Lease) (*lease.Leases, error) {
 		}
 	}
 
-	leases := &lease.Leases{}
-	err = dynamodbattribute.UnmarshalListOfMaps(outputs.items, leases)
-	if err != nil {
-		return nil, errors.NewInternalServer("failed unmarshal of leases", err)
+	leases := lease.Leases{}
+	for _, item := range outputs.items {
+		l := lease.Lease{}
+		err = unmarshalLease(item, &l)
+		if err != nil {
+			return nil, errors.NewInternalServer("failed unmarshal of leases", err)
+		}
+		leases = append(leases, l)
 	}
 
-	return leases, nil
+	return &leases, nil
 }
@@ -100,6 +100,52 @@ func TestGetLeasesScan(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "scan get leases of several principals with accountId",
+			query: &lease.Lease{
+				AccountID:    ptrString("1"),
+				PrincipalIDs: []string{"User1", "User2"},
+			},
+			sInput: &dynamodb.ScanInput{
+				ConsistentRead:   aws.Bool(false),
+				TableName:        aws.String("Leases"),
+				FilterExpression: aws.String("(#0 = :0) AND (#1 IN (:1, :2))"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("AccountId"),
+					"#1": aws.String("PrincipalId"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {
+						S: aws.String("1"),
+					},
+					":1": {
+						S: aws.String("User1"),
+					},
+					":2": {
+						S: aws.String("User2"),
+					},
+				},
+				Limit: ptrInt64(25),
+			},
+			sOutputRec: &dynamodb.ScanOutput{
+				Items: []map[string]*dynamodb.AttributeValue{
+					map[string]*dynamodb.AttributeValue{
+						"AccountId": {
+							S: aws.String("1"),
+						},
+						"PrincipalId": {
+							S: aws.String("User2"),
+						},
+					},
+				},
+			},
+			expLeases: &lease.Leases{
+				{
+					AccountID:   ptrString("1"),
+					PrincipalID: ptrString("User2"),
+				},
+			},
+		},
 		{
 			name:  "scan failure with internal server error",
 			query: &lease.Lease{},
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Outbox - Data Layer Struct
+type Outbox struct {
+	DynamoDB       dynamodbiface.DynamoDBAPI
+	TableName      string `env:"OUTBOX_DB"`
+	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+}
+
+// Write the outbox entry in DynamoDB
+// prevLastModifiedOn parameter is the original lastModifiedOn
+func (o *Outbox) Write(entry *outbox.Entry, prevLastModifiedOn *int64) error {
+
+	var cond expression.ConditionBuilder
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		cond = expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+	} else {
+		cond = expression.Name("LastModifiedOn").AttributeNotExists()
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	putMap, err := dynamodbattribute.MarshalMap(entry)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failure marshaling outbox entry %q", *entry.ID),
+			err,
+		)
+	}
+	input := &dynamodb.PutItemInput{
+		TableName:                 aws.String(o.TableName),
+		Item:                      putMap,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String("NONE"),
+	}
+	err = putItem(input, o.DynamoDB)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == "ConditionalCheckFailedException" {
+			return errors.NewConflict(
+				"outbox entry",
+				*entry.ID,
+				fmt.Errorf("unable to update outbox entry: entry has been modified since request was made"))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for outbox entry %q", *entry.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Get the outbox entry by ID
+func (o *Outbox) Get(ID string) (*outbox.Entry, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(o.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(ID),
+			},
+		},
+		ConsistentRead: aws.Bool(o.ConsistentRead),
+	}
+
+	res, err := getItem(input, o.DynamoDB)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("get failed for outbox entry %q", ID),
+			err,
+		)
+	}
+
+	if len(res.Item) == 0 {
+		return nil, errors.NewNotFound("outbox entry", ID)
+	}
+
+	entry := &outbox.Entry{}
+	err = dynamodbattribute.UnmarshalMap(res.Item, entry)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failure unmarshaling outbox entry %q", ID),
+			err,
+		)
+	}
+	return entry, nil
+}
+
+// outboxPuts creates the transaction items which write the entries to the
+// outbox table, so they can be written in the same transaction as a change
+func outboxPuts(tableName string, entries []*outbox.Entry) ([]*dynamodb.TransactWriteItem, error) {
+	items := []*dynamodb.TransactWriteItem{}
+	for _, entry := range entries {
+		item, err := dynamodbattribute.MarshalMap(entry)
+		if err != nil {
+			return nil, errors.NewInternalServer(
+				fmt.Sprintf("failure marshaling outbox entry %q", *entry.ID),
+				err,
+			)
+		}
+		items = append(items, &dynamodb.TransactWriteItem{
+			Put: &dynamodb.Put{
+				TableName:           aws.String(tableName),
+				Item:                item,
+				ConditionExpression: aws.String("attribute_not_exists(Id)"),
+			},
+		})
+	}
+	return items, nil
+}
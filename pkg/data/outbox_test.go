@@ -0,0 +1,141 @@
+package data
+
+import (
+	gErrors "errors"
+	"fmt"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxGet(t *testing.T) {
+	tests := []struct {
+		name          string
+		dynamoErr     error
+		dynamoOutput  *dynamodb.GetItemOutput
+		expectedErr   error
+		expectedEntry *outbox.Entry
+	}{
+		{
+			name: "should return an outbox entry",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{
+					"Id":             {S: aws.String("abc-123")},
+					"EventType":      {S: aws.String("dce.account.created")},
+					"Data":           {S: aws.String("{}")},
+					"EntryStatus":    {S: aws.String("Pending")},
+					"Attempts":       {N: aws.String("1")},
+					"CreatedOn":      {N: aws.String("1573592058")},
+					"LastModifiedOn": {N: aws.String("1573592058")},
+				},
+			},
+			expectedEntry: &outbox.Entry{
+				ID:             ptrString("abc-123"),
+				Type:           ptrString("dce.account.created"),
+				Data:           ptrString("{}"),
+				Status:         outbox.StatusPending.StatusPtr(),
+				Attempts:       ptrInt64(1),
+				CreatedOn:      ptrInt64(1573592058),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "should return not found",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{},
+			},
+			expectedErr: errors.NewNotFound("outbox entry", "abc-123"),
+		},
+		{
+			name:         "should return dynamo errors",
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.GetItemOutput{},
+			expectedErr:  errors.NewInternalServer("get failed for outbox entry \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("GetItem", &dynamodb.GetItemInput{
+				TableName: aws.String("Outbox"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: aws.String("abc-123"),
+					},
+				},
+				ConsistentRead: aws.Bool(true),
+			}).Return(tt.dynamoOutput, tt.dynamoErr)
+			outboxData := &Outbox{
+				DynamoDB:       &mockDynamo,
+				TableName:      "Outbox",
+				ConsistentRead: true,
+			}
+
+			entry, err := outboxData.Get("abc-123")
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			assert.Equal(t, tt.expectedEntry, entry)
+		})
+	}
+}
+
+func TestOutboxWrite(t *testing.T) {
+	tests := []struct {
+		name              string
+		oldLastModifiedOn *int64
+		dynamoErr         error
+		expectedErr       error
+	}{
+		{
+			name:              "should update the entry",
+			oldLastModifiedOn: ptrInt64(1573592057),
+		},
+		{
+			name:              "should conflict when the entry has been modified",
+			oldLastModifiedOn: ptrInt64(1573592057),
+			dynamoErr:         awserr.New("ConditionalCheckFailedException", "Message", nil),
+			expectedErr: errors.NewConflict(
+				"outbox entry",
+				"abc-123",
+				fmt.Errorf("unable to update outbox entry: entry has been modified since request was made")),
+		},
+		{
+			name:        "should return dynamo errors",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("update failed for outbox entry \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				return *input.TableName == "Outbox" &&
+					*input.Item["Id"].S == "abc-123" &&
+					*input.Item["EntryStatus"].S == "Published"
+			})).Return(&dynamodb.PutItemOutput{}, tt.dynamoErr)
+			outboxData := &Outbox{
+				DynamoDB:  &mockDynamo,
+				TableName: "Outbox",
+			}
+
+			err := outboxData.Write(&outbox.Entry{
+				ID:             ptrString("abc-123"),
+				Type:           ptrString("dce.account.created"),
+				Status:         outbox.StatusPublished.StatusPtr(),
+				LastModifiedOn: ptrInt64(1573592058),
+			}, tt.oldLastModifiedOn)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
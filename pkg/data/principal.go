@@ -0,0 +1,163 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Principal - Data Layer Struct
+type Principal struct {
+	DynamoDB       dynamodbiface.DynamoDBAPI
+	TableName      string `env:"PRINCIPAL_DB"`
+	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+}
+
+// Write the Principal record in DynamoDB
+// prevLastModifiedOn parameter is the original lastModifiedOn
+func (p *Principal) Write(pr *principal.Principal, prevLastModifiedOn *int64) error {
+
+	var cond expression.ConditionBuilder
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		cond = expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+	} else {
+		cond = expression.Name("LastModifiedOn").AttributeNotExists()
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	putMap, err := dynamodbattribute.MarshalMap(pr)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failure marshaling principal %q", *pr.ID),
+			err,
+		)
+	}
+	input := &dynamodb.PutItemInput{
+		TableName:                 aws.String(p.TableName),
+		Item:                      putMap,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String("NONE"),
+	}
+	err = putItem(input, p.DynamoDB)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == "ConditionalCheckFailedException" {
+			return errors.NewConflict(
+				"principal",
+				*pr.ID,
+				fmt.Errorf("unable to update principal: principal has been modified since request was made"))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for principal %q", *pr.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Delete the Principal record in DynamoDB
+func (p *Principal) Delete(pr *principal.Principal) error {
+
+	_, err := p.DynamoDB.DeleteItem(
+		&dynamodb.DeleteItemInput{
+			TableName:    aws.String(p.TableName),
+			ReturnValues: aws.String("NONE"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Id": {
+					S: pr.ID,
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("delete failed for principal %q", *pr.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Get the Principal record by ID
+func (p *Principal) Get(ID string) (*principal.Principal, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(p.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(ID),
+			},
+		},
+		ConsistentRead: aws.Bool(p.ConsistentRead),
+	}
+
+	res, err := getItem(input, p.DynamoDB)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("get failed for principal %q", ID),
+			err,
+		)
+	}
+
+	if len(res.Item) == 0 {
+		return nil, errors.NewNotFound("principal", ID)
+	}
+
+	pr := &principal.Principal{}
+	err = dynamodbattribute.UnmarshalMap(res.Item, pr)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failure unmarshaling principal %q", ID),
+			err,
+		)
+	}
+	return pr, nil
+}
+
+// List Get the list of principal profiles.  Only principals with overridden
+// limits have a profile, so there are few enough to scan every page of the table
+func (p *Principal) List() (*principal.Principals, error) {
+	principals := principal.Principals{}
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		res, err := p.DynamoDB.Scan(&dynamodb.ScanInput{
+			TableName:         aws.String(p.TableName),
+			ConsistentRead:    aws.Bool(p.ConsistentRead),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, errors.NewInternalServer("error getting principals", err)
+		}
+
+		page := principal.Principals{}
+		err = dynamodbattribute.UnmarshalListOfMaps(res.Items, &page)
+		if err != nil {
+			return nil, errors.NewInternalServer("failed unmarshaling of principals", err)
+		}
+		principals = append(principals, page...)
+
+		if len(res.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = res.LastEvaluatedKey
+	}
+
+	return &principals, nil
+}
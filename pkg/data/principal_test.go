@@ -0,0 +1,83 @@
+package data
+
+import (
+	gErrors "errors"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalGet(t *testing.T) {
+	maxLeaseBudgetAmount := 5000.0
+	tests := []struct {
+		name              string
+		dynamoErr         error
+		dynamoOutput      *dynamodb.GetItemOutput
+		expectedErr       error
+		expectedPrincipal *principal.Principal
+	}{
+		{
+			name: "should return a principal",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{
+					"Id":                    {S: aws.String("jdoe99")},
+					"MaxLeaseBudgetAmount":  {N: aws.String("5000")},
+					"MaxLeasePeriod":        {N: aws.String("2592000")},
+					"PrincipalBudgetPeriod": {S: aws.String("MONTHLY")},
+					"CreatedOn":             {N: aws.String("1573592058")},
+					"LastModifiedOn":        {N: aws.String("1573592058")},
+				},
+			},
+			expectedPrincipal: &principal.Principal{
+				ID:                    ptrString("jdoe99"),
+				MaxLeaseBudgetAmount:  &maxLeaseBudgetAmount,
+				MaxLeasePeriod:        ptrInt64(2592000),
+				PrincipalBudgetPeriod: ptrString("MONTHLY"),
+				CreatedOn:             ptrInt64(1573592058),
+				LastModifiedOn:        ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "should return not found",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{},
+			},
+			expectedErr: errors.NewNotFound("principal", "jdoe99"),
+		},
+		{
+			name:         "should return dynamo errors",
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.GetItemOutput{},
+			expectedErr:  errors.NewInternalServer("get failed for principal \"jdoe99\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("GetItem", &dynamodb.GetItemInput{
+				TableName: aws.String("Principals"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: aws.String("jdoe99"),
+					},
+				},
+				ConsistentRead: aws.Bool(false),
+			}).Return(tt.dynamoOutput, tt.dynamoErr)
+			principalData := &Principal{
+				DynamoDB:  &mockDynamo,
+				TableName: "Principals",
+			}
+
+			result, err := principalData.Get("jdoe99")
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			assert.Equal(t, tt.expectedPrincipal, result)
+		})
+	}
+}
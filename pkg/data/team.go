@@ -0,0 +1,163 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// Team - Data Layer Struct
+type Team struct {
+	DynamoDB       dynamodbiface.DynamoDBAPI
+	TableName      string `env:"TEAM_DB"`
+	ConsistentRead bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+}
+
+// Write the Team record in DynamoDB
+// prevLastModifiedOn parameter is the original lastModifiedOn
+func (t *Team) Write(tm *team.Team, prevLastModifiedOn *int64) error {
+
+	var cond expression.ConditionBuilder
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		cond = expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+	} else {
+		cond = expression.Name("LastModifiedOn").AttributeNotExists()
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	putMap, err := dynamodbattribute.MarshalMap(tm)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failure marshaling team %q", *tm.ID),
+			err,
+		)
+	}
+	input := &dynamodb.PutItemInput{
+		TableName:                 aws.String(t.TableName),
+		Item:                      putMap,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String("NONE"),
+	}
+	err = putItem(input, t.DynamoDB)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == "ConditionalCheckFailedException" {
+			return errors.NewConflict(
+				"team",
+				*tm.ID,
+				fmt.Errorf("unable to update team: team has been modified since request was made"))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for team %q", *tm.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Delete the Team record in DynamoDB
+func (t *Team) Delete(tm *team.Team) error {
+
+	_, err := t.DynamoDB.DeleteItem(
+		&dynamodb.DeleteItemInput{
+			TableName:    aws.String(t.TableName),
+			ReturnValues: aws.String("NONE"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Id": {
+					S: tm.ID,
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("delete failed for team %q", *tm.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Get the Team record by ID
+func (t *Team) Get(ID string) (*team.Team, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(t.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(ID),
+			},
+		},
+		ConsistentRead: aws.Bool(t.ConsistentRead),
+	}
+
+	res, err := getItem(input, t.DynamoDB)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("get failed for team %q", ID),
+			err,
+		)
+	}
+
+	if len(res.Item) == 0 {
+		return nil, errors.NewNotFound("team", ID)
+	}
+
+	tm := &team.Team{}
+	err = dynamodbattribute.UnmarshalMap(res.Item, tm)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failure unmarshaling team %q", ID),
+			err,
+		)
+	}
+	return tm, nil
+}
+
+// List Get the list of teams.  There are few enough teams to scan every
+// page of the table
+func (t *Team) List() (*team.Teams, error) {
+	teams := team.Teams{}
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		res, err := t.DynamoDB.Scan(&dynamodb.ScanInput{
+			TableName:         aws.String(t.TableName),
+			ConsistentRead:    aws.Bool(t.ConsistentRead),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, errors.NewInternalServer("error getting teams", err)
+		}
+
+		page := team.Teams{}
+		err = dynamodbattribute.UnmarshalListOfMaps(res.Items, &page)
+		if err != nil {
+			return nil, errors.NewInternalServer("failed unmarshaling of teams", err)
+		}
+		teams = append(teams, page...)
+
+		if len(res.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = res.LastEvaluatedKey
+	}
+
+	return &teams, nil
+}
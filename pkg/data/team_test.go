@@ -0,0 +1,150 @@
+package data
+
+import (
+	gErrors "errors"
+	"fmt"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTeamGet(t *testing.T) {
+	budgetAmount := 5000.0
+	tests := []struct {
+		name         string
+		dynamoErr    error
+		dynamoOutput *dynamodb.GetItemOutput
+		expectedErr  error
+		expectedTeam *team.Team
+	}{
+		{
+			name: "should return a team",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{
+					"Id":   {S: aws.String("abc-123")},
+					"Name": {S: aws.String("platform")},
+					"Leads": {L: []*dynamodb.AttributeValue{
+						{S: aws.String("lead1")},
+					}},
+					"Members": {L: []*dynamodb.AttributeValue{
+						{S: aws.String("user1")},
+						{S: aws.String("user2")},
+					}},
+					"BudgetAmount":   {N: aws.String("5000")},
+					"BudgetCurrency": {S: aws.String("USD")},
+					"BudgetPeriod":   {S: aws.String("MONTHLY")},
+					"CreatedOn":      {N: aws.String("1573592058")},
+					"LastModifiedOn": {N: aws.String("1573592058")},
+				},
+			},
+			expectedTeam: &team.Team{
+				ID:             ptrString("abc-123"),
+				Name:           ptrString("platform"),
+				Leads:          []string{"lead1"},
+				Members:        []string{"user1", "user2"},
+				BudgetAmount:   &budgetAmount,
+				BudgetCurrency: ptrString("USD"),
+				BudgetPeriod:   ptrString("MONTHLY"),
+				CreatedOn:      ptrInt64(1573592058),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "should return not found",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{},
+			},
+			expectedErr: errors.NewNotFound("team", "abc-123"),
+		},
+		{
+			name:         "should return dynamo errors",
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.GetItemOutput{},
+			expectedErr:  errors.NewInternalServer("get failed for team \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("GetItem", &dynamodb.GetItemInput{
+				TableName: aws.String("Teams"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: aws.String("abc-123"),
+					},
+				},
+				ConsistentRead: aws.Bool(false),
+			}).Return(tt.dynamoOutput, tt.dynamoErr)
+			teamData := &Team{
+				DynamoDB:  &mockDynamo,
+				TableName: "Teams",
+			}
+
+			result, err := teamData.Get("abc-123")
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			assert.Equal(t, tt.expectedTeam, result)
+		})
+	}
+}
+
+func TestTeamWrite(t *testing.T) {
+	tests := []struct {
+		name              string
+		oldLastModifiedOn *int64
+		dynamoErr         error
+		expectedErr       error
+	}{
+		{
+			name: "should create the team",
+		},
+		{
+			name:              "should conflict when the team has been modified",
+			oldLastModifiedOn: ptrInt64(1573592057),
+			dynamoErr:         awserr.New("ConditionalCheckFailedException", "Message", nil),
+			expectedErr: errors.NewConflict(
+				"team",
+				"abc-123",
+				fmt.Errorf("unable to update team: team has been modified since request was made")),
+		},
+		{
+			name:        "should return dynamo errors",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("update failed for team \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+				return *input.TableName == "Teams" &&
+					*input.Item["Id"].S == "abc-123" &&
+					*input.Item["Name"].S == "platform" &&
+					*input.Item["Members"].L[0].S == "user1"
+			})).Return(&dynamodb.PutItemOutput{}, tt.dynamoErr)
+			teamData := &Team{
+				DynamoDB:  &mockDynamo,
+				TableName: "Teams",
+			}
+
+			err := teamData.Write(&team.Team{
+				ID:             ptrString("abc-123"),
+				Name:           ptrString("platform"),
+				Members:        []string{"user1"},
+				LastModifiedOn: ptrInt64(1573592058),
+			}, tt.oldLastModifiedOn)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+		})
+	}
+}
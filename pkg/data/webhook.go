@@ -0,0 +1,257 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+)
+
+// Webhook - Data Layer Struct.  Webhook secrets are kept out of DynamoDB, as
+// SecureString parameters in SSM Parameter Store, encrypted with KMS
+type Webhook struct {
+	DynamoDB            dynamodbiface.DynamoDBAPI
+	SSM                 ssmiface.SSMAPI
+	TableName           string `env:"WEBHOOK_DB"`
+	DeadLetterTableName string `env:"WEBHOOK_DEAD_LETTER_DB"`
+	SecretParameterPath string `env:"WEBHOOK_SECRET_PARAMETER_PATH"`
+	ConsistentRead      bool   `env:"USE_CONSISTENT_READS" envDefault:"false"`
+}
+
+// secretParameterName is the name of the SSM parameter holding the webhook's secret
+func (w *Webhook) secretParameterName(ID string) string {
+	return w.SecretParameterPath + "/" + ID
+}
+
+// Write the Webhook record in DynamoDB, and its secret in SSM
+// prevLastModifiedOn parameter is the original lastModifiedOn
+func (w *Webhook) Write(hook *webhook.Webhook, prevLastModifiedOn *int64) error {
+	// Write the secret first, so a webhook is never saved without one
+	if hook.Secret != nil {
+		_, err := w.SSM.PutParameter(&ssm.PutParameterInput{
+			Name:      aws.String(w.secretParameterName(*hook.ID)),
+			Value:     hook.Secret,
+			Type:      aws.String(ssm.ParameterTypeSecureString),
+			Overwrite: aws.Bool(true),
+		})
+		if err != nil {
+			return errors.NewInternalServer(
+				fmt.Sprintf("failed to write secret for webhook %q", *hook.ID),
+				err,
+			)
+		}
+	}
+
+	var cond expression.ConditionBuilder
+	// lastModifiedOn is nil on a create
+	if prevLastModifiedOn != nil {
+		cond = expression.Name("LastModifiedOn").Equal(expression.Value(prevLastModifiedOn))
+	} else {
+		cond = expression.Name("LastModifiedOn").AttributeNotExists()
+	}
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return errors.NewInternalServer("error building query", err)
+	}
+
+	putMap, err := dynamodbattribute.MarshalMap(hook)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failure marshaling webhook %q", *hook.ID),
+			err,
+		)
+	}
+	input := &dynamodb.PutItemInput{
+		TableName:                 aws.String(w.TableName),
+		Item:                      putMap,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              aws.String("NONE"),
+	}
+	err = putItem(input, w.DynamoDB)
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		if awsErr.Code() == "ConditionalCheckFailedException" {
+			return errors.NewConflict(
+				"webhook",
+				*hook.ID,
+				fmt.Errorf("unable to update webhook: webhook has been modified since request was made"))
+		}
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("update failed for webhook %q", *hook.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Delete the Webhook record in DynamoDB
+func (w *Webhook) Delete(hook *webhook.Webhook) error {
+
+	_, err := w.DynamoDB.DeleteItem(
+		&dynamodb.DeleteItemInput{
+			TableName:    aws.String(w.TableName),
+			ReturnValues: aws.String("NONE"),
+			Key: map[string]*dynamodb.AttributeValue{
+				"Id": {
+					S: hook.ID,
+				},
+			},
+		},
+	)
+
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("delete failed for webhook %q", *hook.ID),
+			err,
+		)
+	}
+
+	_, err = w.SSM.DeleteParameter(&ssm.DeleteParameterInput{
+		Name: aws.String(w.secretParameterName(*hook.ID)),
+	})
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsErr.Code() == ssm.ErrCodeParameterNotFound {
+		err = nil
+	}
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failed to delete secret for webhook %q", *hook.ID),
+			err,
+		)
+	}
+
+	return nil
+}
+
+// Get the Webhook record by ID
+func (w *Webhook) Get(ID string) (*webhook.Webhook, error) {
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(w.TableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Id": {
+				S: aws.String(ID),
+			},
+		},
+		ConsistentRead: aws.Bool(w.ConsistentRead),
+	}
+
+	res, err := getItem(input, w.DynamoDB)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("get failed for webhook %q", ID),
+			err,
+		)
+	}
+
+	if len(res.Item) == 0 {
+		return nil, errors.NewNotFound("webhook", ID)
+	}
+
+	hook := &webhook.Webhook{}
+	err = dynamodbattribute.UnmarshalMap(res.Item, hook)
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failure unmarshaling webhook %q", ID),
+			err,
+		)
+	}
+
+	param, err := w.SSM.GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(w.secretParameterName(ID)),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, errors.NewInternalServer(
+			fmt.Sprintf("failed to get secret for webhook %q", ID),
+			err,
+		)
+	}
+	hook.Secret = param.Parameter.Value
+	return hook, nil
+}
+
+// List Get the list of webhooks.  There are few enough webhooks to scan every
+// page of the table
+func (w *Webhook) List() (*webhook.Webhooks, error) {
+	hooks := webhook.Webhooks{}
+	var startKey map[string]*dynamodb.AttributeValue
+	for {
+		res, err := w.DynamoDB.Scan(&dynamodb.ScanInput{
+			TableName:         aws.String(w.TableName),
+			ConsistentRead:    aws.Bool(w.ConsistentRead),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, errors.NewInternalServer("error getting webhooks", err)
+		}
+
+		page := webhook.Webhooks{}
+		err = dynamodbattribute.UnmarshalListOfMaps(res.Items, &page)
+		if err != nil {
+			return nil, errors.NewInternalServer("failed unmarshaling of webhooks", err)
+		}
+		hooks = append(hooks, page...)
+
+		if len(res.LastEvaluatedKey) == 0 {
+			break
+		}
+		startKey = res.LastEvaluatedKey
+	}
+
+	// Webhooks without a secret are left without one, so they can't be signed
+	secrets := map[string]*string{}
+	err := w.SSM.GetParametersByPathPages(&ssm.GetParametersByPathInput{
+		Path:           aws.String(w.SecretParameterPath),
+		WithDecryption: aws.Bool(true),
+	}, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, param := range page.Parameters {
+			secrets[aws.StringValue(param.Name)] = param.Value
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.NewInternalServer("error getting webhook secrets", err)
+	}
+	for i := range hooks {
+		hooks[i].Secret = secrets[w.secretParameterName(aws.StringValue(hooks[i].ID))]
+	}
+
+	return &hooks, nil
+}
+
+// WriteDeadLetter records an event which couldn't be delivered to a webhook
+func (w *Webhook) WriteDeadLetter(deadLetter *webhook.DeadLetter) error {
+	putMap, err := dynamodbattribute.MarshalMap(deadLetter)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failure marshaling dead letter for webhook %q", *deadLetter.WebhookID),
+			err,
+		)
+	}
+
+	err = putItem(&dynamodb.PutItemInput{
+		TableName: aws.String(w.DeadLetterTableName),
+		Item:      putMap,
+	}, w.DynamoDB)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("failed to write dead letter for webhook %q", *deadLetter.WebhookID),
+			err,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,322 @@
+package data
+
+import (
+	gErrors "errors"
+	"fmt"
+	"testing"
+
+	awsmocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWebhookGet(t *testing.T) {
+	tests := []struct {
+		name         string
+		dynamoErr    error
+		dynamoOutput *dynamodb.GetItemOutput
+		ssmErr       error
+		expectedErr  error
+		expectedHook *webhook.Webhook
+	}{
+		{
+			name: "should return a webhook",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{
+					"Id":  {S: aws.String("abc-123")},
+					"Url": {S: aws.String("https://example.com/hook")},
+					"EventTypes": {L: []*dynamodb.AttributeValue{
+						{S: aws.String("dce.lease.created")},
+					}},
+					"CreatedOn":      {N: aws.String("1573592058")},
+					"LastModifiedOn": {N: aws.String("1573592058")},
+				},
+			},
+			expectedHook: &webhook.Webhook{
+				ID:             ptrString("abc-123"),
+				URL:            ptrString("https://example.com/hook"),
+				EventTypes:     []event.Type{event.TypeLeaseCreated},
+				Secret:         ptrString("0123456789abcdef"),
+				CreatedOn:      ptrInt64(1573592058),
+				LastModifiedOn: ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "should return not found",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{},
+			},
+			expectedErr: errors.NewNotFound("webhook", "abc-123"),
+		},
+		{
+			name:         "should return dynamo errors",
+			dynamoErr:    gErrors.New("failure"),
+			dynamoOutput: &dynamodb.GetItemOutput{},
+			expectedErr:  errors.NewInternalServer("get failed for webhook \"abc-123\"", gErrors.New("failure")),
+		},
+		{
+			name: "should return secret errors",
+			dynamoOutput: &dynamodb.GetItemOutput{
+				Item: map[string]*dynamodb.AttributeValue{
+					"Id":  {S: aws.String("abc-123")},
+					"Url": {S: aws.String("https://example.com/hook")},
+				},
+			},
+			ssmErr:      gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("failed to get secret for webhook \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+
+			mockDynamo.On("GetItem", &dynamodb.GetItemInput{
+				TableName: aws.String("Webhooks"),
+				Key: map[string]*dynamodb.AttributeValue{
+					"Id": {
+						S: aws.String("abc-123"),
+					},
+				},
+				ConsistentRead: aws.Bool(false),
+			}).Return(tt.dynamoOutput, tt.dynamoErr)
+			mockSSM := awsmocks.SSMAPI{}
+			mockSSM.On("GetParameter", &ssm.GetParameterInput{
+				Name:           aws.String("/dce/webhooks/secrets/abc-123"),
+				WithDecryption: aws.Bool(true),
+			}).Return(&ssm.GetParameterOutput{
+				Parameter: &ssm.Parameter{Value: aws.String("0123456789abcdef")},
+			}, tt.ssmErr)
+			webhookData := &Webhook{
+				DynamoDB:            &mockDynamo,
+				SSM:                 &mockSSM,
+				TableName:           "Webhooks",
+				SecretParameterPath: "/dce/webhooks/secrets",
+			}
+
+			hook, err := webhookData.Get("abc-123")
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			assert.Equal(t, tt.expectedHook, hook)
+		})
+	}
+}
+
+func TestWebhookList(t *testing.T) {
+	t.Run("should scan every page", func(t *testing.T) {
+		mockDynamo := awsmocks.DynamoDBAPI{}
+		lastKey := map[string]*dynamodb.AttributeValue{
+			"Id": {S: aws.String("abc-123")},
+		}
+
+		mockDynamo.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey == nil
+		})).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{"Id": {S: aws.String("abc-123")}},
+			},
+			LastEvaluatedKey: lastKey,
+		}, nil)
+		mockDynamo.On("Scan", mock.MatchedBy(func(input *dynamodb.ScanInput) bool {
+			return input.ExclusiveStartKey != nil && *input.ExclusiveStartKey["Id"].S == "abc-123"
+		})).Return(&dynamodb.ScanOutput{
+			Items: []map[string]*dynamodb.AttributeValue{
+				{"Id": {S: aws.String("def-456")}},
+			},
+		}, nil)
+		mockSSM := awsmocks.SSMAPI{}
+		mockSSM.On("GetParametersByPathPages", &ssm.GetParametersByPathInput{
+			Path:           aws.String("/dce/webhooks/secrets"),
+			WithDecryption: aws.Bool(true),
+		}, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(*ssm.GetParametersByPathOutput, bool) bool)
+			fn(&ssm.GetParametersByPathOutput{
+				Parameters: []*ssm.Parameter{
+					{Name: aws.String("/dce/webhooks/secrets/abc-123"), Value: aws.String("0123456789abcdef")},
+				},
+			}, true)
+		}).Return(nil)
+		webhookData := &Webhook{
+			DynamoDB:            &mockDynamo,
+			SSM:                 &mockSSM,
+			TableName:           "Webhooks",
+			SecretParameterPath: "/dce/webhooks/secrets",
+		}
+
+		// Webhooks without a secret in SSM are listed without one
+		hooks, err := webhookData.List()
+		assert.Nil(t, err)
+		assert.Equal(t, &webhook.Webhooks{
+			{ID: ptrString("abc-123"), Secret: ptrString("0123456789abcdef")},
+			{ID: ptrString("def-456")},
+		}, hooks)
+		mockDynamo.AssertNumberOfCalls(t, "Scan", 2)
+	})
+
+	t.Run("should return dynamo errors", func(t *testing.T) {
+		mockDynamo := awsmocks.DynamoDBAPI{}
+		mockDynamo.On("Scan", mock.Anything).Return(nil, gErrors.New("failure"))
+		webhookData := &Webhook{
+			DynamoDB:  &mockDynamo,
+			TableName: "Webhooks",
+		}
+
+		_, err := webhookData.List()
+		assert.True(t, errors.Is(err, errors.NewInternalServer("error getting webhooks", gErrors.New("failure"))))
+	})
+}
+
+func TestWebhookWrite(t *testing.T) {
+	tests := []struct {
+		name              string
+		oldLastModifiedOn *int64
+		ssmErr            error
+		dynamoErr         error
+		expectedErr       error
+	}{
+		{
+			name: "should create the webhook",
+		},
+		{
+			name:              "should conflict when the webhook has been modified",
+			oldLastModifiedOn: ptrInt64(1573592057),
+			dynamoErr:         awserr.New("ConditionalCheckFailedException", "Message", nil),
+			expectedErr: errors.NewConflict(
+				"webhook",
+				"abc-123",
+				fmt.Errorf("unable to update webhook: webhook has been modified since request was made")),
+		},
+		{
+			name:        "should return dynamo errors",
+			dynamoErr:   gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("update failed for webhook \"abc-123\"", gErrors.New("failure")),
+		},
+		{
+			name:        "should not save the webhook when its secret can't be written",
+			ssmErr:      gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("failed to write secret for webhook \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockSSM := awsmocks.SSMAPI{}
+
+			mockSSM.On("PutParameter", &ssm.PutParameterInput{
+				Name:      aws.String("/dce/webhooks/secrets/abc-123"),
+				Value:     aws.String("0123456789abcdef"),
+				Type:      aws.String("SecureString"),
+				Overwrite: aws.Bool(true),
+			}).Return(&ssm.PutParameterOutput{}, tt.ssmErr)
+			if tt.ssmErr == nil {
+				// The secret is never written to DynamoDB
+				mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+					_, hasSecret := input.Item["Secret"]
+					return *input.TableName == "Webhooks" &&
+						*input.Item["Id"].S == "abc-123" &&
+						*input.Item["Url"].S == "https://example.com/hook" &&
+						!hasSecret
+				})).Return(&dynamodb.PutItemOutput{}, tt.dynamoErr)
+			}
+			webhookData := &Webhook{
+				DynamoDB:            &mockDynamo,
+				SSM:                 &mockSSM,
+				TableName:           "Webhooks",
+				SecretParameterPath: "/dce/webhooks/secrets",
+			}
+
+			err := webhookData.Write(&webhook.Webhook{
+				ID:             ptrString("abc-123"),
+				URL:            ptrString("https://example.com/hook"),
+				Secret:         ptrString("0123456789abcdef"),
+				LastModifiedOn: ptrInt64(1573592058),
+			}, tt.oldLastModifiedOn)
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+			mockSSM.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookDelete(t *testing.T) {
+	tests := []struct {
+		name        string
+		ssmErr      error
+		expectedErr error
+	}{
+		{
+			name: "should delete the webhook and its secret",
+		},
+		{
+			name:   "should delete a webhook without a secret",
+			ssmErr: awserr.New(ssm.ErrCodeParameterNotFound, "Message", nil),
+		},
+		{
+			name:        "should return secret errors",
+			ssmErr:      gErrors.New("failure"),
+			expectedErr: errors.NewInternalServer("failed to delete secret for webhook \"abc-123\"", gErrors.New("failure")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDynamo := awsmocks.DynamoDBAPI{}
+			mockSSM := awsmocks.SSMAPI{}
+
+			mockDynamo.On("DeleteItem", mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+				return *input.TableName == "Webhooks" && *input.Key["Id"].S == "abc-123"
+			})).Return(&dynamodb.DeleteItemOutput{}, nil)
+			mockSSM.On("DeleteParameter", &ssm.DeleteParameterInput{
+				Name: aws.String("/dce/webhooks/secrets/abc-123"),
+			}).Return(&ssm.DeleteParameterOutput{}, tt.ssmErr)
+			webhookData := &Webhook{
+				DynamoDB:            &mockDynamo,
+				SSM:                 &mockSSM,
+				TableName:           "Webhooks",
+				SecretParameterPath: "/dce/webhooks/secrets",
+			}
+
+			err := webhookData.Delete(&webhook.Webhook{ID: ptrString("abc-123")})
+			assert.Truef(t, errors.Is(err, tt.expectedErr), "actual error %q doesn't match expected error %q", err, tt.expectedErr)
+			mockDynamo.AssertExpectations(t)
+			mockSSM.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookWriteDeadLetter(t *testing.T) {
+	mockDynamo := awsmocks.DynamoDBAPI{}
+
+	mockDynamo.On("PutItem", mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		return *input.TableName == "WebhookDeadLetters" &&
+			*input.Item["WebhookId"].S == "abc-123" &&
+			*input.Item["EventId"].S == "def-456" &&
+			*input.Item["Attempts"].N == "5"
+	})).Return(&dynamodb.PutItemOutput{}, nil)
+	webhookData := &Webhook{
+		DynamoDB:            &mockDynamo,
+		TableName:           "Webhooks",
+		DeadLetterTableName: "WebhookDeadLetters",
+	}
+
+	eventType := event.TypeLeaseCreated
+	err := webhookData.WriteDeadLetter(&webhook.DeadLetter{
+		WebhookID: ptrString("abc-123"),
+		EventID:   ptrString("def-456"),
+		EventType: &eventType,
+		URL:       ptrString("https://example.com/hook"),
+		Payload:   ptrString("{}"),
+		Attempts:  ptrInt64(5),
+		LastError: ptrString("webhook responded with status 500 Internal Server Error"),
+		CreatedOn: ptrInt64(1573592058),
+	})
+	assert.Nil(t, err)
+	mockDynamo.AssertExpectations(t)
+}
@@ -60,6 +60,8 @@ type DBer interface {
 	FindLeasesByPrincipal(principalID string) ([]*Lease, error)
 	FindLeasesByStatus(status LeaseStatus) ([]*Lease, error)
 	UpdateAccountPrincipalPolicyHash(accountID string, prevHash string, nextHash string) (*Account, error)
+	UpdateAccountResetResult(accountID string, result ResetResult) (*Account, error)
+	UpdateLeaseForecastNotifiedOn(accountID string, principalID string, notifiedOn int64) (*Lease, error)
 	OrphanAccount(accountID string) (*Account, error)
 }
 
@@ -90,6 +92,8 @@ func (db *DB) GetAccount(accountID string) (*Account, error) {
 
 // GetReadyAccount returns an available account record with a
 // corresponding status of 'Ready'
+// Deprecated: new leases are created with lease.Service, which chooses
+// the account with an account.Selector
 func (db *DB) GetReadyAccount() (*Account, error) {
 	accounts, err := db.FindAccountsByStatus(Ready)
 	if len(accounts) < 1 {
@@ -605,6 +609,82 @@ func (db *DB) UpdateAccountPrincipalPolicyHash(accountID string, prevHash string
 	return unmarshalAccount(result.Attributes)
 }
 
+// UpdateAccountResetResult records the result of the last reset on the account,
+// and returns the updated record on success
+func (db *DB) UpdateAccountResetResult(accountID string, resetResult ResetResult) (*Account, error) {
+	resetResultValue, err := dynamodbattribute.Marshal(resetResult)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := db.Client.UpdateItem(
+		&dynamodb.UpdateItemInput{
+			TableName: aws.String(db.AccountTableName),
+			// Find Account for the requested accountId
+			Key: map[string]*dynamodb.AttributeValue{
+				"Id": {
+					S: aws.String(accountID),
+				},
+			},
+			UpdateExpression: aws.String("set LastResetResult=:lastResetResult, " +
+				"LastModifiedOn=:lastModifiedOn"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":lastResetResult": resetResultValue,
+				":lastModifiedOn": {
+					N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+				},
+			},
+			// Don't create accounts that have been deleted
+			ConditionExpression: aws.String("attribute_exists(Id)"),
+			// Return the updated record
+			ReturnValues: aws.String("ALL_NEW"),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalAccount(result.Attributes)
+}
+
+// UpdateLeaseForecastNotifiedOn records when the lease's owner was warned that
+// the lease is projected to go over budget, and returns the updated record on success
+func (db *DB) UpdateLeaseForecastNotifiedOn(accountID string, principalID string, notifiedOn int64) (*Lease, error) {
+	result, err := db.Client.UpdateItem(
+		&dynamodb.UpdateItemInput{
+			TableName: aws.String(db.LeaseTableName),
+			// Find Lease for the requested accountId
+			Key: map[string]*dynamodb.AttributeValue{
+				"AccountId": {
+					S: aws.String(accountID),
+				},
+				"PrincipalId": {
+					S: aws.String(principalID),
+				},
+			},
+			UpdateExpression: aws.String("set ForecastNotifiedOn=:forecastNotifiedOn, " +
+				"LastModifiedOn=:lastModifiedOn"),
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":forecastNotifiedOn": {
+					N: aws.String(strconv.FormatInt(notifiedOn, 10)),
+				},
+				":lastModifiedOn": {
+					N: aws.String(strconv.FormatInt(time.Now().Unix(), 10)),
+				},
+			},
+			// Don't create leases that have been deleted
+			ConditionExpression: aws.String("attribute_exists(AccountId)"),
+			// Return the updated record
+			ReturnValues: aws.String("ALL_NEW"),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalLease(result.Attributes)
+}
+
 // GetLeasesInput contains the filtering criteria for the GetLeases scan.
 type GetLeasesInput struct {
 	StartKeys   map[string]string
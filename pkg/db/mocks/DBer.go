@@ -344,6 +344,52 @@ func (_m *DBer) UpdateAccountPrincipalPolicyHash(accountID string, prevHash stri
 	return r0, r1
 }
 
+// UpdateAccountResetResult provides a mock function with given fields: accountID, result
+func (_m *DBer) UpdateAccountResetResult(accountID string, result db.ResetResult) (*db.Account, error) {
+	ret := _m.Called(accountID, result)
+
+	var r0 *db.Account
+	if rf, ok := ret.Get(0).(func(string, db.ResetResult) *db.Account); ok {
+		r0 = rf(accountID, result)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*db.Account)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, db.ResetResult) error); ok {
+		r1 = rf(accountID, result)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLeaseForecastNotifiedOn provides a mock function with given fields: accountID, principalID, notifiedOn
+func (_m *DBer) UpdateLeaseForecastNotifiedOn(accountID string, principalID string, notifiedOn int64) (*db.Lease, error) {
+	ret := _m.Called(accountID, principalID, notifiedOn)
+
+	var r0 *db.Lease
+	if rf, ok := ret.Get(0).(func(string, string, int64) *db.Lease); ok {
+		r0 = rf(accountID, principalID, notifiedOn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*db.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, int64) error); ok {
+		r1 = rf(accountID, principalID, notifiedOn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpsertLease provides a mock function with given fields: lease
 func (_m *DBer) UpsertLease(lease db.Lease) (*db.Lease, error) {
 	ret := _m.Called(lease)
@@ -11,10 +11,25 @@ type Account struct {
 	AccountStatus       AccountStatus          `json:"AccountStatus"`  // Status of the AWS Account
 	LastModifiedOn      int64                  `json:"LastModifiedOn"` // Last Modified Epoch Timestamp
 	CreatedOn           int64                  `json:"CreatedOn"`
-	AdminRoleArn        string                 `json:"AdminRoleArn"`        // Assumed by the master account, to manage this user account
-	PrincipalRoleArn    string                 `json:"PrincipalRoleArn"`    // Assumed by principal users
-	PrincipalPolicyHash string                 `json:"PrincipalPolicyHash"` // The the hash of the policy version deployed
-	Metadata            map[string]interface{} `json:"Metadata"`            // Any org specific metadata pertaining to the account
+	AdminRoleArn        string                 `json:"AdminRoleArn"`              // Assumed by the master account, to manage this user account
+	PrincipalRoleArn    string                 `json:"PrincipalRoleArn"`          // Assumed by principal users
+	PrincipalPolicyHash string                 `json:"PrincipalPolicyHash"`       // The the hash of the policy version deployed
+	Metadata            map[string]interface{} `json:"Metadata"`                  // Any org specific metadata pertaining to the account
+	LastResetResult     *ResetResult           `json:"LastResetResult,omitempty"` // Result of the last time the account was reset
+}
+
+// ResetResult is the outcome of an account reset
+type ResetResult struct {
+	Attempt           int      `json:"Attempt"`                     // Attempt number, counting consecutive failed resets
+	Succeeded         bool     `json:"Succeeded"`                   // Whether the reset deleted all of the account's resources
+	StartedOn         int64    `json:"StartedOn"`                   // Reset start Epoch Timestamp
+	Duration          int64    `json:"Duration"`                    // Reset duration, in seconds
+	ResourcesDeleted  []string `json:"ResourcesDeleted"`            // Resources deleted by the reset
+	ResourcesFailed   []string `json:"ResourcesFailed"`             // Resources the reset failed to delete
+	Error             string   `json:"Error,omitempty"`             // Why the reset failed
+	ReportKey         string   `json:"ReportKey,omitempty"`         // S3 key of the JSON report of a dry run reset
+	ReportCSVKey      string   `json:"ReportCSVKey,omitempty"`      // S3 key of the CSV report of a dry run reset
+	VerificationError string   `json:"VerificationError,omitempty"` // Why the account failed verification after the reset
 }
 
 // Lease is a type corresponding to a Lease
@@ -33,6 +48,9 @@ type Lease struct {
 	LeaseStatusModifiedOn    int64                  `json:"LeaseStatusModifiedOn"`    // Last Modified Epoch Timestamp
 	ExpiresOn                int64                  `json:"ExpiresOn"`                // Lease expiration time as Epoch
 	Metadata                 map[string]interface{} `json:"Metadata"`                 // Arbitrary key-value metadata to store with lease object
+	TeamID                   string                 `json:"TeamId,omitempty"`         // Team whose budget the lease shares
+	// When the owner was warned the lease is projected to go over budget
+	ForecastNotifiedOn int64 `json:"ForecastNotifiedOn,omitempty"`
 }
 
 // Timestamp is a timestamp type for epoch format
@@ -58,6 +76,8 @@ const (
 	Leased AccountStatus = "Leased"
 	// Orphaned status
 	Orphaned AccountStatus = "Orphaned"
+	// ResetFailed status
+	ResetFailed AccountStatus = "ResetFailed"
 )
 
 // ParseAccountStatus - parses the string into an account status.
@@ -69,6 +89,8 @@ func ParseAccountStatus(status string) (AccountStatus, error) {
 		return NotReady, nil
 	case "leased":
 		return Leased, nil
+	case "resetfailed":
+		return ResetFailed, nil
 	}
 	return None, fmt.Errorf("Invalid account status %s", status)
 }
@@ -83,6 +105,8 @@ const (
 	Active LeaseStatus = "Active"
 	// Inactive status
 	Inactive LeaseStatus = "Inactive"
+	// Pending status
+	Pending LeaseStatus = "Pending"
 )
 
 // ParseLeaseStatus - parses the string into an account status.
@@ -92,6 +116,8 @@ func ParseLeaseStatus(status string) (LeaseStatus, error) {
 		return Active, nil
 	case "inactive":
 		return Inactive, nil
+	case "pending":
+		return Pending, nil
 	}
 	return EmptyLeaseStatus, fmt.Errorf("Cannot parse value %s", status)
 }
@@ -106,6 +132,12 @@ const (
 	LeaseOverBudget LeaseStatusReason = "OverBudget"
 	// LeaseOverPrincipalBudget means the lease is over its principal budgeted amount and is therefore reset/reclaimed.
 	LeaseOverPrincipalBudget LeaseStatusReason = "OverPrincipalBudget"
+	// LeaseOverTeamBudget means the leases of the lease's team are over the team's budgeted amount,
+	// and the lease is therefore reset/reclaimed.
+	LeaseOverTeamBudget LeaseStatusReason = "OverTeamBudget"
+	// LeaseOverForecastBudget means the lease's forecasted spend is too far over its budgeted amount,
+	// so the lease was ended early and is therefore reset/reclaimed.
+	LeaseOverForecastBudget LeaseStatusReason = "OverForecastBudget"
 	// LeaseDestroyed means the lease has been deleted via an API call or other user action.
 	LeaseDestroyed LeaseStatusReason = "Destroyed"
 	// LeaseActive means the lease is still active.
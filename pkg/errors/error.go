@@ -17,6 +17,7 @@ const (
 	alreadyExistsError = "AlreadyExistsError"
 	notFoundError      = "NotFoundError"
 	conflictError      = "ConflictError"
+	forbiddenError     = "ForbiddenError"
 )
 
 type detailError struct {
@@ -167,6 +168,19 @@ func NewBadRequest(m string) *StatusError {
 	}
 }
 
+// NewForbidden returns a new error representing a request the user isn't allowed to make
+func NewForbidden(m string) *StatusError {
+	return &StatusError{
+		httpCode: http.StatusForbidden,
+		cause:    nil,
+		Details: detailError{
+			Message: m,
+			Code:    forbiddenError,
+		},
+		stack: callers(),
+	}
+}
+
 // NewServiceUnavailable returns a new error representing service unavailable
 func NewServiceUnavailable(m string) *StatusError {
 	return &StatusError{
@@ -90,6 +90,19 @@ func TestNew(t *testing.T) {
 			},
 			expectedJSON: "{\"error\":{\"message\":\"failure message\",\"code\":\"ClientError\"}}\n",
 		},
+		{
+			name: "new forbidden",
+			err:  NewForbidden("failure message"),
+			expectedStatusError: StatusError{
+				httpCode: http.StatusForbidden,
+				Details: detailError{
+					Message: "failure message",
+					Code:    clientError,
+				},
+				cause: nil,
+			},
+			expectedJSON: "{\"error\":{\"message\":\"failure message\",\"code\":\"ForbiddenError\"}}\n",
+		},
 		{
 			name: "new service unavailable",
 			err:  NewServiceUnavailable("failure message"),
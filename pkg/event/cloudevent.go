@@ -0,0 +1,67 @@
+package event
+
+import (
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/google/uuid"
+)
+
+// CloudEventsSpecVersion is the version of the CloudEvents spec events are formatted with
+const CloudEventsSpecVersion = "1.0"
+
+// Type is the type of a DCE event
+type Type string
+
+// Types of DCE events
+const (
+	TypeAccountCreated         Type = outbox.TypeAccountCreated
+	TypeAccountDeleted         Type = outbox.TypeAccountDeleted
+	TypeAccountUpdated         Type = outbox.TypeAccountUpdated
+	TypeAccountReset           Type = outbox.TypeAccountReset
+	TypeLeaseCreated           Type = "dce.lease.created"
+	TypeLeaseEnded             Type = "dce.lease.ended"
+	TypeLeaseUpdated           Type = "dce.lease.updated"
+	TypeLeaseReservationFailed Type = "dce.lease.reservation_failed"
+)
+
+// CloudEvent is an event envelope in the CloudEvents 1.0 format.
+// See https://github.com/cloudevents/spec/blob/v1.0/spec.md
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            Type        `json:"type"`
+	Subject         string      `json:"subject,omitempty"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// NewCloudEvent wraps the data in a new event envelope
+func NewCloudEvent(source string, eventType Type, data interface{}) *CloudEvent {
+	return &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              uuid.New().String(),
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject(data),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// subject is the ID of the account or lease the event is about
+func subject(data interface{}) string {
+	switch d := data.(type) {
+	case *account.Account:
+		return aws.StringValue(d.ID)
+	case *lease.Lease:
+		return aws.StringValue(d.ID)
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
+)
+
+// EventBridgeEvent is for publishing events to an EventBridge event bus
+type EventBridgeEvent struct {
+	eventBridge eventbridgeiface.EventBridgeAPI
+	busName     string
+	source      string
+	eventType   Type
+}
+
+// Publish an event to the event bus, wrapped in a CloudEvent
+func (e *EventBridgeEvent) Publish(i interface{}) error {
+	return e.put(NewCloudEvent(e.source, e.eventType, i))
+}
+
+// PublishWithID publishes an event to the event bus, using the ID as the
+// ID of the CloudEvent so subscribers can discard duplicates
+func (e *EventBridgeEvent) PublishWithID(id string, i interface{}) error {
+	cloudEvent := NewCloudEvent(e.source, e.eventType, i)
+	cloudEvent.ID = id
+	return e.put(cloudEvent)
+}
+
+func (e *EventBridgeEvent) put(cloudEvent *CloudEvent) error {
+	detailJSON, err := json.Marshal(cloudEvent)
+	if err != nil {
+		return errors.NewInternalServer("unable to marshal response", err)
+	}
+
+	// Send the event
+	output, err := e.eventBridge.PutEvents(&eventbridge.PutEventsInput{
+		Entries: []*eventbridge.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String(e.busName),
+				Source:       aws.String(e.source),
+				DetailType:   aws.String(string(e.eventType)),
+				Detail:       aws.String(string(detailJSON)),
+				Time:         aws.Time(cloudEvent.Time),
+			},
+		},
+	})
+	if err != nil {
+		return errors.NewInternalServer("failed to put event to EventBridge", err)
+	}
+	if aws.Int64Value(output.FailedEntryCount) > 0 {
+		errorMessage := ""
+		if len(output.Entries) > 0 {
+			errorMessage = aws.StringValue(output.Entries[0].ErrorMessage)
+		}
+		return errors.NewInternalServer(
+			fmt.Sprintf("failed to put %s event to EventBridge: %s", e.eventType, errorMessage),
+			nil,
+		)
+	}
+	return nil
+}
+
+// NewEventBridgeEvent creates a new EventBridge eventing struct, for one type of event
+func NewEventBridgeEvent(eventBridge eventbridgeiface.EventBridgeAPI, busName string, source string, eventType Type) (*EventBridgeEvent, error) {
+	if busName == "" {
+		return nil, errors.NewInternalServer("an event bus name is required to publish to EventBridge", nil)
+	}
+	return &EventBridgeEvent{
+		eventBridge: eventBridge,
+		busName:     busName,
+		source:      source,
+		eventType:   eventType,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package event
+
+import (
+	"encoding/json"
+	gErrors "errors"
+	"math"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBridge(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		event       interface{}
+		putOutput   *eventbridge.PutEventsOutput
+		putErr      error
+		expectedErr error
+	}{
+		{
+			name: "publish eventbridge event",
+			event: &account.Account{
+				ID:     aws.String("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			putOutput: &eventbridge.PutEventsOutput{FailedEntryCount: aws.Int64(0)},
+		},
+		{
+			name: "publish eventbridge error",
+			event: &account.Account{
+				ID: aws.String("123456789012"),
+			},
+			putErr:      gErrors.New("error"),
+			expectedErr: errors.NewInternalServer("failed to put event to EventBridge", nil),
+		},
+		{
+			name: "publish eventbridge failed entry",
+			event: &account.Account{
+				ID: aws.String("123456789012"),
+			},
+			putOutput: &eventbridge.PutEventsOutput{
+				FailedEntryCount: aws.Int64(1),
+				Entries: []*eventbridge.PutEventsResultEntry{
+					{ErrorCode: aws.String("InternalFailure"), ErrorMessage: aws.String("internal failure")},
+				},
+			},
+			expectedErr: errors.NewInternalServer("failed to put dce.account.updated event to EventBridge: internal failure", nil),
+		},
+		{
+			name:        "unmarshal error",
+			event:       math.Inf(1),
+			expectedErr: errors.NewInternalServer("unable to marshal response", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEventBridge := &mocks.EventBridgeAPI{}
+			eventer, err := NewEventBridgeEvent(mockEventBridge, "dce-bus", "dce.test", TypeAccountUpdated)
+			require.Nil(t, err)
+
+			var putInput *eventbridge.PutEventsInput
+			mockEventBridge.On("PutEvents", mock.AnythingOfType("*eventbridge.PutEventsInput")).
+				Run(func(args mock.Arguments) {
+					putInput = args.Get(0).(*eventbridge.PutEventsInput)
+				}).
+				Return(tt.putOutput, tt.putErr)
+
+			err = eventer.Publish(tt.event)
+			if tt.expectedErr != nil {
+				require.NotNil(t, err)
+				assert.Equal(t, tt.expectedErr.Error(), err.Error())
+				return
+			}
+			require.Nil(t, err)
+
+			// Check the event is wrapped in a CloudEvent
+			require.Len(t, putInput.Entries, 1)
+			entry := putInput.Entries[0]
+			assert.Equal(t, "dce-bus", *entry.EventBusName)
+			assert.Equal(t, "dce.test", *entry.Source)
+			assert.Equal(t, "dce.account.updated", *entry.DetailType)
+
+			detail := map[string]interface{}{}
+			require.Nil(t, json.Unmarshal([]byte(*entry.Detail), &detail))
+			assert.Equal(t, "1.0", detail["specversion"])
+			assert.Equal(t, "dce.test", detail["source"])
+			assert.Equal(t, "dce.account.updated", detail["type"])
+			assert.Equal(t, "123456789012", detail["subject"])
+			assert.Equal(t, "application/json", detail["datacontenttype"])
+			assert.NotEmpty(t, detail["id"])
+			assert.NotEmpty(t, detail["time"])
+			assert.Equal(t, map[string]interface{}{
+				"id":            "123456789012",
+				"accountStatus": "Ready",
+			}, detail["data"])
+		})
+	}
+
+	t.Run("publish eventbridge event with ID", func(t *testing.T) {
+		mockEventBridge := &mocks.EventBridgeAPI{}
+		eventer, err := NewEventBridgeEvent(mockEventBridge, "dce-bus", "dce.test", TypeAccountCreated)
+		require.Nil(t, err)
+
+		var putInput *eventbridge.PutEventsInput
+		mockEventBridge.On("PutEvents", mock.AnythingOfType("*eventbridge.PutEventsInput")).
+			Run(func(args mock.Arguments) {
+				putInput = args.Get(0).(*eventbridge.PutEventsInput)
+			}).
+			Return(&eventbridge.PutEventsOutput{FailedEntryCount: aws.Int64(0)}, nil)
+
+		err = eventer.PublishWithID("abc-123", &account.Account{ID: aws.String("123456789012")})
+		require.Nil(t, err)
+
+		detail := map[string]interface{}{}
+		require.Nil(t, json.Unmarshal([]byte(*putInput.Entries[0].Detail), &detail))
+		assert.Equal(t, "abc-123", detail["id"])
+	})
+
+	t.Run("requires an event bus name", func(t *testing.T) {
+		_, err := NewEventBridgeEvent(&mocks.EventBridgeAPI{}, "", "dce.test", TypeAccountUpdated)
+		assert.NotNil(t, err)
+	})
+}
@@ -6,6 +6,8 @@ import account "github.com/Optum/dce/pkg/account"
 
 import mock "github.com/stretchr/testify/mock"
 
+import outbox "github.com/Optum/dce/pkg/outbox"
+
 // Servicer is an autogenerated mock type for the Servicer type
 type Servicer struct {
 	mock.Mock
@@ -95,6 +97,20 @@ func (_m *Servicer) LeaseEnd(i interface{}) error {
 	return r0
 }
 
+// LeaseReservationFailed provides a mock function with given fields: i
+func (_m *Servicer) LeaseReservationFailed(i interface{}) error {
+	ret := _m.Called(i)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
+		r0 = rf(i)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LeaseUpdate provides a mock function with given fields: i
 func (_m *Servicer) LeaseUpdate(i interface{}) error {
 	ret := _m.Called(i)
@@ -108,3 +124,17 @@ func (_m *Servicer) LeaseUpdate(i interface{}) error {
 
 	return r0
 }
+
+// PublishEntry provides a mock function with given fields: entry
+func (_m *Servicer) PublishEntry(entry *outbox.Entry) error {
+	ret := _m.Called(entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*outbox.Entry) error); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -4,6 +4,7 @@ package eventiface
 
 import (
 	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/outbox"
 )
 
 // Servicer makes work with the event Hub easier
@@ -22,4 +23,8 @@ type Servicer interface {
 	LeaseEnd(i interface{}) error
 	// LeaseUpdate publish events
 	LeaseUpdate(i interface{}) error
+	// LeaseReservationFailed publish events
+	LeaseReservationFailed(i interface{}) error
+	// PublishEntry publishes an event from the outbox
+	PublishEntry(entry *outbox.Entry) error
 }
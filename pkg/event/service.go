@@ -1,35 +1,56 @@
 package event
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/eventbridge/eventbridgeiface"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
 )
 
+// eventIDAttribute is the message attribute holding the ID of an event
+const eventIDAttribute = "EventId"
+
 // Publisher interface defines anything that can publish an event
 type Publisher interface {
 	Publish(i interface{}) error
 }
 
+// IDPublisher can publish an event with an ID, which subscribers can use
+// to discard duplicates
+type IDPublisher interface {
+	PublishWithID(id string, i interface{}) error
+}
+
 // NewServiceInput are the items required to create a new Eventer service
 type NewServiceInput struct {
-	SnsClient              snsiface.SNSAPI
-	SqsClient              sqsiface.SQSAPI
-	AccountCreatedTopicArn string `env:"ACCOUNT_CREATED_TOPIC_ARN" envDefault:"arn:aws:sns:us-east-1:123456789012:account-create"`
-	AccountDeletedTopicArn string `env:"ACCOUNT_DELETED_TOPIC_ARN" envDefault:"arn:aws:sns:us-east-1:123456789012:account-delete"`
-	AccountResetQueueURL   string `env:"RESET_SQS_URL" envDefault:"DefaultResetSQSUrl"`
-	LeaseAddedTopicArn     string `env:"LEASE_ADDED_TOPIC" envDefault:"arn:aws:sns:us-east-1:123456789012:lease-added"`
+	SnsClient                      snsiface.SNSAPI
+	SqsClient                      sqsiface.SQSAPI
+	EventBridgeClient              eventbridgeiface.EventBridgeAPI
+	AccountCreatedTopicArn         string `env:"ACCOUNT_CREATED_TOPIC_ARN" envDefault:"arn:aws:sns:us-east-1:123456789012:account-create"`
+	AccountDeletedTopicArn         string `env:"ACCOUNT_DELETED_TOPIC_ARN" envDefault:"arn:aws:sns:us-east-1:123456789012:account-delete"`
+	AccountResetQueueURL           string `env:"RESET_SQS_URL" envDefault:"DefaultResetSQSUrl"`
+	LeaseAddedTopicArn             string `env:"LEASE_ADDED_TOPIC" envDefault:"arn:aws:sns:us-east-1:123456789012:lease-added"`
+	LeaseReservationFailedTopicArn string `env:"LEASE_RESERVATION_FAILED_TOPIC" envDefault:"arn:aws:sns:us-east-1:123456789012:lease-reservation-failed"`
+	EventBusName                   string `env:"EVENT_BUS_NAME" envDefault:""`
+	EventSource                    string `env:"EVENT_SOURCE" envDefault:"dce"`
 }
 
 // Service is the public interface for publishing events
 type Service struct {
-	accountCreate []Publisher
-	accountDelete []Publisher
-	accountUpdate []Publisher
-	accountReset  []Publisher
-	leaseCreate   []Publisher
-	leaseEnd      []Publisher
-	leaseUpdate   []Publisher
+	accountCreate          []Publisher
+	accountDelete          []Publisher
+	accountUpdate          []Publisher
+	accountReset           []Publisher
+	leaseCreate            []Publisher
+	leaseEnd               []Publisher
+	leaseUpdate            []Publisher
+	leaseReservationFailed []Publisher
 }
 
 func (e *Service) publish(i interface{}, p ...Publisher) error {
@@ -42,6 +63,21 @@ func (e *Service) publish(i interface{}, p ...Publisher) error {
 	return nil
 }
 
+func (e *Service) publishWithID(id string, i interface{}, p ...Publisher) error {
+	for _, n := range p {
+		var err error
+		if idPublisher, ok := n.(IDPublisher); ok {
+			err = idPublisher.PublishWithID(id, i)
+		} else {
+			err = n.Publish(i)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // AccountCreate publish events
 func (e *Service) AccountCreate(data *account.Account) error {
 	return e.publish(data, e.accountCreate...)
@@ -77,6 +113,43 @@ func (e *Service) LeaseUpdate(i interface{}) error {
 	return e.publish(i, e.leaseUpdate...)
 }
 
+// LeaseReservationFailed publish events
+func (e *Service) LeaseReservationFailed(i interface{}) error {
+	return e.publish(i, e.leaseReservationFailed...)
+}
+
+// PublishEntry publishes an event from the outbox, using the ID of the
+// entry as the ID of the event
+func (e *Service) PublishEntry(entry *outbox.Entry) error {
+	var publishers []Publisher
+	switch aws.StringValue(entry.Type) {
+	case outbox.TypeAccountCreated:
+		publishers = e.accountCreate
+	case outbox.TypeAccountDeleted:
+		publishers = e.accountDelete
+	case outbox.TypeAccountUpdated:
+		publishers = e.accountUpdate
+	case outbox.TypeAccountReset:
+		publishers = e.accountReset
+	default:
+		return errors.NewInternalServer(
+			fmt.Sprintf("unknown outbox event type %q", aws.StringValue(entry.Type)),
+			nil,
+		)
+	}
+
+	data := &account.Account{}
+	err := json.Unmarshal([]byte(aws.StringValue(entry.Data)), data)
+	if err != nil {
+		return errors.NewInternalServer(
+			fmt.Sprintf("unable to unmarshal data of outbox entry %q", aws.StringValue(entry.ID)),
+			err,
+		)
+	}
+
+	return e.publishWithID(aws.StringValue(entry.ID), data, publishers...)
+}
+
 // NewService creates a new instance of Eventer
 func NewService(input NewServiceInput) (*Service, error) {
 	newEventer := &Service{}
@@ -117,11 +190,54 @@ func NewService(input NewServiceInput) (*Service, error) {
 		return nil, err
 	}
 
+	reservationFailedLease, err := NewSnsEvent(input.SnsClient, input.LeaseReservationFailedTopicArn)
+	if err != nil {
+		return nil, err
+	}
+
 	newEventer.leaseCreate = []Publisher{
 		createLease,
 	}
+	newEventer.leaseReservationFailed = []Publisher{
+		reservationFailedLease,
+	}
 	newEventer.leaseEnd = []Publisher{}
 	newEventer.leaseUpdate = []Publisher{}
 
+	//////////////////////////////////////////////////////////////////////
+	// EventBridge Eventing
+	//////////////////////////////////////////////////////////////////////
+	if input.EventBusName != "" {
+		err = newEventer.withEventBridge(input)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return newEventer, nil
 }
+
+// withEventBridge publishes every type of event to the EventBridge event bus
+func (e *Service) withEventBridge(input NewServiceInput) error {
+	publishers := []struct {
+		eventType  Type
+		publishers *[]Publisher
+	}{
+		{TypeAccountCreated, &e.accountCreate},
+		{TypeAccountDeleted, &e.accountDelete},
+		{TypeAccountUpdated, &e.accountUpdate},
+		{TypeAccountReset, &e.accountReset},
+		{TypeLeaseCreated, &e.leaseCreate},
+		{TypeLeaseEnded, &e.leaseEnd},
+		{TypeLeaseUpdated, &e.leaseUpdate},
+		{TypeLeaseReservationFailed, &e.leaseReservationFailed},
+	}
+	for _, p := range publishers {
+		eventBridgeEvent, err := NewEventBridgeEvent(input.EventBridgeClient, input.EventBusName, input.EventSource, p.eventType)
+		if err != nil {
+			return err
+		}
+		*p.publishers = append(*p.publishers, eventBridgeEvent)
+	}
+	return nil
+}
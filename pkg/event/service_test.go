@@ -7,8 +7,12 @@ import (
 	"github.com/Optum/dce/pkg/account"
 	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
 	"github.com/Optum/dce/pkg/event/mocks"
+	"github.com/Optum/dce/pkg/outbox"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewEvent(t *testing.T) {
@@ -20,15 +24,17 @@ func TestNewEvent(t *testing.T) {
 		accountCreatedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:createAccount")
 		accountDeletedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:deleteAccount")
 		leaseAddedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:createLease")
+		leaseReservationFailedTopicArn, _ := arn.Parse("arn:aws:sns:us-east-1:123456789012:reservationFailedLease")
 		accountResetQueueURL := "http://sqs.com/queue"
 
 		eventer, err := NewService(NewServiceInput{
-			SnsClient:              mockSns,
-			SqsClient:              mockSqs,
-			AccountCreatedTopicArn: accountCreatedTopicArn.String(),
-			AccountDeletedTopicArn: accountDeletedTopicArn.String(),
-			LeaseAddedTopicArn:     leaseAddedTopicArn.String(),
-			AccountResetQueueURL:   accountResetQueueURL,
+			SnsClient:                      mockSns,
+			SqsClient:                      mockSqs,
+			AccountCreatedTopicArn:         accountCreatedTopicArn.String(),
+			AccountDeletedTopicArn:         accountDeletedTopicArn.String(),
+			LeaseAddedTopicArn:             leaseAddedTopicArn.String(),
+			LeaseReservationFailedTopicArn: leaseReservationFailedTopicArn.String(),
+			AccountResetQueueURL:           accountResetQueueURL,
 		})
 
 		assert.Nil(t, err)
@@ -54,6 +60,56 @@ func TestNewEvent(t *testing.T) {
 		}, eventer.leaseCreate)
 		assert.Equal(t, []Publisher{}, eventer.leaseUpdate)
 		assert.Equal(t, []Publisher{}, eventer.leaseEnd)
+		assert.Equal(t, []Publisher{
+			&SnsEvent{
+				sns:      mockSns,
+				topicArn: leaseReservationFailedTopicArn,
+			},
+		}, eventer.leaseReservationFailed)
+	})
+
+	t.Run("New Eventer with EventBridge", func(t *testing.T) {
+		mockSns := &awsMocks.SNSAPI{}
+		mockSqs := &awsMocks.SQSAPI{}
+		mockEventBridge := &awsMocks.EventBridgeAPI{}
+
+		eventer, err := NewService(NewServiceInput{
+			SnsClient:                      mockSns,
+			SqsClient:                      mockSqs,
+			EventBridgeClient:              mockEventBridge,
+			AccountCreatedTopicArn:         "arn:aws:sns:us-east-1:123456789012:createAccount",
+			AccountDeletedTopicArn:         "arn:aws:sns:us-east-1:123456789012:deleteAccount",
+			LeaseAddedTopicArn:             "arn:aws:sns:us-east-1:123456789012:createLease",
+			LeaseReservationFailedTopicArn: "arn:aws:sns:us-east-1:123456789012:reservationFailedLease",
+			AccountResetQueueURL:           "http://sqs.com/queue",
+			EventBusName:                   "dce-bus",
+			EventSource:                    "dce.test",
+		})
+		assert.Nil(t, err)
+
+		eventBridgeEvent := func(eventType Type) *EventBridgeEvent {
+			return &EventBridgeEvent{
+				eventBridge: mockEventBridge,
+				busName:     "dce-bus",
+				source:      "dce.test",
+				eventType:   eventType,
+			}
+		}
+
+		// Every event is published to EventBridge, after any SNS or SQS publishers
+		assert.Len(t, eventer.accountCreate, 2)
+		assert.Equal(t, eventBridgeEvent(TypeAccountCreated), eventer.accountCreate[1])
+		assert.Len(t, eventer.accountDelete, 2)
+		assert.Equal(t, eventBridgeEvent(TypeAccountDeleted), eventer.accountDelete[1])
+		assert.Equal(t, []Publisher{eventBridgeEvent(TypeAccountUpdated)}, eventer.accountUpdate)
+		assert.Len(t, eventer.accountReset, 2)
+		assert.Equal(t, eventBridgeEvent(TypeAccountReset), eventer.accountReset[1])
+		assert.Len(t, eventer.leaseCreate, 2)
+		assert.Equal(t, eventBridgeEvent(TypeLeaseCreated), eventer.leaseCreate[1])
+		assert.Equal(t, []Publisher{eventBridgeEvent(TypeLeaseEnded)}, eventer.leaseEnd)
+		assert.Equal(t, []Publisher{eventBridgeEvent(TypeLeaseUpdated)}, eventer.leaseUpdate)
+		assert.Len(t, eventer.leaseReservationFailed, 2)
+		assert.Equal(t, eventBridgeEvent(TypeLeaseReservationFailed), eventer.leaseReservationFailed[1])
 	})
 
 }
@@ -61,41 +117,44 @@ func TestNewEvent(t *testing.T) {
 func TestEventAccountPublishers(t *testing.T) {
 
 	tests := []struct {
-		name                            string
-		event                           *account.Account
-		expectedAccountCreatePublishErr error
-		expectedAccountDeletePublishErr error
-		expectedAccountUpdatePublishErr error
-		expectedLeaseCreatePublishErr   error
-		expectedLeaseEndPublishErr      error
-		expectedLeaseUpdatePublishErr   error
-		expectedAccountResetPublishErr  error
+		name                                     string
+		event                                    *account.Account
+		expectedAccountCreatePublishErr          error
+		expectedAccountDeletePublishErr          error
+		expectedAccountUpdatePublishErr          error
+		expectedLeaseCreatePublishErr            error
+		expectedLeaseEndPublishErr               error
+		expectedLeaseUpdatePublishErr            error
+		expectedAccountResetPublishErr           error
+		expectedLeaseReservationFailedPublishErr error
 	}{
 		{
 			name: "publish events",
 			event: &account.Account{
 				Status: account.StatusReady.StatusPtr(),
 			},
-			expectedAccountCreatePublishErr: nil,
-			expectedAccountDeletePublishErr: nil,
-			expectedAccountUpdatePublishErr: nil,
-			expectedLeaseCreatePublishErr:   nil,
-			expectedLeaseEndPublishErr:      nil,
-			expectedLeaseUpdatePublishErr:   nil,
-			expectedAccountResetPublishErr:  nil,
+			expectedAccountCreatePublishErr:          nil,
+			expectedAccountDeletePublishErr:          nil,
+			expectedAccountUpdatePublishErr:          nil,
+			expectedLeaseCreatePublishErr:            nil,
+			expectedLeaseEndPublishErr:               nil,
+			expectedLeaseUpdatePublishErr:            nil,
+			expectedAccountResetPublishErr:           nil,
+			expectedLeaseReservationFailedPublishErr: nil,
 		},
 		{
 			name: "publish event with errors",
 			event: &account.Account{
 				Status: account.StatusReady.StatusPtr(),
 			},
-			expectedAccountCreatePublishErr: errors.New("failure"),
-			expectedAccountDeletePublishErr: errors.New("failure"),
-			expectedAccountUpdatePublishErr: errors.New("failure"),
-			expectedLeaseCreatePublishErr:   errors.New("failure"),
-			expectedLeaseEndPublishErr:      errors.New("failure"),
-			expectedLeaseUpdatePublishErr:   errors.New("failure"),
-			expectedAccountResetPublishErr:  errors.New("failure"),
+			expectedAccountCreatePublishErr:          errors.New("failure"),
+			expectedAccountDeletePublishErr:          errors.New("failure"),
+			expectedAccountUpdatePublishErr:          errors.New("failure"),
+			expectedLeaseCreatePublishErr:            errors.New("failure"),
+			expectedLeaseEndPublishErr:               errors.New("failure"),
+			expectedLeaseUpdatePublishErr:            errors.New("failure"),
+			expectedAccountResetPublishErr:           errors.New("failure"),
+			expectedLeaseReservationFailedPublishErr: errors.New("failure"),
 		},
 	}
 
@@ -115,15 +174,18 @@ func TestEventAccountPublishers(t *testing.T) {
 			mockLeaseUpdatedPublisher.On("Publish", tt.event).Return(tt.expectedLeaseUpdatePublishErr)
 			mockResetAccountPublisher := mocks.Publisher{}
 			mockResetAccountPublisher.On("Publish", tt.event).Return(tt.expectedAccountResetPublishErr)
+			mockLeaseReservationFailedPublisher := mocks.Publisher{}
+			mockLeaseReservationFailedPublisher.On("Publish", tt.event).Return(tt.expectedLeaseReservationFailedPublishErr)
 
 			eventSvc := Service{
-				accountCreate: []Publisher{&mockCreateAccountPublisher},
-				accountDelete: []Publisher{&mockDeleteAccountPublisher},
-				accountUpdate: []Publisher{&mockUpdateAccountPublisher},
-				accountReset:  []Publisher{&mockResetAccountPublisher},
-				leaseCreate:   []Publisher{&mockLeaseCreatedPublisher},
-				leaseEnd:      []Publisher{&mockLeaseEndedPublisher},
-				leaseUpdate:   []Publisher{&mockLeaseUpdatedPublisher},
+				accountCreate:          []Publisher{&mockCreateAccountPublisher},
+				accountDelete:          []Publisher{&mockDeleteAccountPublisher},
+				accountUpdate:          []Publisher{&mockUpdateAccountPublisher},
+				accountReset:           []Publisher{&mockResetAccountPublisher},
+				leaseCreate:            []Publisher{&mockLeaseCreatedPublisher},
+				leaseEnd:               []Publisher{&mockLeaseEndedPublisher},
+				leaseUpdate:            []Publisher{&mockLeaseUpdatedPublisher},
+				leaseReservationFailed: []Publisher{&mockLeaseReservationFailedPublisher},
 			}
 
 			var err error
@@ -154,11 +216,65 @@ func TestEventAccountPublishers(t *testing.T) {
 			err = eventSvc.LeaseUpdate(tt.event)
 			assert.Equal(t, tt.expectedLeaseUpdatePublishErr, err)
 			mockLeaseUpdatedPublisher.AssertExpectations(t)
+
+			err = eventSvc.LeaseReservationFailed(tt.event)
+			assert.Equal(t, tt.expectedLeaseReservationFailedPublishErr, err)
+			mockLeaseReservationFailedPublisher.AssertExpectations(t)
 		})
 	}
 
 }
 
+func TestEventPublishEntry(t *testing.T) {
+
+	t.Run("publishes an account event with the entry ID", func(t *testing.T) {
+		mockSns := &awsMocks.SNSAPI{}
+		createAccount, err := NewSnsEvent(mockSns, "arn:aws:sns:us-east-1:123456789012:createAccount")
+		assert.Nil(t, err)
+		mockPublisher := &mocks.Publisher{}
+		mockPublisher.On("Publish", mock.MatchedBy(func(acct *account.Account) bool {
+			return *acct.ID == "123456789012" && *acct.Status == account.StatusNotReady
+		})).Return(nil)
+
+		eventSvc := Service{
+			accountCreate: []Publisher{createAccount, mockPublisher},
+		}
+
+		mockSns.On("Publish", mock.MatchedBy(func(input *sns.PublishInput) bool {
+			return *input.MessageAttributes["EventId"].StringValue == "abc-123"
+		})).Return(nil, nil)
+
+		err = eventSvc.PublishEntry(&outbox.Entry{
+			ID:   aws.String("abc-123"),
+			Type: aws.String(outbox.TypeAccountCreated),
+			Data: aws.String(`{"id":"123456789012","accountStatus":"NotReady"}`),
+		})
+		assert.Nil(t, err)
+		mockSns.AssertExpectations(t)
+		mockPublisher.AssertExpectations(t)
+	})
+
+	t.Run("fails on an unknown event type", func(t *testing.T) {
+		eventSvc := Service{}
+		err := eventSvc.PublishEntry(&outbox.Entry{
+			ID:   aws.String("abc-123"),
+			Type: aws.String("dce.unknown"),
+			Data: aws.String(`{}`),
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("fails on invalid data", func(t *testing.T) {
+		eventSvc := Service{}
+		err := eventSvc.PublishEntry(&outbox.Entry{
+			ID:   aws.String("abc-123"),
+			Type: aws.String(outbox.TypeAccountReset),
+			Data: aws.String(`not json`),
+		})
+		assert.NotNil(t, err)
+	})
+}
+
 func TestPublishingWithRange(t *testing.T) {
 
 	type data struct {
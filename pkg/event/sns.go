@@ -19,6 +19,12 @@ type SnsEvent struct {
 
 // Publish an event to the topic
 func (s *SnsEvent) Publish(i interface{}) error {
+	return s.PublishWithID("", i)
+}
+
+// PublishWithID publishes an event to the topic, with the ID in the
+// EventId message attribute so subscribers can discard duplicates
+func (s *SnsEvent) PublishWithID(id string, i interface{}) error {
 	bodyJSON, err := json.Marshal(i)
 	if err != nil {
 		return errors.NewInternalServer("unable to marshal response", err)
@@ -33,12 +39,22 @@ func (s *SnsEvent) Publish(i interface{}) error {
 		return errors.NewInternalServer("failed to prepare SNS body JSON", err)
 	}
 
-	// Send the message
-	_, err = s.sns.Publish(&sns.PublishInput{
+	input := &sns.PublishInput{
 		Message:          aws.String(string(message)),
 		TopicArn:         aws.String(s.topicArn.String()),
 		MessageStructure: aws.String("json"),
-	})
+	}
+	if id != "" {
+		input.MessageAttributes = map[string]*sns.MessageAttributeValue{
+			eventIDAttribute: {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(id),
+			},
+		}
+	}
+
+	// Send the message
+	_, err = s.sns.Publish(input)
 	if err != nil {
 		return errors.NewInternalServer("failed to publish message to SNS topic", err)
 	}
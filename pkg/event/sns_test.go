@@ -79,4 +79,27 @@ func TestSns(t *testing.T) {
 		})
 	}
 
+	t.Run("publish sns event with ID", func(t *testing.T) {
+		mockSns := &mocks.SNSAPI{}
+		eventer, _ := NewSnsEvent(mockSns, "arn:aws:sns:us-east-1:123456789012:test")
+
+		mockSns.On("Publish",
+			&sns.PublishInput{
+				Message:          aws.String("{\"Body\":\"{\\\"key\\\":\\\"value\\\"}\",\"default\":\"{\\\"key\\\":\\\"value\\\"}\"}"),
+				TopicArn:         aws.String("arn:aws:sns:us-east-1:123456789012:test"),
+				MessageStructure: aws.String("json"),
+				MessageAttributes: map[string]*sns.MessageAttributeValue{
+					"EventId": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String("abc-123"),
+					},
+				},
+			},
+		).Return(nil, nil)
+
+		err := eventer.PublishWithID("abc-123", data{Key: "value"})
+		assert.Nil(t, err)
+		mockSns.AssertExpectations(t)
+	})
+
 }
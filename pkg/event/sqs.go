@@ -17,6 +17,12 @@ type SqsEvent struct {
 
 // Publish an event to the topic
 func (s *SqsEvent) Publish(i interface{}) error {
+	return s.PublishWithID("", i)
+}
+
+// PublishWithID publishes an event to the queue, with the ID in the
+// EventId message attribute so consumers can discard duplicates
+func (s *SqsEvent) PublishWithID(id string, i interface{}) error {
 	bodyJSON, err := json.Marshal(i)
 	if err != nil {
 		return errors.NewInternalServer("unable to marshal response", err)
@@ -27,6 +33,14 @@ func (s *SqsEvent) Publish(i interface{}) error {
 		QueueUrl:    aws.String(s.url),
 		MessageBody: aws.String(string(bodyJSON)),
 	}
+	if id != "" {
+		input.MessageAttributes = map[string]*sqs.MessageAttributeValue{
+			eventIDAttribute: {
+				DataType:    aws.String("String"),
+				StringValue: aws.String(id),
+			},
+		}
+	}
 
 	// Send the message
 	_, err = s.sqs.SendMessage(&input)
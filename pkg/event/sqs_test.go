@@ -78,4 +78,26 @@ func TestSqs(t *testing.T) {
 		})
 	}
 
+	t.Run("publish sqs event with ID", func(t *testing.T) {
+		mockSqs := &mocks.SQSAPI{}
+		eventer, _ := NewSqsEvent(mockSqs, "http://url.com")
+
+		mockSqs.On("SendMessage",
+			&sqs.SendMessageInput{
+				MessageBody: aws.String("{\"key\":\"value\"}"),
+				QueueUrl:    aws.String("http://url.com"),
+				MessageAttributes: map[string]*sqs.MessageAttributeValue{
+					"EventId": {
+						DataType:    aws.String("String"),
+						StringValue: aws.String("abc-123"),
+					},
+				},
+			},
+		).Return(nil, nil)
+
+		err := eventer.PublishWithID("abc-123", data{Key: "value"})
+		assert.Nil(t, err)
+		mockSqs.AssertExpectations(t)
+	})
+
 }
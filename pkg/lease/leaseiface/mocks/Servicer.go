@@ -11,6 +11,75 @@ type Servicer struct {
 	mock.Mock
 }
 
+// ActivateScheduled provides a mock function with given fields: ID
+func (_m *Servicer) ActivateScheduled(ID string) (*lease.Lease, error) {
+	ret := _m.Called(ID)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string) *lease.Lease); ok {
+		r0 = rf(ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Approve provides a mock function with given fields: ID
+func (_m *Servicer) Approve(ID string) (*lease.Lease, error) {
+	ret := _m.Called(ID)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string) *lease.Lease); ok {
+		r0 = rf(ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: data
+func (_m *Servicer) Create(data *lease.Lease) (*lease.Lease, error) {
+	ret := _m.Called(data)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(*lease.Lease) *lease.Lease); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*lease.Lease) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields: ID
 func (_m *Servicer) Delete(ID string) (*lease.Lease, error) {
 	ret := _m.Called(ID)
@@ -34,6 +103,29 @@ func (_m *Servicer) Delete(ID string) (*lease.Lease, error) {
 	return r0, r1
 }
 
+// FulfillPending provides a mock function with given fields: accountID
+func (_m *Servicer) FulfillPending(accountID string) (*lease.Lease, error) {
+	ret := _m.Called(accountID)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string) *lease.Lease); ok {
+		r0 = rf(accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: ID
 func (_m *Servicer) Get(ID string) (*lease.Lease, error) {
 	ret := _m.Called(ID)
@@ -93,3 +185,72 @@ func (_m *Servicer) ListPages(query *lease.Lease, fn func(*lease.Leases) bool) e
 
 	return r0
 }
+
+// Reject provides a mock function with given fields: ID
+func (_m *Servicer) Reject(ID string) (*lease.Lease, error) {
+	ret := _m.Called(ID)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string) *lease.Lease); ok {
+		r0 = rf(ID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(ID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestApproval provides a mock function with given fields: data
+func (_m *Servicer) RequestApproval(data *lease.Lease) (*lease.Lease, error) {
+	ret := _m.Called(data)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(*lease.Lease) *lease.Lease); ok {
+		r0 = rf(data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*lease.Lease) error); ok {
+		r1 = rf(data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ID, data
+func (_m *Servicer) Update(ID string, data *lease.Lease) (*lease.Lease, error) {
+	ret := _m.Called(ID, data)
+
+	var r0 *lease.Lease
+	if rf, ok := ret.Get(0).(func(string, *lease.Lease) *lease.Lease); ok {
+		r0 = rf(ID, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*lease.Lease)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, *lease.Lease) error); ok {
+		r1 = rf(ID, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
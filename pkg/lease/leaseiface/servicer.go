@@ -14,6 +14,27 @@ type Servicer interface {
 	//// Save writes the record to the dataSvc
 	//Save(data *lease.Lease) error
 
+	// Create creates a new lease for a principal and claims a Ready account for it
+	Create(data *lease.Lease) (*lease.Lease, error)
+
+	// RequestApproval creates a new lease for a principal which waits for an approver
+	RequestApproval(data *lease.Lease) (*lease.Lease, error)
+
+	// Approve approves a lease which is pending approval and claims a Ready account for it
+	Approve(ID string) (*lease.Lease, error)
+
+	// Reject rejects a lease which is pending approval
+	Reject(ID string) (*lease.Lease, error)
+
+	// FulfillPending gives a Ready account to the oldest pending lease that can use it
+	FulfillPending(accountID string) (*lease.Lease, error)
+
+	// ActivateScheduled claims a Ready account for a scheduled lease which has reached its start time
+	ActivateScheduled(ID string) (*lease.Lease, error)
+
+	// Update changes the budget amount and expiration of an active lease
+	Update(ID string, data *lease.Lease) (*lease.Lease, error)
+
 	// Update the Lease record to status Inactive in DynamoDB
 	Delete(ID string) (*lease.Lease, error)
 
@@ -2,6 +2,7 @@
 
 package mocks
 
+import account "github.com/Optum/dce/pkg/account"
 import lease "github.com/Optum/dce/pkg/lease"
 import mock "github.com/stretchr/testify/mock"
 
@@ -10,6 +11,20 @@ type ReaderWriterDeleter struct {
 	mock.Mock
 }
 
+// ActivatePending provides a mock function with given fields: input, lastModifiedOn
+func (_m *ReaderWriterDeleter) ActivatePending(input *lease.Lease, lastModifiedOn *int64) error {
+	ret := _m.Called(input, lastModifiedOn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease, *int64) error); ok {
+		r0 = rf(input, lastModifiedOn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: input
 func (_m *ReaderWriterDeleter) Delete(input *lease.Lease) error {
 	ret := _m.Called(input)
@@ -83,3 +98,17 @@ func (_m *ReaderWriterDeleter) Write(input *lease.Lease, lastModifiedOn *int64)
 
 	return r0
 }
+
+// WriteWithAccountStatus provides a mock function with given fields: input, lastModifiedOn, prevAccountStatus, nextAccountStatus
+func (_m *ReaderWriterDeleter) WriteWithAccountStatus(input *lease.Lease, lastModifiedOn *int64, prevAccountStatus account.Status, nextAccountStatus account.Status) error {
+	ret := _m.Called(input, lastModifiedOn, prevAccountStatus, nextAccountStatus)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*lease.Lease, *int64, account.Status, account.Status) error); ok {
+		r0 = rf(input, lastModifiedOn, prevAccountStatus, nextAccountStatus)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
@@ -11,28 +11,39 @@ import (
 // Lease is a type corresponding to a Lease
 // table record
 type Lease struct {
-	AccountID                *string       `json:"accountId,omitempty" dynamodbav:"AccountId" schema:"accountId,omitempty"`                                                        // AWS Account ID
-	PrincipalID              *string       `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`                                                  // Azure User Principal ID
-	ID                       *string       `json:"id,omitempty" dynamodbav:"Id,omitempty" schema:"id,omitempty"`                                                                   // Lease ID
-	Status                   *Status       `json:"leaseStatus,omitempty" dynamodbav:"LeaseStatus,omitempty" schema:"status,omitempty"`                                             // Status of the Lease
-	StatusReason             *StatusReason `json:"leaseStatusReason,omitempty" dynamodbav:"LeaseStatusReason,omitempty" schema:"-"`                                                // Reason for the status of the lease
-	CreatedOn                *int64        `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty" schema:"createdOn,omitempty"`                                              // Created Epoch Timestamp
-	LastModifiedOn           *int64        `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty" schema:"lastModifiedOn,omitempty"`                               // Last Modified Epoch Timestamp
-	BudgetAmount             *float64      `json:"budgetAmount,omitempty" dynamodbav:"BudgetAmount,omitempty" schema:"budgetAmount,omitempty"`                                     // Budget Amount allocated for this lease
-	BudgetCurrency           *string       `json:"budgetCurrency,omitempty" dynamodbav:"BudgetCurrency,omitempty" schema:"budgetCurrency,omitempty"`                               // Budget currency
-	BudgetNotificationEmails *[]string     `json:"budgetNotificationEmails,omitempty" dynamodbav:"BudgetNotificationEmails,omitempty" schema:"budgetNotificationEmails,omitempty"` // Budget notification emails
-	StatusModifiedOn         *int64        `json:"leaseStatusModifiedOn,omitempty" dynamodbav:"LeaseStatusModifiedOn,omitempty" schema:"leaseStatusModifiedOn,omitempty"`          // Last Modified Epoch Timestamp
-	ExpiresOn                *int64        `json:"expiresOn,omitempty" dynamodbav:"ExpiresOn,omitempty" schema:"expiresOn,omitempty"`                                              // Lease expiration time as Epoch
-	Limit                    *int64        `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
-	NextAccountID            *string       `json:"-" dynamodbav:"-" schema:"nextAccountId,omitempty"`
-	NextPrincipalID          *string       `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
+	AccountID                *string                `json:"accountId,omitempty" dynamodbav:"AccountId" schema:"accountId,omitempty"`                                                        // AWS Account ID
+	PrincipalID              *string                `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`                                                  // Azure User Principal ID
+	ID                       *string                `json:"id,omitempty" dynamodbav:"Id,omitempty" schema:"id,omitempty"`                                                                   // Lease ID
+	Status                   *Status                `json:"leaseStatus,omitempty" dynamodbav:"LeaseStatus,omitempty" schema:"status,omitempty"`                                             // Status of the Lease
+	StatusReason             *StatusReason          `json:"leaseStatusReason,omitempty" dynamodbav:"LeaseStatusReason,omitempty" schema:"-"`                                                // Reason for the status of the lease
+	CreatedOn                *int64                 `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty" schema:"createdOn,omitempty"`                                              // Created Epoch Timestamp
+	LastModifiedOn           *int64                 `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty" schema:"lastModifiedOn,omitempty"`                               // Last Modified Epoch Timestamp
+	BudgetAmount             *float64               `json:"budgetAmount,omitempty" dynamodbav:"BudgetAmount,omitempty" schema:"budgetAmount,omitempty"`                                     // Budget Amount allocated for this lease
+	BudgetCurrency           *string                `json:"budgetCurrency,omitempty" dynamodbav:"BudgetCurrency,omitempty" schema:"budgetCurrency,omitempty"`                               // Budget currency
+	BudgetNotificationEmails *[]string              `json:"budgetNotificationEmails,omitempty" dynamodbav:"BudgetNotificationEmails,omitempty" schema:"budgetNotificationEmails,omitempty"` // Budget notification emails
+	StatusModifiedOn         *int64                 `json:"leaseStatusModifiedOn,omitempty" dynamodbav:"LeaseStatusModifiedOn,omitempty" schema:"leaseStatusModifiedOn,omitempty"`          // Last Modified Epoch Timestamp
+	ExpiresOn                *int64                 `json:"expiresOn,omitempty" dynamodbav:"ExpiresOn,omitempty" schema:"expiresOn,omitempty"`                                              // Lease expiration time as Epoch
+	StartsOn                 *int64                 `json:"startsOn,omitempty" dynamodbav:"StartsOn,omitempty" schema:"startsOn,omitempty"`                                                 // Start time of a scheduled lease as Epoch
+	Metadata                 map[string]interface{} `json:"metadata,omitempty" dynamodbav:"Metadata,omitempty" schema:"-"`                                                                  // Arbitrary key-value metadata to store with lease object
+	AccountSelector          map[string]string      `json:"accountSelector,omitempty" dynamodbav:"AccountSelector,omitempty" schema:"-"`                                                    // Hints for selecting the account to lease, e.g. metadata.accountTier=gpu
+	TeamID                   *string                `json:"teamId,omitempty" dynamodbav:"TeamId,omitempty" schema:"teamId,omitempty"`                                                       // Team whose budget the lease shares
+	ForecastNotifiedOn       *int64                 `json:"forecastNotifiedOn,omitempty" dynamodbav:"ForecastNotifiedOn,omitempty" schema:"-"`                                              // When the owner was warned the lease is projected to go over budget, as Epoch
+	Limit                    *int64                 `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
+	NextAccountID            *string                `json:"-" dynamodbav:"-" schema:"nextAccountId,omitempty"`
+	NextPrincipalID          *string                `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
+	PrincipalIDs             []string               `json:"-" dynamodbav:"-" schema:"-"` // Principals the leases are listed for, or all principals when empty
 }
 
 // Validate the lease data
 func (l *Lease) Validate() error {
+	accountIDRules := validateAccountID
+	// Pending leases are still waiting for an account
+	if l.Status != nil && *l.Status == StatusPending {
+		accountIDRules = []validation.Rule{validation.By(isNil)}
+	}
 	err := validation.ValidateStruct(l,
 		validation.Field(&l.ID, validateID...),
-		validation.Field(&l.AccountID, validateAccountID...),
+		validation.Field(&l.AccountID, accountIDRules...),
 		validation.Field(&l.PrincipalID, validatePrincipalID...),
 		validation.Field(&l.LastModifiedOn, validateInt64...),
 		validation.Field(&l.Status, validateStatus...),
@@ -57,6 +68,8 @@ const (
 	StatusActive Status = "Active"
 	// StatusInactive status
 	StatusInactive Status = "Inactive"
+	// StatusPending status
+	StatusPending Status = "Pending"
 )
 
 // String returns the string value of Status
@@ -83,6 +96,8 @@ func ParseStatus(status string) (Status, error) {
 		return StatusActive, nil
 	case "inactive":
 		return StatusInactive, nil
+	case "pending":
+		return StatusPending, nil
 	}
 	return StatusEmpty, fmt.Errorf("Cannot parse value %s", status)
 }
@@ -97,6 +112,12 @@ const (
 	StatusReasonOverBudget StatusReason = "OverBudget"
 	// StatusReasonOverPrincipalBudget means the lease is over its principal budgeted amount and is therefore reset/reclaimed.
 	StatusReasonOverPrincipalBudget StatusReason = "OverPrincipalBudget"
+	// StatusReasonOverTeamBudget means the leases of the lease's team are over the team's budgeted amount,
+	// and the lease is therefore reset/reclaimed.
+	StatusReasonOverTeamBudget StatusReason = "OverTeamBudget"
+	// StatusReasonOverForecastBudget means the lease's forecasted spend is too far over its budgeted amount,
+	// so the lease was ended early and is therefore reset/reclaimed.
+	StatusReasonOverForecastBudget StatusReason = "OverForecastBudget"
 	// StatusReasonDestroyed means the lease has been deleted via an API call or other user action.
 	StatusReasonDestroyed StatusReason = "Destroyed"
 	// StatusReasonActive means the lease is still active.
@@ -107,6 +128,19 @@ const (
 	// StatusReasonAccountOrphaned means that the health of the account was compromised.  The account has been orphaned
 	// which means the leases are also made Inactive
 	StatusReasonAccountOrphaned StatusReason = "LeaseAccountOrphaned"
+	// StatusReasonPendingAccount means there were no accounts available when the lease was requested.
+	// The lease is on the waitlist for the next account to finish resetting
+	StatusReasonPendingAccount StatusReason = "PendingAccount"
+	// StatusReasonScheduled means the lease is reserved for a future start time.
+	// The lease gets an account when it starts
+	StatusReasonScheduled StatusReason = "Scheduled"
+	// StatusReasonReservationFailed means there were no accounts available when a scheduled lease started
+	StatusReasonReservationFailed StatusReason = "ReservationFailed"
+	// StatusReasonPendingApproval means the lease was requested over the max lease budget or period.
+	// The lease gets an account once an approver approves it
+	StatusReasonPendingApproval StatusReason = "PendingApproval"
+	// StatusReasonRejected means an approver rejected the lease
+	StatusReasonRejected StatusReason = "Rejected"
 )
 
 // StatusReasonPtr returns a pointer to the string value of StatusReason
@@ -1,10 +1,17 @@
 package lease
 
 import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
 	"time"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
 	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/imdario/mergo"
 )
 
 // Writer put an item into the data store
@@ -12,6 +19,24 @@ type Writer interface {
 	Write(input *Lease, lastModifiedOn *int64) error
 }
 
+// AccountStatusWriter puts an item into the data store and transitions the status of its account
+// in a single transaction
+type AccountStatusWriter interface {
+	WriteWithAccountStatus(input *Lease, lastModifiedOn *int64, prevAccountStatus account.Status, nextAccountStatus account.Status) error
+}
+
+// PendingActivator moves a pending lease onto an account, making the lease Active and the account Leased
+// in a single transaction
+type PendingActivator interface {
+	ActivatePending(input *Lease, lastModifiedOn *int64) error
+}
+
+// NewPendingModifiedConflict is the conflict returned by ActivatePending when the pending
+// lease has changed since it was read, as opposed to a conflict on the account being claimed
+func NewPendingModifiedConflict(ID string) error {
+	return errors.NewConflict("lease", ID, fmt.Errorf("unable to update lease: leases has been modified since request was made"))
+}
+
 // SingleReader Reads an item information from the data store
 type SingleReader interface {
 	Get(leaseID string) (*Lease, error)
@@ -32,17 +57,31 @@ type Reader interface {
 type ReaderWriter interface {
 	Reader
 	Writer
+	AccountStatusWriter
+	PendingActivator
+}
+
+// AccountReader reads the accounts available to lease
+type AccountReader interface {
+	Get(ID string) (*account.Account, error)
+	List(query *account.Account) (*account.Accounts, error)
 }
 
 // Eventer for publishing events
 type Eventer interface {
-	Publish() error
+	LeaseCreate(i interface{}) error
+	LeaseUpdate(i interface{}) error
+	LeaseReservationFailed(i interface{}) error
 }
 
 // Service is a type corresponding to a Lease table record
 type Service struct {
-	dataSvc  ReaderWriter
-	eventSvc Eventer
+	dataSvc                     ReaderWriter
+	accountSvc                  AccountReader
+	accountSelector             account.Selector
+	eventSvc                    Eventer
+	maxActiveLeasesPerPrincipal int
+	waitlistEnabled             bool
 }
 
 // Get returns a lease from ID
@@ -80,7 +119,482 @@ func (a *Service) Save(data *Lease) error {
 	return nil
 }
 
-// Delete finds a given lease and checks if it's active and then updates it to status `Inactive`. Returns the lease.
+// Create creates a new lease for a principal and claims a Ready account for it. Returns the lease.
+func (a *Service) Create(data *Lease) (*Lease, error) {
+	new, err := a.newLease(data)
+	if err != nil {
+		return nil, err
+	}
+	now := *new.CreatedOn
+
+	// Scheduled leases are reserved now, and get an account when they start
+	if data.StartsOn != nil && *data.StartsOn > now {
+		new.Status = StatusPending.StatusPtr()
+		new.StatusReason = StatusReasonScheduled.StatusReasonPtr()
+		err = a.dataSvc.Write(new, nil)
+		if err != nil {
+			return nil, err
+		}
+		return new, nil
+	}
+
+	readyAccounts, err := a.listReadyAccounts()
+	if err != nil {
+		return nil, err
+	}
+	accounts := a.accountSelector.Select(readyAccounts, data.AccountSelector)
+
+	claimed, err := a.claimAccount(new, accounts, func(l *Lease) error {
+		return a.dataSvc.WriteWithAccountStatus(l, nil, account.StatusReady, account.StatusLeased)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		if !a.waitlistEnabled {
+			return nil, errors.NewServiceUnavailable("No Available accounts at this moment")
+		}
+		// Put the lease on the waitlist until an account finishes resetting
+		new.Status = StatusPending.StatusPtr()
+		new.StatusReason = StatusReasonPendingAccount.StatusReasonPtr()
+		err = a.dataSvc.Write(new, nil)
+		if err != nil {
+			return nil, err
+		}
+		return new, nil
+	}
+
+	err = a.publishLeaseCreate(new)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, nil
+}
+
+// RequestApproval creates a new lease for a principal which waits for an approver before it
+// claims an account. Returns the lease.
+func (a *Service) RequestApproval(data *Lease) (*Lease, error) {
+	new, err := a.newLease(data)
+	if err != nil {
+		return nil, err
+	}
+
+	new.Status = StatusPending.StatusPtr()
+	new.StatusReason = StatusReasonPendingApproval.StatusReasonPtr()
+	err = a.dataSvc.Write(new, nil)
+	if err != nil {
+		return nil, err
+	}
+	return new, nil
+}
+
+// newLease validates a requested lease and builds it as an Active lease without an account
+func (a *Service) newLease(data *Lease) (*Lease, error) {
+	// Validate the incoming record doesn't have unneeded fields
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.PrincipalID, validatePrincipalID...),
+		validation.Field(&data.ID, validation.By(isNil)),
+		validation.Field(&data.AccountID, validation.By(isNil)),
+		validation.Field(&data.Status, validation.By(isNil)),
+		validation.Field(&data.StatusReason, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.StatusModifiedOn, validation.By(isNil)),
+		validation.Field(&data.ForecastNotifiedOn, validation.By(isNil)),
+		validation.Field(&data.AccountSelector, validation.By(isValidAccountSelector)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("lease", err)
+	}
+
+	// Fail if the principal already has the max number of active leases.
+	// Leases on the waitlist count towards the max
+	activeLeases := 0
+	for _, status := range []Status{StatusActive, StatusPending} {
+		err = a.ListPages(&Lease{
+			PrincipalID: data.PrincipalID,
+			Status:      status.StatusPtr(),
+		}, func(leases *Leases) bool {
+			activeLeases = activeLeases + len(*leases)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if activeLeases >= a.maxActiveLeasesPerPrincipal {
+		return nil, errors.NewConflict("lease", *data.PrincipalID,
+			fmt.Errorf("principal already has %d active leases, which is the max of %d active leases per principal",
+				activeLeases, a.maxActiveLeasesPerPrincipal))
+	}
+
+	now := time.Now().Unix()
+	leaseID := uuid.New().String()
+	new := &Lease{
+		ID:                       &leaseID,
+		PrincipalID:              data.PrincipalID,
+		Status:                   StatusActive.StatusPtr(),
+		StatusReason:             StatusReasonActive.StatusReasonPtr(),
+		BudgetAmount:             data.BudgetAmount,
+		BudgetCurrency:           data.BudgetCurrency,
+		BudgetNotificationEmails: data.BudgetNotificationEmails,
+		ExpiresOn:                data.ExpiresOn,
+		StartsOn:                 data.StartsOn,
+		Metadata:                 data.Metadata,
+		AccountSelector:          data.AccountSelector,
+		TeamID:                   data.TeamID,
+		CreatedOn:                &now,
+		LastModifiedOn:           &now,
+		StatusModifiedOn:         &now,
+	}
+	return new, nil
+}
+
+// Approve approves a lease which is pending approval and claims a Ready account for it.
+// Approved leases which start in the future stay reserved until their start time. Returns the lease.
+func (a *Service) Approve(ID string) (*Lease, error) {
+	data, err := a.getPendingApproval(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	prevLastModifiedOn := data.LastModifiedOn
+	data.LastModifiedOn = &now
+	data.StatusModifiedOn = &now
+
+	if data.StartsOn != nil && *data.StartsOn > now {
+		data.StatusReason = StatusReasonScheduled.StatusReasonPtr()
+		err = a.dataSvc.Write(data, prevLastModifiedOn)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	readyAccounts, err := a.listReadyAccounts()
+	if err != nil {
+		return nil, err
+	}
+	accounts := a.accountSelector.Select(readyAccounts, data.AccountSelector)
+
+	data.Status = StatusActive.StatusPtr()
+	data.StatusReason = StatusReasonActive.StatusReasonPtr()
+	claimed, err := a.claimAccount(data, accounts, func(l *Lease) error {
+		return a.dataSvc.ActivatePending(l, prevLastModifiedOn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		if !a.waitlistEnabled {
+			// The lease stays pending approval, so it can be approved again later
+			return nil, errors.NewServiceUnavailable("No Available accounts at this moment")
+		}
+		data.Status = StatusPending.StatusPtr()
+		data.StatusReason = StatusReasonPendingAccount.StatusReasonPtr()
+		err = a.dataSvc.Write(data, prevLastModifiedOn)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	err = a.publishLeaseCreate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Reject rejects a lease which is pending approval, making it Inactive. Returns the lease.
+func (a *Service) Reject(ID string) (*Lease, error) {
+	data, err := a.getPendingApproval(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	prevLastModifiedOn := data.LastModifiedOn
+	data.Status = StatusInactive.StatusPtr()
+	data.StatusReason = StatusReasonRejected.StatusReasonPtr()
+	data.LastModifiedOn = &now
+	data.StatusModifiedOn = &now
+	err = a.dataSvc.Write(data, prevLastModifiedOn)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// getPendingApproval gets a lease which is waiting for an approver
+func (a *Service) getPendingApproval(ID string) (*Lease, error) {
+	data, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+	if data.Status == nil || *data.Status != StatusPending ||
+		data.StatusReason == nil || *data.StatusReason != StatusReasonPendingApproval {
+		return nil, errors.NewConflict("lease", ID, fmt.Errorf("lease is not pending approval"))
+	}
+	return data, nil
+}
+
+// ActivateScheduled claims a Ready account for a scheduled lease which has reached its start time.
+// If there are no accounts available, the lease goes on the waitlist when it's enabled, or is
+// ended otherwise, and the reservation failure is published.  Returns the lease.
+func (a *Service) ActivateScheduled(ID string) (*Lease, error) {
+	data, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if data.Status == nil || *data.Status != StatusPending ||
+		data.StatusReason == nil || *data.StatusReason != StatusReasonScheduled {
+		return nil, errors.NewConflict("lease", ID, fmt.Errorf("lease is not scheduled"))
+	}
+	if data.StartsOn != nil && *data.StartsOn > now {
+		return nil, errors.NewConflict("lease", ID, fmt.Errorf("lease does not start until %d", *data.StartsOn))
+	}
+
+	readyAccounts, err := a.listReadyAccounts()
+	if err != nil {
+		return nil, err
+	}
+	accounts := a.accountSelector.Select(readyAccounts, data.AccountSelector)
+
+	prevLastModifiedOn := data.LastModifiedOn
+	data.Status = StatusActive.StatusPtr()
+	data.StatusReason = StatusReasonActive.StatusReasonPtr()
+	data.LastModifiedOn = &now
+	data.StatusModifiedOn = &now
+
+	claimed, err := a.claimAccount(data, accounts, func(l *Lease) error {
+		return a.dataSvc.ActivatePending(l, prevLastModifiedOn)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		err = a.publishLeaseCreate(data)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+
+	// The pool is exhausted
+	if a.waitlistEnabled {
+		data.Status = StatusPending.StatusPtr()
+		data.StatusReason = StatusReasonPendingAccount.StatusReasonPtr()
+	} else {
+		data.Status = StatusInactive.StatusPtr()
+		data.StatusReason = StatusReasonReservationFailed.StatusReasonPtr()
+	}
+	err = a.dataSvc.Write(data, prevLastModifiedOn)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("No accounts available for scheduled lease %s, the lease is now %s", ID, *data.Status)
+
+	err = a.eventSvc.LeaseReservationFailed(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// claimAccount claims the first account it can for the lease.  Claiming writes the lease and moves the
+// account from Ready to Leased in one transaction, so if another request claims the account first
+// we get a conflict and move on to the next one.  If the lease itself changed since it was read,
+// the conflict is returned.  Returns whether an account was claimed.
+func (a *Service) claimAccount(data *Lease, accounts account.Accounts, claim func(*Lease) error) (bool, error) {
+	for _, acct := range accounts {
+		data.AccountID = acct.ID
+		err := claim(data)
+		if err == nil {
+			return true, nil
+		}
+		var httpErr errors.HTTPCode
+		if !errors.As(err, &httpErr) || httpErr.HTTPCode() != http.StatusConflict ||
+			errors.Is(err, NewPendingModifiedConflict(*data.ID)) {
+			data.AccountID = nil
+			return false, err
+		}
+		log.Printf("Account %s was claimed by another lease, trying the next Ready account", *acct.ID)
+	}
+	data.AccountID = nil
+	return false, nil
+}
+
+// publishLeaseCreate publishes a lease which has claimed an account
+func (a *Service) publishLeaseCreate(data *Lease) error {
+	err := a.eventSvc.LeaseCreate(data)
+	if err != nil {
+		// Release the account, so it's not stuck with a lease nobody was told about
+		prevLastModifiedOn := data.LastModifiedOn
+		data.Status = StatusInactive.StatusPtr()
+		data.StatusReason = StatusReasonRolledBack.StatusReasonPtr()
+		rollbackErr := a.dataSvc.WriteWithAccountStatus(data, prevLastModifiedOn, account.StatusLeased, account.StatusReady)
+		if rollbackErr != nil {
+			log.Printf("Failed to roll back lease %s for account %s: %s", *data.ID, *data.AccountID, rollbackErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// FulfillPending gives a Ready account to the oldest pending lease that can use it.
+// Returns the fulfilled lease, or nil if no pending lease matches the account.
+func (a *Service) FulfillPending(accountID string) (*Lease, error) {
+	acct, err := a.accountSvc.Get(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if acct.Status == nil || *acct.Status != account.StatusReady {
+		return nil, nil
+	}
+
+	pending := Leases{}
+	err = a.ListPages(&Lease{
+		Status: StatusPending.StatusPtr(),
+	}, func(leases *Leases) bool {
+		pending = append(pending, *leases...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	// First come, first served
+	sort.SliceStable(pending, func(i, j int) bool {
+		return *pending[i].CreatedOn < *pending[j].CreatedOn
+	})
+
+	now := time.Now().Unix()
+	for _, l := range pending {
+		waiting := l
+		// Scheduled leases wait for their start time, and over-limit leases wait for an approver
+		if waiting.StartsOn != nil && *waiting.StartsOn > now {
+			continue
+		}
+		if waiting.StatusReason != nil && *waiting.StatusReason == StatusReasonPendingApproval {
+			continue
+		}
+		if len(a.accountSelector.Select(account.Accounts{*acct}, waiting.AccountSelector)) == 0 {
+			continue
+		}
+
+		prevLastModifiedOn := waiting.LastModifiedOn
+		waiting.AccountID = acct.ID
+		waiting.Status = StatusActive.StatusPtr()
+		waiting.StatusReason = StatusReasonActive.StatusReasonPtr()
+		waiting.LastModifiedOn = &now
+		waiting.StatusModifiedOn = &now
+
+		err = a.dataSvc.ActivatePending(&waiting, prevLastModifiedOn)
+		if err != nil {
+			var httpErr errors.HTTPCode
+			if errors.As(err, &httpErr) && httpErr.HTTPCode() == http.StatusConflict {
+				// The lease was cancelled or the account was claimed since we looked,
+				// so try the next pending lease
+				log.Printf("Unable to give account %s to pending lease %s: %s", accountID, *waiting.ID, err)
+				continue
+			}
+			return nil, err
+		}
+
+		err = a.publishLeaseCreate(&waiting)
+		if err != nil {
+			return nil, err
+		}
+
+		return &waiting, nil
+	}
+
+	return nil, nil
+}
+
+// listReadyAccounts gets every account which can be leased
+func (a *Service) listReadyAccounts() (account.Accounts, error) {
+	query := &account.Account{
+		Status: account.StatusReady.StatusPtr(),
+	}
+	accounts := account.Accounts{}
+	for {
+		records, err := a.accountSvc.List(query)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, *records...)
+		if query.NextID == nil {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+// Update changes the budget amount and expiration of an active lease. Returns the lease.
+func (a *Service) Update(ID string, data *Lease) (*Lease, error) {
+	err := validation.ValidateStruct(data,
+		// Only the budget amount and expiration can be changed
+		validation.Field(&data.ID, validation.NilOrNotEmpty, validation.In(ID)),
+		validation.Field(&data.AccountID, validation.By(isNil)),
+		validation.Field(&data.PrincipalID, validation.By(isNil)),
+		validation.Field(&data.Status, validation.By(isNil)),
+		validation.Field(&data.StatusReason, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.StatusModifiedOn, validation.By(isNil)),
+		validation.Field(&data.BudgetCurrency, validation.By(isNil)),
+		validation.Field(&data.BudgetNotificationEmails, validation.By(isNil)),
+		validation.Field(&data.Metadata, validation.By(isNil)),
+		validation.Field(&data.TeamID, validation.By(isNil)),
+		validation.Field(&data.StartsOn, validation.By(isNil)),
+		validation.Field(&data.AccountSelector, validation.By(isNil)),
+		validation.Field(&data.ForecastNotifiedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("lease", err)
+	}
+
+	lease, err := a.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validation.ValidateStruct(lease,
+		validation.Field(&lease.Status, validation.NotNil, validation.By(isLeaseActive)),
+	)
+	if err != nil {
+		return nil, errors.NewConflict("lease", ID, err)
+	}
+
+	err = mergo.Merge(lease, *data, mergo.WithOverride)
+	if err != nil {
+		return nil, errors.NewInternalServer("unexpected error updating lease", err)
+	}
+
+	// Save uses the LastModifiedOn of the lease we just read, so
+	// a concurrent change to the lease will fail with a conflict
+	err = a.Save(lease)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lease is already saved, so a failure to publish the event
+	// doesn't fail the update
+	err = a.eventSvc.LeaseUpdate(lease)
+	if err != nil {
+		log.Printf("Failed to publish update event for lease %s: %s", ID, err)
+	}
+	return lease, nil
+}
+
+// Delete finds a given lease and checks if it's active or pending and then updates it to status `Inactive`. Returns the lease.
 func (a *Service) Delete(ID string) (*Lease, error) {
 
 	data, err := a.dataSvc.Get(ID)
@@ -89,7 +603,7 @@ func (a *Service) Delete(ID string) (*Lease, error) {
 	}
 
 	err = validation.ValidateStruct(data,
-		validation.Field(&data.Status, validation.NotNil, validation.By(isLeaseActive)),
+		validation.Field(&data.Status, validation.NotNil, validation.By(isLeaseActiveOrPending)),
 	)
 	if err != nil {
 		return nil, errors.NewConflict("lease", *data.ID, err)
@@ -133,7 +647,7 @@ func (a *Service) ListPages(query *Lease, fn func(*Leases) bool) error {
 		if !fn(records) {
 			break
 		}
-		if query.PrincipalID == nil {
+		if query.NextPrincipalID == nil {
 			break
 		}
 	}
@@ -143,14 +657,37 @@ func (a *Service) ListPages(query *Lease, fn func(*Leases) bool) error {
 
 // NewServiceInput Input for creating a new Service
 type NewServiceInput struct {
-	DataSvc  ReaderWriter
-	EventSvc Eventer
+	MaxActiveLeasesPerPrincipal int    `env:"MAX_ACTIVE_LEASES_PER_PRINCIPAL" envDefault:"1"`
+	AccountSelectorStrategy     string `env:"ACCOUNT_SELECTOR_STRATEGY" envDefault:"LeastRecentlyLeased"`
+	DataSvc                     ReaderWriter
+	AccountSvc                  AccountReader
+	AccountSelector             account.Selector
+	EventSvc                    Eventer
+	WaitlistEnabled             bool `env:"LEASE_WAITLIST_ENABLED" envDefault:"false"`
 }
 
 // NewService creates a new instance of the Service
 func NewService(input NewServiceInput) *Service {
+	// A principal can always have at least one active lease
+	maxActiveLeasesPerPrincipal := input.MaxActiveLeasesPerPrincipal
+	if maxActiveLeasesPerPrincipal < 1 {
+		maxActiveLeasesPerPrincipal = 1
+	}
+	accountSelector := input.AccountSelector
+	if accountSelector == nil {
+		var err error
+		accountSelector, err = account.NewSelector(account.SelectorStrategy(input.AccountSelectorStrategy))
+		if err != nil {
+			log.Printf("%s, selecting the least recently leased account instead", err)
+			accountSelector, _ = account.NewSelector(account.SelectorStrategyLeastRecentlyLeased)
+		}
+	}
 	return &Service{
-		dataSvc:  input.DataSvc,
-		eventSvc: input.EventSvc,
+		dataSvc:                     input.DataSvc,
+		accountSvc:                  input.AccountSvc,
+		accountSelector:             accountSelector,
+		eventSvc:                    input.EventSvc,
+		maxActiveLeasesPerPrincipal: maxActiveLeasesPerPrincipal,
+		waitlistEnabled:             input.WaitlistEnabled,
 	}
 }
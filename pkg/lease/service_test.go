@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Optum/dce/pkg/account"
 	"github.com/Optum/dce/pkg/errors"
 	"github.com/Optum/dce/pkg/lease"
 	"github.com/Optum/dce/pkg/lease/mocks"
@@ -129,6 +130,521 @@ func TestDelete(t *testing.T) {
 	}
 }
 
+func TestCreate(t *testing.T) {
+	expiresOn := time.Now().AddDate(0, 0, 7).Unix()
+	budget := 100.0
+	accountClaimedErr := errors.NewConflict("account", "123456789012", fmt.Errorf("unable to update account: account status is not \"Ready\""))
+
+	type claim struct {
+		accountID string
+		err       error
+	}
+
+	tests := []struct {
+		name          string
+		req           *lease.Lease
+		activeLeases  *lease.Leases
+		pendingLeases *lease.Leases
+		accounts      *account.Accounts
+		accountsErr   error
+		claims        []claim
+		publishErr    error
+		waitlist      bool
+		writeErr      error
+		expAccountID  *string
+		expPending    bool
+		expScheduled  bool
+		expErr        error
+	}{
+		{
+			name: "should create a lease on the first ready account",
+			req: &lease.Lease{
+				PrincipalID:  ptrString("test:arn"),
+				BudgetAmount: &budget,
+				ExpiresOn:    &expiresOn,
+				TeamID:       ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+				{ID: ptrString("210987654321")},
+			},
+			claims: []claim{
+				{accountID: "123456789012"},
+			},
+			expAccountID: ptrString("123456789012"),
+		},
+		{
+			name: "should move on to the next account when an account is claimed by another lease",
+			req: &lease.Lease{
+				PrincipalID:  ptrString("test:arn"),
+				BudgetAmount: &budget,
+				ExpiresOn:    &expiresOn,
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+				{ID: ptrString("210987654321")},
+			},
+			claims: []claim{
+				{accountID: "123456789012", err: accountClaimedErr},
+				{accountID: "210987654321"},
+			},
+			expAccountID: ptrString("210987654321"),
+		},
+		{
+			name: "should claim the least recently leased account matching the account selector",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+				AccountSelector: map[string]string{
+					"metadata.accountTier": "gpu",
+				},
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("111111111111"), LastModifiedOn: aws.Int64(100)},
+				{ID: ptrString("123456789012"), LastModifiedOn: aws.Int64(300), Metadata: map[string]interface{}{"accountTier": "gpu"}},
+				{ID: ptrString("210987654321"), LastModifiedOn: aws.Int64(200), Metadata: map[string]interface{}{"accountTier": "gpu"}},
+			},
+			claims: []claim{
+				{accountID: "210987654321"},
+			},
+			expAccountID: ptrString("210987654321"),
+		},
+		{
+			name: "should fail when no ready account matches the account selector",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+				AccountSelector: map[string]string{
+					"metadata.accountTier": "gpu",
+				},
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("111111111111")},
+			},
+			expErr: errors.NewServiceUnavailable("No Available accounts at this moment"),
+		},
+		{
+			name: "should fail validation on an unsupported account selector",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+				AccountSelector: map[string]string{
+					"accountTier": "gpu",
+				},
+			},
+			expErr: errors.NewValidation("lease", fmt.Errorf("accountSelector: unsupported account selector \"accountTier\".")),
+		},
+		{
+			name: "should fail when every ready account is claimed by another lease",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+			},
+			claims: []claim{
+				{accountID: "123456789012", err: accountClaimedErr},
+			},
+			expErr: errors.NewServiceUnavailable("No Available accounts at this moment"),
+		},
+		{
+			name: "should fail when there are no ready accounts",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts:     &account.Accounts{},
+			expErr:       errors.NewServiceUnavailable("No Available accounts at this moment"),
+		},
+		{
+			name: "should fail when the claim fails with an unexpected error",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+				{ID: ptrString("210987654321")},
+			},
+			claims: []claim{
+				{accountID: "123456789012", err: errors.NewInternalServer("failure", nil)},
+			},
+			expErr: errors.NewInternalServer("failure", nil),
+		},
+		{
+			name: "should fail when listing accounts fails",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accountsErr:  errors.NewInternalServer("failure", nil),
+			expErr:       errors.NewInternalServer("failure", nil),
+		},
+		{
+			name: "should fail when the principal has the max active leases",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{
+				{
+					AccountID: ptrString("123456789012"),
+					Status:    lease.StatusActive.StatusPtr(),
+				},
+			},
+			expErr: errors.NewConflict("lease", "test:arn", fmt.Errorf("principal already has 1 active leases, which is the max of 1 active leases per principal")),
+		},
+		{
+			name: "should fail when the principal has the max leases including pending leases",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			pendingLeases: &lease.Leases{
+				{
+					ID:     ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					Status: lease.StatusPending.StatusPtr(),
+				},
+			},
+			expErr: errors.NewConflict("lease", "test:arn", fmt.Errorf("principal already has 1 active leases, which is the max of 1 active leases per principal")),
+		},
+		{
+			name: "should put the lease on the waitlist when there are no ready accounts",
+			req: &lease.Lease{
+				PrincipalID:  ptrString("test:arn"),
+				BudgetAmount: &budget,
+				ExpiresOn:    &expiresOn,
+				AccountSelector: map[string]string{
+					"metadata.accountTier": "gpu",
+				},
+			},
+			activeLeases: &lease.Leases{},
+			accounts:     &account.Accounts{},
+			waitlist:     true,
+			expPending:   true,
+		},
+		{
+			name: "should put the lease on the waitlist when every ready account is claimed by another lease",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+			},
+			claims: []claim{
+				{accountID: "123456789012", err: accountClaimedErr},
+			},
+			waitlist:   true,
+			expPending: true,
+		},
+		{
+			name: "should reserve an account for a lease starting in the future",
+			req: &lease.Lease{
+				PrincipalID:  ptrString("test:arn"),
+				BudgetAmount: &budget,
+				ExpiresOn:    &expiresOn,
+				StartsOn:     aws.Int64(time.Now().AddDate(0, 0, 2).Unix()),
+			},
+			activeLeases: &lease.Leases{},
+			expPending:   true,
+			expScheduled: true,
+		},
+		{
+			name: "should fail when writing the pending lease fails",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts:     &account.Accounts{},
+			waitlist:     true,
+			writeErr:     errors.NewInternalServer("failure", nil),
+			expErr:       errors.NewInternalServer("failure", nil),
+		},
+		{
+			name: "should fail validation when an account is requested",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+				AccountID:   ptrString("123456789012"),
+			},
+			expErr: errors.NewValidation("lease", fmt.Errorf("accountId: must be empty.")),
+		},
+		{
+			name: "should release the account when publishing the lease fails",
+			req: &lease.Lease{
+				PrincipalID: ptrString("test:arn"),
+			},
+			activeLeases: &lease.Leases{},
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+			},
+			claims: []claim{
+				{accountID: "123456789012"},
+			},
+			publishErr: errors.NewInternalServer("failure", nil),
+			expErr:     errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountReader{}
+			mocksEvents := &mocks.Eventer{}
+
+			mocksRwd.On("List", mock.MatchedBy(func(q *lease.Lease) bool {
+				return *q.PrincipalID == *tt.req.PrincipalID && *q.Status == lease.StatusActive
+			})).Return(tt.activeLeases, nil)
+			pendingLeases := tt.pendingLeases
+			if pendingLeases == nil {
+				pendingLeases = &lease.Leases{}
+			}
+			mocksRwd.On("List", mock.MatchedBy(func(q *lease.Lease) bool {
+				return *q.PrincipalID == *tt.req.PrincipalID && *q.Status == lease.StatusPending
+			})).Return(pendingLeases, nil)
+			mocksRwd.On("Write", mock.MatchedBy(func(l *lease.Lease) bool {
+				return l.AccountID == nil && *l.Status == lease.StatusPending
+			}), (*int64)(nil)).Return(tt.writeErr)
+			mocksAccounts.On("List", mock.MatchedBy(func(q *account.Account) bool {
+				return *q.Status == account.StatusReady
+			})).Return(tt.accounts, tt.accountsErr)
+			for _, c := range tt.claims {
+				accountID := c.accountID
+				mocksRwd.On("WriteWithAccountStatus", mock.MatchedBy(func(l *lease.Lease) bool {
+					return *l.AccountID == accountID
+				}), (*int64)(nil), account.StatusReady, account.StatusLeased).Return(c.err).Once()
+			}
+			mocksRwd.On("WriteWithAccountStatus", mock.Anything, mock.AnythingOfType("*int64"), account.StatusLeased, account.StatusReady).Return(nil)
+			mocksEvents.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(tt.publishErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:         mocksRwd,
+					AccountSvc:      mocksAccounts,
+					EventSvc:        mocksEvents,
+					WaitlistEnabled: tt.waitlist,
+				},
+			)
+
+			result, err := leaseSvc.Create(tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expPending {
+				assert.Nil(t, result.AccountID)
+				assert.NotNil(t, result.ID)
+				assert.Equal(t, lease.StatusPending.StatusPtr(), result.Status)
+				if tt.expScheduled {
+					assert.Equal(t, lease.StatusReasonScheduled.StatusReasonPtr(), result.StatusReason)
+					assert.Equal(t, tt.req.StartsOn, result.StartsOn)
+					mocksAccounts.AssertNotCalled(t, "List", mock.Anything)
+				} else {
+					assert.Equal(t, lease.StatusReasonPendingAccount.StatusReasonPtr(), result.StatusReason)
+				}
+				assert.Equal(t, tt.req.AccountSelector, result.AccountSelector)
+				mocksEvents.AssertNotCalled(t, "LeaseCreate", mock.Anything)
+			} else if tt.expAccountID == nil {
+				assert.Nil(t, result)
+			} else {
+				assert.Equal(t, tt.expAccountID, result.AccountID)
+				assert.Equal(t, tt.req.PrincipalID, result.PrincipalID)
+				assert.NotNil(t, result.ID)
+				assert.Equal(t, lease.StatusActive.StatusPtr(), result.Status)
+				assert.Equal(t, lease.StatusReasonActive.StatusReasonPtr(), result.StatusReason)
+				assert.Equal(t, tt.req.BudgetAmount, result.BudgetAmount)
+				assert.Equal(t, tt.req.ExpiresOn, result.ExpiresOn)
+				assert.Equal(t, tt.req.TeamID, result.TeamID)
+				mocksEvents.AssertCalled(t, "LeaseCreate", result)
+			}
+			if tt.publishErr != nil {
+				mocksRwd.AssertCalled(t, "WriteWithAccountStatus", mock.MatchedBy(func(l *lease.Lease) bool {
+					return *l.Status == lease.StatusInactive && *l.StatusReason == lease.StatusReasonRolledBack
+				}), mock.AnythingOfType("*int64"), account.StatusLeased, account.StatusReady)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	now := time.Now().Unix()
+	expiresOn := now + 1000
+	budget := 200.0
+
+	type response struct {
+		data *lease.Lease
+		err  error
+	}
+
+	tests := []struct {
+		name      string
+		ID        string
+		updLease  *lease.Lease
+		getLease  *lease.Lease
+		getErr    error
+		returnErr error
+		eventErr  error
+		exp       response
+	}{
+		{
+			name: "should extend a lease",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				ExpiresOn:    &expiresOn,
+				BudgetAmount: &budget,
+			},
+			getLease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:         lease.StatusActive.StatusPtr(),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("test:arn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+			},
+			exp: response{
+				data: &lease.Lease{
+					ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					Status:         lease.StatusActive.StatusPtr(),
+					AccountID:      ptrString("123456789012"),
+					PrincipalID:    ptrString("test:arn"),
+					CreatedOn:      &now,
+					LastModifiedOn: &now,
+					ExpiresOn:      &expiresOn,
+					BudgetAmount:   &budget,
+				},
+			},
+		},
+		{
+			name: "should fail validation on principal change",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				PrincipalID: ptrString("other:arn"),
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("principalId: must be empty.")),
+			},
+		},
+		{
+			name: "should fail validation on team change",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				TeamID: ptrString("team-1"),
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("teamId: must be empty.")),
+			},
+		},
+		{
+			name: "should fail validation on start date change",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				StartsOn: &now,
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("startsOn: must be empty.")),
+			},
+		},
+		{
+			name: "should fail validation on account selector change",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				AccountSelector: map[string]string{"metadata.accountTier": "gpu"},
+			},
+			exp: response{
+				err: errors.NewValidation("lease", fmt.Errorf("accountSelector: must be empty.")),
+			},
+		},
+		{
+			name: "should conflict on inactive lease",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				ExpiresOn: &expiresOn,
+			},
+			getLease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:         lease.StatusInactive.StatusPtr(),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("test:arn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+			},
+			exp: response{
+				err: errors.NewConflict("lease", "70c2d96d-7938-4ec9-917d-476f2b09cc04", fmt.Errorf("leaseStatus: must be active lease.")),
+			},
+		},
+		{
+			name: "should fail on concurrent write",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				ExpiresOn: &expiresOn,
+			},
+			getLease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:         lease.StatusActive.StatusPtr(),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("test:arn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+			},
+			returnErr: errors.NewConflict("lease", "70c2d96d-7938-4ec9-917d-476f2b09cc04", fmt.Errorf("conditional check failed")),
+			exp: response{
+				err: errors.NewConflict("lease", "70c2d96d-7938-4ec9-917d-476f2b09cc04", fmt.Errorf("conditional check failed")),
+			},
+		},
+		{
+			name: "should return the saved lease when the update event fails",
+			ID:   "70c2d96d-7938-4ec9-917d-476f2b09cc04",
+			updLease: &lease.Lease{
+				ExpiresOn: &expiresOn,
+			},
+			getLease: &lease.Lease{
+				ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				Status:         lease.StatusActive.StatusPtr(),
+				AccountID:      ptrString("123456789012"),
+				PrincipalID:    ptrString("test:arn"),
+				CreatedOn:      &now,
+				LastModifiedOn: &now,
+			},
+			eventErr: fmt.Errorf("failure"),
+			exp: response{
+				data: &lease.Lease{
+					ID:             ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+					Status:         lease.StatusActive.StatusPtr(),
+					AccountID:      ptrString("123456789012"),
+					PrincipalID:    ptrString("test:arn"),
+					CreatedOn:      &now,
+					LastModifiedOn: &now,
+					ExpiresOn:      &expiresOn,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Get", tt.ID).Return(tt.getLease, tt.getErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), &now).Return(tt.returnErr)
+			mocksEvents := &mocks.Eventer{}
+			mocksEvents.On("LeaseUpdate", mock.AnythingOfType("*lease.Lease")).Return(tt.eventErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:  mocksRwd,
+					EventSvc: mocksEvents,
+				},
+			)
+
+			updLease, err := leaseSvc.Update(tt.ID, tt.updLease)
+			assert.True(t, errors.Is(err, tt.exp.err), "actual error %q doesn't match expected error %q", err, tt.exp.err)
+			assert.Equal(t, tt.exp.data, updLease)
+			if tt.exp.err == nil {
+				mocksEvents.AssertCalled(t, "LeaseUpdate", updLease)
+			} else {
+				mocksEvents.AssertNotCalled(t, "LeaseUpdate", mock.Anything)
+			}
+		})
+	}
+}
+
 func TestSave(t *testing.T) {
 	now := time.Now().Unix()
 
@@ -324,3 +840,650 @@ func TestGetLeases(t *testing.T) {
 	}
 
 }
+
+func TestFulfillPending(t *testing.T) {
+	accountConflictErr := errors.NewConflict("account", "123456789012", fmt.Errorf("unable to update account: account status is not \"Ready\""))
+	olderLeaseID := "22222222-7938-4ec9-917d-476f2b09cc04"
+
+	tests := []struct {
+		name          string
+		account       *account.Account
+		pendingLeases *lease.Leases
+		activateErrs  map[string]error
+		publishErr    error
+		expLeaseID    *string
+		expErr        error
+	}{
+		{
+			name: "should give the account to the oldest pending lease",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:             ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("newer"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(200),
+					LastModifiedOn: aws.Int64(200),
+				},
+				{
+					ID:             ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("older"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(100),
+					LastModifiedOn: aws.Int64(100),
+				},
+			},
+			expLeaseID: ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+		},
+		{
+			name: "should skip pending leases whose account selector doesn't match",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:              ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:     ptrString("older"),
+					Status:          lease.StatusPending.StatusPtr(),
+					CreatedOn:       aws.Int64(100),
+					LastModifiedOn:  aws.Int64(100),
+					AccountSelector: map[string]string{"metadata.accountTier": "gpu"},
+				},
+				{
+					ID:             ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("newer"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(200),
+					LastModifiedOn: aws.Int64(200),
+				},
+			},
+			expLeaseID: ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+		},
+		{
+			name: "should skip scheduled leases which haven't started",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:             ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("older"),
+					Status:         lease.StatusPending.StatusPtr(),
+					StatusReason:   lease.StatusReasonScheduled.StatusReasonPtr(),
+					CreatedOn:      aws.Int64(100),
+					LastModifiedOn: aws.Int64(100),
+					StartsOn:       aws.Int64(time.Now().AddDate(0, 0, 2).Unix()),
+				},
+			},
+		},
+		{
+			name: "should skip leases pending approval",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:             ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("older"),
+					Status:         lease.StatusPending.StatusPtr(),
+					StatusReason:   lease.StatusReasonPendingApproval.StatusReasonPtr(),
+					CreatedOn:      aws.Int64(100),
+					LastModifiedOn: aws.Int64(100),
+				},
+				{
+					ID:             ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("newer"),
+					Status:         lease.StatusPending.StatusPtr(),
+					StatusReason:   lease.StatusReasonPendingAccount.StatusReasonPtr(),
+					CreatedOn:      aws.Int64(200),
+					LastModifiedOn: aws.Int64(200),
+				},
+			},
+			expLeaseID: ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+		},
+		{
+			name: "should do nothing when there are no pending leases",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{},
+		},
+		{
+			name: "should do nothing when the account isn't ready",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusNotReady.StatusPtr(),
+			},
+		},
+		{
+			name: "should do nothing when the account was claimed first",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:             ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("older"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(100),
+					LastModifiedOn: aws.Int64(100),
+				},
+			},
+			activateErrs: map[string]error{olderLeaseID: accountConflictErr},
+		},
+		{
+			name: "should try the next pending lease when a lease changed since it was listed",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:             ptrString(olderLeaseID),
+					PrincipalID:    ptrString("older"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(100),
+					LastModifiedOn: aws.Int64(100),
+				},
+				{
+					ID:             ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("newer"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(200),
+					LastModifiedOn: aws.Int64(200),
+				},
+			},
+			activateErrs: map[string]error{olderLeaseID: lease.NewPendingModifiedConflict(olderLeaseID)},
+			expLeaseID:   ptrString("11111111-7938-4ec9-917d-476f2b09cc04"),
+		},
+		{
+			name: "should release the account when publishing the lease fails",
+			account: &account.Account{
+				ID:     ptrString("123456789012"),
+				Status: account.StatusReady.StatusPtr(),
+			},
+			pendingLeases: &lease.Leases{
+				{
+					ID:             ptrString("22222222-7938-4ec9-917d-476f2b09cc04"),
+					PrincipalID:    ptrString("older"),
+					Status:         lease.StatusPending.StatusPtr(),
+					CreatedOn:      aws.Int64(100),
+					LastModifiedOn: aws.Int64(100),
+				},
+			},
+			publishErr: errors.NewInternalServer("failure", nil),
+			expErr:     errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountReader{}
+			mocksEvents := &mocks.Eventer{}
+
+			mocksAccounts.On("Get", *tt.account.ID).Return(tt.account, nil)
+			mocksRwd.On("List", mock.MatchedBy(func(q *lease.Lease) bool {
+				return *q.Status == lease.StatusPending
+			})).Return(tt.pendingLeases, nil)
+			mocksRwd.On("ActivatePending", mock.AnythingOfType("*lease.Lease"), mock.AnythingOfType("*int64")).Return(func(l *lease.Lease, _ *int64) error {
+				return tt.activateErrs[*l.ID]
+			})
+			mocksRwd.On("WriteWithAccountStatus", mock.Anything, mock.AnythingOfType("*int64"), account.StatusLeased, account.StatusReady).Return(nil)
+			mocksEvents.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(tt.publishErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:    mocksRwd,
+					AccountSvc: mocksAccounts,
+					EventSvc:   mocksEvents,
+				},
+			)
+
+			result, err := leaseSvc.FulfillPending(*tt.account.ID)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expLeaseID == nil {
+				assert.Nil(t, result)
+			} else {
+				assert.Equal(t, tt.expLeaseID, result.ID)
+				assert.Equal(t, tt.account.ID, result.AccountID)
+				assert.Equal(t, lease.StatusActive.StatusPtr(), result.Status)
+				assert.Equal(t, lease.StatusReasonActive.StatusReasonPtr(), result.StatusReason)
+				mocksRwd.AssertCalled(t, "ActivatePending", result, mock.AnythingOfType("*int64"))
+				mocksEvents.AssertCalled(t, "LeaseCreate", result)
+			}
+			if tt.publishErr != nil {
+				mocksRwd.AssertCalled(t, "WriteWithAccountStatus", mock.MatchedBy(func(l *lease.Lease) bool {
+					return *l.Status == lease.StatusInactive && *l.StatusReason == lease.StatusReasonRolledBack
+				}), mock.AnythingOfType("*int64"), account.StatusLeased, account.StatusReady)
+			}
+		})
+	}
+}
+
+func TestActivateScheduled(t *testing.T) {
+	leaseID := "70c2d96d-7938-4ec9-917d-476f2b09cc04"
+	startsOn := time.Now().Add(-time.Minute).Unix()
+	accountClaimedErr := errors.NewConflict("account", "123456789012", fmt.Errorf("unable to update account: account status is not \"Ready\""))
+
+	scheduledLease := func() *lease.Lease {
+		return &lease.Lease{
+			ID:             ptrString(leaseID),
+			PrincipalID:    ptrString("test:arn"),
+			Status:         lease.StatusPending.StatusPtr(),
+			StatusReason:   lease.StatusReasonScheduled.StatusReasonPtr(),
+			CreatedOn:      aws.Int64(100),
+			LastModifiedOn: aws.Int64(100),
+			StartsOn:       &startsOn,
+		}
+	}
+
+	tests := []struct {
+		name            string
+		getLease        *lease.Lease
+		accounts        *account.Accounts
+		claimErrs       []error
+		waitlist        bool
+		publishErr      error
+		expAccountID    *string
+		expStatus       *lease.Status
+		expStatusReason *lease.StatusReason
+		expNotify       bool
+		expErr          error
+	}{
+		{
+			name:     "should claim an account when the lease starts",
+			getLease: scheduledLease(),
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+			},
+			claimErrs:       []error{nil},
+			expAccountID:    ptrString("123456789012"),
+			expStatus:       lease.StatusActive.StatusPtr(),
+			expStatusReason: lease.StatusReasonActive.StatusReasonPtr(),
+		},
+		{
+			name:     "should move on to the next account when an account is claimed by another lease",
+			getLease: scheduledLease(),
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+				{ID: ptrString("210987654321")},
+			},
+			claimErrs:       []error{accountClaimedErr, nil},
+			expAccountID:    ptrString("210987654321"),
+			expStatus:       lease.StatusActive.StatusPtr(),
+			expStatusReason: lease.StatusReasonActive.StatusReasonPtr(),
+		},
+		{
+			name:            "should end the lease and notify when the pool is exhausted",
+			getLease:        scheduledLease(),
+			accounts:        &account.Accounts{},
+			expStatus:       lease.StatusInactive.StatusPtr(),
+			expStatusReason: lease.StatusReasonReservationFailed.StatusReasonPtr(),
+			expNotify:       true,
+		},
+		{
+			name:            "should put the lease on the waitlist and notify when the pool is exhausted",
+			getLease:        scheduledLease(),
+			accounts:        &account.Accounts{},
+			waitlist:        true,
+			expStatus:       lease.StatusPending.StatusPtr(),
+			expStatusReason: lease.StatusReasonPendingAccount.StatusReasonPtr(),
+			expNotify:       true,
+		},
+		{
+			name: "should fail when the lease hasn't started",
+			getLease: func() *lease.Lease {
+				l := scheduledLease()
+				l.StartsOn = aws.Int64(4102444800)
+				return l
+			}(),
+			expErr: errors.NewConflict("lease", leaseID, fmt.Errorf("lease does not start until 4102444800")),
+		},
+		{
+			name: "should fail when the lease isn't scheduled",
+			getLease: func() *lease.Lease {
+				l := scheduledLease()
+				l.Status = lease.StatusActive.StatusPtr()
+				return l
+			}(),
+			expErr: errors.NewConflict("lease", leaseID, fmt.Errorf("lease is not scheduled")),
+		},
+		{
+			name:     "should release the account when publishing the lease fails",
+			getLease: scheduledLease(),
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+			},
+			claimErrs:  []error{nil},
+			publishErr: errors.NewInternalServer("failure", nil),
+			expErr:     errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountReader{}
+			mocksEvents := &mocks.Eventer{}
+
+			mocksRwd.On("Get", leaseID).Return(tt.getLease, nil)
+			mocksAccounts.On("List", mock.MatchedBy(func(q *account.Account) bool {
+				return *q.Status == account.StatusReady
+			})).Return(tt.accounts, nil)
+			for i, claimErr := range tt.claimErrs {
+				accountID := *(*tt.accounts)[i].ID
+				mocksRwd.On("ActivatePending", mock.MatchedBy(func(l *lease.Lease) bool {
+					return *l.AccountID == accountID
+				}), aws.Int64(100)).Return(claimErr).Once()
+			}
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), aws.Int64(100)).Return(nil)
+			mocksRwd.On("WriteWithAccountStatus", mock.Anything, mock.AnythingOfType("*int64"), account.StatusLeased, account.StatusReady).Return(nil)
+			mocksEvents.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(tt.publishErr)
+			mocksEvents.On("LeaseReservationFailed", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:         mocksRwd,
+					AccountSvc:      mocksAccounts,
+					EventSvc:        mocksEvents,
+					WaitlistEnabled: tt.waitlist,
+				},
+			)
+
+			result, err := leaseSvc.ActivateScheduled(leaseID)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr != nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tt.expAccountID, result.AccountID)
+			assert.Equal(t, tt.expStatus, result.Status)
+			assert.Equal(t, tt.expStatusReason, result.StatusReason)
+			if tt.expAccountID != nil {
+				mocksEvents.AssertCalled(t, "LeaseCreate", result)
+			} else {
+				mocksEvents.AssertNotCalled(t, "LeaseCreate", mock.Anything)
+				mocksRwd.AssertCalled(t, "Write", result, aws.Int64(100))
+			}
+			if tt.expNotify {
+				mocksEvents.AssertCalled(t, "LeaseReservationFailed", result)
+			} else {
+				mocksEvents.AssertNotCalled(t, "LeaseReservationFailed", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestRequestApproval(t *testing.T) {
+	tests := []struct {
+		name         string
+		req          *lease.Lease
+		activeLeases *lease.Leases
+		writeErr     error
+		expErr       error
+	}{
+		{
+			name: "should create a lease pending approval",
+			req: &lease.Lease{
+				PrincipalID:  ptrString("User1"),
+				BudgetAmount: aws.Float64(5000),
+				ExpiresOn:    aws.Int64(4102444800),
+			},
+			activeLeases: &lease.Leases{},
+		},
+		{
+			name: "should fail when the principal has the max active leases",
+			req: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			activeLeases: &lease.Leases{
+				{ID: ptrString("70c2d96d-7938-4ec9-917d-476f2b09cc04")},
+			},
+			expErr: errors.NewConflict("lease", "User1", fmt.Errorf("principal already has 1 active leases, which is the max of 1 active leases per principal")),
+		},
+		{
+			name: "should fail on a write error",
+			req: &lease.Lease{
+				PrincipalID: ptrString("User1"),
+			},
+			activeLeases: &lease.Leases{},
+			writeErr:     errors.NewInternalServer("failure", nil),
+			expErr:       errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountReader{}
+			mocksEvents := &mocks.Eventer{}
+
+			mocksRwd.On("List", mock.MatchedBy(func(q *lease.Lease) bool {
+				return *q.Status == lease.StatusActive
+			})).Return(tt.activeLeases, nil)
+			mocksRwd.On("List", mock.MatchedBy(func(q *lease.Lease) bool {
+				return *q.Status == lease.StatusPending
+			})).Return(&lease.Leases{}, nil)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), (*int64)(nil)).Return(tt.writeErr)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:                     mocksRwd,
+					AccountSvc:                  mocksAccounts,
+					EventSvc:                    mocksEvents,
+					MaxActiveLeasesPerPrincipal: 1,
+				},
+			)
+
+			result, err := leaseSvc.RequestApproval(tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr != nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.NotNil(t, result.ID)
+			assert.Nil(t, result.AccountID)
+			assert.Equal(t, lease.StatusPending.StatusPtr(), result.Status)
+			assert.Equal(t, lease.StatusReasonPendingApproval.StatusReasonPtr(), result.StatusReason)
+			assert.Equal(t, tt.req.BudgetAmount, result.BudgetAmount)
+			mocksAccounts.AssertNotCalled(t, "List", mock.Anything)
+			mocksEvents.AssertNotCalled(t, "LeaseCreate", mock.Anything)
+		})
+	}
+}
+
+func TestApprove(t *testing.T) {
+	leaseID := "70c2d96d-7938-4ec9-917d-476f2b09cc04"
+
+	pendingApprovalLease := func() *lease.Lease {
+		return &lease.Lease{
+			ID:             ptrString(leaseID),
+			PrincipalID:    ptrString("test:arn"),
+			Status:         lease.StatusPending.StatusPtr(),
+			StatusReason:   lease.StatusReasonPendingApproval.StatusReasonPtr(),
+			CreatedOn:      aws.Int64(100),
+			LastModifiedOn: aws.Int64(100),
+		}
+	}
+
+	tests := []struct {
+		name            string
+		getLease        *lease.Lease
+		accounts        *account.Accounts
+		waitlist        bool
+		activateErr     error
+		expAccountID    *string
+		expStatus       *lease.Status
+		expStatusReason *lease.StatusReason
+		expErr          error
+	}{
+		{
+			name:     "should claim an account for the approved lease",
+			getLease: pendingApprovalLease(),
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+			},
+			expAccountID:    ptrString("123456789012"),
+			expStatus:       lease.StatusActive.StatusPtr(),
+			expStatusReason: lease.StatusReasonActive.StatusReasonPtr(),
+		},
+		{
+			name: "should schedule an approved lease which starts in the future",
+			getLease: func() *lease.Lease {
+				l := pendingApprovalLease()
+				l.StartsOn = aws.Int64(4102444800)
+				return l
+			}(),
+			expStatus:       lease.StatusPending.StatusPtr(),
+			expStatusReason: lease.StatusReasonScheduled.StatusReasonPtr(),
+		},
+		{
+			name:            "should put the approved lease on the waitlist when the pool is exhausted",
+			getLease:        pendingApprovalLease(),
+			accounts:        &account.Accounts{},
+			waitlist:        true,
+			expStatus:       lease.StatusPending.StatusPtr(),
+			expStatusReason: lease.StatusReasonPendingAccount.StatusReasonPtr(),
+		},
+		{
+			name:     "should fail when the pool is exhausted",
+			getLease: pendingApprovalLease(),
+			accounts: &account.Accounts{},
+			expErr:   errors.NewServiceUnavailable("No Available accounts at this moment"),
+		},
+		{
+			name: "should fail when the lease isn't pending approval",
+			getLease: func() *lease.Lease {
+				l := pendingApprovalLease()
+				l.StatusReason = lease.StatusReasonPendingAccount.StatusReasonPtr()
+				return l
+			}(),
+			expErr: errors.NewConflict("lease", leaseID, fmt.Errorf("lease is not pending approval")),
+		},
+		{
+			name:     "should conflict when the lease changes while claiming an account",
+			getLease: pendingApprovalLease(),
+			accounts: &account.Accounts{
+				{ID: ptrString("123456789012")},
+				{ID: ptrString("234567890123")},
+			},
+			activateErr: lease.NewPendingModifiedConflict(leaseID),
+			expErr:      lease.NewPendingModifiedConflict(leaseID),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksAccounts := &mocks.AccountReader{}
+			mocksEvents := &mocks.Eventer{}
+
+			mocksRwd.On("Get", leaseID).Return(tt.getLease, nil)
+			mocksAccounts.On("List", mock.MatchedBy(func(q *account.Account) bool {
+				return *q.Status == account.StatusReady
+			})).Return(tt.accounts, nil)
+			mocksRwd.On("ActivatePending", mock.AnythingOfType("*lease.Lease"), aws.Int64(100)).Return(tt.activateErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), aws.Int64(100)).Return(nil)
+			mocksEvents.On("LeaseCreate", mock.AnythingOfType("*lease.Lease")).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc:         mocksRwd,
+					AccountSvc:      mocksAccounts,
+					EventSvc:        mocksEvents,
+					WaitlistEnabled: tt.waitlist,
+				},
+			)
+
+			result, err := leaseSvc.Approve(leaseID)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr != nil {
+				assert.Nil(t, result)
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+				if tt.activateErr != nil {
+					// The other accounts aren't tried for a lease that has changed
+					mocksRwd.AssertNumberOfCalls(t, "ActivatePending", 1)
+				}
+				return
+			}
+			assert.Equal(t, tt.expAccountID, result.AccountID)
+			assert.Equal(t, tt.expStatus, result.Status)
+			assert.Equal(t, tt.expStatusReason, result.StatusReason)
+			if tt.expAccountID != nil {
+				mocksRwd.AssertCalled(t, "ActivatePending", result, aws.Int64(100))
+				mocksEvents.AssertCalled(t, "LeaseCreate", result)
+			} else {
+				mocksRwd.AssertCalled(t, "Write", result, aws.Int64(100))
+				mocksEvents.AssertNotCalled(t, "LeaseCreate", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	leaseID := "70c2d96d-7938-4ec9-917d-476f2b09cc04"
+
+	tests := []struct {
+		name     string
+		getLease *lease.Lease
+		expErr   error
+	}{
+		{
+			name: "should end a lease pending approval",
+			getLease: &lease.Lease{
+				ID:             ptrString(leaseID),
+				PrincipalID:    ptrString("test:arn"),
+				Status:         lease.StatusPending.StatusPtr(),
+				StatusReason:   lease.StatusReasonPendingApproval.StatusReasonPtr(),
+				LastModifiedOn: aws.Int64(100),
+			},
+		},
+		{
+			name: "should fail when the lease isn't pending approval",
+			getLease: &lease.Lease{
+				ID:             ptrString(leaseID),
+				PrincipalID:    ptrString("test:arn"),
+				AccountID:      ptrString("123456789012"),
+				Status:         lease.StatusActive.StatusPtr(),
+				StatusReason:   lease.StatusReasonActive.StatusReasonPtr(),
+				LastModifiedOn: aws.Int64(100),
+			},
+			expErr: errors.NewConflict("lease", leaseID, fmt.Errorf("lease is not pending approval")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+
+			mocksRwd.On("Get", leaseID).Return(tt.getLease, nil)
+			mocksRwd.On("Write", mock.AnythingOfType("*lease.Lease"), aws.Int64(100)).Return(nil)
+
+			leaseSvc := lease.NewService(
+				lease.NewServiceInput{
+					DataSvc: mocksRwd,
+				},
+			)
+
+			result, err := leaseSvc.Reject(leaseID)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expErr != nil {
+				assert.Nil(t, result)
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+				return
+			}
+			assert.Equal(t, lease.StatusInactive.StatusPtr(), result.Status)
+			assert.Equal(t, lease.StatusReasonRejected.StatusReasonPtr(), result.StatusReason)
+			mocksRwd.AssertCalled(t, "Write", result, aws.Int64(100))
+		})
+	}
+}
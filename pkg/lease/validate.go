@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"regexp"
 
+	"github.com/Optum/dce/pkg/account"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
 )
@@ -48,3 +49,16 @@ func isLeaseActive(value interface{}) error {
 	}
 	return nil
 }
+
+func isLeaseActiveOrPending(value interface{}) error {
+	s, _ := value.(*Status)
+	if s.String() != StatusActive.String() && s.String() != StatusPending.String() {
+		return errors.New("must be active lease or pending lease")
+	}
+	return nil
+}
+
+func isValidAccountSelector(value interface{}) error {
+	hints, _ := value.(map[string]string)
+	return account.ValidateSelectorHints(hints)
+}
@@ -0,0 +1,93 @@
+// Package outbox holds events which are written to the data store in the
+// same transaction as the change they describe.  A relay publishes them
+// after the transaction is committed, so a change is never saved without
+// its events, or its events published without the change.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Types of events written to the outbox.
+// These are the same as the types of the events the relay publishes
+const (
+	TypeAccountCreated = "dce.account.created"
+	TypeAccountDeleted = "dce.account.deleted"
+	TypeAccountUpdated = "dce.account.updated"
+	TypeAccountReset   = "dce.account.reset"
+)
+
+// Status is the status of an outbox entry
+type Status string
+
+const (
+	// StatusPending is waiting to be published
+	StatusPending Status = "Pending"
+	// StatusPublished has been published
+	StatusPublished Status = "Published"
+	// StatusFailed failed to be published after all attempts
+	StatusFailed Status = "Failed"
+)
+
+// String returns the string value of Status
+func (c Status) String() string {
+	return string(c)
+}
+
+// StatusPtr returns a pointer to the string value of Status
+func (c Status) StatusPtr() *Status {
+	return &c
+}
+
+// Entry is an event in the outbox.  The ID of the entry is used as the ID of
+// the published event, so subscribers can discard duplicates
+type Entry struct {
+	ID             *string `json:"id,omitempty" dynamodbav:"Id"`                         // Entry ID, and the ID of the published event
+	Type           *string `json:"type,omitempty" dynamodbav:"EventType"`                // Type of the event
+	Data           *string `json:"data,omitempty" dynamodbav:"Data"`                     // JSON of the account or lease the event is about
+	Status         *Status `json:"status,omitempty" dynamodbav:"EntryStatus"`            // Status of the entry
+	Attempts       *int64  `json:"attempts,omitempty" dynamodbav:"Attempts"`             // Number of times publishing has been attempted
+	LastError      *string `json:"lastError,omitempty" dynamodbav:"LastError,omitempty"` // Error from the last failed attempt
+	CreatedOn      *int64  `json:"createdOn,omitempty" dynamodbav:"CreatedOn"`           // Entry CreatedOn
+	LastModifiedOn *int64  `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn"` // Last Modified Epoch Timestamp
+	ExpiresOn      *int64  `json:"expiresOn,omitempty" dynamodbav:"ExpiresOn,omitempty"` // Epoch Timestamp after which a published entry is removed
+}
+
+// NewEntry creates a pending entry for an event about the data
+func NewEntry(eventType string, data interface{}) (*Entry, error) {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.NewInternalServer(fmt.Sprintf("unable to marshal %s event data", eventType), err)
+	}
+	id := uuid.New().String()
+	d := string(dataJSON)
+	now := time.Now().Unix()
+	attempts := int64(0)
+	return &Entry{
+		ID:             &id,
+		Type:           &eventType,
+		Data:           &d,
+		Status:         StatusPending.StatusPtr(),
+		Attempts:       &attempts,
+		CreatedOn:      &now,
+		LastModifiedOn: &now,
+	}, nil
+}
+
+// NewEntries creates a pending entry for each type of event about the data
+func NewEntries(data interface{}, eventTypes ...string) ([]*Entry, error) {
+	entries := []*Entry{}
+	for _, eventType := range eventTypes {
+		entry, err := NewEntry(eventType, data)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
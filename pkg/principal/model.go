@@ -0,0 +1,73 @@
+// Package principal manages principal profiles, which override the default
+// lease limits for individual principals
+package principal
+
+import (
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Budget periods a principal's spend is measured over
+const (
+	// BudgetPeriodWeekly measures spend from the beginning of the week
+	BudgetPeriodWeekly = "WEEKLY"
+	// BudgetPeriodMonthly measures spend from the beginning of the month
+	BudgetPeriodMonthly = "MONTHLY"
+)
+
+// Principal - Handles importing and exporting principal profiles.  Limits which
+// are empty fall back to the defaults
+type Principal struct {
+	ID                    *string  `json:"id,omitempty" dynamodbav:"Id"`                                                 // Principal ID
+	MaxLeaseBudgetAmount  *float64 `json:"maxLeaseBudgetAmount,omitempty" dynamodbav:"MaxLeaseBudgetAmount,omitempty"`   // Max budget amount of each lease
+	MaxLeasePeriod        *int64   `json:"maxLeasePeriod,omitempty" dynamodbav:"MaxLeasePeriod,omitempty"`               // Max lease period, in seconds
+	PrincipalBudgetAmount *float64 `json:"principalBudgetAmount,omitempty" dynamodbav:"PrincipalBudgetAmount,omitempty"` // Max spend of the principal's leases, each budget period
+	PrincipalBudgetPeriod *string  `json:"principalBudgetPeriod,omitempty" dynamodbav:"PrincipalBudgetPeriod,omitempty"` // Period the principal's spend is measured over
+	CreatedOn             *int64   `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty"`                         // Principal CreatedOn
+	LastModifiedOn        *int64   `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty"`               // Last Modified Epoch Timestamp
+}
+
+// Validate the principal data
+func (p *Principal) Validate() error {
+	err := validation.ValidateStruct(p,
+		validation.Field(&p.ID, validateID...),
+		validation.Field(&p.MaxLeaseBudgetAmount, validateAmount...),
+		validation.Field(&p.MaxLeasePeriod, validatePeriod...),
+		validation.Field(&p.PrincipalBudgetAmount, validateAmount...),
+		validation.Field(&p.PrincipalBudgetPeriod, validateBudgetPeriod...),
+		validation.Field(&p.LastModifiedOn, validateInt64...),
+		validation.Field(&p.CreatedOn, validateInt64...),
+	)
+	if err != nil {
+		return errors.NewValidation("principal", err)
+	}
+	return nil
+}
+
+// Override returns the limits, with the limits set on the principal in their place
+func (p *Principal) Override(limits Limits) Limits {
+	if p.MaxLeaseBudgetAmount != nil {
+		limits.MaxLeaseBudgetAmount = *p.MaxLeaseBudgetAmount
+	}
+	if p.MaxLeasePeriod != nil {
+		limits.MaxLeasePeriod = *p.MaxLeasePeriod
+	}
+	if p.PrincipalBudgetAmount != nil {
+		limits.PrincipalBudgetAmount = *p.PrincipalBudgetAmount
+	}
+	if p.PrincipalBudgetPeriod != nil {
+		limits.PrincipalBudgetPeriod = *p.PrincipalBudgetPeriod
+	}
+	return limits
+}
+
+// Principals is a list of type Principal
+type Principals []Principal
+
+// Limits are the lease limits which apply to a principal
+type Limits struct {
+	MaxLeaseBudgetAmount  float64 // MAX_LEASE_BUDGET_AMOUNT
+	MaxLeasePeriod        int64   // MAX_LEASE_PERIOD
+	PrincipalBudgetAmount float64 // PRINCIPAL_BUDGET_AMOUNT
+	PrincipalBudgetPeriod string  // PRINCIPAL_BUDGET_PERIOD
+}
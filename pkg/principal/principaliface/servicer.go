@@ -0,0 +1,24 @@
+//
+
+package principaliface
+
+import (
+	"github.com/Optum/dce/pkg/principal"
+)
+
+// Servicer makes working with the Principal Service struct easier
+type Servicer interface {
+	// Get returns a principal from ID
+	Get(ID string) (*principal.Principal, error)
+	// Save writes the record to the dataSvc
+	Save(data *principal.Principal) error
+	// Put creates or replaces the profile of the principal
+	Put(ID string, data *principal.Principal) (*principal.Principal, error)
+	// Delete the principal's profile
+	Delete(data *principal.Principal) error
+	// List Get the list of principal profiles
+	List() (*principal.Principals, error)
+	// Limits returns the limits which apply to the principal: the defaults,
+	// overridden by any limits set on the principal's profile
+	Limits(ID string, defaults principal.Limits) (*principal.Limits, error)
+}
@@ -0,0 +1,173 @@
+package principal
+
+import (
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Writer put an item into the data store
+type Writer interface {
+	Write(i *Principal, lastModifiedOn *int64) error
+}
+
+// Deleter Deletes a Principal from the data store
+type Deleter interface {
+	Delete(i *Principal) error
+}
+
+// SingleReader Reads Principal information from the data store
+type SingleReader interface {
+	Get(ID string) (*Principal, error)
+}
+
+// MultipleReader reads multiple principals from the data store
+type MultipleReader interface {
+	List() (*Principals, error)
+}
+
+// Reader data Layer
+type Reader interface {
+	SingleReader
+	MultipleReader
+}
+
+// WriterDeleter data layer
+type WriterDeleter interface {
+	Writer
+	Deleter
+}
+
+// ReaderWriterDeleter includes Reader and Writer interfaces
+type ReaderWriterDeleter interface {
+	Reader
+	WriterDeleter
+}
+
+// Service is a type corresponding to a Principal table record
+type Service struct {
+	dataSvc ReaderWriterDeleter
+}
+
+// Get returns a principal from ID
+func (s *Service) Get(ID string) (*Principal, error) {
+
+	new, err := s.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, err
+}
+
+// Save writes the record to the dataSvc
+func (s *Service) Save(data *Principal) error {
+	var lastModifiedOn *int64
+	now := time.Now().Unix()
+	if data.LastModifiedOn == nil {
+		lastModifiedOn = nil
+		data.CreatedOn = &now
+		data.LastModifiedOn = &now
+	} else {
+		lastModifiedOn = data.LastModifiedOn
+		data.LastModifiedOn = &now
+	}
+
+	err := data.Validate()
+	if err != nil {
+		return err
+	}
+	err = s.dataSvc.Write(data, lastModifiedOn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Put creates or replaces the profile of the principal.  Limits which
+// aren't provided fall back to the defaults
+func (s *Service) Put(ID string, data *Principal) (*Principal, error) {
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.NilOrNotEmpty, validation.In(ID)),
+		validation.Field(&data.MaxLeaseBudgetAmount, validateAmount...),
+		validation.Field(&data.MaxLeasePeriod, validatePeriod...),
+		validation.Field(&data.PrincipalBudgetAmount, validateAmount...),
+		validation.Field(&data.PrincipalBudgetPeriod, validateBudgetPeriod...),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("principal", err)
+	}
+
+	new := &Principal{
+		ID:                    &ID,
+		MaxLeaseBudgetAmount:  data.MaxLeaseBudgetAmount,
+		MaxLeasePeriod:        data.MaxLeasePeriod,
+		PrincipalBudgetAmount: data.PrincipalBudgetAmount,
+		PrincipalBudgetPeriod: data.PrincipalBudgetPeriod,
+	}
+
+	// Replacing a profile keeps its created date, and is conditional
+	// on it not being modified since
+	existing, err := s.dataSvc.Get(ID)
+	if err != nil {
+		if !errors.Is(err, errors.NewNotFound("principal", ID)) {
+			return nil, err
+		}
+	} else {
+		new.CreatedOn = existing.CreatedOn
+		new.LastModifiedOn = existing.LastModifiedOn
+	}
+
+	err = s.Save(new)
+	if err != nil {
+		return nil, err
+	}
+	return new, nil
+}
+
+// Delete the principal's profile
+func (s *Service) Delete(data *Principal) error {
+	return s.dataSvc.Delete(data)
+}
+
+// List Get the list of principal profiles
+func (s *Service) List() (*Principals, error) {
+
+	principals, err := s.dataSvc.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return principals, nil
+}
+
+// Limits returns the limits which apply to the principal: the defaults,
+// overridden by any limits set on the principal's profile
+func (s *Service) Limits(ID string, defaults Limits) (*Limits, error) {
+
+	p, err := s.dataSvc.Get(ID)
+	if err != nil {
+		if errors.Is(err, errors.NewNotFound("principal", ID)) {
+			return &defaults, nil
+		}
+		return nil, err
+	}
+
+	limits := p.Override(defaults)
+	return &limits, nil
+}
+
+// NewServiceInput Input for creating a new Service
+type NewServiceInput struct {
+	DataSvc ReaderWriterDeleter
+}
+
+// NewService creates a new instance of the Service
+func NewService(input NewServiceInput) *Service {
+	return &Service{
+		dataSvc: input.DataSvc,
+	}
+}
@@ -0,0 +1,180 @@
+package principal_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/principal"
+	"github.com/Optum/dce/pkg/principal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func ptrInt64(i int64) *int64 {
+	ptrI := i
+	return &ptrI
+}
+
+func ptrFloat64(f float64) *float64 {
+	ptrF := f
+	return &ptrF
+}
+
+func TestPutPrincipal(t *testing.T) {
+
+	tests := []struct {
+		name              string
+		req               *principal.Principal
+		getPrincipal      *principal.Principal
+		getErr            error
+		expPrevModifiedOn *int64
+		expPrincipal      *principal.Principal
+		expErr            error
+	}{
+		{
+			name: "should create a profile",
+			req: &principal.Principal{
+				MaxLeaseBudgetAmount: ptrFloat64(5000),
+			},
+			getErr: errors.NewNotFound("principal", "jdoe99"),
+			expPrincipal: &principal.Principal{
+				ID:                   ptrString("jdoe99"),
+				MaxLeaseBudgetAmount: ptrFloat64(5000),
+			},
+		},
+		{
+			name: "should replace an existing profile",
+			req: &principal.Principal{
+				PrincipalBudgetAmount: ptrFloat64(2000),
+				PrincipalBudgetPeriod: ptrString("MONTHLY"),
+			},
+			getPrincipal: &principal.Principal{
+				ID:                   ptrString("jdoe99"),
+				MaxLeaseBudgetAmount: ptrFloat64(5000),
+				CreatedOn:            ptrInt64(1573592058),
+				LastModifiedOn:       ptrInt64(1573592058),
+			},
+			expPrevModifiedOn: ptrInt64(1573592058),
+			expPrincipal: &principal.Principal{
+				ID:                    ptrString("jdoe99"),
+				PrincipalBudgetAmount: ptrFloat64(2000),
+				PrincipalBudgetPeriod: ptrString("MONTHLY"),
+				CreatedOn:             ptrInt64(1573592058),
+			},
+		},
+		{
+			name: "should fail on an invalid budget period",
+			req: &principal.Principal{
+				PrincipalBudgetPeriod: ptrString("DAILY"),
+			},
+			expErr: errors.NewValidation("principal", fmt.Errorf("principalBudgetPeriod: must be WEEKLY or MONTHLY.")), //nolint golint
+		},
+		{
+			name: "should fail on a negative lease period",
+			req: &principal.Principal{
+				MaxLeasePeriod: ptrInt64(-1),
+			},
+			expErr: errors.NewValidation("principal", fmt.Errorf("maxLeasePeriod: must be greater than zero.")), //nolint golint
+		},
+		{
+			name: "should fail on get errors",
+			req: &principal.Principal{
+				MaxLeaseBudgetAmount: ptrFloat64(5000),
+			},
+			getErr: errors.NewInternalServer("failure", nil),
+			expErr: errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Get", "jdoe99").Return(tt.getPrincipal, tt.getErr)
+			mocksRwd.On("Write", mock.AnythingOfType("*principal.Principal"), tt.expPrevModifiedOn).Return(nil)
+
+			principalSvc := principal.NewService(principal.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			result, err := principalSvc.Put("jdoe99", tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expPrincipal != nil {
+				assert.Equal(t, tt.expPrincipal.ID, result.ID)
+				assert.Equal(t, tt.expPrincipal.MaxLeaseBudgetAmount, result.MaxLeaseBudgetAmount)
+				assert.Equal(t, tt.expPrincipal.MaxLeasePeriod, result.MaxLeasePeriod)
+				assert.Equal(t, tt.expPrincipal.PrincipalBudgetAmount, result.PrincipalBudgetAmount)
+				assert.Equal(t, tt.expPrincipal.PrincipalBudgetPeriod, result.PrincipalBudgetPeriod)
+				if tt.expPrincipal.CreatedOn != nil {
+					assert.Equal(t, tt.expPrincipal.CreatedOn, result.CreatedOn)
+				}
+				mocksRwd.AssertNumberOfCalls(t, "Write", 1)
+			} else {
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+
+	defaults := principal.Limits{
+		MaxLeaseBudgetAmount:  1000,
+		MaxLeasePeriod:        704800,
+		PrincipalBudgetAmount: 1000,
+		PrincipalBudgetPeriod: "WEEKLY",
+	}
+
+	tests := []struct {
+		name         string
+		getPrincipal *principal.Principal
+		getErr       error
+		expLimits    *principal.Limits
+		expErr       error
+	}{
+		{
+			name: "should override the defaults with the profile's limits",
+			getPrincipal: &principal.Principal{
+				ID:                    ptrString("jdoe99"),
+				MaxLeaseBudgetAmount:  ptrFloat64(5000),
+				PrincipalBudgetPeriod: ptrString("MONTHLY"),
+			},
+			expLimits: &principal.Limits{
+				MaxLeaseBudgetAmount:  5000,
+				MaxLeasePeriod:        704800,
+				PrincipalBudgetAmount: 1000,
+				PrincipalBudgetPeriod: "MONTHLY",
+			},
+		},
+		{
+			name:      "should return the defaults without a profile",
+			getErr:    errors.NewNotFound("principal", "jdoe99"),
+			expLimits: &defaults,
+		},
+		{
+			name:   "should fail on get errors",
+			getErr: errors.NewInternalServer("failure", nil),
+			expErr: errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Get", "jdoe99").Return(tt.getPrincipal, tt.getErr)
+
+			principalSvc := principal.NewService(principal.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			result, err := principalSvc.Limits("jdoe99", defaults)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			assert.Equal(t, tt.expLimits, result)
+		})
+	}
+}
@@ -40,8 +40,10 @@ func (athenaReset AthenaReset) DeleteNamedQuery(input *athena.DeleteNamedQueryIn
 	return athenaReset.Client.DeleteNamedQuery(input)
 }
 
-// DeleteAthenaResources deletes all aethna resources in the current aws session
-func DeleteAthenaResources(athenaSvc AthenaService) error {
+// DeleteAthenaResources deletes all aethna resources in the current aws session.
+// Returns the resources that were deleted, and any resource that failed to delete
+func DeleteAthenaResources(athenaSvc AthenaService) (*Resources, error) {
+	resources := &Resources{}
 
 	var maxResult int64 = 50
 	// Delete all workgroups
@@ -50,7 +52,7 @@ func DeleteAthenaResources(athenaSvc AthenaService) error {
 	}
 	listWorkGroupsOutput, err := athenaSvc.ListWorkGroups(listWorkGroupsInput)
 	if err != nil {
-		return err
+		return resources, err
 	}
 
 	for _, workGroup := range listWorkGroupsOutput.WorkGroups {
@@ -64,18 +66,21 @@ func DeleteAthenaResources(athenaSvc AthenaService) error {
 			WorkGroup:             workGroup.Name,
 		}
 		log.Printf("Starting Athena workgroup delete %v", workGroup)
+		resourceName := "AthenaWorkGroup - " + *workGroup.Name
 		_, err := athenaSvc.DeleteWorkGroup(deleteWorkGroupInput)
 		if err != nil {
 			log.Printf("Athena workgroup delete error: %v", err)
-			return err
+			resources.Failed = append(resources.Failed, resourceName)
+			return resources, err
 		}
+		resources.Deleted = append(resources.Deleted, resourceName)
 	}
 
 	// Delete all namedqueries
 	listNamedQueriesInput := &athena.ListNamedQueriesInput{}
 	listNamedQueriesOutput, err := athenaSvc.ListNamedQueries(listNamedQueriesInput)
 	if err != nil {
-		return err
+		return resources, err
 	}
 
 	for _, namedQuery := range listNamedQueriesOutput.NamedQueryIds {
@@ -83,11 +88,30 @@ func DeleteAthenaResources(athenaSvc AthenaService) error {
 		deleteNamedQueryInput := &athena.DeleteNamedQueryInput{
 			NamedQueryId: namedQuery,
 		}
+		resourceName := "AthenaNamedQuery - " + *namedQuery
 		_, err := athenaSvc.DeleteNamedQuery(deleteNamedQueryInput)
 		if err != nil {
-			return err
+			resources.Failed = append(resources.Failed, resourceName)
+			return resources, err
 		}
+		resources.Deleted = append(resources.Deleted, resourceName)
 	}
 
-	return nil
+	return resources, nil
+}
+
+// AthenaResetter is the reset step for Athena resources,
+// which aws-nuke doesn't support
+type AthenaResetter struct {
+	Service AthenaService
+}
+
+// Name of the reset step
+func (a *AthenaResetter) Name() string {
+	return StepAthena
+}
+
+// Reset deletes the Athena workgroups and named queries
+func (a *AthenaResetter) Reset() (*Resources, error) {
+	return DeleteAthenaResources(a.Service)
 }
@@ -46,6 +46,13 @@ func (athenaReset mockAthenaReset) DeleteNamedQuery(input *athena.DeleteNamedQue
 
 func TestDeleteAthenaResources(t *testing.T) {
 	mockAthena := new(mockAthenaReset)
-	err := DeleteAthenaResources(mockAthena)
+	resources, err := DeleteAthenaResources(mockAthena)
 	assert.Nil(t, err, "There should be no errors")
+	assert.Equal(t, &Resources{
+		Deleted: []string{
+			"AthenaWorkGroup - wg1",
+			"AthenaNamedQuery - test-query-1",
+			"AthenaNamedQuery - test-query-2",
+		},
+	}, resources)
 }
@@ -0,0 +1,551 @@
+package reset
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"text/template"
+	"time"
+
+	dceAccount "github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface"
+	"github.com/Optum/dce/pkg/common"
+	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/lease"
+	"github.com/avast/retry-go"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/athena"
+	"github.com/aws/aws-sdk-go/service/route53domains"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+	"github.com/pkg/errors"
+)
+
+// JobConfig configures the reset of an account
+type JobConfig struct {
+	ParentAccountID       string
+	ChildAccountID        string
+	AdminRoleName         string
+	PrincipalRoleName     string
+	PrincipalPolicyName   string
+	Regions               []string
+	Steps                 []string
+	IsNukeEnabled         bool
+	IsVerifyEnabled       bool
+	NukeTemplateDefault   string
+	NukeTemplateBucket    string
+	NukeTemplateKey       string
+	ReportBucket          string
+	NukeOverridesBucket   string
+	MaxFailedAttempts     int
+	ResetCompleteTopicArn string
+}
+
+// Job resets an account, and records the result on the account.
+// Jobs are run by the reset CodeBuild, and by the process_reset_queue Lambda
+type Job struct {
+	Config         *JobConfig
+	Session        client.ConfigProvider
+	Token          common.TokenService
+	Storage        common.Storager
+	DB             db.DBer
+	SNS            common.Notificationer
+	Leases         PendingLeaseFulfiller
+	AccountManager accountmanageriface.Servicer
+	Accounts       AccountGetter
+	Events         AccountUpdatePublisher
+}
+
+// PendingLeaseFulfiller gives Ready accounts to leases on the waitlist
+type PendingLeaseFulfiller interface {
+	FulfillPending(accountID string) (*lease.Lease, error)
+}
+
+// AccountGetter gets an account
+type AccountGetter interface {
+	Get(ID string) (*dceAccount.Account, error)
+}
+
+// AccountUpdatePublisher publishes changes to an account
+type AccountUpdatePublisher interface {
+	AccountUpdate(data *dceAccount.Account) error
+}
+
+// Run resets the account, and updates the account status.
+// Returns an error if the account failed to reset
+func (j *Job) Run() error {
+	config := j.Config
+
+	// Lookup the result of the last reset,
+	// to count consecutive reset failures
+	account, err := j.DB.GetAccount(config.ChildAccountID)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get account %s", config.ChildAccountID)
+	}
+	if account == nil {
+		return fmt.Errorf("Account %s does not exist", config.ChildAccountID)
+	}
+	startedOn := time.Now()
+	resetResult := db.ResetResult{
+		Attempt:   NextResetAttempt(account),
+		StartedOn: startedOn.Unix(),
+	}
+	log.Printf("Starting reset attempt %d for account %s\n", resetResult.Attempt, config.ChildAccountID)
+
+	if !config.IsNukeEnabled {
+		log.Println("INFO: Nuke is set in Dry Run mode and will not remove " +
+			"any resources and cannot set back the state of the DCE child account " +
+			"Please set 'RESET_NUKE_DRY_RUN' to not 'true' to exit Dry Run " +
+			"mode.")
+	}
+
+	nuke := &nukeResetter{
+		job: j,
+		// Execute nuke as a dry run, if isNukeEnabled is off
+		isDryRun: !config.IsNukeEnabled,
+	}
+	resources := &Resources{}
+	overrides, err := j.NukeConfigOverrides(account)
+	if err == nil {
+		nuke.overrides = overrides
+		resources, err = j.registry(nuke, appendUnique(config.Regions, overrides.Regions...)).Reset()
+	}
+	resetResult.Duration = int64(time.Since(startedOn).Seconds())
+	resetResult.ResourcesDeleted = resources.Deleted
+	resetResult.ResourcesFailed = resources.Failed
+	resetResult.ReportKey = nuke.reportKey
+	resetResult.ReportCSVKey = nuke.reportCSVKey
+	if err != nil {
+		resetResult.Error = err.Error()
+		updateErr := UpdateDBPostResetFailure(j.DB, j.SNS, config.ChildAccountID, resetResult, config.MaxFailedAttempts, config.ResetCompleteTopicArn)
+		if updateErr != nil {
+			log.Printf("Failed to update the DB post-reset for account %s:  %s", config.ChildAccountID, updateErr)
+		} else {
+			j.publishAccountUpdate()
+		}
+		return errors.Wrapf(err, "Failed to reset account %s", config.ChildAccountID)
+	}
+	resetResult.Succeeded = true
+	log.Printf("%s  :  Nuke Success\n", config.ChildAccountID)
+
+	// Check the account is healthy, before returning it to the account pool.
+	// A dry run leaves resources in the account, so isn't verified
+	if config.IsVerifyEnabled && config.IsNukeEnabled {
+		verifier := &Verifier{
+			AccountManager:      j.AccountManager,
+			PrincipalPolicyName: config.PrincipalPolicyName,
+			RemainingResources:  nuke.remainingResources,
+		}
+		err = verifier.Verify(account)
+		if err != nil {
+			resetResult.VerificationError = err.Error()
+			updateErr := UpdateDBPostVerifyFailure(j.DB, j.SNS, config.ChildAccountID, resetResult, config.ResetCompleteTopicArn)
+			if updateErr != nil {
+				log.Printf("Failed to update the DB post-reset for account %s:  %s", config.ChildAccountID, updateErr)
+			} else {
+				j.publishAccountUpdate()
+			}
+			return errors.Wrapf(err, "Account %s failed verification after reset", config.ChildAccountID)
+		}
+		log.Printf("%s  :  Verification Success\n", config.ChildAccountID)
+	}
+
+	// Update the DB with Account/Lease statuses
+	err = UpdateDBPostReset(j.DB, j.SNS, j.Leases, config.ChildAccountID, resetResult, config.ResetCompleteTopicArn)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to update the DB post-reset for account %s", config.ChildAccountID)
+	}
+	j.publishAccountUpdate()
+	return nil
+}
+
+// publishAccountUpdate publishes the account, after the reset has changed it.
+// The account has already been updated, so failures are logged and ignored
+func (j *Job) publishAccountUpdate() {
+	if j.Accounts == nil || j.Events == nil {
+		return
+	}
+	acct, err := j.Accounts.Get(j.Config.ChildAccountID)
+	if err != nil {
+		log.Printf("Failed to get account %s, to publish the account update:  %s", j.Config.ChildAccountID, err)
+		return
+	}
+	err = j.Events.AccountUpdate(acct)
+	if err != nil {
+		log.Printf("Failed to publish the account update for %s:  %s", j.Config.ChildAccountID, err)
+	}
+}
+
+// NukeConfigOverrides reads the account's overrides of the aws-nuke configuration,
+// from the account metadata, and from the YAML file in S3 at the
+// metadata's nukeConfigKey
+func (j *Job) NukeConfigOverrides(account *db.Account) (*NukeConfigOverrides, error) {
+	overrides, err := NukeConfigOverridesFromMetadata(account.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := account.Metadata[MetadataNukeConfigKey]
+	if !ok {
+		return overrides, nil
+	}
+	keyStr, ok := key.(string)
+	if !ok || keyStr == "" {
+		return nil, fmt.Errorf("Invalid %s account metadata: expected an S3 key", MetadataNukeConfigKey)
+	}
+	log.Printf("Using Nuke Configuration overrides from S3: %s/%s", j.Config.NukeOverridesBucket, keyStr)
+	overridesYAML, err := j.Storage.GetObject(j.Config.NukeOverridesBucket, keyStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get nuke config overrides at s3://%s/%s",
+			j.Config.NukeOverridesBucket, keyStr)
+	}
+	fileOverrides, err := NukeConfigOverridesFromYAML([]byte(overridesYAML))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid nuke config overrides at s3://%s/%s",
+			j.Config.NukeOverridesBucket, keyStr)
+	}
+	overrides.Add(fileOverrides)
+
+	return overrides, nil
+}
+
+// registry registers each of the reset steps, in the order they run.
+// Only the steps in the job's config are enabled
+func (j *Job) registry(nuke *nukeResetter, regions []string) *Registry {
+	config := j.Config
+	adminRoleArn := "arn:aws:iam::" + config.ChildAccountID + ":role/" + config.AdminRoleName
+	creds := j.Token.NewCredentials(j.Session, adminRoleArn)
+
+	// Only aws-nuke supports Dry Run mode, so skip the other steps
+	enabledSteps := config.Steps
+	if !config.IsNukeEnabled {
+		enabledSteps = []string{StepAwsNuke}
+	}
+	registry := NewRegistry(enabledSteps)
+
+	// Delete items nuke doesn't support currently
+	registry.Register(&AthenaResetter{
+		Service: &AthenaReset{
+			Client: athena.New(j.Session, &aws.Config{
+				Credentials: creds,
+			}),
+		},
+	})
+
+	// Delete items which need to be emptied or detached before nuke can delete them
+	for _, region := range regions {
+		regionConfig := &aws.Config{
+			Credentials: creds,
+			Region:      aws.String(region),
+		}
+		registry.Register(&S3VersionedBucketsResetter{
+			Client: s3.New(j.Session, regionConfig),
+			Region: region,
+		})
+		registry.Register(&ServiceCatalogResetter{
+			Service: servicecatalog.New(j.Session, regionConfig),
+		})
+	}
+
+	// Route53 domain registration is only available in us-east-1
+	registry.Register(&Route53DomainsResetter{
+		Service: route53domains.New(j.Session, &aws.Config{
+			Credentials: creds,
+			Region:      aws.String("us-east-1"),
+		}),
+	})
+
+	// Execute aws-nuke, to delete all remaining resources from the account
+	registry.Register(nuke)
+
+	return registry
+}
+
+// nukeResetter is the reset step which runs aws-nuke
+type nukeResetter struct {
+	job       *Job
+	isDryRun  bool
+	overrides *NukeConfigOverrides
+	// S3 keys of the dry run report
+	reportKey    string
+	reportCSVKey string
+}
+
+// Name of the reset step
+func (n *nukeResetter) Name() string {
+	return StepAwsNuke
+}
+
+// Reset runs aws-nuke against the account.
+// aws-nuke logs the resources it deletes, rather than returning them.
+// A dry run uploads a report of the resources aws-nuke would delete
+func (n *nukeResetter) Reset() (*Resources, error) {
+	if !n.isDryRun {
+		return &Resources{}, n.nukeAccount()
+	}
+
+	startedOn := time.Now()
+	output, err := captureStdout(n.nukeAccount)
+	if err != nil {
+		return &Resources{}, err
+	}
+
+	// The report is informational, so don't fail the reset without it
+	config := n.job.Config
+	n.reportKey, n.reportCSVKey, err = UploadReport(n.job.Storage, config.ReportBucket, config.ChildAccountID, output, startedOn)
+	if err != nil {
+		log.Printf("Failed to upload reset report for account %s: %s", config.ChildAccountID, err)
+	}
+	return &Resources{}, nil
+}
+
+func (n *nukeResetter) nukeAccount() error {
+	// Generate the configuration of the yaml file using the template file
+	// provided and substituting necessary phrases.
+
+	config := n.job.Config
+
+	var rendered bytes.Buffer
+	err := n.job.GenerateNukeConfig(&rendered)
+	if err != nil {
+		return err
+	}
+
+	// Add the account's overrides, and check the result before
+	// letting aws-nuke loose on the account
+	conf, err := MergeNukeConfig(rendered.Bytes(), config.ChildAccountID, n.overrides)
+	if err != nil {
+		return err
+	}
+	err = ValidateNukeConfig(conf, config.ChildAccountID)
+	if err != nil {
+		return err
+	}
+
+	// Print the contents of the config file, for logging/debugging
+	log.Println("Rendered nuke file:")
+	log.Print(string(conf))
+
+	// Create the file
+	configFile := fmt.Sprintf("/tmp/nuke-config-%s.yml", config.ChildAccountID)
+	err = ioutil.WriteFile(configFile, conf, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create file %s", configFile)
+	}
+
+	// Configure the NukeAccountInput
+	nukeAccountInput := NukeAccountInput{
+		ChildAccountID: config.ChildAccountID,
+		RoleName:       config.AdminRoleName,
+		ConfigPath:     configFile,
+		NoDryRun:       !n.isDryRun,
+		Token:          n.job.Token,
+		Nuke:           Nuke{},
+	}
+
+	// Nukes based on the configuration file that is generated
+	// Attempt Nuke 3 times in the case not all resources get deleted
+	return retry.Do(
+		func() error {
+			return NukeAccount(&nukeAccountInput)
+		},
+		retry.Attempts(3),         // Retry 3 times
+		retry.LastErrorOnly(true), // Only return the last error
+	)
+}
+
+// GenerateNukeConfig renders the aws-nuke configuration for the account.
+// The template is downloaded from S3, if a template bucket and key are configured,
+// otherwise the default template file is used
+func (j *Job) GenerateNukeConfig(f io.Writer) error {
+	config := j.Config
+
+	// Verify the nuke template configuration to download file from s3 or to
+	// use the default
+	var templateFile string
+	if config.NukeTemplateBucket != "STUB" && config.NukeTemplateKey != "STUB" {
+		log.Printf("Using Nuke Configuration from S3: %s/%s",
+			config.NukeTemplateBucket, config.NukeTemplateKey)
+
+		// Download the file from S3
+		templateFile = fmt.Sprintf("/tmp/nuke-config-template-%s.yml", config.ChildAccountID)
+		err := j.Storage.Download(config.NukeTemplateBucket,
+			config.NukeTemplateKey, templateFile)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to download nuke template at s3://%s/%s to %s",
+				config.NukeTemplateBucket, config.NukeTemplateKey, templateFile)
+		}
+	} else {
+		log.Printf("Using Default Nuke Configuration: %s",
+			config.NukeTemplateDefault)
+
+		// Use default template
+		templateFile = config.NukeTemplateDefault
+	}
+
+	templateText, err := ioutil.ReadFile(templateFile)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to read nuke template %s", templateFile)
+	}
+	tmpl, err := template.New(templateFile).Parse(string(templateText))
+	if err != nil {
+		log.Printf("Failed to generate nuke config for acount %s using template %s: %s",
+			config.ChildAccountID, templateFile, err)
+		return err
+	}
+
+	type templateParams struct {
+		ParentAccountID string
+		ID              string
+		AdminRole       string
+		PrincipalRole   string
+		PrincipalPolicy string
+		Regions         []string
+	}
+
+	err = tmpl.Execute(f, &templateParams{
+		ParentAccountID: config.ParentAccountID,
+		ID:              config.ChildAccountID,
+		AdminRole:       config.AdminRoleName,
+		PrincipalRole:   config.PrincipalRoleName,
+		PrincipalPolicy: config.PrincipalPolicyName,
+		Regions:         config.Regions,
+	})
+	if err != nil {
+		log.Printf("Failed to generate nuke config for acount %s using template %s: %s",
+			config.ChildAccountID, templateFile, err)
+		return err
+	}
+
+	return nil
+}
+
+// NextResetAttempt numbers the reset attempt, counting consecutive failures
+// since the account was last reset successfully
+func NextResetAttempt(account *db.Account) int {
+	if account.LastResetResult == nil || account.LastResetResult.Succeeded {
+		return 1
+	}
+	return account.LastResetResult.Attempt + 1
+}
+
+// UpdateDBPostReset changes any leases for the Account
+// from "Status=ResetLock" to "Status=Active"
+// Also, if the account was set as "Status=NotReady",
+// will update to "Status=Ready" and hand the account to the
+// oldest pending lease waiting for one
+func UpdateDBPostReset(dbSvc db.DBer, snsSvc common.Notificationer, leaseSvc PendingLeaseFulfiller, accountID string, resetResult db.ResetResult, snsTopicArn string) error {
+
+	// Record the reset on the account
+	_, err := dbSvc.UpdateAccountResetResult(accountID, resetResult)
+	if err != nil {
+		return err
+	}
+
+	// If the Account.Status=NotReady, change it back to Status=Ready
+	log.Printf("Setting Account Status from NotReady to Ready: %s", accountID)
+	becameReady := false
+	account, err := dbSvc.TransitionAccountStatus(
+		accountID,
+		db.NotReady, db.Ready)
+
+	// Ignore StatusTransitionErrors
+	// (just means the status was NOT previously NotReady")
+	if err != nil {
+		if _, ok := err.(*db.StatusTransitionError); !ok {
+			return err
+		}
+		account, err = dbSvc.GetAccount(accountID)
+		if err != nil {
+			return err
+		}
+	} else {
+		becameReady = true
+	}
+
+	err = PublishResetComplete(snsSvc, account, snsTopicArn)
+	if err != nil {
+		return err
+	}
+
+	// The account is Ready again, so give it to any lease on the waitlist.
+	// The account is still usable if this fails, so don't fail the reset
+	if becameReady {
+		pendingLease, err := leaseSvc.FulfillPending(accountID)
+		if err != nil {
+			log.Printf("Failed to fulfill pending leases with account %s: %s", accountID, err)
+		} else if pendingLease != nil {
+			log.Printf("Leased account %s to pending lease %s for principal %s", accountID, *pendingLease.ID, *pendingLease.PrincipalID)
+		}
+	}
+	return nil
+}
+
+// UpdateDBPostResetFailure records a failed reset on the Account.
+// After maxFailedAttempts consecutive failures, if the account was set as
+// "Status=NotReady", will update to "Status=ResetFailed", so it is no longer
+// added to the reset queue
+func UpdateDBPostResetFailure(dbSvc db.DBer, snsSvc common.Notificationer, accountID string, resetResult db.ResetResult, maxFailedAttempts int, snsTopicArn string) error {
+	account, err := dbSvc.UpdateAccountResetResult(accountID, resetResult)
+	if err != nil {
+		return err
+	}
+
+	if maxFailedAttempts > 0 && resetResult.Attempt >= maxFailedAttempts {
+		log.Printf("Reset failed %d times, setting Account Status from NotReady to ResetFailed: %s", resetResult.Attempt, accountID)
+		resetFailedAccount, err := dbSvc.TransitionAccountStatus(
+			accountID,
+			db.NotReady, db.ResetFailed)
+
+		// Ignore StatusTransitionErrors
+		// (just means the status was NOT previously NotReady")
+		if err != nil {
+			if _, ok := err.(*db.StatusTransitionError); !ok {
+				return err
+			}
+		} else {
+			account = resetFailedAccount
+		}
+	}
+
+	return PublishResetComplete(snsSvc, account, snsTopicArn)
+}
+
+// UpdateDBPostVerifyFailure records the reset on the Account,
+// and sets the account to "Status=Orphaned", as it failed verification
+// after the reset. Orphaning the account ends any active leases on it.
+func UpdateDBPostVerifyFailure(dbSvc db.DBer, snsSvc common.Notificationer, accountID string, resetResult db.ResetResult, snsTopicArn string) error {
+	_, err := dbSvc.UpdateAccountResetResult(accountID, resetResult)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Account failed verification, setting Account Status to Orphaned: %s", accountID)
+	account, err := dbSvc.OrphanAccount(accountID)
+	if err != nil {
+		return err
+	}
+
+	return PublishResetComplete(snsSvc, account, snsTopicArn)
+}
+
+// PublishResetComplete sends the account, with the result of the reset,
+// to the reset complete SNS topic
+func PublishResetComplete(snsSvc common.Notificationer, account *db.Account, snsTopicArn string) error {
+	log.Printf("Notifying Reset Topic that the account is complete for: %s", account.ID)
+	snsMessage, err := common.PrepareSNSMessageJSON(account)
+	if err != nil {
+		log.Printf("Failed to create SNS account-created message for %s: %s", account.ID, err)
+		return err
+	}
+	log.Print(snsMessage)
+	_, err = snsSvc.PublishMessage(aws.String(snsTopicArn), aws.String(snsMessage), true)
+	if err != nil {
+		log.Print("Issue in publishing message: %s" + err.Error())
+		return err
+	}
+	return nil
+}
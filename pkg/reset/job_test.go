@@ -0,0 +1,476 @@
+package reset_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	commonMocks "github.com/Optum/dce/pkg/common/mocks"
+	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/db/mocks"
+	"github.com/Optum/dce/pkg/lease"
+	leaseMocks "github.com/Optum/dce/pkg/lease/leaseiface/mocks"
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob(t *testing.T) {
+	t.Run("UpdateDBPostReset", func(t *testing.T) {
+
+		t.Run("Should change account status from NotReady to Ready", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			leaseSvc := &leaseMocks.Servicer{}
+
+			// Should record the reset on the account
+			resetResult := db.ResetResult{Attempt: 1, Succeeded: true}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{}, nil)
+			defer dbSvc.AssertExpectations(t)
+
+			// Should give the account to a pending lease
+			leaseSvc.On("FulfillPending", "111").Return(nil, nil)
+			defer leaseSvc.AssertExpectations(t)
+
+			// Should change the Account Status
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
+				Return(&db.Account{}, nil)
+
+			snsSvc.On("PublishMessage",
+				mock.MatchedBy(func(arn *string) bool {
+					return *arn == "Topic"
+				}),
+				mock.MatchedBy(func(message *string) bool {
+					// Parse the message JSON
+					messageObj := unmarshal(t, *message)
+					// `default` and `body` and JSON embedded within the message JSON
+					msgDefault := unmarshal(t, messageObj["default"].(string))
+					msgBody := unmarshal(t, messageObj["Body"].(string))
+
+					assert.Equal(t, msgDefault, msgBody, "SNS default/Body should  match")
+
+					// Check that we're sending the account object
+					assert.Equal(t, "", msgBody["Id"])
+
+					return true
+				}), true,
+			).Return(aws.String("mock message"), nil)
+			defer snsSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostReset(dbSvc, snsSvc, leaseSvc, "111", resetResult, "Topic")
+			dbSvc.AssertNumberOfCalls(t, "TransitionLeaseStatus", 0)
+			dbSvc.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+			require.Nil(t, err)
+		})
+
+		t.Run("Should not fail when fulfilling pending leases fails", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			leaseSvc := &leaseMocks.Servicer{}
+
+			// Should record the reset on the account
+			resetResult := db.ResetResult{Attempt: 1, Succeeded: true}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{}, nil)
+
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
+				Return(&db.Account{}, nil)
+			snsSvc.On("PublishMessage", mock.Anything, mock.Anything, true).
+				Return(aws.String("mock message"), nil)
+			leaseSvc.On("FulfillPending", "111").Return(nil, errors.New("test error"))
+			defer leaseSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostReset(dbSvc, snsSvc, leaseSvc, "111", resetResult, "Topic")
+			require.Nil(t, err)
+		})
+
+		t.Run("Should give the account to a pending lease", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			leaseSvc := &leaseMocks.Servicer{}
+
+			// Should record the reset on the account
+			resetResult := db.ResetResult{Attempt: 1, Succeeded: true}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{}, nil)
+
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
+				Return(&db.Account{}, nil)
+			snsSvc.On("PublishMessage", mock.Anything, mock.Anything, true).
+				Return(aws.String("mock message"), nil)
+			leaseSvc.On("FulfillPending", "111").Return(&lease.Lease{
+				ID:          aws.String("70c2d96d-7938-4ec9-917d-476f2b09cc04"),
+				AccountID:   aws.String("111"),
+				PrincipalID: aws.String("jdoe123"),
+				Status:      lease.StatusActive.StatusPtr(),
+			}, nil)
+			defer leaseSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostReset(dbSvc, snsSvc, leaseSvc, "111", resetResult, "Topic")
+			require.Nil(t, err)
+		})
+
+		t.Run("Should not change account status of Leased accounts", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			leaseSvc := &leaseMocks.Servicer{}
+
+			// Should record the reset on the account
+			resetResult := db.ResetResult{Attempt: 1, Succeeded: true}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{}, nil)
+			defer dbSvc.AssertExpectations(t)
+
+			// Mock Account status change, so it returns an error
+			// saying that the Account was not in "NotReady" state.
+			// We'll just ignore this error, because it means
+			// we don't want to change status at all.
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
+				Return(nil, &db.StatusTransitionError{})
+
+			dbSvc.
+				On("GetAccount", "111").
+				Return(&db.Account{}, nil)
+
+			snsSvc.On("PublishMessage",
+				mock.MatchedBy(func(arn *string) bool {
+					return *arn == "Topic"
+				}),
+				mock.MatchedBy(func(message *string) bool {
+					// Parse the message JSON
+					messageObj := unmarshal(t, *message)
+					// `default` and `body` and JSON embedded within the message JSON
+					msgDefault := unmarshal(t, messageObj["default"].(string))
+					msgBody := unmarshal(t, messageObj["Body"].(string))
+
+					assert.Equal(t, msgDefault, msgBody, "SNS default/Body should  match")
+
+					// Check that we're sending the account object
+					assert.Equal(t, "", msgBody["Id"])
+
+					return true
+				}), true,
+			).Return(aws.String("mock message"), nil)
+			defer snsSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostReset(dbSvc, snsSvc, leaseSvc, "111", resetResult, "Topic")
+			dbSvc.AssertNumberOfCalls(t, "TransitionLeaseStatus", 0)
+			dbSvc.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+			// Leased accounts aren't given to pending leases
+			leaseSvc.AssertNotCalled(t, "FulfillPending", mock.Anything)
+			require.Nil(t, err)
+		})
+
+		t.Run("Should handle DB errors (TransitionAccountStatus)", func(t *testing.T) {
+			snsSvc := &commonMocks.Notificationer{}
+			dbSvc := &mocks.DBer{}
+			leaseSvc := &leaseMocks.Servicer{}
+
+			// Should record the reset on the account
+			resetResult := db.ResetResult{Attempt: 1, Succeeded: true}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{}, nil)
+			defer dbSvc.AssertExpectations(t)
+
+			// Mock Account status change, so it returns an error
+			// saying that the Account was not in "NotReady" state.
+			// We'll just ignore this error, because it means
+			// we don't want to change status at all.
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.Ready).
+				Return(nil, errors.New("test error"))
+
+			err := reset.UpdateDBPostReset(dbSvc, snsSvc, leaseSvc, "111", resetResult, "Topic")
+			dbSvc.AssertNumberOfCalls(t, "TransitionLeaseStatus", 0)
+			dbSvc.AssertNumberOfCalls(t, "TransitionAccountStatus", 1)
+			require.Equal(t, errors.New("test error"), err)
+		})
+	})
+
+	t.Run("UpdateDBPostResetFailure", func(t *testing.T) {
+
+		t.Run("Should record the failed reset on the account", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			defer dbSvc.AssertExpectations(t)
+
+			resetResult := db.ResetResult{
+				Attempt:         1,
+				ResourcesFailed: []string{"AthenaWorkGroup - wg1"},
+				Error:           "test error",
+			}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{
+					ID:              "111",
+					AccountStatus:   db.NotReady,
+					LastResetResult: &resetResult,
+				}, nil)
+
+			snsSvc.On("PublishMessage",
+				mock.MatchedBy(func(arn *string) bool {
+					return *arn == "Topic"
+				}),
+				mock.MatchedBy(func(message *string) bool {
+					messageObj := unmarshal(t, *message)
+					msgBody := unmarshal(t, messageObj["Body"].(string))
+
+					// Check that we're sending the account, with the reset result
+					assert.Equal(t, "111", msgBody["Id"])
+					assert.Equal(t, "NotReady", msgBody["AccountStatus"])
+					assert.Equal(t, "test error", msgBody["LastResetResult"].(map[string]interface{})["Error"])
+
+					return true
+				}), true,
+			).Return(aws.String("mock message"), nil)
+			defer snsSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostResetFailure(dbSvc, snsSvc, "111", resetResult, 3, "Topic")
+			dbSvc.AssertNotCalled(t, "TransitionAccountStatus", mock.Anything, mock.Anything, mock.Anything)
+			require.Nil(t, err)
+		})
+
+		t.Run("Should change account status from NotReady to ResetFailed after max attempts", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			defer dbSvc.AssertExpectations(t)
+
+			resetResult := db.ResetResult{Attempt: 3, Error: "test error"}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{ID: "111", AccountStatus: db.NotReady}, nil)
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.ResetFailed).
+				Return(&db.Account{ID: "111", AccountStatus: db.ResetFailed}, nil)
+
+			snsSvc.On("PublishMessage",
+				mock.Anything,
+				mock.MatchedBy(func(message *string) bool {
+					messageObj := unmarshal(t, *message)
+					msgBody := unmarshal(t, messageObj["Body"].(string))
+
+					assert.Equal(t, "ResetFailed", msgBody["AccountStatus"])
+
+					return true
+				}), true,
+			).Return(aws.String("mock message"), nil)
+			defer snsSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostResetFailure(dbSvc, snsSvc, "111", resetResult, 3, "Topic")
+			require.Nil(t, err)
+		})
+
+		t.Run("Should not change account status of Leased accounts", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+
+			resetResult := db.ResetResult{Attempt: 3, Error: "test error"}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{ID: "111", AccountStatus: db.Leased}, nil)
+			dbSvc.
+				On("TransitionAccountStatus", "111", db.NotReady, db.ResetFailed).
+				Return(nil, &db.StatusTransitionError{})
+			snsSvc.On("PublishMessage", mock.Anything, mock.Anything, true).
+				Return(aws.String("mock message"), nil)
+
+			err := reset.UpdateDBPostResetFailure(dbSvc, snsSvc, "111", resetResult, 3, "Topic")
+			require.Nil(t, err)
+		})
+
+		t.Run("Should handle DB errors (UpdateAccountResetResult)", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+
+			resetResult := db.ResetResult{Attempt: 1, Error: "test error"}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(nil, errors.New("test error"))
+
+			err := reset.UpdateDBPostResetFailure(dbSvc, snsSvc, "111", resetResult, 3, "Topic")
+			require.Equal(t, errors.New("test error"), err)
+			snsSvc.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("UpdateDBPostVerifyFailure", func(t *testing.T) {
+
+		t.Run("Should orphan the account", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+			defer dbSvc.AssertExpectations(t)
+
+			resetResult := db.ResetResult{
+				Attempt:           1,
+				Succeeded:         true,
+				VerificationError: "test error",
+			}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{ID: "111", AccountStatus: db.NotReady}, nil)
+			dbSvc.
+				On("OrphanAccount", "111").
+				Return(&db.Account{
+					ID:              "111",
+					AccountStatus:   db.Orphaned,
+					LastResetResult: &resetResult,
+				}, nil)
+
+			snsSvc.On("PublishMessage",
+				mock.Anything,
+				mock.MatchedBy(func(message *string) bool {
+					messageObj := unmarshal(t, *message)
+					msgBody := unmarshal(t, messageObj["Body"].(string))
+
+					assert.Equal(t, "Orphaned", msgBody["AccountStatus"])
+					assert.Equal(t, "test error", msgBody["LastResetResult"].(map[string]interface{})["VerificationError"])
+
+					return true
+				}), true,
+			).Return(aws.String("mock message"), nil)
+			defer snsSvc.AssertExpectations(t)
+
+			err := reset.UpdateDBPostVerifyFailure(dbSvc, snsSvc, "111", resetResult, "Topic")
+			require.Nil(t, err)
+		})
+
+		t.Run("Should handle DB errors (OrphanAccount)", func(t *testing.T) {
+			dbSvc := &mocks.DBer{}
+			snsSvc := &commonMocks.Notificationer{}
+
+			resetResult := db.ResetResult{Attempt: 1, Succeeded: true, VerificationError: "test error"}
+			dbSvc.
+				On("UpdateAccountResetResult", "111", resetResult).
+				Return(&db.Account{ID: "111", AccountStatus: db.NotReady}, nil)
+			dbSvc.
+				On("OrphanAccount", "111").
+				Return(nil, errors.New("test error"))
+
+			err := reset.UpdateDBPostVerifyFailure(dbSvc, snsSvc, "111", resetResult, "Topic")
+			require.Equal(t, errors.New("test error"), err)
+			snsSvc.AssertNotCalled(t, "PublishMessage", mock.Anything, mock.Anything, mock.Anything)
+		})
+	})
+
+	t.Run("NextResetAttempt", func(t *testing.T) {
+		// Accounts that have never been reset
+		assert.Equal(t, 1, reset.NextResetAttempt(&db.Account{}))
+		// Accounts that were reset successfully
+		assert.Equal(t, 1, reset.NextResetAttempt(&db.Account{
+			LastResetResult: &db.ResetResult{Attempt: 2, Succeeded: true},
+		}))
+		// Accounts that failed to reset
+		assert.Equal(t, 3, reset.NextResetAttempt(&db.Account{
+			LastResetResult: &db.ResetResult{Attempt: 2},
+		}))
+	})
+
+	t.Run("NukeConfigOverrides", func(t *testing.T) {
+
+		t.Run("Should combine account metadata with the S3 overrides file", func(t *testing.T) {
+			storageSvc := &commonMocks.Storager{}
+			storageSvc.
+				On("GetObject", "artifacts", "overrides/ABC123.yml").
+				Return("regions: [ap-south-1]\nfilters:\n  IAMRole:\n    - value: keep\n", nil)
+			job := &reset.Job{
+				Config:  &reset.JobConfig{NukeOverridesBucket: "artifacts"},
+				Storage: storageSvc,
+			}
+
+			overrides, err := job.NukeConfigOverrides(&db.Account{
+				ID: "ABC123",
+				Metadata: unmarshal(t, `{
+					"nukeRegions": ["eu-west-1"],
+					"nukeConfigKey": "overrides/ABC123.yml"
+				}`),
+			})
+			require.Nil(t, err)
+			assert.Equal(t, &reset.NukeConfigOverrides{
+				Regions: []string{"eu-west-1", "ap-south-1"},
+				Filters: map[string][]reset.NukeFilter{
+					"IAMRole": {{Value: "keep"}},
+				},
+			}, overrides)
+		})
+
+		t.Run("Should not read S3 without a nukeConfigKey", func(t *testing.T) {
+			storageSvc := &commonMocks.Storager{}
+			job := &reset.Job{
+				Config:  &reset.JobConfig{NukeOverridesBucket: "artifacts"},
+				Storage: storageSvc,
+			}
+
+			overrides, err := job.NukeConfigOverrides(&db.Account{ID: "ABC123"})
+			require.Nil(t, err)
+			assert.Equal(t, &reset.NukeConfigOverrides{}, overrides)
+			storageSvc.AssertNotCalled(t, "GetObject", mock.Anything, mock.Anything)
+		})
+
+		t.Run("Should handle S3 errors", func(t *testing.T) {
+			storageSvc := &commonMocks.Storager{}
+			storageSvc.
+				On("GetObject", "artifacts", "overrides/ABC123.yml").
+				Return("", errors.New("test error"))
+			job := &reset.Job{
+				Config:  &reset.JobConfig{NukeOverridesBucket: "artifacts"},
+				Storage: storageSvc,
+			}
+
+			_, err := job.NukeConfigOverrides(&db.Account{
+				ID:       "ABC123",
+				Metadata: map[string]interface{}{"nukeConfigKey": "overrides/ABC123.yml"},
+			})
+			require.NotNil(t, err)
+			assert.Equal(t, "Failed to get nuke config overrides at s3://artifacts/overrides/ABC123.yml: test error", err.Error())
+		})
+	})
+
+	t.Run("testNukeConfigGeneration", func(t *testing.T) {
+
+		var b bytes.Buffer
+		job := &reset.Job{
+			Config: &reset.JobConfig{
+				ParentAccountID:     "DEF456",
+				ChildAccountID:      "ABC123",
+				AdminRoleName:       "AdminRole",
+				Regions:             []string{"us-east-1", "us-west-1"},
+				PrincipalRoleName:   "PrincipalRole",
+				PrincipalPolicyName: "PrincipalPolicy",
+				NukeTemplateDefault: "../../cmd/codebuild/reset/default-nuke-config-template.yml",
+				NukeTemplateBucket:  "STUB",
+				NukeTemplateKey:     "STUB",
+			},
+		}
+
+		err := job.GenerateNukeConfig(&b)
+		assert.NoError(t, err)
+
+		got := b.String()
+		want := "regions:\n  - \"global\"\n  # DCE Principals roles are currently locked down\n  # to only access these two regions\n  # This significantly reduces the run time of nuke.\n  - \"us-east-1\"\n  - \"us-west-1\"\n\naccount-blacklist:\n  - \"DEF456\" # Arbitrary production account id\n\nresource-types:\n  excludes:\n    - S3Object # Let the S3Bucket delete all Objects instead of individual objects (optimization)\n\naccounts:\n  \"ABC123\": # Child Account\n    filters:\n      IAMPolicy:\n        - type: \"contains\"\n          value: \"PrincipalPolicy\"\n      IAMRole:\n        - \"AdminRole\"\n        - \"PrincipalRole\"\n      IAMRolePolicy:\n        - type: \"contains\"\n          value: \"AdminRole\"\n        - type: \"contains\"\n          value: \"PrincipalRole\"\n        - type: \"contains\"\n          value: \"PrincipalPolicy\"\n      IAMRolePolicyAttachment:\n        # Do not remove the policy from the principal user role\n        - \"PrincipalRole -> PrincipalPolicy\"\n        - property: RoleName\n          value: \"AdminRole\"\n"
+		assert.Equal(t, got, want, "Template subsitition works")
+	})
+}
+
+func unmarshal(t *testing.T, jsonStr string) map[string]interface{} {
+	var data map[string]interface{}
+	err := json.Unmarshal([]byte(jsonStr), &data)
+	require.Nil(t, err,
+		fmt.Sprintf("Failed to unmarshal JSON: %s; %s", jsonStr, err),
+	)
+
+	return data
+}
@@ -0,0 +1,236 @@
+package reset
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Account metadata keys, which customize the aws-nuke configuration for the account
+const (
+	// Regions to nuke, in addition to the configured regions. eg. ["eu-west-1"]
+	MetadataNukeRegions = "nukeRegions"
+	// Filters for resources to keep, by aws-nuke resource type. eg. {"S3Bucket": [{"value": "shared-bucket"}]}
+	MetadataNukeFilters = "nukeFilters"
+	// S3 key of a YAML file with regions and filters for the account
+	MetadataNukeConfigKey = "nukeConfigKey"
+)
+
+// NukeConfigOverrides customizes the aws-nuke configuration for an account.
+// Overrides are merged into the configuration rendered from the nuke template.
+type NukeConfigOverrides struct {
+	Regions []string                `json:"regions,omitempty" yaml:"regions,omitempty"`
+	Filters map[string][]NukeFilter `json:"filters,omitempty" yaml:"filters,omitempty"`
+}
+
+// NukeFilter keeps resources which match the filter.
+// See https://github.com/rebuy-de/aws-nuke#filtering-resources
+type NukeFilter struct {
+	Type     string `json:"type,omitempty" yaml:"type,omitempty"`
+	Property string `json:"property,omitempty" yaml:"property,omitempty"`
+	Value    string `json:"value" yaml:"value"`
+	Invert   string `json:"invert,omitempty" yaml:"invert,omitempty"`
+}
+
+// Add merges other overrides into the overrides
+func (o *NukeConfigOverrides) Add(other *NukeConfigOverrides) {
+	if other == nil {
+		return
+	}
+	o.Regions = appendUnique(o.Regions, other.Regions...)
+	for resourceType, filters := range other.Filters {
+		if o.Filters == nil {
+			o.Filters = map[string][]NukeFilter{}
+		}
+		o.Filters[resourceType] = append(o.Filters[resourceType], filters...)
+	}
+}
+
+// NukeConfigOverridesFromMetadata reads the regions and filters from account metadata
+func NukeConfigOverridesFromMetadata(metadata map[string]interface{}) (*NukeConfigOverrides, error) {
+	overrides := &NukeConfigOverrides{}
+
+	// Metadata is loosely typed, so convert it through JSON
+	metadataOverrides := map[string]interface{}{}
+	if regions, ok := metadata[MetadataNukeRegions]; ok {
+		metadataOverrides["regions"] = regions
+	}
+	if filters, ok := metadata[MetadataNukeFilters]; ok {
+		metadataOverrides["filters"] = filters
+	}
+	metadataJSON, err := json.Marshal(metadataOverrides)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(metadataJSON, overrides)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid %s or %s account metadata", MetadataNukeRegions, MetadataNukeFilters)
+	}
+
+	return overrides, nil
+}
+
+// NukeConfigOverridesFromYAML reads the regions and filters from a YAML file
+func NukeConfigOverridesFromYAML(overridesYAML []byte) (*NukeConfigOverrides, error) {
+	overrides := &NukeConfigOverrides{}
+	err := yaml.UnmarshalStrict(overridesYAML, overrides)
+	if err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// nukeConfig is the part of the aws-nuke configuration which may be overridden.
+// Other configuration is kept as is.
+type nukeConfig struct {
+	Regions          []string                     `yaml:"regions"`
+	AccountBlacklist []string                     `yaml:"account-blacklist"`
+	Accounts         map[string]nukeAccountConfig `yaml:"accounts"`
+	Other            map[string]interface{}       `yaml:",inline"`
+}
+
+type nukeAccountConfig struct {
+	// Filters may be a string, to match the resource's ID, or a NukeFilter
+	Filters map[string][]interface{} `yaml:"filters,omitempty"`
+	Other   map[string]interface{}   `yaml:",inline"`
+}
+
+// MergeNukeConfig adds the overrides to the account in an aws-nuke YAML configuration
+func MergeNukeConfig(nukeConfigYAML []byte, accountID string, overrides *NukeConfigOverrides) ([]byte, error) {
+	// Keep the rendered config as is (with its comments), if there's nothing to add
+	if overrides == nil || (len(overrides.Regions) == 0 && len(overrides.Filters) == 0) {
+		return nukeConfigYAML, nil
+	}
+
+	config := &nukeConfig{}
+	err := yaml.Unmarshal(nukeConfigYAML, config)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse nuke config")
+	}
+
+	config.Regions = appendUnique(config.Regions, overrides.Regions...)
+
+	if len(overrides.Filters) > 0 {
+		if config.Accounts == nil {
+			config.Accounts = map[string]nukeAccountConfig{}
+		}
+		account := config.Accounts[accountID]
+		if account.Filters == nil {
+			account.Filters = map[string][]interface{}{}
+		}
+		for resourceType, filters := range overrides.Filters {
+			for _, filter := range filters {
+				account.Filters[resourceType] = append(account.Filters[resourceType], filter)
+			}
+		}
+		config.Accounts[accountID] = account
+	}
+
+	return yaml.Marshal(config)
+}
+
+// aws-nuke filter types
+var nukeFilterTypes = map[string]bool{
+	"":              true,
+	"exact":         true,
+	"contains":      true,
+	"glob":          true,
+	"regex":         true,
+	"dateOlderThan": true,
+}
+
+var nukeRegion = regexp.MustCompile(`^(global|[a-z]{2}(-gov)?-[a-z]+-[0-9])$`)
+
+// ValidateNukeConfig checks that the aws-nuke YAML configuration is able to nuke the account
+func ValidateNukeConfig(nukeConfigYAML []byte, accountID string) error {
+	config := &nukeConfig{}
+	err := yaml.Unmarshal(nukeConfigYAML, config)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse nuke config")
+	}
+
+	if len(config.Regions) == 0 {
+		return fmt.Errorf("nuke config has no regions")
+	}
+	for _, region := range config.Regions {
+		if !nukeRegion.MatchString(region) {
+			return fmt.Errorf("nuke config has invalid region %q", region)
+		}
+	}
+
+	// aws-nuke refuses to run without a blacklist, to protect production accounts
+	if len(config.AccountBlacklist) == 0 {
+		return fmt.Errorf("nuke config has no account-blacklist")
+	}
+	for _, blacklisted := range config.AccountBlacklist {
+		if blacklisted == accountID {
+			return fmt.Errorf("nuke config account-blacklist includes account %s", accountID)
+		}
+	}
+
+	account, ok := config.Accounts[accountID]
+	if !ok {
+		return fmt.Errorf("nuke config has no accounts entry for account %s", accountID)
+	}
+	for resourceType, filters := range account.Filters {
+		for _, filter := range filters {
+			err := validateNukeFilter(filter)
+			if err != nil {
+				return errors.Wrapf(err, "nuke config has an invalid %s filter", resourceType)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateNukeFilter(filter interface{}) error {
+	// Strings match the resource's ID
+	if _, ok := filter.(string); ok {
+		return nil
+	}
+
+	filterYAML, err := yaml.Marshal(filter)
+	if err != nil {
+		return err
+	}
+	nukeFilter := &NukeFilter{}
+	err = yaml.UnmarshalStrict(filterYAML, nukeFilter)
+	if err != nil {
+		return err
+	}
+
+	if !nukeFilterTypes[nukeFilter.Type] {
+		return fmt.Errorf("unknown filter type %q", nukeFilter.Type)
+	}
+	if nukeFilter.Value == "" {
+		return fmt.Errorf("filter has no value")
+	}
+	if nukeFilter.Type == "regex" {
+		_, err := regexp.Compile(nukeFilter.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendUnique appends the values which aren't already in the list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
@@ -0,0 +1,218 @@
+package reset_test
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNukeConfig = `regions:
+  - "global"
+  - "us-east-1"
+
+account-blacklist:
+  - "DEF456"
+
+resource-types:
+  excludes:
+    - S3Object
+
+accounts:
+  "ABC123":
+    filters:
+      IAMRole:
+        - "AdminRole"
+      IAMRolePolicyAttachment:
+        - property: RoleName
+          value: "AdminRole"
+`
+
+func TestNukeConfigOverrides(t *testing.T) {
+
+	t.Run("should read overrides from account metadata", func(t *testing.T) {
+		overrides, err := reset.NukeConfigOverridesFromMetadata(unmarshal(t, `{
+			"nukeRegions": ["eu-west-1"],
+			"nukeFilters": {
+				"S3Bucket": [{"type": "glob", "value": "shared-*"}]
+			},
+			"costCenter": "123"
+		}`))
+		require.Nil(t, err)
+		assert.Equal(t, &reset.NukeConfigOverrides{
+			Regions: []string{"eu-west-1"},
+			Filters: map[string][]reset.NukeFilter{
+				"S3Bucket": {{Type: "glob", Value: "shared-*"}},
+			},
+		}, overrides)
+	})
+
+	t.Run("should fail on invalid account metadata", func(t *testing.T) {
+		_, err := reset.NukeConfigOverridesFromMetadata(unmarshal(t, `{
+			"nukeRegions": "eu-west-1"
+		}`))
+		require.NotNil(t, err)
+	})
+
+	t.Run("should read overrides from YAML", func(t *testing.T) {
+		overrides, err := reset.NukeConfigOverridesFromYAML([]byte(`
+regions:
+  - ap-south-1
+filters:
+  IAMRole:
+    - value: keep
+`))
+		require.Nil(t, err)
+		assert.Equal(t, &reset.NukeConfigOverrides{
+			Regions: []string{"ap-south-1"},
+			Filters: map[string][]reset.NukeFilter{
+				"IAMRole": {{Value: "keep"}},
+			},
+		}, overrides)
+	})
+
+	t.Run("should fail on unknown YAML fields", func(t *testing.T) {
+		_, err := reset.NukeConfigOverridesFromYAML([]byte(`account-blacklist: ["ABC123"]`))
+		require.NotNil(t, err)
+	})
+
+	t.Run("should add overrides", func(t *testing.T) {
+		overrides := &reset.NukeConfigOverrides{
+			Regions: []string{"eu-west-1"},
+		}
+		overrides.Add(&reset.NukeConfigOverrides{
+			Regions: []string{"eu-west-1", "ap-south-1"},
+			Filters: map[string][]reset.NukeFilter{
+				"IAMRole": {{Value: "keep"}},
+			},
+		})
+		assert.Equal(t, &reset.NukeConfigOverrides{
+			Regions: []string{"eu-west-1", "ap-south-1"},
+			Filters: map[string][]reset.NukeFilter{
+				"IAMRole": {{Value: "keep"}},
+			},
+		}, overrides)
+	})
+}
+
+func TestMergeNukeConfig(t *testing.T) {
+
+	t.Run("should add regions and filters to the account", func(t *testing.T) {
+		merged, err := reset.MergeNukeConfig([]byte(testNukeConfig), "ABC123", &reset.NukeConfigOverrides{
+			Regions: []string{"us-east-1", "eu-west-1"},
+			Filters: map[string][]reset.NukeFilter{
+				"IAMRole":  {{Value: "keep"}},
+				"S3Bucket": {{Type: "glob", Value: "shared-*"}},
+			},
+		})
+		require.Nil(t, err)
+		assert.Equal(t, `regions:
+- global
+- us-east-1
+- eu-west-1
+account-blacklist:
+- DEF456
+accounts:
+  ABC123:
+    filters:
+      IAMRole:
+      - AdminRole
+      - value: keep
+      IAMRolePolicyAttachment:
+      - property: RoleName
+        value: AdminRole
+      S3Bucket:
+      - type: glob
+        value: shared-*
+resource-types:
+  excludes:
+  - S3Object
+`, string(merged))
+	})
+
+	t.Run("should keep the config as is, without overrides", func(t *testing.T) {
+		merged, err := reset.MergeNukeConfig([]byte(testNukeConfig), "ABC123", &reset.NukeConfigOverrides{})
+		require.Nil(t, err)
+		assert.Equal(t, testNukeConfig, string(merged))
+	})
+
+	t.Run("should fail on invalid YAML", func(t *testing.T) {
+		_, err := reset.MergeNukeConfig([]byte("regions: ["), "ABC123", &reset.NukeConfigOverrides{
+			Regions: []string{"eu-west-1"},
+		})
+		require.NotNil(t, err)
+	})
+}
+
+func TestValidateNukeConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID string
+		overrides *reset.NukeConfigOverrides
+		expErr    string
+	}{
+		{
+			name:      "valid config",
+			accountID: "ABC123",
+			overrides: &reset.NukeConfigOverrides{},
+		},
+		{
+			name:      "invalid region",
+			accountID: "ABC123",
+			overrides: &reset.NukeConfigOverrides{Regions: []string{"mars-1"}},
+			expErr:    `nuke config has invalid region "mars-1"`,
+		},
+		{
+			name:      "blacklisted account",
+			accountID: "DEF456",
+			overrides: &reset.NukeConfigOverrides{},
+			expErr:    "nuke config account-blacklist includes account DEF456",
+		},
+		{
+			name:      "missing account",
+			accountID: "GHI789",
+			overrides: &reset.NukeConfigOverrides{},
+			expErr:    "nuke config has no accounts entry for account GHI789",
+		},
+		{
+			name:      "unknown filter type",
+			accountID: "ABC123",
+			overrides: &reset.NukeConfigOverrides{Filters: map[string][]reset.NukeFilter{
+				"S3Bucket": {{Type: "prefix", Value: "shared-"}},
+			}},
+			expErr: `nuke config has an invalid S3Bucket filter: unknown filter type "prefix"`,
+		},
+		{
+			name:      "empty filter",
+			accountID: "ABC123",
+			overrides: &reset.NukeConfigOverrides{Filters: map[string][]reset.NukeFilter{
+				"S3Bucket": {{Type: "glob"}},
+			}},
+			expErr: "nuke config has an invalid S3Bucket filter: filter has no value",
+		},
+		{
+			name:      "invalid regex filter",
+			accountID: "ABC123",
+			overrides: &reset.NukeConfigOverrides{Filters: map[string][]reset.NukeFilter{
+				"S3Bucket": {{Type: "regex", Value: "("}},
+			}},
+			expErr: "nuke config has an invalid S3Bucket filter: error parsing regexp: missing closing ): `(`",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := reset.MergeNukeConfig([]byte(testNukeConfig), "ABC123", tt.overrides)
+			require.Nil(t, err)
+
+			err = reset.ValidateNukeConfig(merged, tt.accountID)
+			if tt.expErr == "" {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+				assert.Equal(t, tt.expErr, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package reset
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Optum/dce/pkg/common"
+	"github.com/pkg/errors"
+)
+
+// Actions aws-nuke takes on a resource
+const (
+	ReportActionRemove = "Remove"
+	ReportActionFilter = "Filter"
+)
+
+// Report lists every resource aws-nuke would delete or filter out
+// of an account, during a dry run reset
+type Report struct {
+	AccountID string            `json:"accountId"`
+	CreatedOn int64             `json:"createdOn"`
+	Resources []*ReportResource `json:"resources"`
+}
+
+// ReportResource is a resource found by aws-nuke
+type ReportResource struct {
+	Region       string `json:"region"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId,omitempty"`
+	Properties   string `json:"properties,omitempty"`
+	Action       string `json:"action"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// aws-nuke prints each resource as "<region> - <type> - <id> - <properties> - <message>",
+// where the ID and properties are optional
+var (
+	reportLineRegion = regexp.MustCompile(`^[a-z0-9-]+$`)
+	reportLineType   = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+	// aws-nuke colors its output when run in a terminal
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+// ParseNukeOutput reads the resources from the output of an aws-nuke dry run.
+// Lines which aren't resources are skipped.
+func ParseNukeOutput(output io.Reader) ([]*ReportResource, error) {
+	resources := []*ReportResource{}
+
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		resource := parseNukeOutputLine(ansiEscape.ReplaceAllString(scanner.Text(), ""))
+		if resource != nil {
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, scanner.Err()
+}
+
+func parseNukeOutputLine(line string) *ReportResource {
+	fields := strings.Split(strings.TrimSpace(line), " - ")
+	if len(fields) < 3 {
+		return nil
+	}
+	if !reportLineRegion.MatchString(fields[0]) || !reportLineType.MatchString(fields[1]) {
+		return nil
+	}
+
+	resource := &ReportResource{
+		Region:       fields[0],
+		ResourceType: fields[1],
+	}
+
+	message := fields[len(fields)-1]
+	switch message {
+	case "would remove":
+		resource.Action = ReportActionRemove
+	// Only a dry run is reported, so skip the progress of a real run
+	case "triggered remove", "waiting", "removed", "failed":
+		return nil
+	default:
+		resource.Action = ReportActionFilter
+		resource.Reason = message
+	}
+
+	// The ID and properties are both optional.
+	// Properties are printed in brackets, eg. [Name: "my-bucket"]
+	middle := fields[2 : len(fields)-1]
+	for i, field := range middle {
+		if strings.HasPrefix(field, "[") && strings.HasSuffix(middle[len(middle)-1], "]") {
+			resource.Properties = strings.Join(middle[i:], " - ")
+			middle = middle[:i]
+			break
+		}
+	}
+	resource.ResourceID = strings.Join(middle, " - ")
+
+	return resource
+}
+
+// WriteJSON writes the report as JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// WriteCSV writes the report as CSV, with a row for each resource
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write([]string{"region", "resourceType", "resourceId", "properties", "action", "reason"})
+	if err != nil {
+		return err
+	}
+	for _, resource := range r.Resources {
+		err = writer.Write([]string{
+			resource.Region,
+			resource.ResourceType,
+			resource.ResourceID,
+			resource.Properties,
+			resource.Action,
+			resource.Reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReportKey is the S3 key of the report for the account,
+// for the reset at the given time, with the given file extension
+func ReportKey(accountID string, createdOn time.Time, extension string) string {
+	return fmt.Sprintf("reset-reports/%s/%s.%s", accountID, createdOn.UTC().Format("20060102T150405Z"), extension)
+}
+
+// captureStdout runs fn, and returns everything it printed to stdout.
+// aws-nuke prints its results straight to the stdout file descriptor,
+// so the descriptor is redirected to a pipe while fn runs.
+// The output is still copied to stdout, for the logs.
+func captureStdout(fn func() error) ([]byte, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	stdout, err := syscall.Dup(syscall.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	stdoutFile := os.NewFile(uintptr(stdout), "stdout")
+	defer stdoutFile.Close()
+
+	err = syscall.Dup2(int(writer.Fd()), syscall.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &bytes.Buffer{}
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(io.MultiWriter(output, stdoutFile), reader)
+		copied <- err
+	}()
+
+	fnErr := fn()
+
+	// Restore stdout, and close the pipe so the copy can finish
+	err = syscall.Dup2(stdout, syscall.Stdout)
+	_ = writer.Close()
+	copyErr := <-copied
+	if err != nil {
+		return output.Bytes(), err
+	}
+	if copyErr != nil {
+		return output.Bytes(), copyErr
+	}
+	return output.Bytes(), fnErr
+}
+
+// UploadReport uploads the report of an aws-nuke dry run, as JSON and CSV.
+// Returns the S3 keys of the JSON and CSV reports
+func UploadReport(storager common.Storager, bucket string, accountID string, nukeOutput []byte, createdOn time.Time) (string, string, error) {
+	resources, err := ParseNukeOutput(bytes.NewReader(nukeOutput))
+	if err != nil {
+		return "", "", errors.Wrap(err, "Failed to parse aws-nuke output")
+	}
+	report := &Report{
+		AccountID: accountID,
+		CreatedOn: createdOn.Unix(),
+		Resources: resources,
+	}
+
+	jsonKey := ReportKey(accountID, createdOn, "json")
+	err = uploadReportFile(storager, bucket, jsonKey, report.WriteJSON)
+	if err != nil {
+		return "", "", err
+	}
+
+	csvKey := ReportKey(accountID, createdOn, "csv")
+	err = uploadReportFile(storager, bucket, csvKey, report.WriteCSV)
+	if err != nil {
+		return "", "", err
+	}
+
+	log.Printf("Uploaded reset report with %d resources to s3://%s/%s", len(resources), bucket, jsonKey)
+	return jsonKey, csvKey, nil
+}
+
+// uploadReportFile writes the report to a file, and uploads it to S3
+func uploadReportFile(storager common.Storager, bucket string, key string, write func(w io.Writer) error) error {
+	reportFile := fmt.Sprintf("/tmp/reset-report-%s", path.Base(key))
+	f, err := os.Create(reportFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = write(f)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to write reset report %s", reportFile)
+	}
+
+	err = storager.Upload(bucket, key, reportFile)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to upload reset report to s3://%s/%s", bucket, key)
+	}
+	return nil
+}
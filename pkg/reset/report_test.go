@@ -0,0 +1,113 @@
+package reset_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNukeOutput(t *testing.T) {
+	output := `aws-nuke version v2.12.0
+Scanning account 123456789012
+us-east-1 - EC2Instance - i-0123456789abcdef0 - [Identifier: "i-0123456789abcdef0", tag:Name: "test"] - would remove
+us-east-1 - IAMRole - DCEPrincipal - filtered by config
+global - S3Bucket - [Name: "my-bucket - logs"] - would remove
+us-west-1 - EC2VPC - vpc-123 - [IsDefault: "true"] - filtered by config
+` + "\x1b[32mus-west-1\x1b[0m - CloudWatchLogsLogGroup - /aws/lambda/test - would remove" + `
+Scan complete: 4 total, 3 nukeable, 1 filtered.
+
+The above resources would be deleted with the supplied configuration. Provide --no-dry-run to actually destroy resources.
+`
+
+	resources, err := reset.ParseNukeOutput(strings.NewReader(output))
+	require.Nil(t, err)
+	assert.Equal(t, []*reset.ReportResource{
+		{
+			Region:       "us-east-1",
+			ResourceType: "EC2Instance",
+			ResourceID:   "i-0123456789abcdef0",
+			Properties:   `[Identifier: "i-0123456789abcdef0", tag:Name: "test"]`,
+			Action:       reset.ReportActionRemove,
+		},
+		{
+			Region:       "us-east-1",
+			ResourceType: "IAMRole",
+			ResourceID:   "DCEPrincipal",
+			Action:       reset.ReportActionFilter,
+			Reason:       "filtered by config",
+		},
+		{
+			Region:       "global",
+			ResourceType: "S3Bucket",
+			Properties:   `[Name: "my-bucket - logs"]`,
+			Action:       reset.ReportActionRemove,
+		},
+		{
+			Region:       "us-west-1",
+			ResourceType: "EC2VPC",
+			ResourceID:   "vpc-123",
+			Properties:   `[IsDefault: "true"]`,
+			Action:       reset.ReportActionFilter,
+			Reason:       "filtered by config",
+		},
+		{
+			Region:       "us-west-1",
+			ResourceType: "CloudWatchLogsLogGroup",
+			ResourceID:   "/aws/lambda/test",
+			Action:       reset.ReportActionRemove,
+		},
+	}, resources)
+}
+
+func TestReport(t *testing.T) {
+	report := &reset.Report{
+		AccountID: "123456789012",
+		CreatedOn: 1577836800,
+		Resources: []*reset.ReportResource{
+			{
+				Region:       "us-east-1",
+				ResourceType: "EC2VPC",
+				ResourceID:   "vpc-123",
+				Properties:   `[IsDefault: "true"]`,
+				Action:       reset.ReportActionFilter,
+				Reason:       "filtered by config",
+			},
+		},
+	}
+
+	t.Run("should write JSON", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := report.WriteJSON(buf)
+		require.Nil(t, err)
+		assert.JSONEq(t, `{
+			"accountId": "123456789012",
+			"createdOn": 1577836800,
+			"resources": [{
+				"region": "us-east-1",
+				"resourceType": "EC2VPC",
+				"resourceId": "vpc-123",
+				"properties": "[IsDefault: \"true\"]",
+				"action": "Filter",
+				"reason": "filtered by config"
+			}]
+		}`, buf.String())
+	})
+
+	t.Run("should write CSV", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := report.WriteCSV(buf)
+		require.Nil(t, err)
+		assert.Equal(t, "region,resourceType,resourceId,properties,action,reason\n"+
+			`us-east-1,EC2VPC,vpc-123,"[IsDefault: ""true""]",Filter,filtered by config`+"\n", buf.String())
+	})
+
+	t.Run("should key reports by account and time", func(t *testing.T) {
+		assert.Equal(t, "reset-reports/123456789012/20200101T000000Z.csv",
+			reset.ReportKey("123456789012", time.Unix(1577836800, 0), "csv"))
+	})
+}
@@ -0,0 +1,101 @@
+package reset
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the reset steps, used to enable steps in configuration
+const (
+	StepAthena             = "Athena"
+	StepS3VersionedBuckets = "S3VersionedBuckets"
+	StepServiceCatalog     = "ServiceCatalog"
+	StepRoute53Domains     = "Route53Domains"
+	StepAwsNuke            = "AwsNuke"
+)
+
+//go:generate mockery -name Resetter
+
+// Resetter is a step of an account reset, which deletes
+// a type of resource from the account
+type Resetter interface {
+	// Name of the step, used to enable the step in configuration
+	Name() string
+	// Reset deletes the step's resources from the account.
+	// Returns the resources that were deleted, and any resources that failed to delete
+	Reset() (*Resources, error)
+}
+
+// Registry is an ordered list of reset steps.
+// Only the enabled steps are run when the account is reset.
+type Registry struct {
+	steps   []Resetter
+	enabled map[string]bool
+}
+
+// NewRegistry creates a Registry, which runs the named steps
+func NewRegistry(enabledSteps []string) *Registry {
+	enabled := map[string]bool{}
+	for _, name := range enabledSteps {
+		enabled[name] = true
+	}
+	return &Registry{
+		enabled: enabled,
+	}
+}
+
+// Register adds a step to the end of the registry.
+// A step may be registered more than once, eg. for each AWS region
+func (r *Registry) Register(step Resetter) {
+	r.steps = append(r.steps, step)
+}
+
+// Steps returns the enabled steps, in the order they will run
+func (r *Registry) Steps() []Resetter {
+	steps := []Resetter{}
+	for _, step := range r.steps {
+		if r.enabled[step.Name()] {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// Reset runs each enabled step in order, and stops at the first step that fails.
+// Returns the resources deleted by all of the steps, and any resources that failed to delete
+func (r *Registry) Reset() (*Resources, error) {
+	resources := &Resources{}
+
+	err := r.validate()
+	if err != nil {
+		return resources, err
+	}
+
+	for _, step := range r.Steps() {
+		log.Printf("Starting %s reset step", step.Name())
+		stepResources, err := step.Reset()
+		resources.Add(stepResources)
+		if err != nil {
+			return resources, errors.Wrapf(err, "Failed to reset %s", step.Name())
+		}
+	}
+
+	return resources, nil
+}
+
+// validate that each enabled step has been registered,
+// so misspelled steps aren't silently skipped
+func (r *Registry) validate() error {
+	registered := map[string]bool{}
+	for _, step := range r.steps {
+		registered[step.Name()] = true
+	}
+	for name := range r.enabled {
+		if !registered[name] {
+			return fmt.Errorf("unknown reset step %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package reset_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/Optum/dce/pkg/reset/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMockStep(name string, resources *reset.Resources, err error) *mocks.Resetter {
+	step := &mocks.Resetter{}
+	step.On("Name").Return(name)
+	step.On("Reset").Return(resources, err)
+	return step
+}
+
+func TestRegistry(t *testing.T) {
+
+	t.Run("should run enabled steps in order", func(t *testing.T) {
+		athena := newMockStep(reset.StepAthena, &reset.Resources{Deleted: []string{"AthenaWorkGroup - wg1"}}, nil)
+		s3 := newMockStep(reset.StepS3VersionedBuckets, &reset.Resources{}, nil)
+		nuke := newMockStep(reset.StepAwsNuke, &reset.Resources{Deleted: []string{"EC2Instance - i-123"}}, nil)
+
+		registry := reset.NewRegistry([]string{reset.StepAwsNuke, reset.StepAthena})
+		registry.Register(athena)
+		registry.Register(s3)
+		registry.Register(nuke)
+
+		assert.Equal(t, []reset.Resetter{athena, nuke}, registry.Steps())
+
+		resources, err := registry.Reset()
+		require.Nil(t, err)
+		assert.Equal(t, &reset.Resources{
+			Deleted: []string{"AthenaWorkGroup - wg1", "EC2Instance - i-123"},
+		}, resources)
+		athena.AssertCalled(t, "Reset")
+		nuke.AssertCalled(t, "Reset")
+		s3.AssertNotCalled(t, "Reset")
+	})
+
+	t.Run("should stop at the first step that fails", func(t *testing.T) {
+		athena := newMockStep(reset.StepAthena, &reset.Resources{
+			Deleted: []string{"AthenaWorkGroup - wg1"},
+			Failed:  []string{"AthenaNamedQuery - query1"},
+		}, errors.New("test error"))
+		nuke := newMockStep(reset.StepAwsNuke, &reset.Resources{}, nil)
+
+		registry := reset.NewRegistry([]string{reset.StepAthena, reset.StepAwsNuke})
+		registry.Register(athena)
+		registry.Register(nuke)
+
+		resources, err := registry.Reset()
+		require.NotNil(t, err)
+		assert.Equal(t, "Failed to reset Athena: test error", err.Error())
+		assert.Equal(t, []string{"AthenaNamedQuery - query1"}, resources.Failed)
+		nuke.AssertNotCalled(t, "Reset")
+	})
+
+	t.Run("should fail on unknown steps", func(t *testing.T) {
+		nuke := newMockStep(reset.StepAwsNuke, &reset.Resources{}, nil)
+
+		registry := reset.NewRegistry([]string{"Athenaa", reset.StepAwsNuke})
+		registry.Register(nuke)
+
+		_, err := registry.Reset()
+		assert.Equal(t, "unknown reset step \"Athenaa\"", err.Error())
+		nuke.AssertNotCalled(t, "Reset")
+	})
+}
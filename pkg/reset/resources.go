@@ -0,0 +1,17 @@
+package reset
+
+// Resources lists the resources deleted by a reset,
+// and the resources it failed to delete
+type Resources struct {
+	Deleted []string
+	Failed  []string
+}
+
+// Add the resources from another step of the reset
+func (r *Resources) Add(other *Resources) {
+	if other == nil {
+		return
+	}
+	r.Deleted = append(r.Deleted, other.Deleted...)
+	r.Failed = append(r.Failed, other.Failed...)
+}
@@ -0,0 +1,62 @@
+package reset
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53domains"
+)
+
+//go:generate mockery -name Route53DomainsService
+
+// Route53DomainsService interface
+type Route53DomainsService interface {
+	ListDomains(input *route53domains.ListDomainsInput) (*route53domains.ListDomainsOutput, error)
+	DisableDomainAutoRenew(input *route53domains.DisableDomainAutoRenewInput) (*route53domains.DisableDomainAutoRenewOutput, error)
+}
+
+// Route53DomainsResetter is the reset step for domains registered with Route53.
+// Registered domains can't be deleted, so the step turns off auto-renew,
+// and the domains lapse when their registration expires.
+type Route53DomainsResetter struct {
+	Service Route53DomainsService
+}
+
+// Name of the reset step
+func (r *Route53DomainsResetter) Name() string {
+	return StepRoute53Domains
+}
+
+// Reset turns off auto-renew for each domain registered in the account
+func (r *Route53DomainsResetter) Reset() (*Resources, error) {
+	resources := &Resources{}
+
+	input := &route53domains.ListDomainsInput{}
+	for {
+		output, err := r.Service.ListDomains(input)
+		if err != nil {
+			return resources, err
+		}
+
+		for _, domain := range output.Domains {
+			if !aws.BoolValue(domain.AutoRenew) {
+				continue
+			}
+			resourceName := "Route53Domain - " + aws.StringValue(domain.DomainName)
+			log.Printf("Disabling auto-renew for Route53 domain %s", aws.StringValue(domain.DomainName))
+			_, err := r.Service.DisableDomainAutoRenew(&route53domains.DisableDomainAutoRenewInput{
+				DomainName: domain.DomainName,
+			})
+			if err != nil {
+				resources.Failed = append(resources.Failed, resourceName)
+				return resources, err
+			}
+			resources.Deleted = append(resources.Deleted, resourceName)
+		}
+
+		if aws.StringValue(output.NextPageMarker) == "" {
+			return resources, nil
+		}
+		input.Marker = output.NextPageMarker
+	}
+}
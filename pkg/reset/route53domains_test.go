@@ -0,0 +1,45 @@
+package reset_test
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/Optum/dce/pkg/reset/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/route53domains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoute53DomainsResetter(t *testing.T) {
+	domainsSvc := &mocks.Route53DomainsService{}
+	defer domainsSvc.AssertExpectations(t)
+
+	// Domains are paginated
+	domainsSvc.On("ListDomains", &route53domains.ListDomainsInput{}).
+		Return(&route53domains.ListDomainsOutput{
+			Domains: []*route53domains.DomainSummary{
+				{DomainName: aws.String("example.com"), AutoRenew: aws.Bool(true)},
+			},
+			NextPageMarker: aws.String("next"),
+		}, nil).Once()
+	domainsSvc.On("ListDomains", &route53domains.ListDomainsInput{Marker: aws.String("next")}).
+		Return(&route53domains.ListDomainsOutput{
+			Domains: []*route53domains.DomainSummary{
+				// Domains already set to lapse are left alone
+				{DomainName: aws.String("example.org"), AutoRenew: aws.Bool(false)},
+			},
+		}, nil).Once()
+	domainsSvc.On("DisableDomainAutoRenew", &route53domains.DisableDomainAutoRenewInput{
+		DomainName: aws.String("example.com"),
+	}).Return(&route53domains.DisableDomainAutoRenewOutput{}, nil)
+
+	resetter := &reset.Route53DomainsResetter{Service: domainsSvc}
+	assert.Equal(t, reset.StepRoute53Domains, resetter.Name())
+
+	resources, err := resetter.Reset()
+	require.Nil(t, err)
+	assert.Equal(t, &reset.Resources{
+		Deleted: []string{"Route53Domain - example.com"},
+	}, resources)
+}
@@ -0,0 +1,119 @@
+package reset
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Optum/dce/pkg/awsiface"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3VersionedBucketsResetter is the reset step which empties versioned S3 buckets,
+// so aws-nuke is able to delete them.
+// Buckets are only emptied in the client's region.
+type S3VersionedBucketsResetter struct {
+	Client awsiface.S3API
+	Region string
+}
+
+// Name of the reset step
+func (s *S3VersionedBucketsResetter) Name() string {
+	return StepS3VersionedBuckets
+}
+
+// Reset deletes every object version and delete marker
+// from the versioned buckets in the region
+func (s *S3VersionedBucketsResetter) Reset() (*Resources, error) {
+	resources := &Resources{}
+
+	buckets, err := s.Client.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return resources, err
+	}
+
+	for _, bucket := range buckets.Buckets {
+		location, err := s.Client.GetBucketLocation(&s3.GetBucketLocationInput{
+			Bucket: bucket.Name,
+		})
+		if err != nil {
+			return resources, err
+		}
+		if s3.NormalizeBucketLocation(aws.StringValue(location.LocationConstraint)) != s.Region {
+			continue
+		}
+
+		versioning, err := s.Client.GetBucketVersioning(&s3.GetBucketVersioningInput{
+			Bucket: bucket.Name,
+		})
+		if err != nil {
+			return resources, err
+		}
+		// Buckets with versioning suspended may still have object versions
+		if versioning.Status == nil {
+			continue
+		}
+
+		log.Printf("Emptying versioned S3 bucket %s", *bucket.Name)
+		err = s.emptyBucket(*bucket.Name, resources)
+		if err != nil {
+			return resources, err
+		}
+	}
+
+	return resources, nil
+}
+
+// emptyBucket deletes each page of object versions and delete markers in the bucket
+func (s *S3VersionedBucketsResetter) emptyBucket(bucket string, resources *Resources) error {
+	input := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+	}
+	deleted := 0
+	failed := 0
+	for {
+		versions, err := s.Client.ListObjectVersions(input)
+		if err != nil {
+			return err
+		}
+
+		objects := []*s3.ObjectIdentifier{}
+		for _, version := range versions.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range versions.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+
+		if len(objects) > 0 {
+			output, err := s.Client.DeleteObjects(&s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3.Delete{
+					Objects: objects,
+					Quiet:   aws.Bool(true),
+				},
+			})
+			if err != nil {
+				return err
+			}
+			deleted = deleted + len(objects) - len(output.Errors)
+			failed = failed + len(output.Errors)
+			for _, deleteErr := range output.Errors {
+				resources.Failed = append(resources.Failed, fmt.Sprintf("S3ObjectVersion - %s/%s (%s)",
+					bucket, aws.StringValue(deleteErr.Key), aws.StringValue(deleteErr.VersionId)))
+			}
+		}
+
+		if !aws.BoolValue(versions.IsTruncated) {
+			break
+		}
+		input.KeyMarker = versions.NextKeyMarker
+		input.VersionIdMarker = versions.NextVersionIdMarker
+	}
+
+	resources.Deleted = append(resources.Deleted, fmt.Sprintf("S3ObjectVersions - %s (%d versions)", bucket, deleted))
+	if failed > 0 {
+		return fmt.Errorf("failed to delete object versions from S3 bucket %s", bucket)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package reset_test
+
+import (
+	"testing"
+
+	awsMocks "github.com/Optum/dce/pkg/awsiface/mocks"
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3VersionedBucketsResetter(t *testing.T) {
+	s3Svc := &awsMocks.S3API{}
+	s3Svc.On("ListBuckets", &s3.ListBucketsInput{}).Return(&s3.ListBucketsOutput{
+		Buckets: []*s3.Bucket{
+			{Name: aws.String("versioned")},
+			{Name: aws.String("unversioned")},
+			{Name: aws.String("other-region")},
+		},
+	}, nil)
+
+	// us-east-1 buckets have no location constraint
+	s3Svc.On("GetBucketLocation", &s3.GetBucketLocationInput{Bucket: aws.String("versioned")}).
+		Return(&s3.GetBucketLocationOutput{}, nil)
+	s3Svc.On("GetBucketLocation", &s3.GetBucketLocationInput{Bucket: aws.String("unversioned")}).
+		Return(&s3.GetBucketLocationOutput{}, nil)
+	s3Svc.On("GetBucketLocation", &s3.GetBucketLocationInput{Bucket: aws.String("other-region")}).
+		Return(&s3.GetBucketLocationOutput{LocationConstraint: aws.String("us-west-2")}, nil)
+
+	s3Svc.On("GetBucketVersioning", &s3.GetBucketVersioningInput{Bucket: aws.String("versioned")}).
+		Return(&s3.GetBucketVersioningOutput{Status: aws.String("Enabled")}, nil)
+	s3Svc.On("GetBucketVersioning", &s3.GetBucketVersioningInput{Bucket: aws.String("unversioned")}).
+		Return(&s3.GetBucketVersioningOutput{}, nil)
+
+	// Object versions are paginated
+	s3Svc.On("ListObjectVersions", &s3.ListObjectVersionsInput{
+		Bucket: aws.String("versioned"),
+	}).Return(&s3.ListObjectVersionsOutput{
+		Versions: []*s3.ObjectVersion{
+			{Key: aws.String("a.txt"), VersionId: aws.String("1")},
+			{Key: aws.String("a.txt"), VersionId: aws.String("2")},
+		},
+		IsTruncated:         aws.Bool(true),
+		NextKeyMarker:       aws.String("a.txt"),
+		NextVersionIdMarker: aws.String("2"),
+	}, nil).Once()
+	s3Svc.On("ListObjectVersions", &s3.ListObjectVersionsInput{
+		Bucket:          aws.String("versioned"),
+		KeyMarker:       aws.String("a.txt"),
+		VersionIdMarker: aws.String("2"),
+	}).Return(&s3.ListObjectVersionsOutput{
+		DeleteMarkers: []*s3.DeleteMarkerEntry{
+			{Key: aws.String("b.txt"), VersionId: aws.String("3")},
+		},
+		IsTruncated: aws.Bool(false),
+	}, nil).Once()
+
+	s3Svc.On("DeleteObjects", &s3.DeleteObjectsInput{
+		Bucket: aws.String("versioned"),
+		Delete: &s3.Delete{
+			Objects: []*s3.ObjectIdentifier{
+				{Key: aws.String("a.txt"), VersionId: aws.String("1")},
+				{Key: aws.String("a.txt"), VersionId: aws.String("2")},
+			},
+			Quiet: aws.Bool(true),
+		},
+	}).Return(&s3.DeleteObjectsOutput{}, nil)
+	s3Svc.On("DeleteObjects", &s3.DeleteObjectsInput{
+		Bucket: aws.String("versioned"),
+		Delete: &s3.Delete{
+			Objects: []*s3.ObjectIdentifier{
+				{Key: aws.String("b.txt"), VersionId: aws.String("3")},
+			},
+			Quiet: aws.Bool(true),
+		},
+	}).Return(&s3.DeleteObjectsOutput{
+		Errors: []*s3.Error{
+			{Key: aws.String("b.txt"), VersionId: aws.String("3"), Code: aws.String("AccessDenied")},
+		},
+	}, nil)
+
+	resetter := &reset.S3VersionedBucketsResetter{
+		Client: s3Svc,
+		Region: "us-east-1",
+	}
+	assert.Equal(t, reset.StepS3VersionedBuckets, resetter.Name())
+
+	resources, err := resetter.Reset()
+	require.NotNil(t, err)
+	assert.Equal(t, "failed to delete object versions from S3 bucket versioned", err.Error())
+	assert.Equal(t, &reset.Resources{
+		Deleted: []string{"S3ObjectVersions - versioned (2 versions)"},
+		Failed:  []string{"S3ObjectVersion - versioned/b.txt (3)"},
+	}, resources)
+	s3Svc.AssertExpectations(t)
+	s3Svc.AssertNotCalled(t, "GetBucketVersioning", &s3.GetBucketVersioningInput{Bucket: aws.String("other-region")})
+}
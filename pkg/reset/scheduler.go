@@ -0,0 +1,185 @@
+package reset
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/pkg/errors"
+)
+
+// BuildService interface
+type BuildService interface {
+	ListBuildsForProject(input *codebuild.ListBuildsForProjectInput) (*codebuild.ListBuildsForProjectOutput, error)
+	BatchGetBuilds(input *codebuild.BatchGetBuildsInput) (*codebuild.BatchGetBuildsOutput, error)
+}
+
+// Scheduler decides which accounts to reset, and in which order.
+// Resets run in the reset CodeBuild project or in the process_reset_queue
+// Lambda, and the scheduler caps the number of resets running at once.
+type Scheduler struct {
+	Builds    BuildService
+	BuildName string
+	// Most resets to run at once. 0 for no limit
+	MaxInFlight int
+	// Account metadata key which groups accounts into pools,
+	// eg. "accountTier". All accounts are in one pool, if empty
+	PoolMetadataKey string
+}
+
+// lambdaResetTimeout is the longest a reset can run in the
+// process_reset_queue Lambda, which is the maximum Lambda timeout
+const lambdaResetTimeout = 15 * time.Minute
+
+// InFlight finds the accounts with a reset in progress, in a reset build or in the
+// process_reset_queue Lambda.  Resets in the Lambda are recorded on the NotReady accounts.
+// Resets are only counted to enforce MaxInFlight, so none are found without a limit
+func (s *Scheduler) InFlight(notReady []*account.Account) (map[string]bool, error) {
+	inFlight := map[string]bool{}
+	if s.MaxInFlight <= 0 {
+		return inFlight, nil
+	}
+	now := time.Now()
+
+	for _, acct := range notReady {
+		if lambdaResetInFlight(acct, now) {
+			inFlight[aws.StringValue(acct.ID)] = true
+		}
+	}
+
+	input := &codebuild.ListBuildsForProjectInput{
+		ProjectName: aws.String(s.BuildName),
+		// Newest builds first
+		SortOrder: aws.String(codebuild.SortOrderTypeDescending),
+	}
+	for {
+		list, err := s.Builds.ListBuildsForProject(input)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to list builds for %s", s.BuildName)
+		}
+		if len(list.Ids) == 0 {
+			return inFlight, nil
+		}
+
+		builds, err := s.Builds.BatchGetBuilds(&codebuild.BatchGetBuildsInput{
+			Ids: list.Ids,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get builds for %s", s.BuildName)
+		}
+
+		timedOut := false
+		for _, build := range builds.Builds {
+			if aws.StringValue(build.BuildStatus) == codebuild.StatusTypeInProgress {
+				accountID := buildAccountID(build)
+				if accountID != "" {
+					inFlight[accountID] = true
+				}
+			}
+			// Builds older than their timeout can't still be running
+			if build.StartTime != nil && build.TimeoutInMinutes != nil &&
+				build.StartTime.Add(time.Duration(*build.TimeoutInMinutes)*time.Minute).Before(now) {
+				timedOut = true
+			}
+		}
+
+		// Builds are listed newest first, so every older build
+		// started before this one, and has finished too
+		if timedOut || list.NextToken == nil {
+			return inFlight, nil
+		}
+		input.NextToken = list.NextToken
+	}
+}
+
+// lambdaResetInFlight checks whether the account is being reset in the process_reset_queue Lambda.
+// The reset has finished once it records a result for a reset started after it, or the Lambda has timed out
+func lambdaResetInFlight(acct *account.Account, now time.Time) bool {
+	if acct.ResetStartedOn == nil {
+		return false
+	}
+	if time.Unix(*acct.ResetStartedOn, 0).Add(lambdaResetTimeout).Before(now) {
+		return false
+	}
+	return acct.LastResetResult == nil || acct.LastResetResult.StartedOn < *acct.ResetStartedOn
+}
+
+// buildAccountID finds the account a reset build is resetting
+func buildAccountID(build *codebuild.Build) string {
+	if build.Environment == nil {
+		return ""
+	}
+	for _, envVar := range build.Environment.EnvironmentVariables {
+		if aws.StringValue(envVar.Name) == "RESET_ACCOUNT" {
+			return aws.StringValue(envVar.Value)
+		}
+	}
+	return ""
+}
+
+// Capacity is the number of resets which may be started,
+// with the given accounts already being reset.
+// Returns -1 if there's no limit
+func (s *Scheduler) Capacity(inFlight map[string]bool) int {
+	if s.MaxInFlight <= 0 {
+		return -1
+	}
+	capacity := s.MaxInFlight - len(inFlight)
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
+
+// Pool is the pool the account belongs to
+func (s *Scheduler) Pool(acct *account.Account) string {
+	if s.PoolMetadataKey == "" {
+		return ""
+	}
+	value, ok := acct.Metadata[s.PoolMetadataKey]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// Prioritize orders the accounts to reset.
+// Accounts in the pools with the fewest Ready accounts are reset first,
+// then accounts which have waited longest since their status last changed.
+func (s *Scheduler) Prioritize(accounts []*account.Account, readyByPool map[string]int) []*account.Account {
+	prioritized := make([]*account.Account, len(accounts))
+	copy(prioritized, accounts)
+
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		readyI := readyByPool[s.Pool(prioritized[i])]
+		readyJ := readyByPool[s.Pool(prioritized[j])]
+		if readyI != readyJ {
+			return readyI < readyJ
+		}
+		return aws.Int64Value(prioritized[i].LastModifiedOn) < aws.Int64Value(prioritized[j].LastModifiedOn)
+	})
+
+	return prioritized
+}
+
+// Schedule picks the accounts to reset now, in the order to reset them.
+// Accounts which are already being reset are skipped.
+func (s *Scheduler) Schedule(accounts []*account.Account, readyByPool map[string]int, inFlight map[string]bool) []*account.Account {
+	capacity := s.Capacity(inFlight)
+
+	scheduled := []*account.Account{}
+	for _, acct := range s.Prioritize(accounts, readyByPool) {
+		if capacity >= 0 && len(scheduled) >= capacity {
+			break
+		}
+		if inFlight[aws.StringValue(acct.ID)] {
+			continue
+		}
+		scheduled = append(scheduled, acct)
+	}
+
+	return scheduled
+}
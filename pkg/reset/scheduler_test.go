@@ -0,0 +1,205 @@
+package reset_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/Optum/dce/pkg/reset/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/codebuild"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func resetBuild(id string, status string, accountID string, startTime time.Time) *codebuild.Build {
+	return &codebuild.Build{
+		Id:               aws.String(id),
+		BuildStatus:      aws.String(status),
+		StartTime:        aws.Time(startTime),
+		TimeoutInMinutes: aws.Int64(480),
+		Environment: &codebuild.ProjectEnvironment{
+			EnvironmentVariables: []*codebuild.EnvironmentVariable{
+				{Name: aws.String("RESET_ACCOUNT"), Value: aws.String(accountID)},
+			},
+		},
+	}
+}
+
+func TestSchedulerInFlight(t *testing.T) {
+
+	t.Run("should find accounts with builds in progress", func(t *testing.T) {
+		buildSvc := &mocks.BuildService{}
+		buildSvc.
+			On("ListBuildsForProject", mock.MatchedBy(func(input *codebuild.ListBuildsForProjectInput) bool {
+				return *input.ProjectName == "ResetCodeBuild" && *input.SortOrder == "DESCENDING" && input.NextToken == nil
+			})).
+			Return(&codebuild.ListBuildsForProjectOutput{
+				Ids:       aws.StringSlice([]string{"build-3", "build-2"}),
+				NextToken: aws.String("next"),
+			}, nil)
+		buildSvc.
+			On("ListBuildsForProject", mock.MatchedBy(func(input *codebuild.ListBuildsForProjectInput) bool {
+				return aws.StringValue(input.NextToken) == "next"
+			})).
+			Return(&codebuild.ListBuildsForProjectOutput{
+				Ids: aws.StringSlice([]string{"build-1"}),
+			}, nil)
+		buildSvc.
+			On("BatchGetBuilds", &codebuild.BatchGetBuildsInput{Ids: aws.StringSlice([]string{"build-3", "build-2"})}).
+			Return(&codebuild.BatchGetBuildsOutput{
+				Builds: []*codebuild.Build{
+					resetBuild("build-3", "IN_PROGRESS", "333", time.Now()),
+					resetBuild("build-2", "SUCCEEDED", "222", time.Now()),
+				},
+			}, nil)
+		buildSvc.
+			On("BatchGetBuilds", &codebuild.BatchGetBuildsInput{Ids: aws.StringSlice([]string{"build-1"})}).
+			Return(&codebuild.BatchGetBuildsOutput{
+				Builds: []*codebuild.Build{
+					resetBuild("build-1", "IN_PROGRESS", "111", time.Now()),
+				},
+			}, nil)
+
+		scheduler := &reset.Scheduler{Builds: buildSvc, BuildName: "ResetCodeBuild", MaxInFlight: 5}
+		inFlight, err := scheduler.InFlight(nil)
+		require.Nil(t, err)
+		assert.Equal(t, map[string]bool{"333": true, "111": true}, inFlight)
+	})
+
+	t.Run("should stop at builds older than their timeout", func(t *testing.T) {
+		buildSvc := &mocks.BuildService{}
+		buildSvc.
+			On("ListBuildsForProject", mock.Anything).
+			Return(&codebuild.ListBuildsForProjectOutput{
+				Ids:       aws.StringSlice([]string{"build-2"}),
+				NextToken: aws.String("next"),
+			}, nil).Once()
+		buildSvc.
+			On("BatchGetBuilds", mock.Anything).
+			Return(&codebuild.BatchGetBuildsOutput{
+				Builds: []*codebuild.Build{
+					resetBuild("build-2", "SUCCEEDED", "222", time.Now().Add(-9*time.Hour)),
+				},
+			}, nil)
+
+		scheduler := &reset.Scheduler{Builds: buildSvc, BuildName: "ResetCodeBuild", MaxInFlight: 5}
+		inFlight, err := scheduler.InFlight(nil)
+		require.Nil(t, err)
+		assert.Equal(t, map[string]bool{}, inFlight)
+		buildSvc.AssertNumberOfCalls(t, "ListBuildsForProject", 1)
+	})
+
+	t.Run("should find accounts being reset in the Lambda", func(t *testing.T) {
+		buildSvc := &mocks.BuildService{}
+		buildSvc.
+			On("ListBuildsForProject", mock.Anything).
+			Return(&codebuild.ListBuildsForProjectOutput{}, nil)
+
+		now := time.Now().Unix()
+		notReady := []*account.Account{
+			// Reset in progress
+			{ID: aws.String("111"), ResetStartedOn: aws.Int64(now - 60)},
+			// Reset finished
+			{
+				ID:              aws.String("222"),
+				ResetStartedOn:  aws.Int64(now - 120),
+				LastResetResult: &account.ResetResult{StartedOn: now - 119},
+			},
+			// Reset timed out
+			{ID: aws.String("333"), ResetStartedOn: aws.Int64(now - 3600)},
+			// Never reset in the Lambda
+			{ID: aws.String("444")},
+		}
+
+		scheduler := &reset.Scheduler{Builds: buildSvc, BuildName: "ResetCodeBuild", MaxInFlight: 5}
+		inFlight, err := scheduler.InFlight(notReady)
+		require.Nil(t, err)
+		assert.Equal(t, map[string]bool{"111": true}, inFlight)
+	})
+
+	t.Run("should not look for resets without a limit", func(t *testing.T) {
+		buildSvc := &mocks.BuildService{}
+
+		scheduler := &reset.Scheduler{Builds: buildSvc, BuildName: "ResetCodeBuild"}
+		inFlight, err := scheduler.InFlight([]*account.Account{
+			{ID: aws.String("111"), ResetStartedOn: aws.Int64(time.Now().Unix())},
+		})
+		require.Nil(t, err)
+		assert.Equal(t, map[string]bool{}, inFlight)
+		buildSvc.AssertNotCalled(t, "ListBuildsForProject", mock.Anything)
+	})
+
+	t.Run("should handle CodeBuild errors", func(t *testing.T) {
+		buildSvc := &mocks.BuildService{}
+		buildSvc.
+			On("ListBuildsForProject", mock.Anything).
+			Return(nil, errors.New("test error"))
+
+		scheduler := &reset.Scheduler{Builds: buildSvc, BuildName: "ResetCodeBuild", MaxInFlight: 5}
+		_, err := scheduler.InFlight(nil)
+		require.NotNil(t, err)
+		assert.Equal(t, "Failed to list builds for ResetCodeBuild: test error", err.Error())
+	})
+}
+
+func TestSchedulerSchedule(t *testing.T) {
+	notReady := func(id string, tier string, lastModifiedOn int64) *account.Account {
+		acct := &account.Account{
+			ID:             aws.String(id),
+			Status:         account.StatusNotReady.StatusPtr(),
+			LastModifiedOn: aws.Int64(lastModifiedOn),
+		}
+		if tier != "" {
+			acct.Metadata = map[string]interface{}{"accountTier": tier}
+		}
+		return acct
+	}
+	ids := func(accounts []*account.Account) []string {
+		result := []string{}
+		for _, acct := range accounts {
+			result = append(result, *acct.ID)
+		}
+		return result
+	}
+
+	accounts := []*account.Account{
+		notReady("1", "standard", 300),
+		notReady("2", "gpu", 200),
+		notReady("3", "standard", 100),
+		notReady("4", "gpu", 400),
+		notReady("5", "", 50),
+	}
+	readyByPool := map[string]int{"standard": 10, "gpu": 1, "": 5}
+
+	t.Run("should prioritize pools with the fewest Ready accounts, then the longest waiting", func(t *testing.T) {
+		scheduler := &reset.Scheduler{PoolMetadataKey: "accountTier"}
+		assert.Equal(t, []string{"2", "4", "5", "3", "1"}, ids(scheduler.Prioritize(accounts, readyByPool)))
+	})
+
+	t.Run("should prioritize the longest waiting, without pools", func(t *testing.T) {
+		scheduler := &reset.Scheduler{}
+		assert.Equal(t, []string{"5", "3", "2", "1", "4"}, ids(scheduler.Prioritize(accounts, map[string]int{"": 16})))
+	})
+
+	t.Run("should only schedule up to the max in flight", func(t *testing.T) {
+		scheduler := &reset.Scheduler{PoolMetadataKey: "accountTier", MaxInFlight: 3}
+		scheduled := scheduler.Schedule(accounts, readyByPool, map[string]bool{"4": true})
+		assert.Equal(t, []string{"2", "5"}, ids(scheduled))
+	})
+
+	t.Run("should not schedule when at the max in flight", func(t *testing.T) {
+		scheduler := &reset.Scheduler{MaxInFlight: 1}
+		scheduled := scheduler.Schedule(accounts, readyByPool, map[string]bool{"9": true, "8": true})
+		assert.Equal(t, []string{}, ids(scheduled))
+	})
+
+	t.Run("should schedule every account not in flight, without a max", func(t *testing.T) {
+		scheduler := &reset.Scheduler{PoolMetadataKey: "accountTier"}
+		scheduled := scheduler.Schedule(accounts, readyByPool, map[string]bool{"4": true})
+		assert.Equal(t, []string{"2", "5", "3", "1"}, ids(scheduled))
+	})
+}
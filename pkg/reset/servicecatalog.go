@@ -0,0 +1,147 @@
+package reset
+
+import (
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+)
+
+//go:generate mockery -name ServiceCatalogService
+
+// ServiceCatalogService interface
+type ServiceCatalogService interface {
+	SearchProvisionedProducts(input *servicecatalog.SearchProvisionedProductsInput) (*servicecatalog.SearchProvisionedProductsOutput, error)
+	TerminateProvisionedProduct(input *servicecatalog.TerminateProvisionedProductInput) (*servicecatalog.TerminateProvisionedProductOutput, error)
+	SearchProductsAsAdmin(input *servicecatalog.SearchProductsAsAdminInput) (*servicecatalog.SearchProductsAsAdminOutput, error)
+	ListPortfoliosForProduct(input *servicecatalog.ListPortfoliosForProductInput) (*servicecatalog.ListPortfoliosForProductOutput, error)
+	DisassociateProductFromPortfolio(input *servicecatalog.DisassociateProductFromPortfolioInput) (*servicecatalog.DisassociateProductFromPortfolioOutput, error)
+	DeleteProduct(input *servicecatalog.DeleteProductInput) (*servicecatalog.DeleteProductOutput, error)
+}
+
+// ServiceCatalogResetter is the reset step which terminates Service Catalog
+// provisioned products, and deletes Service Catalog products
+type ServiceCatalogResetter struct {
+	Service ServiceCatalogService
+}
+
+// Name of the reset step
+func (sc *ServiceCatalogResetter) Name() string {
+	return StepServiceCatalog
+}
+
+// Reset terminates the provisioned products in the account,
+// then removes products from their portfolios and deletes them
+func (sc *ServiceCatalogResetter) Reset() (*Resources, error) {
+	resources := &Resources{}
+
+	err := sc.terminateProvisionedProducts(resources)
+	if err != nil {
+		return resources, err
+	}
+
+	err = sc.deleteProducts(resources)
+	if err != nil {
+		return resources, err
+	}
+
+	return resources, nil
+}
+
+func (sc *ServiceCatalogResetter) terminateProvisionedProducts(resources *Resources) error {
+	input := &servicecatalog.SearchProvisionedProductsInput{
+		AccessLevelFilter: &servicecatalog.AccessLevelFilter{
+			Key:   aws.String("Account"),
+			Value: aws.String("self"),
+		},
+	}
+	for {
+		output, err := sc.Service.SearchProvisionedProducts(input)
+		if err != nil {
+			return err
+		}
+
+		for _, provisionedProduct := range output.ProvisionedProducts {
+			resourceName := "ServiceCatalogProvisionedProduct - " + aws.StringValue(provisionedProduct.Id)
+			log.Printf("Terminating Service Catalog provisioned product %s", aws.StringValue(provisionedProduct.Id))
+			_, err := sc.Service.TerminateProvisionedProduct(&servicecatalog.TerminateProvisionedProductInput{
+				ProvisionedProductId: provisionedProduct.Id,
+				IgnoreErrors:         aws.Bool(true),
+			})
+			if err != nil {
+				resources.Failed = append(resources.Failed, resourceName)
+				return err
+			}
+			resources.Deleted = append(resources.Deleted, resourceName)
+		}
+
+		if aws.StringValue(output.NextPageToken) == "" {
+			return nil
+		}
+		input.PageToken = output.NextPageToken
+	}
+}
+
+func (sc *ServiceCatalogResetter) deleteProducts(resources *Resources) error {
+	input := &servicecatalog.SearchProductsAsAdminInput{}
+	for {
+		output, err := sc.Service.SearchProductsAsAdmin(input)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range output.ProductViewDetails {
+			productID := product.ProductViewSummary.ProductId
+			resourceName := "ServiceCatalogProduct - " + aws.StringValue(productID)
+
+			// Products can't be deleted while they're in a portfolio
+			err := sc.disassociatePortfolios(productID)
+			if err != nil {
+				resources.Failed = append(resources.Failed, resourceName)
+				return err
+			}
+
+			log.Printf("Deleting Service Catalog product %s", aws.StringValue(productID))
+			_, err = sc.Service.DeleteProduct(&servicecatalog.DeleteProductInput{
+				Id: productID,
+			})
+			if err != nil {
+				resources.Failed = append(resources.Failed, resourceName)
+				return err
+			}
+			resources.Deleted = append(resources.Deleted, resourceName)
+		}
+
+		if aws.StringValue(output.NextPageToken) == "" {
+			return nil
+		}
+		input.PageToken = output.NextPageToken
+	}
+}
+
+func (sc *ServiceCatalogResetter) disassociatePortfolios(productID *string) error {
+	input := &servicecatalog.ListPortfoliosForProductInput{
+		ProductId: productID,
+	}
+	for {
+		output, err := sc.Service.ListPortfoliosForProduct(input)
+		if err != nil {
+			return err
+		}
+
+		for _, portfolio := range output.PortfolioDetails {
+			_, err := sc.Service.DisassociateProductFromPortfolio(&servicecatalog.DisassociateProductFromPortfolioInput{
+				ProductId:   productID,
+				PortfolioId: portfolio.Id,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if aws.StringValue(output.NextPageToken) == "" {
+			return nil
+		}
+		input.PageToken = output.NextPageToken
+	}
+}
@@ -0,0 +1,96 @@
+package reset_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/Optum/dce/pkg/reset/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/servicecatalog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceCatalogResetter(t *testing.T) {
+
+	searchProvisionedProductsInput := &servicecatalog.SearchProvisionedProductsInput{
+		AccessLevelFilter: &servicecatalog.AccessLevelFilter{
+			Key:   aws.String("Account"),
+			Value: aws.String("self"),
+		},
+	}
+
+	t.Run("should terminate provisioned products and delete products", func(t *testing.T) {
+		scSvc := &mocks.ServiceCatalogService{}
+		defer scSvc.AssertExpectations(t)
+
+		scSvc.On("SearchProvisionedProducts", searchProvisionedProductsInput).
+			Return(&servicecatalog.SearchProvisionedProductsOutput{
+				ProvisionedProducts: []*servicecatalog.ProvisionedProductAttribute{
+					{Id: aws.String("pp-1")},
+				},
+			}, nil)
+		scSvc.On("TerminateProvisionedProduct", &servicecatalog.TerminateProvisionedProductInput{
+			ProvisionedProductId: aws.String("pp-1"),
+			IgnoreErrors:         aws.Bool(true),
+		}).Return(&servicecatalog.TerminateProvisionedProductOutput{}, nil)
+
+		scSvc.On("SearchProductsAsAdmin", &servicecatalog.SearchProductsAsAdminInput{}).
+			Return(&servicecatalog.SearchProductsAsAdminOutput{
+				ProductViewDetails: []*servicecatalog.ProductViewDetail{
+					{ProductViewSummary: &servicecatalog.ProductViewSummary{ProductId: aws.String("prod-1")}},
+				},
+			}, nil)
+		scSvc.On("ListPortfoliosForProduct", &servicecatalog.ListPortfoliosForProductInput{
+			ProductId: aws.String("prod-1"),
+		}).Return(&servicecatalog.ListPortfoliosForProductOutput{
+			PortfolioDetails: []*servicecatalog.PortfolioDetail{
+				{Id: aws.String("port-1")},
+			},
+		}, nil)
+		scSvc.On("DisassociateProductFromPortfolio", &servicecatalog.DisassociateProductFromPortfolioInput{
+			ProductId:   aws.String("prod-1"),
+			PortfolioId: aws.String("port-1"),
+		}).Return(&servicecatalog.DisassociateProductFromPortfolioOutput{}, nil)
+		scSvc.On("DeleteProduct", &servicecatalog.DeleteProductInput{
+			Id: aws.String("prod-1"),
+		}).Return(&servicecatalog.DeleteProductOutput{}, nil)
+
+		resetter := &reset.ServiceCatalogResetter{Service: scSvc}
+		assert.Equal(t, reset.StepServiceCatalog, resetter.Name())
+
+		resources, err := resetter.Reset()
+		require.Nil(t, err)
+		assert.Equal(t, &reset.Resources{
+			Deleted: []string{
+				"ServiceCatalogProvisionedProduct - pp-1",
+				"ServiceCatalogProduct - prod-1",
+			},
+		}, resources)
+	})
+
+	t.Run("should fail when a product can't be deleted", func(t *testing.T) {
+		scSvc := &mocks.ServiceCatalogService{}
+
+		scSvc.On("SearchProvisionedProducts", searchProvisionedProductsInput).
+			Return(&servicecatalog.SearchProvisionedProductsOutput{}, nil)
+		scSvc.On("SearchProductsAsAdmin", &servicecatalog.SearchProductsAsAdminInput{}).
+			Return(&servicecatalog.SearchProductsAsAdminOutput{
+				ProductViewDetails: []*servicecatalog.ProductViewDetail{
+					{ProductViewSummary: &servicecatalog.ProductViewSummary{ProductId: aws.String("prod-1")}},
+				},
+			}, nil)
+		scSvc.On("ListPortfoliosForProduct", &servicecatalog.ListPortfoliosForProductInput{
+			ProductId: aws.String("prod-1"),
+		}).Return(&servicecatalog.ListPortfoliosForProductOutput{}, nil)
+		scSvc.On("DeleteProduct", &servicecatalog.DeleteProductInput{
+			Id: aws.String("prod-1"),
+		}).Return(nil, errors.New("test error"))
+
+		resetter := &reset.ServiceCatalogResetter{Service: scSvc}
+		resources, err := resetter.Reset()
+		assert.Equal(t, errors.New("test error"), err)
+		assert.Equal(t, []string{"ServiceCatalogProduct - prod-1"}, resources.Failed)
+	})
+}
@@ -0,0 +1,109 @@
+package reset
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface"
+	"github.com/Optum/dce/pkg/arn"
+	"github.com/Optum/dce/pkg/db"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/pkg/errors"
+)
+
+// Most remaining resources to list in a verification error
+const maxRemainingResourcesListed = 10
+
+// Verifier checks an account is healthy after it's reset,
+// before it's returned to the account pool
+type Verifier struct {
+	AccountManager      accountmanageriface.Servicer
+	PrincipalPolicyName string
+	// Finds resources still in the account
+	RemainingResources func() ([]string, error)
+}
+
+// Verify checks that:
+// - the admin role is still assumable
+// - the principal role and policy exist, with the expected policy hash
+// - no resources remain in the account
+func (v *Verifier) Verify(dbAccount *db.Account) error {
+	acct, err := verifierAccount(dbAccount, v.PrincipalPolicyName)
+	if err != nil {
+		return err
+	}
+
+	err = v.AccountManager.ValidateAccess(acct.AdminRoleArn)
+	if err != nil {
+		return errors.Wrapf(err, "Admin role %s is not assumable", dbAccount.AdminRoleArn)
+	}
+
+	err = v.AccountManager.ValidatePrincipalAccess(acct)
+	if err != nil {
+		return errors.Wrap(err, "Principal role or policy is invalid")
+	}
+
+	remaining, err := v.RemainingResources()
+	if err != nil {
+		return errors.Wrap(err, "Failed to find remaining resources")
+	}
+	if len(remaining) > 0 {
+		listed := remaining
+		if len(listed) > maxRemainingResourcesListed {
+			listed = listed[:maxRemainingResourcesListed]
+		}
+		return fmt.Errorf("%d resources remain after reset: %s", len(remaining), strings.Join(listed, ", "))
+	}
+
+	return nil
+}
+
+// verifierAccount converts the account record to the model used by the account manager
+func verifierAccount(dbAccount *db.Account, principalPolicyName string) (*account.Account, error) {
+	adminRoleArn, err := arn.NewFromArn(dbAccount.AdminRoleArn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid admin role ARN %q", dbAccount.AdminRoleArn)
+	}
+	principalRoleArn, err := arn.NewFromArn(dbAccount.PrincipalRoleArn)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid principal role ARN %q", dbAccount.PrincipalRoleArn)
+	}
+
+	return &account.Account{
+		ID:                  aws.String(dbAccount.ID),
+		AdminRoleArn:        adminRoleArn,
+		PrincipalRoleArn:    principalRoleArn,
+		PrincipalPolicyArn:  arn.New("aws", "iam", "", dbAccount.ID, fmt.Sprintf("policy/%s", principalPolicyName)),
+		PrincipalPolicyHash: aws.String(dbAccount.PrincipalPolicyHash),
+	}, nil
+}
+
+// remainingResources runs aws-nuke in dry run mode, with the same configuration,
+// and lists the resources it would still remove
+func (n *nukeResetter) remainingResources() ([]string, error) {
+	scan := &nukeResetter{
+		job:       n.job,
+		isDryRun:  true,
+		overrides: n.overrides,
+	}
+	log.Printf("Scanning account %s for remaining resources", n.job.Config.ChildAccountID)
+	output, err := captureStdout(scan.nukeAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := ParseNukeOutput(bytes.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+	remaining := []string{}
+	for _, resource := range resources {
+		if resource.Action == ReportActionRemove {
+			remaining = append(remaining, fmt.Sprintf("%s - %s - %s", resource.Region, resource.ResourceType, resource.ResourceID))
+		}
+	}
+	return remaining, nil
+}
@@ -0,0 +1,114 @@
+package reset_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Optum/dce/pkg/account"
+	"github.com/Optum/dce/pkg/accountmanager/accountmanageriface/mocks"
+	"github.com/Optum/dce/pkg/arn"
+	"github.com/Optum/dce/pkg/db"
+	"github.com/Optum/dce/pkg/reset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifierVerify(t *testing.T) {
+	dbAccount := &db.Account{
+		ID:                  "123456789012",
+		AdminRoleArn:        "arn:aws:iam::123456789012:role/AdminRole",
+		PrincipalRoleArn:    "arn:aws:iam::123456789012:role/DCEPrincipal",
+		PrincipalPolicyHash: "\"hash\"",
+	}
+	noResources := func() ([]string, error) {
+		return []string{}, nil
+	}
+
+	tests := []struct {
+		name               string
+		validateAccessErr  error
+		validatePrincipal  bool
+		validatePrincErr   error
+		remainingResources func() ([]string, error)
+		expectedErr        string
+	}{
+		{
+			name:               "should verify a healthy account",
+			validatePrincipal:  true,
+			remainingResources: noResources,
+		},
+		{
+			name:               "should fail when the admin role isn't assumable",
+			validateAccessErr:  errors.New("access denied"),
+			remainingResources: noResources,
+			expectedErr:        "Admin role arn:aws:iam::123456789012:role/AdminRole is not assumable: access denied",
+		},
+		{
+			name:               "should fail when the principal policy is invalid",
+			validatePrincipal:  true,
+			validatePrincErr:   errors.New("hash mismatch"),
+			remainingResources: noResources,
+			expectedErr:        "Principal role or policy is invalid: hash mismatch",
+		},
+		{
+			name:              "should fail when resources remain",
+			validatePrincipal: true,
+			remainingResources: func() ([]string, error) {
+				return []string{"us-east-1 - EC2Instance - i-123"}, nil
+			},
+			expectedErr: "1 resources remain after reset: us-east-1 - EC2Instance - i-123",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountManagerSvc := &mocks.Servicer{}
+			accountManagerSvc.On("ValidateAccess", mock.MatchedBy(func(roleArn *arn.ARN) bool {
+				return roleArn.String() == "arn:aws:iam::123456789012:role/AdminRole"
+			})).Return(tt.validateAccessErr)
+			if tt.validatePrincipal {
+				accountManagerSvc.On("ValidatePrincipalAccess", mock.MatchedBy(func(acct *account.Account) bool {
+					return *acct.ID == "123456789012" &&
+						acct.PrincipalPolicyArn.String() == "arn:aws:iam::123456789012:policy/DCEPrincipalDefaultPolicy" &&
+						*acct.PrincipalPolicyHash == "\"hash\""
+				})).Return(tt.validatePrincErr)
+			}
+
+			verifier := &reset.Verifier{
+				AccountManager:      accountManagerSvc,
+				PrincipalPolicyName: "DCEPrincipalDefaultPolicy",
+				RemainingResources:  tt.remainingResources,
+			}
+			err := verifier.Verify(dbAccount)
+			if tt.expectedErr == "" {
+				require.Nil(t, err)
+			} else {
+				require.NotNil(t, err)
+				assert.Equal(t, tt.expectedErr, err.Error())
+			}
+			accountManagerSvc.AssertExpectations(t)
+		})
+	}
+
+	t.Run("should fail with an invalid admin role ARN", func(t *testing.T) {
+		verifier := &reset.Verifier{AccountManager: &mocks.Servicer{}}
+		err := verifier.Verify(&db.Account{ID: "123456789012", AdminRoleArn: "invalid"})
+		require.NotNil(t, err)
+	})
+
+	t.Run("should list at most 10 remaining resources", func(t *testing.T) {
+		accountManagerSvc := &mocks.Servicer{}
+		accountManagerSvc.On("ValidateAccess", mock.Anything).Return(nil)
+		accountManagerSvc.On("ValidatePrincipalAccess", mock.Anything).Return(nil)
+		verifier := &reset.Verifier{
+			AccountManager: accountManagerSvc,
+			RemainingResources: func() ([]string, error) {
+				return []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}, nil
+			},
+		}
+		err := verifier.Verify(dbAccount)
+		require.NotNil(t, err)
+		assert.Equal(t, "12 resources remain after reset: 1, 2, 3, 4, 5, 6, 7, 8, 9, 10", err.Error())
+	})
+}
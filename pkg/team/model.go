@@ -0,0 +1,79 @@
+// Package team manages teams of principals, whose leases share a budget
+package team
+
+import (
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// Budget periods a team's spend is measured over
+const (
+	// BudgetPeriodWeekly measures spend from the beginning of the week
+	BudgetPeriodWeekly = "WEEKLY"
+	// BudgetPeriodMonthly measures spend from the beginning of the month
+	BudgetPeriodMonthly = "MONTHLY"
+)
+
+// Team - Handles importing and exporting Teams
+type Team struct {
+	ID             *string  `json:"id,omitempty" dynamodbav:"Id"`                                   // Team ID
+	Name           *string  `json:"name,omitempty" dynamodbav:"Name"`                               // Name of the team
+	Leads          []string `json:"leads,omitempty" dynamodbav:"Leads,omitempty"`                   // Principals who lead the team
+	Members        []string `json:"members,omitempty" dynamodbav:"Members,omitempty"`               // Principals on the team
+	BudgetAmount   *float64 `json:"budgetAmount,omitempty" dynamodbav:"BudgetAmount,omitempty"`     // Spend ceiling shared by the team's leases, or none when empty
+	BudgetCurrency *string  `json:"budgetCurrency,omitempty" dynamodbav:"BudgetCurrency,omitempty"` // Budget currency
+	BudgetPeriod   *string  `json:"budgetPeriod,omitempty" dynamodbav:"BudgetPeriod,omitempty"`     // Period the team's spend is measured over
+	CreatedOn      *int64   `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty"`           // Team CreatedOn
+	LastModifiedOn *int64   `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty"` // Last Modified Epoch Timestamp
+}
+
+// Validate the team data
+func (t *Team) Validate() error {
+	err := validation.ValidateStruct(t,
+		validation.Field(&t.ID, validateID...),
+		validation.Field(&t.Name, validateName...),
+		validation.Field(&t.Leads, validatePrincipalIDs...),
+		validation.Field(&t.Members, validatePrincipalIDs...),
+		validation.Field(&t.BudgetAmount, validateBudgetAmount...),
+		validation.Field(&t.BudgetPeriod, validateBudgetPeriod...),
+		validation.Field(&t.LastModifiedOn, validateInt64...),
+		validation.Field(&t.CreatedOn, validateInt64...),
+	)
+	if err != nil {
+		return errors.NewValidation("team", err)
+	}
+	return nil
+}
+
+// HasMember returns true if the principal is a member or a lead of the team
+func (t *Team) HasMember(principalID string) bool {
+	return t.HasLead(principalID) || contains(t.Members, principalID)
+}
+
+// HasLead returns true if the principal leads the team
+func (t *Team) HasLead(principalID string) bool {
+	return contains(t.Leads, principalID)
+}
+
+// Principals returns the leads and members of the team
+func (t *Team) Principals() []string {
+	principals := append([]string{}, t.Leads...)
+	for _, member := range t.Members {
+		if !contains(principals, member) {
+			principals = append(principals, member)
+		}
+	}
+	return principals
+}
+
+// Teams is a list of type Team
+type Teams []Team
+
+func contains(principalIDs []string, principalID string) bool {
+	for _, p := range principalIDs {
+		if p == principalID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package team
+
+import (
+	"time"
+
+	"github.com/Optum/dce/pkg/currency"
+	"github.com/Optum/dce/pkg/errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/imdario/mergo"
+)
+
+// Writer put an item into the data store
+type Writer interface {
+	Write(i *Team, lastModifiedOn *int64) error
+}
+
+// Deleter Deletes a Team from the data store
+type Deleter interface {
+	Delete(i *Team) error
+}
+
+// SingleReader Reads Team information from the data store
+type SingleReader interface {
+	Get(ID string) (*Team, error)
+}
+
+// MultipleReader reads multiple teams from the data store
+type MultipleReader interface {
+	List() (*Teams, error)
+}
+
+// Reader data Layer
+type Reader interface {
+	SingleReader
+	MultipleReader
+}
+
+// WriterDeleter data layer
+type WriterDeleter interface {
+	Writer
+	Deleter
+}
+
+// ReaderWriterDeleter includes Reader and Writer interfaces
+type ReaderWriterDeleter interface {
+	Reader
+	WriterDeleter
+}
+
+// Service is a type corresponding to a Team table record
+type Service struct {
+	dataSvc ReaderWriterDeleter
+}
+
+// Get returns a team from ID
+func (s *Service) Get(ID string) (*Team, error) {
+
+	new, err := s.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, err
+}
+
+// Save writes the record to the dataSvc
+func (s *Service) Save(data *Team) error {
+	var lastModifiedOn *int64
+	now := time.Now().Unix()
+	if data.LastModifiedOn == nil {
+		lastModifiedOn = nil
+		data.CreatedOn = &now
+		data.LastModifiedOn = &now
+	} else {
+		lastModifiedOn = data.LastModifiedOn
+		data.LastModifiedOn = &now
+	}
+
+	err := data.Validate()
+	if err != nil {
+		return err
+	}
+	err = s.dataSvc.Write(data, lastModifiedOn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Create creates a new team using the data provided.  Team budgets are measured
+// monthly in USD, unless another period or currency is provided
+func (s *Service) Create(data *Team) (*Team, error) {
+	// Validate the incoming record doesn't have unneeded fields
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.By(isNil)),
+		validation.Field(&data.Name, validateName...),
+		validation.Field(&data.BudgetAmount, validateBudgetAmount...),
+		validation.Field(&data.BudgetPeriod, validateBudgetPeriod...),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("team", err)
+	}
+
+	budgetCurrency := data.BudgetCurrency
+	if budgetCurrency == nil {
+		usd := currency.USD
+		budgetCurrency = &usd
+	}
+	budgetPeriod := data.BudgetPeriod
+	if budgetPeriod == nil {
+		monthly := BudgetPeriodMonthly
+		budgetPeriod = &monthly
+	}
+
+	id := uuid.New().String()
+	new := &Team{
+		ID:             &id,
+		Name:           data.Name,
+		Leads:          data.Leads,
+		Members:        data.Members,
+		BudgetAmount:   data.BudgetAmount,
+		BudgetCurrency: budgetCurrency,
+		BudgetPeriod:   budgetPeriod,
+	}
+
+	err = s.Save(new)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, nil
+}
+
+// Update the Team record in DynamoDB
+func (s *Service) Update(ID string, data *Team) (*Team, error) {
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.NilOrNotEmpty, validation.In(ID)),
+		validation.Field(&data.BudgetAmount, validateBudgetAmount...),
+		validation.Field(&data.BudgetPeriod, validateBudgetPeriod...),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("team", err)
+	}
+
+	team, err := s.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = mergo.Merge(team, *data, mergo.WithOverride)
+	if err != nil {
+		return nil, errors.NewInternalServer("unexpected error updating team", err)
+	}
+	// Empty lists remove every lead or member from the team
+	if data.Leads != nil {
+		team.Leads = data.Leads
+	}
+	if data.Members != nil {
+		team.Members = data.Members
+	}
+
+	err = s.Save(team)
+	if err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+// Delete the team
+func (s *Service) Delete(data *Team) error {
+	return s.dataSvc.Delete(data)
+}
+
+// List Get the list of teams
+func (s *Service) List() (*Teams, error) {
+
+	teams, err := s.dataSvc.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// TeamMembers returns the leads and members of the teams led by the lead principal
+func (s *Service) TeamMembers(leadPrincipalID string) ([]string, error) {
+
+	teams, err := s.dataSvc.List()
+	if err != nil {
+		return nil, err
+	}
+
+	principals := []string{}
+	for _, t := range *teams {
+		if !t.HasLead(leadPrincipalID) {
+			continue
+		}
+		for _, principal := range t.Principals() {
+			if !contains(principals, principal) {
+				principals = append(principals, principal)
+			}
+		}
+	}
+	return principals, nil
+}
+
+// NewServiceInput Input for creating a new Service
+type NewServiceInput struct {
+	DataSvc ReaderWriterDeleter
+}
+
+// NewService creates a new instance of the Service
+func NewService(input NewServiceInput) *Service {
+	return &Service{
+		dataSvc: input.DataSvc,
+	}
+}
@@ -0,0 +1,261 @@
+package team_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/team"
+	"github.com/Optum/dce/pkg/team/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func ptrInt64(i int64) *int64 {
+	ptrI := i
+	return &ptrI
+}
+
+func ptrFloat64(f float64) *float64 {
+	ptrF := f
+	return &ptrF
+}
+
+func TestCreateTeam(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		req     *team.Team
+		expTeam *team.Team
+		expErr  error
+	}{
+		{
+			name: "should create a team with a monthly USD budget",
+			req: &team.Team{
+				Name:         ptrString("platform"),
+				Leads:        []string{"lead1"},
+				Members:      []string{"user1", "user2"},
+				BudgetAmount: ptrFloat64(5000),
+			},
+			expTeam: &team.Team{
+				Name:           ptrString("platform"),
+				Leads:          []string{"lead1"},
+				Members:        []string{"user1", "user2"},
+				BudgetAmount:   ptrFloat64(5000),
+				BudgetCurrency: ptrString("USD"),
+				BudgetPeriod:   ptrString("MONTHLY"),
+			},
+		},
+		{
+			name: "should create a team with the budget period provided",
+			req: &team.Team{
+				Name:         ptrString("platform"),
+				BudgetPeriod: ptrString("WEEKLY"),
+			},
+			expTeam: &team.Team{
+				Name:           ptrString("platform"),
+				BudgetCurrency: ptrString("USD"),
+				BudgetPeriod:   ptrString("WEEKLY"),
+			},
+		},
+		{
+			name: "should fail on an invalid budget period",
+			req: &team.Team{
+				Name:         ptrString("platform"),
+				BudgetPeriod: ptrString("DAILY"),
+			},
+			expErr: errors.NewValidation("team", fmt.Errorf("budgetPeriod: must be WEEKLY or MONTHLY.")), //nolint golint
+		},
+		{
+			name: "should fail on a negative budget",
+			req: &team.Team{
+				Name:         ptrString("platform"),
+				BudgetAmount: ptrFloat64(-1),
+			},
+			expErr: errors.NewValidation("team", fmt.Errorf("budgetAmount: must be greater than zero.")), //nolint golint
+		},
+		{
+			name:   "should fail without a name",
+			req:    &team.Team{},
+			expErr: errors.NewValidation("team", fmt.Errorf("name: must be a string.")), //nolint golint
+		},
+		{
+			name: "should fail when an ID is provided",
+			req: &team.Team{
+				ID:   ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+				Name: ptrString("platform"),
+			},
+			expErr: errors.NewValidation("team", fmt.Errorf("id: must be empty.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Write", mock.AnythingOfType("*team.Team"), (*int64)(nil)).Return(nil)
+
+			teamSvc := team.NewService(team.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			result, err := teamSvc.Create(tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expTeam != nil {
+				assert.NotNil(t, result.ID)
+				assert.Equal(t, tt.expTeam.Name, result.Name)
+				assert.Equal(t, tt.expTeam.Leads, result.Leads)
+				assert.Equal(t, tt.expTeam.Members, result.Members)
+				assert.Equal(t, tt.expTeam.BudgetAmount, result.BudgetAmount)
+				assert.Equal(t, tt.expTeam.BudgetCurrency, result.BudgetCurrency)
+				assert.Equal(t, tt.expTeam.BudgetPeriod, result.BudgetPeriod)
+				mocksRwd.AssertNumberOfCalls(t, "Write", 1)
+			} else {
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdateTeam(t *testing.T) {
+	existing := func() *team.Team {
+		return &team.Team{
+			ID:             ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+			Name:           ptrString("platform"),
+			Leads:          []string{"lead1"},
+			Members:        []string{"user1", "user2"},
+			BudgetAmount:   ptrFloat64(5000),
+			BudgetCurrency: ptrString("USD"),
+			BudgetPeriod:   ptrString("MONTHLY"),
+			CreatedOn:      ptrInt64(1573592058),
+			LastModifiedOn: ptrInt64(1573592058),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		req     *team.Team
+		expTeam *team.Team
+		expErr  error
+	}{
+		{
+			name: "should update the budget",
+			req: &team.Team{
+				BudgetAmount: ptrFloat64(8000),
+			},
+			expTeam: &team.Team{
+				Members:      []string{"user1", "user2"},
+				BudgetAmount: ptrFloat64(8000),
+			},
+		},
+		{
+			name: "should replace the members",
+			req: &team.Team{
+				Members: []string{"user3"},
+			},
+			expTeam: &team.Team{
+				Members:      []string{"user3"},
+				BudgetAmount: ptrFloat64(5000),
+			},
+		},
+		{
+			name: "should remove every member",
+			req: &team.Team{
+				Members: []string{},
+			},
+			expTeam: &team.Team{
+				Members:      []string{},
+				BudgetAmount: ptrFloat64(5000),
+			},
+		},
+		{
+			name: "should fail on a new ID",
+			req: &team.Team{
+				ID: ptrString("other"),
+			},
+			expErr: errors.NewValidation("team", fmt.Errorf("id: must be a valid value.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Get", "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e").Return(existing(), nil)
+			mocksRwd.On("Write", mock.AnythingOfType("*team.Team"), ptrInt64(1573592058)).Return(nil)
+
+			teamSvc := team.NewService(team.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			result, err := teamSvc.Update("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e", tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expTeam != nil {
+				assert.Equal(t, tt.expTeam.Members, result.Members)
+				assert.Equal(t, tt.expTeam.BudgetAmount, result.BudgetAmount)
+				assert.Equal(t, []string{"lead1"}, result.Leads)
+			}
+		})
+	}
+}
+
+func TestTeamMembers(t *testing.T) {
+
+	tests := []struct {
+		name       string
+		lead       string
+		listErr    error
+		expMembers []string
+		expErr     error
+	}{
+		{
+			name:       "should return the leads and members of the teams the principal leads",
+			lead:       "lead1",
+			expMembers: []string{"lead1", "lead2", "user1", "user2", "user3"},
+		},
+		{
+			name:       "should return no members for a principal who isn't a lead",
+			lead:       "user1",
+			expMembers: []string{},
+		},
+		{
+			name:    "should fail when teams can't be listed",
+			lead:    "lead1",
+			listErr: errors.NewInternalServer("failure", nil),
+			expErr:  errors.NewInternalServer("failure", nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("List").Return(&team.Teams{
+				{
+					Name:    ptrString("platform"),
+					Leads:   []string{"lead1", "lead2"},
+					Members: []string{"user1", "user2"},
+				},
+				{
+					Name:    ptrString("analytics"),
+					Leads:   []string{"lead1"},
+					Members: []string{"user2", "user3"},
+				},
+				{
+					Name:    ptrString("data"),
+					Members: []string{"user4"},
+				},
+			}, tt.listErr)
+
+			teamSvc := team.NewService(team.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			members, err := teamSvc.TeamMembers(tt.lead)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			assert.Equal(t, tt.expMembers, members)
+		})
+	}
+}
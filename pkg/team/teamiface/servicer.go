@@ -0,0 +1,25 @@
+//
+
+package teamiface
+
+import (
+	"github.com/Optum/dce/pkg/team"
+)
+
+// Servicer makes working with the Team Service struct easier
+type Servicer interface {
+	// Get returns a team from ID
+	Get(ID string) (*team.Team, error)
+	// Save writes the record to the dataSvc
+	Save(data *team.Team) error
+	// Create creates a new team using the data provided. Returns the team record
+	Create(data *team.Team) (*team.Team, error)
+	// Update the Team record in DynamoDB
+	Update(ID string, data *team.Team) (*team.Team, error)
+	// Delete the team
+	Delete(data *team.Team) error
+	// List Get the list of teams
+	List() (*team.Teams, error)
+	// TeamMembers returns the leads and members of the teams led by the lead principal
+	TeamMembers(leadPrincipalID string) ([]string, error)
+}
@@ -0,0 +1,63 @@
+package team
+
+import (
+	"errors"
+	"reflect"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// We don't use the internal errors package here because validation will rewrite it anyways
+// Just spit out errors and turn them into validation errors inside the appropriate functions
+
+var validateID = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	is.UUIDv4.Error("must be a UUIDv4"),
+}
+
+var validateName = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	validation.Length(1, 0).Error("must not be empty"),
+}
+
+var validatePrincipalIDs = []validation.Rule{
+	validation.By(isPrincipalIDs),
+}
+
+var validateBudgetAmount = []validation.Rule{
+	validation.By(isPositiveAmount),
+}
+
+var validateBudgetPeriod = []validation.Rule{
+	validation.In(BudgetPeriodWeekly, BudgetPeriodMonthly).Error("must be WEEKLY or MONTHLY"),
+}
+
+var validateInt64 = []validation.Rule{
+	validation.NotNil.Error("must be an epoch timestamp"),
+}
+
+func isNil(value interface{}) error {
+	if !reflect.ValueOf(value).IsNil() {
+		return errors.New("must be empty")
+	}
+	return nil
+}
+
+func isPrincipalIDs(value interface{}) error {
+	principalIDs, _ := value.([]string)
+	for _, p := range principalIDs {
+		if p == "" {
+			return errors.New("must be principal IDs")
+		}
+	}
+	return nil
+}
+
+func isPositiveAmount(value interface{}) error {
+	amount, _ := value.(*float64)
+	if amount != nil && *amount <= 0 {
+		return errors.New("must be greater than zero")
+	}
+	return nil
+}
@@ -1,22 +1,28 @@
 package usage
 
 import (
+	"sort"
+
 	"github.com/Optum/dce/pkg/errors"
 	validation "github.com/go-ozzo/ozzo-validation"
 )
 
 // Usage item
 type Usage struct {
-	PrincipalID     *string  `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`              // User Principal ID
-	AccountID       *string  `json:"accountId,omitempty" dynamodbav:"AccountId,omitempty" schema:"accountId,omitempty"`          // AWS Account ID
-	StartDate       *int64   `json:"startDate,omitempty" dynamodbav:"StartDate" schema:"startDate,omitempty"`                    // Usage start date Epoch Timestamp
-	EndDate         *int64   `json:"endDate,omitempty" dynamodbav:"EndDate,omitempty" schema:"endDate,omitempty"`                // Usage ends date Epoch Timestamp
-	CostAmount      *float64 `json:"costAmount,omitempty" dynamodbav:"CostAmount,omitempty" schema:"costAmount,omitempty"`       // Cost Amount for given period
-	CostCurrency    *string  `json:"costCurrency,omitempty" dynamodbav:"CostCurrency,omitempty" schema:"costCurrency,omitempty"` // Cost currency
-	TimeToLive      *int64   `json:"timeToLive,omitempty" dynamodbav:"TimeToLive,omitempty" schema:"timeToLive,omitempty"`       // ttl attribute
-	Limit           *int64   `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
-	NextStartDate   *int64   `json:"-" dynamodbav:"-" schema:"nextStartDate,omitempty"`
-	NextPrincipalID *string  `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
+	PrincipalID       *string                  `json:"principalId,omitempty" dynamodbav:"PrincipalId" schema:"principalId,omitempty"`              // User Principal ID
+	AccountID         *string                  `json:"accountId,omitempty" dynamodbav:"AccountId,omitempty" schema:"accountId,omitempty"`          // AWS Account ID
+	StartDate         *int64                   `json:"startDate,omitempty" dynamodbav:"StartDate" schema:"startDate,omitempty"`                    // Usage start date Epoch Timestamp
+	EndDate           *int64                   `json:"endDate,omitempty" dynamodbav:"EndDate,omitempty" schema:"endDate,omitempty"`                // Usage ends date Epoch Timestamp
+	CostAmount        *float64                 `json:"costAmount,omitempty" dynamodbav:"CostAmount,omitempty" schema:"costAmount,omitempty"`       // Cost Amount for given period
+	CostCurrency      *string                  `json:"costCurrency,omitempty" dynamodbav:"CostCurrency,omitempty" schema:"costCurrency,omitempty"` // Cost currency
+	CostMetric        *string                  `json:"costMetric,omitempty" dynamodbav:"CostMetric,omitempty" schema:"costMetric,omitempty"`       // Cost Explorer metric the cost amount was calculated with
+	TimeToLive        *int64                   `json:"timeToLive,omitempty" dynamodbav:"TimeToLive,omitempty" schema:"timeToLive,omitempty"`       // ttl attribute
+	AccountCosts      map[string]float64       `json:"accountCosts,omitempty" dynamodbav:"AccountCosts,omitempty" schema:"-"`                      // Cost Amount for given period, by AWS Account ID
+	AccountBreakdowns map[string]CostBreakdown `json:"accountBreakdowns,omitempty" dynamodbav:"AccountBreakdowns,omitempty" schema:"-"`            // Cost Amount for given period by AWS service and region, by AWS Account ID
+	Version           *int64                   `json:"-" dynamodbav:"Version,omitempty" schema:"-"`                                                // Incremented on every write of the record
+	Limit             *int64                   `json:"-" dynamodbav:"-" schema:"limit,omitempty"`
+	NextStartDate     *int64                   `json:"-" dynamodbav:"-" schema:"nextStartDate,omitempty"`
+	NextPrincipalID   *string                  `json:"-" dynamodbav:"-" schema:"nextPrincipalId,omitempty"`
 }
 
 // Validate the account data
@@ -44,7 +50,9 @@ type NewUsageInput struct {
 	EndDate      int64
 	CostAmount   float64
 	CostCurrency string
+	CostMetric   string
 	TimeToLive   int64
+	Breakdown    *CostBreakdown
 }
 
 // NewUsage creates a new instance of usage
@@ -58,6 +66,19 @@ func NewUsage(input NewUsageInput) (*Usage, error) {
 		CostAmount:   &input.CostAmount,
 		CostCurrency: &input.CostCurrency,
 		TimeToLive:   &input.TimeToLive,
+		AccountCosts: map[string]float64{
+			input.AccountID: input.CostAmount,
+		},
+	}
+
+	if input.CostMetric != "" {
+		new.CostMetric = &input.CostMetric
+	}
+
+	if input.Breakdown != nil {
+		new.AccountBreakdowns = map[string]CostBreakdown{
+			input.AccountID: *input.Breakdown,
+		}
 	}
 
 	err := new.Validate()
@@ -68,5 +89,88 @@ func NewUsage(input NewUsageInput) (*Usage, error) {
 
 }
 
+// AccountCost returns the cost amount of the usage for a single account.
+// A principal with multiple active leases has one usage record per day,
+// with the cost of each leased account in AccountCosts.
+func (u *Usage) AccountCost(accountID string) float64 {
+	if u.AccountCosts != nil {
+		return u.AccountCosts[accountID]
+	}
+	// Records written before AccountCosts was added only have a single account
+	if u.AccountID != nil && *u.AccountID == accountID && u.CostAmount != nil {
+		return *u.CostAmount
+	}
+	return 0
+}
+
+// Breakdown returns the cost breakdown of the usage, summed across all accounts
+func (u *Usage) Breakdown() CostBreakdown {
+	breakdown := CostBreakdown{}
+	for _, accountBreakdown := range u.AccountBreakdowns {
+		breakdown.Add(accountBreakdown)
+	}
+	return breakdown
+}
+
+// CostBreakdown is a cost amount by AWS service, and by AWS region
+type CostBreakdown struct {
+	Services map[string]float64 `json:"services,omitempty" dynamodbav:"Services,omitempty"`
+	Regions  map[string]float64 `json:"regions,omitempty" dynamodbav:"Regions,omitempty"`
+}
+
+// Add the costs of another breakdown to this breakdown
+func (b *CostBreakdown) Add(other CostBreakdown) {
+	b.Services = addCosts(b.Services, other.Services)
+	b.Regions = addCosts(b.Regions, other.Regions)
+}
+
+// TopServices returns the n most expensive AWS services, most expensive first
+func (b *CostBreakdown) TopServices(n int) []Cost {
+	return topCosts(b.Services, n)
+}
+
+// TopRegions returns the n most expensive AWS regions, most expensive first
+func (b *CostBreakdown) TopRegions(n int) []Cost {
+	return topCosts(b.Regions, n)
+}
+
+// Cost is the cost amount of a single AWS service or region
+type Cost struct {
+	Name       string  `json:"name"`
+	CostAmount float64 `json:"costAmount"`
+}
+
+func addCosts(costs map[string]float64, other map[string]float64) map[string]float64 {
+	if len(other) == 0 {
+		return costs
+	}
+	if costs == nil {
+		costs = map[string]float64{}
+	}
+	for name, cost := range other {
+		costs[name] = costs[name] + cost
+	}
+	return costs
+}
+
+// topCosts orders costs most expensive first, by name for equal costs.
+// A negative n returns all of the costs
+func topCosts(costs map[string]float64, n int) []Cost {
+	sorted := []Cost{}
+	for name, cost := range costs {
+		sorted = append(sorted, Cost{Name: name, CostAmount: cost})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CostAmount == sorted[j].CostAmount {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].CostAmount > sorted[j].CostAmount
+	})
+	if n >= 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
 // Usages is a list of type Usage
 type Usages []Usage
@@ -0,0 +1,112 @@
+package usage_test
+
+import (
+	"testing"
+
+	"github.com/Optum/dce/pkg/usage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountCost(t *testing.T) {
+	costAmount := 30.0
+
+	tests := []struct {
+		name      string
+		usage     *usage.Usage
+		accountID string
+		exp       float64
+	}{
+		{
+			name: "should return the account's cost",
+			usage: &usage.Usage{
+				AccountID:  &accountID,
+				CostAmount: &costAmount,
+				AccountCosts: map[string]float64{
+					"123456789012": 10,
+					"210987654321": 20,
+				},
+			},
+			accountID: "210987654321",
+			exp:       20,
+		},
+		{
+			name: "should return zero for another account",
+			usage: &usage.Usage{
+				AccountID:  &accountID,
+				CostAmount: &costAmount,
+				AccountCosts: map[string]float64{
+					"123456789012": 30,
+				},
+			},
+			accountID: "210987654321",
+			exp:       0,
+		},
+		{
+			name: "should return the cost amount without account costs",
+			usage: &usage.Usage{
+				AccountID:  &accountID,
+				CostAmount: &costAmount,
+			},
+			accountID: "123456789012",
+			exp:       30,
+		},
+		{
+			name: "should return zero for another account without account costs",
+			usage: &usage.Usage{
+				AccountID:  &accountID,
+				CostAmount: &costAmount,
+			},
+			accountID: "210987654321",
+			exp:       0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, tt.usage.AccountCost(tt.accountID))
+		})
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	u := &usage.Usage{
+		AccountBreakdowns: map[string]usage.CostBreakdown{
+			"123456789012": {
+				Services: map[string]float64{
+					"Amazon EC2": 10,
+					"Amazon S3":  5,
+				},
+			},
+			"210987654321": {
+				Services: map[string]float64{
+					"Amazon EC2":    20,
+					"Amazon Athena": 5,
+				},
+				Regions: map[string]float64{
+					"us-east-1": 25,
+				},
+			},
+		},
+	}
+
+	breakdown := u.Breakdown()
+	assert.Equal(t, map[string]float64{
+		"Amazon EC2":    30,
+		"Amazon S3":     5,
+		"Amazon Athena": 5,
+	}, breakdown.Services)
+	assert.Equal(t, map[string]float64{
+		"us-east-1": 25,
+	}, breakdown.Regions)
+
+	assert.Equal(t, []usage.Cost{
+		{Name: "Amazon EC2", CostAmount: 30},
+		{Name: "Amazon Athena", CostAmount: 5},
+	}, breakdown.TopServices(2))
+	assert.Equal(t, []usage.Cost{
+		{Name: "us-east-1", CostAmount: 25},
+	}, breakdown.TopRegions(-1))
+
+	empty := (&usage.Usage{}).Breakdown()
+	assert.Equal(t, []usage.Cost{}, empty.TopServices(5))
+}
@@ -83,6 +83,11 @@ func (a *Service) Create(data *Usage) (*Usage, error) {
 		}
 	}
 
+	var breakdown *CostBreakdown
+	if accountBreakdown, ok := data.AccountBreakdowns[*data.AccountID]; ok {
+		breakdown = &accountBreakdown
+	}
+
 	new, err := NewUsage(NewUsageInput{
 		StartDate:    *data.StartDate,
 		PrincipalID:  *data.PrincipalID,
@@ -91,6 +96,7 @@ func (a *Service) Create(data *Usage) (*Usage, error) {
 		CostAmount:   *data.CostAmount,
 		CostCurrency: *data.CostCurrency,
 		TimeToLive:   *data.TimeToLive,
+		Breakdown:    breakdown,
 	})
 	if err != nil {
 		return nil, err
@@ -208,6 +208,9 @@ func TestCreate(t *testing.T) {
 					CostCurrency: &costCurrency,
 					CostAmount:   &costAmount,
 					TimeToLive:   &timeToLive,
+					AccountCosts: map[string]float64{
+						accountID: costAmount,
+					},
 				},
 				err: nil,
 			},
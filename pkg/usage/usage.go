@@ -9,6 +9,7 @@ import (
 
 	"github.com/Optum/dce/pkg/common"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -39,8 +40,99 @@ type DBer interface {
 	GetUsageByPrincipal(startDate time.Time, principalID string) ([]*Usage, error)
 }
 
-// PutUsage adds an item to Usage DB
+// putUsageMaxAttempts is the number of times PutUsage will retry
+// when the principal's usage record is changed by another writer
+const putUsageMaxAttempts = 5
+
+// PutUsage adds the account's cost to the principal's usage record for the start date.
+// A principal may have usage in more than one leased account on the same day,
+// so the cost of each account is kept in AccountCosts and CostAmount is their sum.
+// AccountID is only kept while the record has the cost of a single account.
 func (db *DB) PutUsage(input Usage) error {
+	if input.StartDate == nil || input.PrincipalID == nil || input.AccountID == nil || input.CostAmount == nil {
+		return fmt.Errorf("usage record requires a start date, principal ID, account ID, and cost amount")
+	}
+
+	var err error
+	for attempt := 1; attempt <= putUsageMaxAttempts; attempt++ {
+		err = db.mergeUsage(input)
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			log.Printf("Usage record for start date \"%d\" and PrincipalID \"%s\" was modified, retrying (attempt %d of %d)",
+				*input.StartDate, *input.PrincipalID, attempt, putUsageMaxAttempts)
+			continue
+		}
+		return err
+	}
+	return err
+}
+
+// mergeUsage reads the principal's usage record, merges in the account's cost,
+// and writes it back with the next version.  The write fails with a
+// ConditionalCheckFailedException if the record was changed since it was read.
+func (db *DB) mergeUsage(input Usage) error {
+	resp, err := db.Client.GetItem(getInputForGetUsageByPrincipalID(db, time.Unix(*input.StartDate, 0), *input.PrincipalID, true))
+	if err != nil {
+		errorMessage := fmt.Sprintf("Failed to get usage record for start date \"%d\" and PrincipalID \"%s\": %s.", *input.StartDate, *input.PrincipalID, err)
+		log.Print(errorMessage)
+		return err
+	}
+
+	accountCosts := map[string]float64{}
+	accountBreakdowns := map[string]CostBreakdown{}
+	conditionExpression := "attribute_not_exists(StartDate)"
+	var conditionValues map[string]*dynamodb.AttributeValue
+	version := int64(1)
+	if len(resp.Item) > 0 {
+		existing, err := unmarshalUsageRecord(resp.Item)
+		if err != nil {
+			return err
+		}
+		if existing.AccountCosts != nil {
+			for accountID, cost := range existing.AccountCosts {
+				accountCosts[accountID] = cost
+			}
+		} else if existing.AccountID != nil && existing.CostAmount != nil {
+			// Records written before AccountCosts was added only have a single account
+			accountCosts[*existing.AccountID] = *existing.CostAmount
+		}
+		for accountID, breakdown := range existing.AccountBreakdowns {
+			accountBreakdowns[accountID] = breakdown
+		}
+
+		// Only write if no one else has written the record since it was read
+		if existing.Version != nil {
+			conditionExpression = "Version = :prevVersion"
+			conditionValues = map[string]*dynamodb.AttributeValue{
+				":prevVersion": {N: aws.String(strconv.FormatInt(*existing.Version, 10))},
+			}
+			version = *existing.Version + 1
+		} else {
+			conditionExpression = "attribute_exists(StartDate) and attribute_not_exists(Version)"
+		}
+	}
+	input.Version = &version
+
+	accountCosts[*input.AccountID] = *input.CostAmount
+	costAmount := 0.0
+	for _, cost := range accountCosts {
+		costAmount = costAmount + cost
+	}
+	input.AccountCosts = accountCosts
+
+	// Keep the existing breakdown for the account, if the input doesn't have one
+	if breakdown, ok := input.AccountBreakdowns[*input.AccountID]; ok {
+		accountBreakdowns[*input.AccountID] = breakdown
+	}
+	if len(accountBreakdowns) > 0 {
+		input.AccountBreakdowns = accountBreakdowns
+	}
+	input.CostAmount = &costAmount
+
+	// A record with the costs of several accounts isn't for any one account
+	if len(accountCosts) > 1 {
+		input.AccountID = nil
+	}
+
 	item, err := dynamodbattribute.MarshalMap(input)
 	if err != nil {
 		errorMessage := fmt.Sprintf("Failed to add usage record for start date \"%d\" and PrincipalID \"%s\": %s.", *input.StartDate, *input.PrincipalID, err)
@@ -50,8 +142,10 @@ func (db *DB) PutUsage(input Usage) error {
 
 	_, err = db.Client.PutItem(
 		&dynamodb.PutItemInput{
-			TableName: aws.String(db.UsageTableName),
-			Item:      item,
+			TableName:                 aws.String(db.UsageTableName),
+			Item:                      item,
+			ConditionExpression:       aws.String(conditionExpression),
+			ExpressionAttributeValues: conditionValues,
 		},
 	)
 	return err
@@ -189,6 +283,7 @@ type GetUsageOutput struct {
 func (db *DB) GetUsage(input GetUsageInput) (GetUsageOutput, error) {
 	limit := int64(25)
 	filters := make([]string, 0)
+	filterNames := make(map[string]*string)
 	filterValues := make(map[string]*dynamodb.AttributeValue)
 
 	if input.Limit > 0 {
@@ -213,7 +308,9 @@ func (db *DB) GetUsage(input GetUsageInput) (GetUsageOutput, error) {
 	}
 
 	if input.AccountID != "" {
-		filters = append(filters, "AccountId = :accountId")
+		// Records with the costs of several accounts only have them in AccountCosts
+		filters = append(filters, "(AccountId = :accountId or attribute_exists(AccountCosts.#accountId))")
+		filterNames["#accountId"] = aws.String(input.AccountID)
 		filterValues[":accountId"] = &dynamodb.AttributeValue{S: aws.String(input.AccountID)}
 	}
 
@@ -221,6 +318,9 @@ func (db *DB) GetUsage(input GetUsageInput) (GetUsageOutput, error) {
 		filterStatement := strings.Join(filters, " and ")
 		scanInput.FilterExpression = &filterStatement
 		scanInput.ExpressionAttributeValues = filterValues
+		if len(filterNames) > 0 {
+			scanInput.ExpressionAttributeNames = filterNames
+		}
 	}
 
 	if input.StartKeys != nil && len(input.StartKeys) > 0 {
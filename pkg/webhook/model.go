@@ -0,0 +1,113 @@
+// Package webhook manages subscriptions which deliver DCE events to HTTP
+// endpoints, signed with a secret shared with the subscriber
+package webhook
+
+import (
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event"
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+// DeliverableEventTypes are the types of events which can be delivered to webhooks
+var DeliverableEventTypes = []event.Type{
+	event.TypeAccountCreated,
+	event.TypeAccountDeleted,
+	event.TypeLeaseCreated,
+	event.TypeLeaseEnded,
+	event.TypeLeaseReservationFailed,
+}
+
+// Webhook - Handles importing and exporting Webhooks
+type Webhook struct {
+	ID             *string      `json:"id,omitempty" dynamodbav:"Id"`                                   // Webhook ID
+	URL            *string      `json:"url,omitempty" dynamodbav:"Url"`                                 // URL events are POSTed to
+	EventTypes     []event.Type `json:"eventTypes,omitempty" dynamodbav:"EventTypes,omitempty"`         // Types of events delivered, or every type when empty
+	Secret         *string      `json:"secret,omitempty" dynamodbav:"-"`                                // Key the payloads are signed with. Stored outside of the webhooks table
+	CurrentSecret  *string      `json:"currentSecret,omitempty" dynamodbav:"-"`                         // Secret being replaced, required to change the secret
+	CreatedOn      *int64       `json:"createdOn,omitempty" dynamodbav:"CreatedOn,omitempty"`           // Webhook CreatedOn
+	LastModifiedOn *int64       `json:"lastModifiedOn,omitempty" dynamodbav:"LastModifiedOn,omitempty"` // Last Modified Epoch Timestamp
+}
+
+// Validate the webhook data
+func (w *Webhook) Validate() error {
+	err := validation.ValidateStruct(w,
+		validation.Field(&w.ID, validateID...),
+		validation.Field(&w.URL, validateURL...),
+		validation.Field(&w.EventTypes, validateEventTypes...),
+		validation.Field(&w.Secret, validateSecret...),
+		validation.Field(&w.LastModifiedOn, validateInt64...),
+		validation.Field(&w.CreatedOn, validateInt64...),
+	)
+	if err != nil {
+		return errors.NewValidation("webhook", err)
+	}
+	return nil
+}
+
+// Subscribes returns true if events of the type are delivered to the webhook
+func (w *Webhook) Subscribes(eventType event.Type) bool {
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range w.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy of the webhook without its secret.
+// The secret is only returned when the webhook is created
+func (w Webhook) Redacted() *Webhook {
+	w.Secret = nil
+	w.CurrentSecret = nil
+	return &w
+}
+
+// Webhooks is a list of type Webhook
+type Webhooks []Webhook
+
+// Redacted returns a copy of the webhooks without their secrets
+func (w Webhooks) Redacted() *Webhooks {
+	redacted := Webhooks{}
+	for _, hook := range w {
+		redacted = append(redacted, *hook.Redacted())
+	}
+	return &redacted
+}
+
+// DeadLetter is an event which couldn't be delivered to a webhook
+type DeadLetter struct {
+	WebhookID *string     `json:"webhookId,omitempty" dynamodbav:"WebhookId"`           // ID of the webhook
+	EventID   *string     `json:"eventId,omitempty" dynamodbav:"EventId"`               // ID of the event
+	EventType *event.Type `json:"eventType,omitempty" dynamodbav:"EventType"`           // Type of the event
+	URL       *string     `json:"url,omitempty" dynamodbav:"Url"`                       // URL the event was POSTed to
+	Payload   *string     `json:"payload,omitempty" dynamodbav:"Payload"`               // JSON body of the delivery
+	Attempts  *int64      `json:"attempts,omitempty" dynamodbav:"Attempts"`             // Number of delivery attempts
+	LastError *string     `json:"lastError,omitempty" dynamodbav:"LastError,omitempty"` // Error from the last attempt
+	CreatedOn *int64      `json:"createdOn,omitempty" dynamodbav:"CreatedOn"`           // Epoch Timestamp the delivery was abandoned
+}
+
+// NewDeadLetter records the failed delivery of the event to the webhook
+func NewDeadLetter(hook *Webhook, evt *event.CloudEvent, payload []byte, attempts int64, lastError error) *DeadLetter {
+	now := time.Now().Unix()
+	p := string(payload)
+	var lastErr *string
+	if lastError != nil {
+		msg := lastError.Error()
+		lastErr = &msg
+	}
+	return &DeadLetter{
+		WebhookID: hook.ID,
+		EventID:   &evt.ID,
+		EventType: &evt.Type,
+		URL:       hook.URL,
+		Payload:   &p,
+		Attempts:  &attempts,
+		LastError: lastErr,
+		CreatedOn: &now,
+	}
+}
@@ -0,0 +1,385 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event"
+	"github.com/aws/aws-sdk-go/aws"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/imdario/mergo"
+)
+
+// Writer put an item into the data store
+type Writer interface {
+	Write(i *Webhook, lastModifiedOn *int64) error
+}
+
+// Deleter Deletes a Webhook from the data store
+type Deleter interface {
+	Delete(i *Webhook) error
+}
+
+// SingleReader Reads Webhook information from the data store
+type SingleReader interface {
+	Get(ID string) (*Webhook, error)
+}
+
+// MultipleReader reads multiple webhooks from the data store
+type MultipleReader interface {
+	List() (*Webhooks, error)
+}
+
+// Reader data Layer
+type Reader interface {
+	SingleReader
+	MultipleReader
+}
+
+// WriterDeleter data layer
+type WriterDeleter interface {
+	Writer
+	Deleter
+}
+
+// DeadLetterWriter records events which couldn't be delivered
+type DeadLetterWriter interface {
+	WriteDeadLetter(i *DeadLetter) error
+}
+
+// ReaderWriterDeleter includes Reader and Writer interfaces
+type ReaderWriterDeleter interface {
+	Reader
+	WriterDeleter
+	DeadLetterWriter
+}
+
+// HTTPClient sends the requests which deliver events
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Service is a type corresponding to a Webhook table record
+type Service struct {
+	dataSvc     ReaderWriterDeleter
+	client      HTTPClient
+	maxAttempts int64
+	backoffBase time.Duration
+	backoffMax  time.Duration
+	sleep       func(time.Duration)
+}
+
+// Get returns a webhook from ID
+func (s *Service) Get(ID string) (*Webhook, error) {
+
+	new, err := s.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, err
+}
+
+// Save writes the record to the dataSvc
+func (s *Service) Save(data *Webhook) error {
+	var lastModifiedOn *int64
+	now := time.Now().Unix()
+	if data.LastModifiedOn == nil {
+		lastModifiedOn = nil
+		data.CreatedOn = &now
+		data.LastModifiedOn = &now
+	} else {
+		lastModifiedOn = data.LastModifiedOn
+		data.LastModifiedOn = &now
+	}
+
+	err := data.Validate()
+	if err != nil {
+		return err
+	}
+	err = s.dataSvc.Write(data, lastModifiedOn)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Create creates a new webhook using the data provided.  A secret is generated
+// when one isn't provided.  Returns the webhook record, including its secret
+func (s *Service) Create(data *Webhook) (*Webhook, error) {
+	// Validate the incoming record doesn't have unneeded fields
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.By(isNil)),
+		validation.Field(&data.URL, validateURL...),
+		validation.Field(&data.EventTypes, validateEventTypes...),
+		validation.Field(&data.CurrentSecret, validation.By(isNil)),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("webhook", err)
+	}
+
+	secret := data.Secret
+	if secret == nil {
+		secret, err = newSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	id := uuid.New().String()
+	new := &Webhook{
+		ID:         &id,
+		URL:        data.URL,
+		EventTypes: data.EventTypes,
+		Secret:     secret,
+	}
+
+	err = s.Save(new)
+	if err != nil {
+		return nil, err
+	}
+
+	return new, nil
+}
+
+// Update the Webhook record in DynamoDB.  Replacing the secret requires the current secret
+func (s *Service) Update(ID string, data *Webhook) (*Webhook, error) {
+	currentSecretRules := []validation.Rule{validation.By(isNil)}
+	if data.Secret != nil {
+		currentSecretRules = []validation.Rule{validation.NotNil.Error("must be provided to replace the secret")}
+	}
+	err := validation.ValidateStruct(data,
+		validation.Field(&data.ID, validation.NilOrNotEmpty, validation.In(ID)),
+		validation.Field(&data.URL, validation.By(isHTTPURL)),
+		validation.Field(&data.EventTypes, validateEventTypes...),
+		validation.Field(&data.CurrentSecret, currentSecretRules...),
+		validation.Field(&data.LastModifiedOn, validation.By(isNil)),
+		validation.Field(&data.CreatedOn, validation.By(isNil)),
+	)
+	if err != nil {
+		return nil, errors.NewValidation("webhook", err)
+	}
+
+	hook, err := s.dataSvc.Get(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only whoever holds the secret can replace it, so subscribers can keep trusting signatures
+	if data.CurrentSecret != nil {
+		if !hmac.Equal([]byte(*data.CurrentSecret), []byte(aws.StringValue(hook.Secret))) {
+			return nil, errors.NewValidation("webhook", fmt.Errorf("currentSecret: must match the webhook's secret.")) //nolint golint
+		}
+		data.CurrentSecret = nil
+	}
+
+	err = mergo.Merge(hook, *data, mergo.WithOverride)
+	if err != nil {
+		return nil, errors.NewInternalServer("unexpected error updating webhook", err)
+	}
+	// An empty list of event types subscribes the webhook to every type
+	if data.EventTypes != nil {
+		hook.EventTypes = data.EventTypes
+	}
+
+	err = s.Save(hook)
+	if err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Delete the webhook
+func (s *Service) Delete(data *Webhook) error {
+	return s.dataSvc.Delete(data)
+}
+
+// List Get the list of webhooks
+func (s *Service) List() (*Webhooks, error) {
+
+	hooks, err := s.dataSvc.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return hooks, nil
+}
+
+// Deliver the event to each webhook subscribed to its type.  Failed deliveries
+// are retried with exponential backoff, and recorded as a dead letter after the
+// last attempt.  Returns an error if a dead letter couldn't be written
+func (s *Service) Deliver(evt *event.CloudEvent) error {
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return errors.NewInternalServer("unable to marshal event", err)
+	}
+
+	hooks, err := s.dataSvc.List()
+	if err != nil {
+		return err
+	}
+
+	// Slow webhooks shouldn't delay delivery to the rest
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	deferredErrors := []error{}
+	for i := range *hooks {
+		hook := &(*hooks)[i]
+		if !hook.Subscribes(evt.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.deliver(hook, evt, payload)
+			if err != nil {
+				mu.Lock()
+				deferredErrors = append(deferredErrors, err)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(deferredErrors) > 0 {
+		return errors.NewMultiError("failed to record undelivered events", deferredErrors)
+	}
+	return nil
+}
+
+// deliver POSTs the payload to the webhook until it succeeds, it fails with
+// an error which won't be fixed by a retry, or the attempts run out
+func (s *Service) deliver(hook *Webhook, evt *event.CloudEvent, payload []byte) error {
+	delay := s.backoffBase
+	var attempt int64
+	for attempt = 1; ; attempt++ {
+		retry, err := s.post(hook, evt, payload)
+		if err == nil {
+			log.Printf("Delivered %s event %s to webhook %s", evt.Type, evt.ID, *hook.ID)
+			return nil
+		}
+		if !retry || attempt >= s.maxAttempts {
+			log.Printf("ERROR: Failed to deliver %s event %s to webhook %s after %d attempts: %s",
+				evt.Type, evt.ID, *hook.ID, attempt, err)
+			return s.dataSvc.WriteDeadLetter(NewDeadLetter(hook, evt, payload, attempt, err))
+		}
+
+		log.Printf("Failed to deliver %s event %s to webhook %s on attempt %d of %d, retrying in %s: %s",
+			evt.Type, evt.ID, *hook.ID, attempt, s.maxAttempts, delay, err)
+		s.sleep(delay)
+		delay *= 2
+		if delay > s.backoffMax {
+			delay = s.backoffMax
+		}
+	}
+}
+
+// post the signed payload to the webhook.  Returns whether a failed request
+// should be retried
+func (s *Service) post(hook *Webhook, evt *event.CloudEvent, payload []byte) (bool, error) {
+	if hook.Secret == nil {
+		return false, errors.NewInternalServer("webhook has no secret to sign the delivery with", nil)
+	}
+	req, err := NewRequest(hook, evt, payload, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+	retry := res.StatusCode >= 500 ||
+		res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode == http.StatusRequestTimeout
+	return retry, errors.NewInternalServer(
+		"webhook responded with status "+res.Status,
+		nil,
+	)
+}
+
+// newSecret generates a random signing secret
+func newSecret() (*string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return nil, errors.NewInternalServer("unable to generate webhook secret", err)
+	}
+	secret := hex.EncodeToString(b)
+	return &secret, nil
+}
+
+// NewServiceInput Input for creating a new Service
+type NewServiceInput struct {
+	MaxAttempts        int64 `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+	BackoffBaseSeconds int64 `env:"WEBHOOK_BACKOFF_BASE_SECONDS" envDefault:"1"`
+	BackoffMaxSeconds  int64 `env:"WEBHOOK_BACKOFF_MAX_SECONDS" envDefault:"30"`
+	TimeoutSeconds     int64 `env:"WEBHOOK_TIMEOUT_SECONDS" envDefault:"10"`
+	DataSvc            ReaderWriterDeleter
+	HTTPClient         HTTPClient
+}
+
+// newHTTPClient creates the client which delivers events.  It doesn't follow
+// redirects, so a signed delivery is only ever sent to the webhook's URL, and
+// refuses to connect to private, loopback and link-local addresses, even when
+// a public hostname resolves to one
+func newHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isPrivateIP(ip) {
+				return fmt.Errorf("webhook address %s is private, loopback or link-local", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// NewService creates a new instance of the Service
+func NewService(input NewServiceInput) *Service {
+	client := input.HTTPClient
+	if client == nil {
+		client = newHTTPClient(time.Duration(input.TimeoutSeconds) * time.Second)
+	}
+	return &Service{
+		dataSvc:     input.DataSvc,
+		client:      client,
+		maxAttempts: input.MaxAttempts,
+		backoffBase: time.Duration(input.BackoffBaseSeconds) * time.Second,
+		backoffMax:  time.Duration(input.BackoffMaxSeconds) * time.Second,
+		sleep:       time.Sleep,
+	}
+}
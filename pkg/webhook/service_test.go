@@ -0,0 +1,403 @@
+package webhook_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event"
+	"github.com/Optum/dce/pkg/webhook"
+	"github.com/Optum/dce/pkg/webhook/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func ptrString(s string) *string {
+	ptrS := s
+	return &ptrS
+}
+
+func ptrInt64(i int64) *int64 {
+	ptrI := i
+	return &ptrI
+}
+
+func TestCreateWebhook(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		req         *webhook.Webhook
+		expectWrite bool
+		expErr      error
+	}{
+		{
+			name: "should create a webhook with a generated secret",
+			req: &webhook.Webhook{
+				URL:        ptrString("https://example.com/hook"),
+				EventTypes: []event.Type{event.TypeLeaseCreated},
+			},
+			expectWrite: true,
+		},
+		{
+			name: "should create a webhook with the secret provided",
+			req: &webhook.Webhook{
+				URL:    ptrString("https://example.com/hook"),
+				Secret: ptrString("0123456789abcdef"),
+			},
+			expectWrite: true,
+		},
+		{
+			name: "should fail on a short secret",
+			req: &webhook.Webhook{
+				URL:    ptrString("https://example.com/hook"),
+				Secret: ptrString("secret"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("secret: must be at least 16 characters.")), //nolint golint
+		},
+		{
+			name: "should fail on an invalid URL",
+			req: &webhook.Webhook{
+				URL: ptrString("ftp://example.com/hook"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("url: must be an http or https URL.")), //nolint golint
+		},
+		{
+			name: "should fail on an event type which isn't delivered",
+			req: &webhook.Webhook{
+				URL:        ptrString("https://example.com/hook"),
+				EventTypes: []event.Type{event.TypeAccountReset},
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("eventTypes: must be types of events delivered to webhooks.")), //nolint golint
+		},
+		{
+			name: "should fail when an ID is provided",
+			req: &webhook.Webhook{
+				ID:  ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+				URL: ptrString("https://example.com/hook"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("id: must be empty.")), //nolint golint
+		},
+		{
+			name: "should fail on a link-local URL",
+			req: &webhook.Webhook{
+				URL: ptrString("http://169.254.169.254/latest/meta-data"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("url: must not be a private, loopback or link-local address.")), //nolint golint
+		},
+		{
+			name: "should fail on a private URL",
+			req: &webhook.Webhook{
+				URL: ptrString("https://10.1.2.3:8443/hook"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("url: must not be a private, loopback or link-local address.")), //nolint golint
+		},
+		{
+			name: "should fail on a loopback URL",
+			req: &webhook.Webhook{
+				URL: ptrString("http://localhost:8080/hook"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("url: must not be a private, loopback or link-local address.")), //nolint golint
+		},
+		{
+			name: "should fail when a current secret is provided",
+			req: &webhook.Webhook{
+				URL:           ptrString("https://example.com/hook"),
+				CurrentSecret: ptrString("0123456789abcdef"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("currentSecret: must be empty.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Write", mock.AnythingOfType("*webhook.Webhook"), (*int64)(nil)).Return(nil)
+
+			webhookSvc := webhook.NewService(webhook.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			hook, err := webhookSvc.Create(tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expectWrite {
+				assert.NotNil(t, hook.ID)
+				assert.Equal(t, tt.req.URL, hook.URL)
+				assert.True(t, len(*hook.Secret) >= 16)
+				if tt.req.Secret != nil {
+					assert.Equal(t, tt.req.Secret, hook.Secret)
+				}
+				mocksRwd.AssertNumberOfCalls(t, "Write", 1)
+			} else {
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestUpdateWebhook(t *testing.T) {
+	existing := func() *webhook.Webhook {
+		return &webhook.Webhook{
+			ID:             ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+			URL:            ptrString("https://example.com/hook"),
+			EventTypes:     []event.Type{event.TypeLeaseCreated},
+			Secret:         ptrString("0123456789abcdef"),
+			CreatedOn:      ptrInt64(1573592058),
+			LastModifiedOn: ptrInt64(1573592058),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		req       *webhook.Webhook
+		expHook   *webhook.Webhook
+		expSecret *string
+		expErr    error
+	}{
+		{
+			name: "should update the URL",
+			req: &webhook.Webhook{
+				URL: ptrString("https://example.com/other"),
+			},
+			expHook: &webhook.Webhook{
+				URL:        ptrString("https://example.com/other"),
+				EventTypes: []event.Type{event.TypeLeaseCreated},
+			},
+		},
+		{
+			name: "should subscribe to every type of event",
+			req: &webhook.Webhook{
+				EventTypes: []event.Type{},
+			},
+			expHook: &webhook.Webhook{
+				URL:        ptrString("https://example.com/hook"),
+				EventTypes: []event.Type{},
+			},
+		},
+		{
+			name: "should replace the secret with the current secret",
+			req: &webhook.Webhook{
+				Secret:        ptrString("fedcba9876543210"),
+				CurrentSecret: ptrString("0123456789abcdef"),
+			},
+			expHook: &webhook.Webhook{
+				URL:        ptrString("https://example.com/hook"),
+				EventTypes: []event.Type{event.TypeLeaseCreated},
+			},
+			expSecret: ptrString("fedcba9876543210"),
+		},
+		{
+			name: "should fail to replace the secret without the current secret",
+			req: &webhook.Webhook{
+				Secret: ptrString("fedcba9876543210"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("currentSecret: must be provided to replace the secret.")), //nolint golint
+		},
+		{
+			name: "should fail to replace the secret with the wrong current secret",
+			req: &webhook.Webhook{
+				Secret:        ptrString("fedcba9876543210"),
+				CurrentSecret: ptrString("not-the-secret"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("currentSecret: must match the webhook's secret.")), //nolint golint
+		},
+		{
+			name: "should fail on a current secret without a new secret",
+			req: &webhook.Webhook{
+				CurrentSecret: ptrString("0123456789abcdef"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("currentSecret: must be empty.")), //nolint golint
+		},
+		{
+			name: "should fail on a private URL",
+			req: &webhook.Webhook{
+				URL: ptrString("http://192.168.0.10/hook"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("url: must not be a private, loopback or link-local address.")), //nolint golint
+		},
+		{
+			name: "should fail on a new ID",
+			req: &webhook.Webhook{
+				ID: ptrString("other"),
+			},
+			expErr: errors.NewValidation("webhook", fmt.Errorf("id: must be a valid value.")), //nolint golint
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("Get", "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e").Return(existing(), nil)
+			mocksRwd.On("Write", mock.AnythingOfType("*webhook.Webhook"), ptrInt64(1573592058)).Return(nil)
+
+			webhookSvc := webhook.NewService(webhook.NewServiceInput{
+				DataSvc: mocksRwd,
+			})
+
+			hook, err := webhookSvc.Update("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e", tt.req)
+			assert.True(t, errors.Is(err, tt.expErr), "actual error %q doesn't match expected error %q", err, tt.expErr)
+			if tt.expHook != nil {
+				expSecret := ptrString("0123456789abcdef")
+				if tt.expSecret != nil {
+					expSecret = tt.expSecret
+				}
+				assert.Equal(t, tt.expHook.URL, hook.URL)
+				assert.Equal(t, tt.expHook.EventTypes, hook.EventTypes)
+				assert.Equal(t, expSecret, hook.Secret)
+				assert.Nil(t, hook.CurrentSecret)
+			} else {
+				mocksRwd.AssertNotCalled(t, "Write", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		statuses         []int
+		eventTypes       []event.Type
+		expRequests      int32
+		expDeadLetter    bool
+		expDeadLetterErr string
+	}{
+		{
+			name:        "should deliver the event",
+			statuses:    []int{http.StatusOK},
+			expRequests: 1,
+		},
+		{
+			name:        "should deliver the event to a webhook subscribed to its type",
+			statuses:    []int{http.StatusNoContent},
+			eventTypes:  []event.Type{event.TypeAccountCreated, event.TypeLeaseCreated},
+			expRequests: 1,
+		},
+		{
+			name:       "should skip a webhook which isn't subscribed to the type",
+			statuses:   []int{http.StatusOK},
+			eventTypes: []event.Type{event.TypeAccountCreated},
+		},
+		{
+			name:        "should retry until the delivery succeeds",
+			statuses:    []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			expRequests: 3,
+		},
+		{
+			name:             "should dead letter after the last attempt",
+			statuses:         []int{http.StatusBadGateway},
+			expRequests:      3,
+			expDeadLetter:    true,
+			expDeadLetterErr: "webhook responded with status 502 Bad Gateway",
+		},
+		{
+			name:             "should dead letter a rejected delivery without retrying",
+			statuses:         []int{http.StatusBadRequest},
+			expRequests:      1,
+			expDeadLetter:    true,
+			expDeadLetterErr: "webhook responded with status 400 Bad Request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := event.NewCloudEvent("dce", event.TypeLeaseCreated, map[string]string{"id": "abc-123"})
+
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&requests, 1)
+				body, _ := ioutil.ReadAll(r.Body)
+
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, evt.ID, r.Header.Get(webhook.EventIDHeader))
+				assert.Equal(t, string(event.TypeLeaseCreated), r.Header.Get(webhook.EventTypeHeader))
+				assert.True(t, webhook.Verify(
+					"0123456789abcdef",
+					r.Header.Get(webhook.TimestampHeader),
+					body,
+					r.Header.Get(webhook.SignatureHeader),
+				), "signature doesn't match")
+
+				delivered := &event.CloudEvent{}
+				assert.Nil(t, json.Unmarshal(body, delivered))
+				assert.Equal(t, evt.ID, delivered.ID)
+
+				status := tt.statuses[len(tt.statuses)-1]
+				if int(n) <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			mocksRwd := &mocks.ReaderWriterDeleter{}
+			mocksRwd.On("List").Return(&webhook.Webhooks{
+				{
+					ID:         ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+					URL:        ptrString(server.URL),
+					EventTypes: tt.eventTypes,
+					Secret:     ptrString("0123456789abcdef"),
+				},
+			}, nil)
+			mocksRwd.On("WriteDeadLetter", mock.AnythingOfType("*webhook.DeadLetter")).Return(nil)
+
+			webhookSvc := webhook.NewService(webhook.NewServiceInput{
+				DataSvc:     mocksRwd,
+				HTTPClient:  server.Client(),
+				MaxAttempts: 3,
+			})
+
+			err := webhookSvc.Deliver(evt)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expRequests, atomic.LoadInt32(&requests))
+			if tt.expDeadLetter {
+				mocksRwd.AssertCalled(t, "WriteDeadLetter", mock.MatchedBy(func(deadLetter *webhook.DeadLetter) bool {
+					return *deadLetter.WebhookID == "4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e" &&
+						*deadLetter.EventID == evt.ID &&
+						*deadLetter.Attempts == int64(tt.expRequests) &&
+						*deadLetter.LastError == tt.expDeadLetterErr
+				}))
+			} else {
+				mocksRwd.AssertNotCalled(t, "WriteDeadLetter", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestDeliverToPrivateAddress(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mocksRwd := &mocks.ReaderWriterDeleter{}
+	mocksRwd.On("List").Return(&webhook.Webhooks{
+		{
+			ID:     ptrString("4a6e5ea0-0bd4-4b5b-9a0d-4b9a6c1e1d2e"),
+			URL:    ptrString(server.URL),
+			Secret: ptrString("0123456789abcdef"),
+		},
+	}, nil)
+	mocksRwd.On("WriteDeadLetter", mock.AnythingOfType("*webhook.DeadLetter")).Return(nil)
+
+	// Without an HTTP client, the service refuses to connect to the loopback test server
+	webhookSvc := webhook.NewService(webhook.NewServiceInput{
+		DataSvc:        mocksRwd,
+		MaxAttempts:    1,
+		TimeoutSeconds: 1,
+	})
+
+	err := webhookSvc.Deliver(event.NewCloudEvent("dce", event.TypeLeaseCreated, map[string]string{"id": "abc-123"}))
+	assert.Nil(t, err)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	mocksRwd.AssertCalled(t, "WriteDeadLetter", mock.MatchedBy(func(deadLetter *webhook.DeadLetter) bool {
+		return strings.Contains(*deadLetter.LastError, "is private, loopback or link-local")
+	}))
+}
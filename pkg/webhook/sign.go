@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Optum/dce/pkg/errors"
+	"github.com/Optum/dce/pkg/event"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+// Headers sent with each delivery
+const (
+	// SignatureHeader is "sha256=" followed by the signature of the delivery
+	SignatureHeader = "X-DCE-Signature"
+	// TimestampHeader is the Epoch Timestamp the delivery was signed at
+	TimestampHeader = "X-DCE-Timestamp"
+	// EventIDHeader is the ID of the event, which is the same across retries
+	EventIDHeader = "X-DCE-Event-Id"
+	// EventTypeHeader is the type of the event
+	EventTypeHeader = "X-DCE-Event-Type"
+	// signaturePrefix names the algorithm of the signature
+	signaturePrefix = "sha256="
+	// contentType is the CloudEvents structured content mode
+	contentType = "application/cloudevents+json; charset=utf-8"
+)
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp, a ".", and the
+// payload, keyed with the secret.  The timestamp is signed so subscribers can
+// reject replayed deliveries
+func Sign(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns true if the signature header is a valid signature of the
+// timestamp and payload
+func Verify(secret string, timestamp string, payload []byte, signature string) bool {
+	expected := signaturePrefix + Sign(secret, timestamp, payload)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// NewRequest creates the signed request which delivers the event to the webhook
+func NewRequest(hook *Webhook, evt *event.CloudEvent, payload []byte, now time.Time) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, aws.StringValue(hook.URL), bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.NewInternalServer("unable to create webhook request", err)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(EventIDHeader, evt.ID)
+	req.Header.Set(EventTypeHeader, string(evt.Type))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, signaturePrefix+Sign(aws.StringValue(hook.Secret), timestamp, payload))
+	return req, nil
+}
@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/Optum/dce/pkg/event"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+)
+
+// We don't use the internal errors package here because validation will rewrite it anyways
+// Just spit out errors and turn them into validation errors inside the appropriate functions
+
+// minSecretLength is the minimum length of a signing secret
+const minSecretLength = 16
+
+var validateID = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	is.UUIDv4.Error("must be a UUIDv4"),
+}
+
+var validateURL = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	validation.By(isHTTPURL),
+}
+
+var validateEventTypes = []validation.Rule{
+	validation.By(isDeliverableEventTypes),
+}
+
+var validateSecret = []validation.Rule{
+	validation.NotNil.Error("must be a string"),
+	validation.Length(minSecretLength, 0).Error("must be at least 16 characters"),
+}
+
+var validateInt64 = []validation.Rule{
+	validation.NotNil.Error("must be an epoch timestamp"),
+}
+
+func isNil(value interface{}) error {
+	if !reflect.ValueOf(value).IsNil() {
+		return errors.New("must be empty")
+	}
+	return nil
+}
+
+func isHTTPURL(value interface{}) error {
+	s, _ := value.(*string)
+	if s == nil {
+		return nil
+	}
+	u, err := url.Parse(*s)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("must be an http or https URL")
+	}
+	// Hostnames are checked again when they're resolved, as events are delivered
+	host := strings.ToLower(u.Hostname())
+	ip := net.ParseIP(host)
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || (ip != nil && isPrivateIP(ip)) {
+		return errors.New("must not be a private, loopback or link-local address")
+	}
+	return nil
+}
+
+// privateNetworks are the address ranges events aren't delivered to, so a
+// webhook can't reach DCE's own network or the instance metadata service
+var privateNetworks = parseCIDRs(
+	"0.0.0.0/8",      // "This" network
+	"10.0.0.0/8",     // Private
+	"100.64.0.0/10",  // Carrier-grade NAT
+	"127.0.0.0/8",    // Loopback
+	"169.254.0.0/16", // Link-local
+	"172.16.0.0/12",  // Private
+	"192.168.0.0/16", // Private
+	"::/128",         // Unspecified
+	"::1/128",        // Loopback
+	"fc00::/7",       // Unique local
+	"fe80::/10",      // Link-local
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isPrivateIP returns true if the address is private, loopback or link-local
+func isPrivateIP(ip net.IP) bool {
+	for _, network := range privateNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDeliverableEventTypes(value interface{}) error {
+	eventTypes, _ := value.([]event.Type)
+	for _, t := range eventTypes {
+		if !isDeliverable(t) {
+			return errors.New("must be types of events delivered to webhooks")
+		}
+	}
+	return nil
+}
+
+func isDeliverable(eventType event.Type) bool {
+	for _, t := range DeliverableEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
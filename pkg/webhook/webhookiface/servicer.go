@@ -0,0 +1,26 @@
+//
+
+package webhookiface
+
+import (
+	"github.com/Optum/dce/pkg/event"
+	"github.com/Optum/dce/pkg/webhook"
+)
+
+// Servicer makes working with the Webhook Service struct easier
+type Servicer interface {
+	// Get returns a webhook from ID
+	Get(ID string) (*webhook.Webhook, error)
+	// Save writes the record to the dataSvc
+	Save(data *webhook.Webhook) error
+	// Create creates a new webhook using the data provided. Returns the webhook record
+	Create(data *webhook.Webhook) (*webhook.Webhook, error)
+	// Update the Webhook record in DynamoDB
+	Update(ID string, data *webhook.Webhook) (*webhook.Webhook, error)
+	// Delete the webhook
+	Delete(data *webhook.Webhook) error
+	// List Get the list of webhooks
+	List() (*webhook.Webhooks, error)
+	// Deliver the event to each webhook subscribed to its type
+	Deliver(evt *event.CloudEvent) error
+}